@@ -0,0 +1,103 @@
+// cmd/codecbench/main.go
+
+// Command codecbench measures the active JSON codec on tool-result shaped
+// payloads. Compare codecs by running it with and without -tags jsoniter.
+// The pack and unpack lines show the cost of the negotiated MessagePack
+// encoding, which is transcoded from the JSON form.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dkoosis/axe-handle/internal/codec"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+)
+
+func main() {
+	sizes := flag.String("sizes", "1024,65536,1048576", "comma-separated text sizes in bytes")
+	blocks := flag.Int("blocks", 8, "content blocks per tool result")
+	flag.Parse()
+
+	fmt.Printf("codec: %s\n", codec.Name())
+	for _, s := range strings.Split(*sizes, ",") {
+		var size int
+		if _, err := fmt.Sscan(strings.TrimSpace(s), &size); err != nil || size <= 0 {
+			fmt.Fprintf(os.Stderr, "invalid size %q\n", s)
+			os.Exit(2)
+		}
+
+		result := toolResult(size, *blocks)
+		data, err := codec.Marshal(result)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "encode failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		report("encode", size, len(data), testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := codec.Marshal(result); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}))
+		report("decode", size, len(data), testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var out protocol.ToolsCallResult
+				if err := codec.NewDecoder(bytes.NewReader(data)).Decode(&out); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}))
+
+		packed, err := codec.JSONToMsgpack(nil, data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "msgpack encode failed: %v\n", err)
+			os.Exit(1)
+		}
+		report("pack", size, len(packed), testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := codec.JSONToMsgpack(packed[:0], data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}))
+		report("unpack", size, len(packed), testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := codec.MsgpackToJSON(packed); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}))
+	}
+}
+
+// toolResult builds a result with blocks text items totalling about size bytes
+func toolResult(size, blocks int) protocol.ToolsCallResult {
+	// Mix in characters that need escaping so the encoder does real work
+	line := "lorem ipsum \"quoted\" <tag> dolor\tsit amet\n"
+	per := size / blocks
+	text := strings.Repeat(line, per/len(line)+1)[:per]
+
+	result := protocol.ToolsCallResult{}
+	for i := 0; i < blocks; i++ {
+		result.Content = append(result.Content, protocol.Content{Type: "text", Text: text})
+	}
+	return result
+}
+
+// report prints one benchmark line with throughput
+func report(op string, size, encoded int, r testing.BenchmarkResult) {
+	nsPerOp := float64(r.NsPerOp())
+	mbPerSec := float64(encoded) / nsPerOp * 1e3
+	fmt.Printf("%-6s %9d B  %12.0f ns/op  %8.1f MB/s  %8d B/op  %6d allocs/op\n",
+		op, size, nsPerOp, mbPerSec, r.AllocedBytesPerOp(), r.AllocsPerOp())
+}
@@ -0,0 +1,222 @@
+// cmd/server/bench.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+)
+
+// benchClient issues JSON-RPC calls against a server under test
+type benchClient interface {
+	Call(ctx context.Context, method string, params, result interface{}) error
+	Notify(ctx context.Context, method string, params interface{}) error
+	Close() error
+}
+
+// benchOptions configures a bench run
+type benchOptions struct {
+	target      string
+	concurrency int
+	requests    int
+	duration    time.Duration
+	timeout     time.Duration
+	mix         []string // methods, repeated by weight
+}
+
+// benchStats aggregates call outcomes across workers
+type benchStats struct {
+	latencies []time.Duration
+	errors    map[string]int
+	mu        sync.Mutex
+}
+
+// defaultBenchParams are sent for methods that need params
+var defaultBenchParams = map[string]interface{}{
+	protocol.MethodResourcesRead: protocol.ResourcesReadParams{URI: "axe-handle://status"},
+}
+
+// runBench implements the "bench" subcommand
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	target := fs.String("target", "inmemory", `server to drive: "inmemory", "exec:<command>" (stdio) or an SSE base URL`)
+	concurrency := fs.Int("concurrency", 8, "concurrent callers")
+	requests := fs.Int("requests", 10000, "total requests to send (ignored when -duration is set)")
+	duration := fs.Duration("duration", 0, "run for this long instead of a fixed request count")
+	timeout := fs.Duration("timeout", 10*time.Second, "per-request timeout")
+	mix := fs.String("mix", "ping=1,tools/list=1,resources/read=1", "weighted request mix as method=weight pairs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	methods, err := parseMix(*mix)
+	if err != nil {
+		return err
+	}
+	opts := benchOptions{
+		target:      *target,
+		concurrency: *concurrency,
+		requests:    *requests,
+		duration:    *duration,
+		timeout:     *timeout,
+		mix:         methods,
+	}
+	if opts.concurrency < 1 {
+		return fmt.Errorf("concurrency must be at least 1")
+	}
+
+	client, err := dialBenchTarget(opts.target)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := benchInitialize(client, opts.timeout); err != nil {
+		return fmt.Errorf("initialize failed: %w", err)
+	}
+
+	start := time.Now()
+	stats := driveBench(client, opts)
+	printBenchReport(os.Stdout, opts, stats, time.Since(start))
+	return nil
+}
+
+// parseMix expands "a=2,b=1" into [a a b]
+func parseMix(mix string) ([]string, error) {
+	var methods []string
+	for _, part := range strings.Split(mix, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		method, weightStr, found := strings.Cut(part, "=")
+		weight := 1
+		if found {
+			w, err := strconv.Atoi(weightStr)
+			if err != nil || w < 0 {
+				return nil, fmt.Errorf("invalid weight in mix entry %q", part)
+			}
+			weight = w
+		}
+		for i := 0; i < weight; i++ {
+			methods = append(methods, method)
+		}
+	}
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("request mix is empty")
+	}
+	return methods, nil
+}
+
+// benchInitialize performs the MCP handshake
+func benchInitialize(client benchClient, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	params := protocol.InitializeParams{
+		ProtocolVersion: protocol.LatestProtocolVersion,
+		ClientInfo:      protocol.Implementation{Name: "axe-handle-bench", Version: "0.1.0"},
+	}
+	var result json.RawMessage
+	if err := client.Call(ctx, protocol.MethodInitialize, params, &result); err != nil {
+		return err
+	}
+	return client.Notify(ctx, protocol.NotificationInitialized, struct{}{})
+}
+
+// driveBench runs the workers and collects their results
+func driveBench(client benchClient, opts benchOptions) *benchStats {
+	stats := &benchStats{errors: make(map[string]int)}
+
+	var next atomic.Int64
+	deadline := time.Time{}
+	if opts.duration > 0 {
+		deadline = time.Now().Add(opts.duration)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < opts.concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var latencies []time.Duration
+			for {
+				n := next.Add(1) - 1
+				if deadline.IsZero() && n >= int64(opts.requests) {
+					break
+				}
+				if !deadline.IsZero() && time.Now().After(deadline) {
+					break
+				}
+
+				method := opts.mix[n%int64(len(opts.mix))]
+				ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+				began := time.Now()
+				var result json.RawMessage
+				err := client.Call(ctx, method, defaultBenchParams[method], &result)
+				latencies = append(latencies, time.Since(began))
+				cancel()
+
+				if err != nil {
+					stats.recordError(method, err)
+				}
+			}
+			stats.mu.Lock()
+			stats.latencies = append(stats.latencies, latencies...)
+			stats.mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return stats
+}
+
+// recordError counts a failed call by method and error
+func (s *benchStats) recordError(method string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors[method+": "+err.Error()]++
+}
+
+// printBenchReport writes throughput and latency percentiles
+func printBenchReport(w io.Writer, opts benchOptions, stats *benchStats, elapsed time.Duration) {
+	latencies := stats.latencies
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	failed := 0
+	for _, n := range stats.errors {
+		failed += n
+	}
+
+	fmt.Fprintf(w, "target:      %s\n", opts.target)
+	fmt.Fprintf(w, "concurrency: %d\n", opts.concurrency)
+	fmt.Fprintf(w, "requests:    %d (%d failed)\n", len(latencies), failed)
+	fmt.Fprintf(w, "elapsed:     %s\n", elapsed.Round(time.Millisecond))
+	if elapsed > 0 {
+		fmt.Fprintf(w, "throughput:  %.0f req/s\n", float64(len(latencies))/elapsed.Seconds())
+	}
+	if len(latencies) > 0 {
+		fmt.Fprintf(w, "latency:     p50=%s p90=%s p99=%s max=%s\n",
+			percentile(latencies, 0.50), percentile(latencies, 0.90),
+			percentile(latencies, 0.99), latencies[len(latencies)-1])
+	}
+	for msg, n := range stats.errors {
+		fmt.Fprintf(w, "error:       %d x %s\n", n, msg)
+	}
+}
+
+// percentile returns the p-th percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	i := int(float64(len(sorted)-1) * p)
+	return sorted[i]
+}
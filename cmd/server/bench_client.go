@@ -0,0 +1,273 @@
+// cmd/server/bench_client.go
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dkoosis/axe-handle/internal/codec"
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/internal/mcp/server"
+	"github.com/dkoosis/axe-handle/internal/mcp/server/jsonrpc"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// dialBenchTarget connects to the server named by target
+func dialBenchTarget(target string) (benchClient, error) {
+	switch {
+	case target == "inmemory":
+		return dialInMemory()
+	case strings.HasPrefix(target, "exec:"):
+		return dialExec(strings.TrimPrefix(target, "exec:"))
+	case strings.HasPrefix(target, "http://"), strings.HasPrefix(target, "https://"):
+		return dialSSE(target)
+	default:
+		return nil, fmt.Errorf("unknown bench target %q", target)
+	}
+}
+
+// connClient adapts a jsonrpc2 connection to benchClient
+type connClient struct {
+	conn    *jsonrpc2.Conn
+	onClose func() error
+}
+
+// Call implements benchClient
+func (c *connClient) Call(ctx context.Context, method string, params, result interface{}) error {
+	return c.conn.Call(ctx, method, params, result)
+}
+
+// Notify implements benchClient
+func (c *connClient) Notify(ctx context.Context, method string, params interface{}) error {
+	return c.conn.Notify(ctx, method, params)
+}
+
+// Close implements benchClient
+func (c *connClient) Close() error {
+	err := c.conn.Close()
+	if c.onClose != nil {
+		if closeErr := c.onClose(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// dialInMemory starts a server in this process, connected over a pipe
+func dialInMemory() (benchClient, error) {
+	cfg := config.Default()
+	mcp := server.NewServer(cfg)
+	handler := jsonrpc.NewHandler(mcp, jsonrpc.WithWorkers(cfg.Server.Workers, cfg.Server.QueueSize))
+
+	serverSide, clientSide := net.Pipe()
+	ctx := context.Background()
+	serverConn := jsonrpc2.NewConn(ctx, codec.NewObjectStream(serverSide), handler)
+	clientConn := jsonrpc2.NewConn(ctx, codec.NewObjectStream(clientSide), nil)
+
+	return &connClient{
+		conn: clientConn,
+		onClose: func() error {
+			_ = serverConn.Close()
+			_ = handler.Close()
+			return mcp.Shutdown(ctx)
+		},
+	}, nil
+}
+
+// dialExec starts command and speaks to it over stdio
+func dialExec(command string) (benchClient, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("exec target needs a command")
+	}
+
+	cmd := exec.Command(fields[0], fields[1:]...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", fields[0], err)
+	}
+
+	pipe := &stdioConn{Reader: stdout, WriteCloser: stdin}
+	conn := jsonrpc2.NewConn(context.Background(), codec.NewObjectStream(pipe), nil)
+	return &connClient{
+		conn: conn,
+		onClose: func() error {
+			_ = stdin.Close()
+			// The server keeps running after stdin closes, so ask it to stop
+			if err := cmd.Process.Signal(os.Interrupt); err != nil {
+				_ = cmd.Process.Kill()
+			}
+			return cmd.Wait()
+		},
+	}, nil
+}
+
+// stdioConn joins a child's stdout and stdin into one stream
+type stdioConn struct {
+	io.Reader
+	io.WriteCloser
+}
+
+// sseBenchClient drives a remote server over the SSE transport
+type sseBenchClient struct {
+	base       string
+	messageURL string
+	events     io.ReadCloser
+	nextID     atomic.Int64
+
+	pending map[int64]chan *jsonrpc2.Response
+	mu      sync.Mutex
+}
+
+// dialSSE opens the event stream and waits for the session ID
+func dialSSE(base string) (benchClient, error) {
+	base = strings.TrimSuffix(base, "/")
+	resp, err := http.Get(base + "/sse")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("SSE endpoint returned %s", resp.Status)
+	}
+
+	c := &sseBenchClient{
+		base:    base,
+		events:  resp.Body,
+		pending: make(map[int64]chan *jsonrpc2.Response),
+	}
+
+	events := bufio.NewScanner(resp.Body)
+	events.Buffer(make([]byte, 64*1024), 64<<20)
+	sessionID, err := readSessionID(events)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	c.messageURL = base + "/messages?sessionId=" + sessionID
+
+	go c.readEvents(events)
+	return c, nil
+}
+
+// readSessionID reads the first event, which carries the session ID
+func readSessionID(events *bufio.Scanner) (string, error) {
+	for events.Scan() {
+		data, ok := strings.CutPrefix(events.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var hello struct {
+			SessionID string `json:"sessionId"`
+		}
+		if err := json.Unmarshal([]byte(data), &hello); err != nil || hello.SessionID == "" {
+			return "", fmt.Errorf("unexpected first event %q", data)
+		}
+		return hello.SessionID, nil
+	}
+	return "", fmt.Errorf("event stream closed before session started: %v", events.Err())
+}
+
+// readEvents routes responses on the event stream to their callers
+func (c *sseBenchClient) readEvents(events *bufio.Scanner) {
+	for events.Scan() {
+		data, ok := strings.CutPrefix(events.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var resp jsonrpc2.Response
+		if err := json.Unmarshal([]byte(data), &resp); err != nil || resp.ID.IsString {
+			continue
+		}
+		c.mu.Lock()
+		ch, ok := c.pending[int64(resp.ID.Num)]
+		delete(c.pending, int64(resp.ID.Num))
+		c.mu.Unlock()
+		if ok {
+			ch <- &resp
+		}
+	}
+}
+
+// Call implements benchClient
+func (c *sseBenchClient) Call(ctx context.Context, method string, params, result interface{}) error {
+	id := c.nextID.Add(1)
+	ch := make(chan *jsonrpc2.Response, 1)
+	c.mu.Lock()
+	c.pending[id] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	if err := c.post(ctx, map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": method, "params": params}); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case resp := <-ch:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if resp.Result != nil && result != nil {
+			return json.Unmarshal(*resp.Result, result)
+		}
+		return nil
+	}
+}
+
+// Notify implements benchClient
+func (c *sseBenchClient) Notify(ctx context.Context, method string, params interface{}) error {
+	return c.post(ctx, map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+// post sends one message to the session's message endpoint
+func (c *sseBenchClient) post(ctx context.Context, msg interface{}) error {
+	body, err := codec.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.messageURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("message endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Close implements benchClient
+func (c *sseBenchClient) Close() error {
+	return c.events.Close()
+}
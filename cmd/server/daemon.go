@@ -0,0 +1,163 @@
+// cmd/server/daemon.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/config"
+)
+
+// daemonEnv marks a process as the detached child of `serve --daemon`
+const daemonEnv = "AXE_HANDLE_DAEMON"
+
+// stopTimeout is how long `stop` waits for the daemon to exit
+const stopTimeout = 10 * time.Second
+
+// isDaemonChild reports whether this process was started by startDaemon
+func isDaemonChild() bool {
+	return os.Getenv(daemonEnv) == "1"
+}
+
+// pidFilePath returns the configured PID file, or one next to the default config file
+func pidFilePath(cfg *config.Config) string {
+	if cfg.Server.PIDFile != "" {
+		return cfg.Server.PIDFile
+	}
+	return filepath.Join(filepath.Dir(getDefaultConfigPath()), "axe-handle.pid")
+}
+
+// logFilePath returns the configured log file, or one next to the default config file
+func logFilePath(cfg *config.Config) string {
+	if cfg.Server.LogFile != "" {
+		return cfg.Server.LogFile
+	}
+	return filepath.Join(filepath.Dir(getDefaultConfigPath()), "axe-handle.log")
+}
+
+// checkDaemon reports why cfg cannot run in the background. A stdio server
+// talks over the stdin and stdout of the client that started it, which a
+// detached process no longer has.
+func checkDaemon(cfg *config.Config) error {
+	if cfg.Transport.Type == "stdio" {
+		return fmt.Errorf("--daemon needs the sse transport; stdio servers are started by their client")
+	}
+	return nil
+}
+
+// startDaemon re-executes the binary detached from the terminal, with its
+// output redirected to the log file.
+func startDaemon(cfg *config.Config) error {
+	pidFile := pidFilePath(cfg)
+	if pid, err := readPIDFile(pidFile); err == nil && processAlive(pid) {
+		return fmt.Errorf("axe-handle is already running (pid %d)", pid)
+	}
+
+	logFile := logFilePath(cfg)
+	if err := os.MkdirAll(filepath.Dir(logFile), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	out, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer out.Close()
+
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to get executable path: %w", err)
+	}
+
+	cmd := exec.Command(exePath, os.Args[1:]...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	cmd.Env = append(os.Environ(), daemonEnv+"=1")
+	cmd.SysProcAttr = detachedProcAttr()
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start daemon process: %w", err)
+	}
+
+	fmt.Printf("axe-handle started in the background (pid %d)\n", cmd.Process.Pid)
+	fmt.Printf("Logging to %s\n", logFile)
+	return cmd.Process.Release()
+}
+
+// runDaemonCommand implements the `stop` and `status` subcommands, reading
+// the same configuration file as `serve` to find the daemon's PID file
+func runDaemonCommand(name string, args []string, out io.Writer) error {
+	cmd := flag.NewFlagSet(name, flag.ExitOnError)
+	configPath := cmd.String("config", getDefaultConfigPath(), "Path to configuration file (uses AXEHANDLE_CONFIG env var if set, overrides default)")
+	if err := cmd.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	pidFile := pidFilePath(cfg)
+	pid, err := readPIDFile(pidFile)
+	if err != nil || !processAlive(pid) {
+		if name == "status" {
+			fmt.Fprintln(out, "axe-handle is not running")
+			return nil
+		}
+		return fmt.Errorf("axe-handle is not running (no live process in %s)", pidFile)
+	}
+
+	if name == "status" {
+		fmt.Fprintf(out, "axe-handle is running (pid %d)\n", pid)
+		return nil
+	}
+
+	if err := terminateProcess(pid); err != nil {
+		return fmt.Errorf("failed to signal pid %d: %w", pid, err)
+	}
+
+	deadline := time.Now().Add(stopTimeout)
+	for time.Now().Before(deadline) {
+		if !processAlive(pid) {
+			fmt.Fprintf(out, "axe-handle stopped (pid %d)\n", pid)
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("pid %d did not exit within %s", pid, stopTimeout)
+}
+
+// writePIDFile records the current process ID
+func writePIDFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create PID file directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write PID file: %w", err)
+	}
+	return nil
+}
+
+// removePIDFile deletes the PID file, unless another process (e.g. a
+// replacement started by a restart) has since claimed it.
+func removePIDFile(path string) {
+	if pid, err := readPIDFile(path); err == nil && pid == os.Getpid() {
+		_ = os.Remove(path)
+	}
+}
+
+// readPIDFile returns the process ID stored in path
+func readPIDFile(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
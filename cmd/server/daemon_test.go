@@ -0,0 +1,79 @@
+// cmd/server/daemon_test.go
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dkoosis/axe-handle/internal/config"
+)
+
+func TestCheckDaemon(t *testing.T) {
+	for _, tc := range []struct {
+		transport string
+		wantErr   bool
+	}{
+		{"sse", false},
+		{"stdio", true},
+	} {
+		cfg := config.Default()
+		cfg.Transport.Type = tc.transport
+		if err := checkDaemon(cfg); (err != nil) != tc.wantErr {
+			t.Errorf("checkDaemon(%s) error = %v, want error %v", tc.transport, err, tc.wantErr)
+		}
+	}
+}
+
+func TestDaemonCommandsReadConfig(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep as the daemon")
+	}
+	// Stands in for a daemon started with the same --config
+	daemon := exec.Command("sleep", "30")
+	if err := daemon.Start(); err != nil {
+		t.Skipf("cannot start a stand-in daemon: %v", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = daemon.Wait()
+		close(exited)
+	}()
+	defer func() {
+		_ = daemon.Process.Kill()
+		<-exited
+	}()
+
+	dir := t.TempDir()
+	pidFile := filepath.Join(dir, "custom.pid")
+	configFile := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configFile, []byte("server:\n  pidFile: "+pidFile+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pidFile, []byte(strconv.Itoa(daemon.Process.Pid)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(name string) string {
+		t.Helper()
+		var out bytes.Buffer
+		if err := runDaemonCommand(name, []string{"--config", configFile}, &out); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		return out.String()
+	}
+	if got := run("status"); !strings.Contains(got, "is running") {
+		t.Errorf("status = %q, want the daemon running", got)
+	}
+	if got := run("stop"); !strings.Contains(got, "stopped") {
+		t.Errorf("stop = %q, want the daemon stopped", got)
+	}
+	if got := run("status"); !strings.Contains(got, "not running") {
+		t.Errorf("status after stop = %q, want the daemon gone", got)
+	}
+}
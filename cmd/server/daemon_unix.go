@@ -0,0 +1,32 @@
+//go:build !windows
+
+// cmd/server/daemon_unix.go
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// detachedProcAttr starts the child in its own session, away from the terminal
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
+
+// processAlive reports whether a process with the given PID exists
+func processAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
+
+// terminateProcess asks the process to shut down gracefully
+func terminateProcess(pid int) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return proc.Signal(syscall.SIGTERM)
+}
@@ -0,0 +1,33 @@
+//go:build windows
+
+// cmd/server/daemon_windows.go
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// detachedProcAttr starts the child without a console window
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{HideWindow: true}
+}
+
+// processAlive reports whether a process with the given PID exists
+func processAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = proc.Release()
+	return true
+}
+
+// terminateProcess stops the process; Windows has no SIGTERM equivalent
+func terminateProcess(pid int) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return proc.Kill()
+}
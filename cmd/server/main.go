@@ -10,7 +10,9 @@ import (
 	"os/signal"
 	"path/filepath"
 	"syscall"
+	"time"
 
+	"github.com/dkoosis/axe-handle/internal/buildinfo"
 	"github.com/dkoosis/axe-handle/internal/config"
 	"github.com/dkoosis/axe-handle/internal/mcp/server"
 	"github.com/dkoosis/axe-handle/internal/mcp/server/jsonrpc"
@@ -21,34 +23,88 @@ import (
 func main() {
 	// Check if we have a subcommand
 	if len(os.Args) > 1 && os.Args[1] == "setup" {
-		// Process setup command
-		setupCmd := flag.NewFlagSet("setup", flag.ExitOnError)
-		// Define the flag, but ignore the value for now using '_' since runSetup is commented out
-		_ = setupCmd.String("config", getDefaultConfigPath(), "Path to configuration file") // <-- Fix 1: Use _
+		if err := runSetupCommand(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Setup failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-		if err := setupCmd.Parse(os.Args[2:]); err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing setup command flags: %v\n", err)
+	if len(os.Args) > 1 && (os.Args[1] == "version" || os.Args[1] == "--version") {
+		if err := runVersion(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "version failed: %v\n", err)
 			os.Exit(1)
 		}
+		return
+	}
 
-		// Assuming runSetup exists and is defined elsewhere
-		// configPathVal := *configPath // If you were using it, get value like this
-		// if err := runSetup(configPathVal); err != nil {
-		// 	fmt.Fprintf(os.Stderr, "Setup failed: %v\n", err)
-		//  os.Exit(1)
-		// }
+	if len(os.Args) > 1 && os.Args[1] == "new" {
+		if err := runNew(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "new failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "tools" {
+		if err := runTools(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "tools failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "openrpc" {
+		if err := runOpenRPC(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "openrpc failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "self-update" {
+		if err := runSelfUpdate(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "self-update failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Load testing against an in-memory or remote server
+	if len(os.Args) > 1 && os.Args[1] == "bench" {
+		if err := runBench(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "bench failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-		fmt.Println("Setup command placeholder executed.") // Placeholder
+	// Daemon control commands
+	if len(os.Args) > 1 && (os.Args[1] == "stop" || os.Args[1] == "status") {
+		if err := runDaemonCommand(os.Args[1], os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "%s failed: %v\n", os.Args[1], err)
+			os.Exit(1)
+		}
 		return
 	}
 
-	// Regular command (not setup)
+	// Regular command (not setup); "serve" may be given explicitly
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "serve" {
+		args = args[1:]
+	}
 	defaultConfigPath := getDefaultConfigPath()
-	flag.String("config", defaultConfigPath, "Path to configuration file (uses AXEHANDLE_CONFIG env var if set, overrides default)")
-	flag.Parse()
+	configPath := flag.String("config", defaultConfigPath, "Path to configuration file (uses AXEHANDLE_CONFIG env var if set, overrides default)")
+	daemon := flag.Bool("daemon", false, "Run in the background, writing a PID file and logging to the configured log file")
+	profile := flag.String("profile", "", "Serve the stdio session with this configured profile instead of the whole server")
+	chaos := flag.String("chaos", "", "Development only: inject faults into connections, e.g. latency=50ms,jitter=20ms,drop=0.01,partial=0.01,disconnect=0.001,seed=1")
+	if err := flag.CommandLine.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load(*configPath)
 	if err != nil {
 		// Log the config loading error and exit, similar to original logic
 		fmt.Fprintf(os.Stderr, "ERROR: Error loading configuration: %v\n", err) // <-- Fix 2: Log Error and Exit
@@ -56,32 +112,160 @@ func main() {
 	}
 	// If we reach here, cfg should be non-nil and valid
 
-	// --- Start Logging Modification ---
-	// Configure logging
-	// logging.Configure(logging.LogLevel(cfg.Server.LogLevel)) // <-- Original line commented out
-	logging.Configure(logging.LevelDebug)               // <--- FORCE DEBUG LEVEL for this test
-	slog.Debug("DEBUG LOGGING HAS BEEN FORCED ENABLED") // Add this line to confirm
-	// --- End Logging Modification ---
+	// Detach into the background if requested; the child re-enters main below
+	if *daemon {
+		if err := checkDaemon(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *daemon && !isDaemonChild() {
+		if err := startDaemon(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Failed to start daemon: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if isDaemonChild() {
+		pidFile := pidFilePath(cfg)
+		if err := writePIDFile(pidFile); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		defer removePIDFile(pidFile)
+	}
+
+	// Configure logging; a daemon's stderr is its log file
+	logging.Configure(logging.LogLevel(cfg.Server.LogLevel))
+
+	// Profiles are selected by name, over SSE as a path segment
+	if err := cfg.ValidateProfiles(); err != nil {
+		slog.Error("Invalid profiles", "error", err)
+		os.Exit(1)
+	}
+	if _, ok := cfg.Profile(*profile); *profile != "" && !ok {
+		slog.Error("Unknown profile", "profile", *profile)
+		os.Exit(1)
+	}
+
+	// Tenants are recognized by the identities bearer tokens stand for
+	if err := cfg.ValidateTenants(); err != nil {
+		slog.Error("Invalid tenants", "error", err)
+		os.Exit(1)
+	}
+	if len(cfg.Tenants) > 0 && (cfg.Transport.Type != "sse" || len(cfg.Transport.SSE.Tokens) == 0) {
+		slog.Warn("Tenants are configured but clients are not authenticated, so every client shares the untenanted server")
+	}
+
+	// One switch for every development check
+	if err := cfg.ValidateMode(); err != nil {
+		slog.Error("Invalid mode", "error", err)
+		os.Exit(1)
+	}
+	dev := cfg.Development()
+	if dev {
+		slog.Warn("Running in development mode: strict checks on, error internals and messages exposed")
+	}
 
 	// Create server
 	mcp := server.NewServer(cfg)
+	mcp.GetToolsManager().SetStrict(dev)
+
+	// Error metrics and alerting
+	startMetrics(mcp, cfg)
+
+	// Prompt library
+	if err := startPrompts(mcp, cfg); err != nil {
+		slog.Error("Failed to load prompts", "error", err)
+		os.Exit(1)
+	}
+
+	// Tools declared as function definitions
+	if err := loadDeclarativeTools(mcp, cfg); err != nil {
+		slog.Error("Failed to import tools", "error", err)
+		os.Exit(1)
+	}
+
+	startGraphQL(mcp, cfg)
+	startTables(mcp, cfg)
+	startDocker(mcp, cfg)
+	if err := startCalendar(mcp, cfg); err != nil {
+		slog.Error("Failed to configure calendars", "error", err)
+		os.Exit(1)
+	}
+	if err := startMail(mcp, cfg); err != nil {
+		slog.Error("Failed to configure mail", "error", err)
+		os.Exit(1)
+	}
+
+	if err := startS3(mcp, cfg); err != nil {
+		slog.Error("Failed to configure object storage", "error", err)
+		os.Exit(1)
+	}
+
+	if err := startIssues(mcp, cfg); err != nil {
+		slog.Error("Failed to configure issue trackers", "error", err)
+		os.Exit(1)
+	}
+
+	if err := startMemory(mcp, cfg); err != nil {
+		slog.Error("Failed to configure memory", "error", err)
+		os.Exit(1)
+	}
+
+	if err := startNotify(mcp, cfg); err != nil {
+		slog.Error("Failed to configure notifications", "error", err)
+		os.Exit(1)
+	}
+
+	if err := startPlugins(mcp, cfg); err != nil {
+		slog.Error("Failed to start plugins", "error", err)
+		os.Exit(1)
+	}
+
+	// Recurring tool calls; started last so every tool they name is registered
+	if err := startSchedules(mcp, cfg); err != nil {
+		slog.Error("Failed to load schedules", "error", err)
+		os.Exit(1)
+	}
+
+	// Tools for OpenAI-compatible agents
+	startBridge(mcp, cfg)
 
 	// Create handler
-	handler := jsonrpc.NewHandler(mcp)
+	handler := jsonrpc.NewHandler(mcp,
+		jsonrpc.WithWorkers(cfg.Server.Workers, cfg.Server.QueueSize),
+		jsonrpc.WithStrict(dev))
+	mcp.OnShutdown(func(context.Context) error {
+		return handler.Close()
+	})
 
 	// Create transport based on configuration
-	var t transport.Transport
-	if cfg.Transport.Type == "stdio" {
-		t = transport.NewStdioTransport()
-		slog.Info("Using stdio transport")
-	} else if cfg.Transport.Type == "sse" {
-		t = transport.NewSSETransport(cfg.Transport.SSE.Host, cfg.Transport.SSE.Port)
+	t, err := server.NewTransport(cfg, *profile)
+	if err != nil {
+		slog.Error("Failed to create transport", "error", err)
+		os.Exit(1)
+	}
+	if sse, ok := t.(*transport.SSETransport); ok {
 		slog.Info("Using SSE transport",
 			"host", cfg.Transport.SSE.Host,
-			"port", cfg.Transport.SSE.Port)
+			"port", cfg.Transport.SSE.Port,
+			"stream", sse.StreamPath())
+		for _, name := range server.ProfileNames(cfg) {
+			slog.Info("Serving profile", "profile", name, "stream", sse.ProfileStreamPath(name))
+		}
 	} else {
-		slog.Error("Unsupported transport type", "type", cfg.Transport.Type)
-		os.Exit(1)
+		slog.Info("Using stdio transport", "framing", cfg.Transport.Stdio.Framing, "profile", *profile)
+	}
+
+	// Deliberately exercise error and reconnection paths during development
+	if *chaos != "" {
+		chaosCfg, err := transport.ParseChaos(*chaos)
+		if err != nil {
+			slog.Error("Invalid --chaos setting", "error", err)
+			os.Exit(1)
+		}
+		t = transport.NewChaosTransport(t, chaosCfg)
 	}
 
 	// Connect transport
@@ -96,21 +280,71 @@ func main() {
 
 	slog.Info("Axe Handle server started",
 		"name", cfg.Server.Name,
-		"version", cfg.Server.Version)
+		"version", cfg.Server.Version,
+		"build", buildinfo.Get().String())
 
 	// Wait for signals
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-	<-sigCh
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range sigCh {
+		if sig == syscall.SIGHUP {
+			// SIGHUP hands the listener to a freshly started binary and drains us
+			if restartTransport(mcp, t, cfg.Transport.SSE.DrainTimeout) {
+				if err := mcp.Shutdown(context.Background()); err != nil {
+					slog.Error("Error during shutdown", "error", err)
+				}
+				return
+			}
+			continue
+		}
+		break
+	}
 
 	slog.Info("Shutting down...")
 
-	// Graceful shutdown
+	// Graceful shutdown; the transport closes last, after provider hooks
+	// have flushed
+	if err := mcp.Shutdown(context.Background()); err != nil {
+		slog.Error("Error during shutdown", "error", err)
+	}
 	if err := t.Close(); err != nil {
 		slog.Error("Error closing transport", "error", err)
 	}
 }
 
+// restartTransport hands the transport's listener to a replacement process and
+// drains existing sessions. It reports whether the current process should exit.
+// The replacement opens the state store as it starts, so provider hooks run
+// and the store is released first; the sessions left draining run without
+// it, and a failed handoff ends this process too.
+func restartTransport(mcp *server.Server, t transport.Transport, drainTimeout time.Duration) bool {
+	rt, ok := t.(transport.Restartable)
+	if !ok {
+		slog.Warn("Transport does not support zero-downtime restart, ignoring SIGHUP")
+		return false
+	}
+
+	if err := mcp.Release(context.Background()); err != nil {
+		slog.Error("Error releasing server resources", "error", err)
+	}
+	if _, err := rt.Handoff(); err != nil {
+		slog.Error("Failed to start replacement process, shutting down", "error", err)
+		if err := t.Close(); err != nil {
+			slog.Error("Error closing transport", "error", err)
+		}
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+
+	if err := rt.Drain(ctx); err != nil {
+		slog.Error("Error draining transport", "error", err)
+	}
+	slog.Info("Handed off to replacement process, exiting")
+	return true
+}
+
 // getDefaultConfigPath returns the default path for the configuration file
 func getDefaultConfigPath() string {
 	// Allow override via environment variable first
@@ -124,12 +358,3 @@ func getDefaultConfigPath() string {
 	}
 	return filepath.Join(homeDir, ".config", "axe-handle", "config.yaml")
 }
-
-// Placeholder for runSetup - replace with your actual implementation
-// func runSetup(configPath string) error {
-//   fmt.Printf("Running setup with config path: %s\n", configPath)
-//   // Add actual setup logic here
-//   return nil
-// }
-
-// NOTE: No DefaultConfig function assumed anymore
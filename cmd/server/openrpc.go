@@ -0,0 +1,49 @@
+// cmd/server/openrpc.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/internal/mcp/server"
+)
+
+// runOpenRPC prints an OpenRPC description of the server configured by
+// --config. The same document is served as the axe-handle://openrpc resource.
+func runOpenRPC(args []string) error {
+	fs := flag.NewFlagSet("openrpc", flag.ContinueOnError)
+	configPath := fs.String("config", getDefaultConfigPath(), "Path to configuration file")
+	output := fs.String("o", "", "Write the document to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	mcp := server.NewServer(cfg)
+	defer func() { _ = mcp.Shutdown(context.Background()) }()
+	if err := loadDeclarativeTools(mcp, cfg); err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(mcp.OpenRPC())
+}
@@ -0,0 +1,308 @@
+// cmd/server/scaffold.go
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// defaultModulePath is used when no go.mod is found in the working directory
+const defaultModulePath = "github.com/dkoosis/axe-handle"
+
+// providerNamePattern is what a generated provider's package name may be
+var providerNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// scaffoldData fills the provider templates
+type scaffoldData struct {
+	Name   string // package name
+	Module string // module path of the repository
+	Dir    string // package directory relative to the module root
+}
+
+// runNew dispatches `axe-handle new <kind> ...`
+func runNew(args []string) error {
+	if len(args) == 0 || args[0] != "provider" {
+		return fmt.Errorf("usage: axe-handle new provider <name> [-dir path]")
+	}
+	return runNewProvider(args[1:])
+}
+
+// runNewProvider generates a provider package skeleton
+func runNewProvider(args []string) error {
+	fs := flag.NewFlagSet("new provider", flag.ContinueOnError)
+	dir := fs.String("dir", filepath.Join("internal", "providers"), "Directory to create the provider package in")
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("usage: axe-handle new provider <name> [-dir path]")
+	}
+	name := args[0]
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if !providerNamePattern.MatchString(name) {
+		return fmt.Errorf("provider name %q must be a lowercase Go package name", name)
+	}
+
+	data := scaffoldData{
+		Name:   name,
+		Module: modulePath(),
+		Dir:    filepath.ToSlash(filepath.Join(*dir, name)),
+	}
+	pkgDir := filepath.Join(*dir, name)
+	if _, err := os.Stat(pkgDir); err == nil {
+		return fmt.Errorf("%s already exists", pkgDir)
+	}
+	if err := os.MkdirAll(pkgDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", pkgDir, err)
+	}
+
+	files := map[string]*template.Template{
+		name + ".go":      providerTemplate,
+		name + "_test.go": providerTestTemplate,
+	}
+	for file, tmpl := range files {
+		path := filepath.Join(pkgDir, file)
+		if err := writeTemplate(path, tmpl, data); err != nil {
+			return err
+		}
+		fmt.Printf("Created %s\n", path)
+	}
+
+	if err := registrationTemplate.Execute(os.Stdout, data); err != nil {
+		return fmt.Errorf("failed to print registration snippet: %w", err)
+	}
+	return nil
+}
+
+// writeTemplate renders tmpl to a new file at path
+func writeTemplate(path string, tmpl *template.Template, data scaffoldData) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := tmpl.Execute(f, data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to render %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// modulePath reads the module path from ./go.mod
+func modulePath() string {
+	f, err := os.Open("go.mod")
+	if err != nil {
+		return defaultModulePath
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if mod, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.TrimSpace(mod)
+		}
+	}
+	return defaultModulePath
+}
+
+// providerTemplate is the generated provider implementation
+var providerTemplate = template.Must(template.New("provider").Parse(`// {{.Dir}}/{{.Name}}.go
+
+// Package {{.Name}} provides MCP resources, tools and prompts.
+package {{.Name}}
+
+import (
+	"fmt"
+
+	"{{.Module}}/internal/mcp/prompts"
+	"{{.Module}}/internal/mcp/protocol"
+	"{{.Module}}/internal/mcp/resources"
+	"{{.Module}}/internal/mcp/tools"
+)
+
+// Provider implements the resource, tool and prompt provider interfaces.
+// Delete the parts you don't need.
+type Provider struct{}
+
+// New creates a {{.Name}} provider
+func New() *Provider {
+	return &Provider{}
+}
+
+// Ensure Provider implements the provider interfaces
+var (
+	_ resources.Provider = (*Provider)(nil)
+	_ tools.Provider     = (*Provider)(nil)
+	_ prompts.Provider   = (*Provider)(nil)
+)
+
+// ListResources returns the provider's resources
+func (p *Provider) ListResources() ([]resources.Resource, error) {
+	return []resources.Resource{
+		{
+			URI:         "{{.Name}}://readme",
+			Name:        "{{.Name}} readme",
+			Description: "What this provider offers",
+			MimeType:    "text/plain",
+		},
+	}, nil
+}
+
+// GetResource returns the content of a resource
+func (p *Provider) GetResource(uri string) (interface{}, error) {
+	if uri == "{{.Name}}://readme" {
+		return "The {{.Name}} provider.", nil
+	}
+	return nil, resources.ErrResourceNotFound
+}
+
+// ListTools returns the provider's tools
+func (p *Provider) ListTools() ([]tools.Tool, error) {
+	return []tools.Tool{
+		{
+			Name:        "{{.Name}}_greet",
+			Description: "Greets someone by name",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Who to greet",
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
+	}, nil
+}
+
+// ExecuteTool runs a tool
+func (p *Provider) ExecuteTool(name string, args map[string]interface{}) (interface{}, error) {
+	if name == "{{.Name}}_greet" {
+		who, ok := args["name"].(string)
+		if !ok || who == "" {
+			return nil, tools.ErrInvalidToolArguments
+		}
+		return protocol.ToolsCallResult{
+			Content: []protocol.Content{protocol.TextContent(fmt.Sprintf("Hello, %s!", who))},
+		}, nil
+	}
+	return nil, tools.ErrToolNotFound
+}
+
+// ListPrompts returns the provider's prompts
+func (p *Provider) ListPrompts() ([]prompts.Prompt, error) {
+	return []prompts.Prompt{
+		{
+			Name:        "{{.Name}}_intro",
+			Description: "Introduces the {{.Name}} provider",
+		},
+	}, nil
+}
+
+// GetPrompt renders a prompt
+func (p *Provider) GetPrompt(name string, args map[string]string) (interface{}, error) {
+	if name == "{{.Name}}_intro" {
+		return protocol.PromptsGetResult{
+			Messages: []protocol.PromptMessage{
+				protocol.UserMessage(protocol.TextContent("What can the {{.Name}} provider do?")),
+			},
+		}, nil
+	}
+	return nil, prompts.ErrPromptNotFound
+}
+`))
+
+// providerTestTemplate is the generated table-driven test
+var providerTestTemplate = template.Must(template.New("test").Parse(`// {{.Dir}}/{{.Name}}_test.go
+package {{.Name}}
+
+import (
+	"errors"
+	"testing"
+
+	"{{.Module}}/internal/mcp/protocol"
+	"{{.Module}}/internal/mcp/tools"
+	"{{.Module}}/pkg/mcptest"
+	"{{.Module}}/pkg/providertest"
+)
+
+func TestContract(t *testing.T) {
+	providertest.Run(t, New())
+}
+
+func TestServed(t *testing.T) {
+	c := mcptest.New(t, mcptest.WithToolProvider(New()))
+	c.Initialize()
+
+	var list struct{ Tools []protocol.Tool }
+	c.MustCall(protocol.MethodToolsList, nil, &list)
+	var listed bool
+	for _, tool := range list.Tools {
+		listed = listed || tool.Name == "{{.Name}}_greet"
+	}
+	if !listed {
+		t.Fatalf("tools/list = %+v, want {{.Name}}_greet", list.Tools)
+	}
+
+	var result protocol.ToolsCallResult
+	c.MustCall(protocol.MethodToolsCall, map[string]interface{}{
+		"name":      "{{.Name}}_greet",
+		"arguments": map[string]interface{}{"name": "Ada"},
+	}, &result)
+	if result.IsError || len(result.Content) != 1 || result.Content[0].Text != "Hello, Ada!" {
+		t.Errorf("tools/call = %+v, want text %q", result, "Hello, Ada!")
+	}
+}
+
+func TestExecuteTool(t *testing.T) {
+	tests := []struct {
+		name    string
+		tool    string
+		args    map[string]interface{}
+		want    string
+		wantErr error
+	}{
+		{name: "greets", tool: "{{.Name}}_greet", args: map[string]interface{}{"name": "Ada"}, want: "Hello, Ada!"},
+		{name: "missing name", tool: "{{.Name}}_greet", args: map[string]interface{}{}, wantErr: tools.ErrInvalidToolArguments},
+		{name: "unknown tool", tool: "nope", wantErr: tools.ErrToolNotFound},
+	}
+
+	p := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.ExecuteTool(tt.tool, tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExecuteTool() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			result, ok := got.(protocol.ToolsCallResult)
+			if !ok || len(result.Content) != 1 || result.Content[0].Text != tt.want {
+				t.Errorf("ExecuteTool() = %#v, want text %q", got, tt.want)
+			}
+		})
+	}
+}
+`))
+
+// registrationTemplate explains how to wire the new provider in
+var registrationTemplate = template.Must(template.New("register").Parse(`
+Register the provider where the server is created (cmd/server/main.go):
+
+	import "{{.Module}}/{{.Dir}}"
+
+	{{.Name}}Provider := {{.Name}}.New()
+	mcp.RegisterResourceProvider({{.Name}}Provider)
+	mcp.RegisterToolProvider({{.Name}}Provider)
+	mcp.RegisterPromptProvider({{.Name}}Provider)
+`))
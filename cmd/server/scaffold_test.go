@@ -0,0 +1,38 @@
+// cmd/server/scaffold_test.go
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestScaffoldedProviderIsServed generates a provider inside the module and
+// runs its tests, which list and call its tool through a running server
+func TestScaffoldedProviderIsServed(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and tests a generated package")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not found")
+	}
+
+	// Generated code imports internal packages, so it must live in the module
+	dir, err := os.MkdirTemp(filepath.Join("..", "..", "internal", "providers"), "scaffoldtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	if err := runNewProvider([]string{"zzgreeter", "-dir", dir}); err != nil {
+		t.Fatalf("runNewProvider() error = %v", err)
+	}
+
+	cmd := exec.Command(goBin, "test", "-count=1", "./"+filepath.Base(dir)+"/zzgreeter")
+	cmd.Dir = filepath.Dir(dir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("tests of the scaffolded provider failed: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,249 @@
+// cmd/server/selfupdate.go
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/buildinfo"
+)
+
+// Self-update defaults
+const (
+	defaultReleaseURL = "https://api.github.com/repos/dkoosis/axe-handle/releases/latest"
+	checksumsAsset    = "checksums.txt"
+	signatureAsset    = "checksums.txt.sig" // base64 ed25519 signature of checksumsAsset
+	updateTimeout     = 5 * time.Minute
+	maxMetadataBytes  = 1 << 20
+)
+
+// release is the subset of a GitHub release we use
+type release struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// assetURL returns the download URL of the named asset
+func (r *release) assetURL(name string) (string, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a.URL, true
+		}
+	}
+	return "", false
+}
+
+// runSelfUpdate replaces the running binary with the latest release
+func runSelfUpdate(args []string) error {
+	fs := flag.NewFlagSet("self-update", flag.ContinueOnError)
+	releaseURL := fs.String("url", defaultReleaseURL, "Release endpoint returning the latest release as JSON")
+	publicKey := fs.String("public-key", "", "Base64 ed25519 key; when set, the release's checksums must be signed with it")
+	checkOnly := fs.Bool("check", false, "Report whether an update is available without installing it")
+	force := fs.Bool("force", false, "Reinstall even if the latest release is already running")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), updateTimeout)
+	defer cancel()
+
+	rel, err := fetchRelease(ctx, *releaseURL)
+	if err != nil {
+		return err
+	}
+	current := buildinfo.Get().Version
+	if rel.TagName == current && !*force {
+		fmt.Printf("axe-handle %s is up to date\n", current)
+		return nil
+	}
+	if *checkOnly {
+		fmt.Printf("Update available: %s (running %s)\n", rel.TagName, current)
+		return nil
+	}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to get executable path: %w", err)
+	}
+	if exePath, err = filepath.EvalSymlinks(exePath); err != nil {
+		return fmt.Errorf("failed to resolve executable path: %w", err)
+	}
+
+	want, err := releaseChecksum(ctx, rel, *publicKey)
+	if err != nil {
+		return err
+	}
+	if err := installRelease(ctx, rel, exePath, want); err != nil {
+		return err
+	}
+	fmt.Printf("Updated axe-handle %s -> %s\n", current, rel.TagName)
+
+	// Client configurations may still point at where the binary used to be
+	fixClientPaths(exePath)
+	return nil
+}
+
+// platformAsset names the release binary for this OS and architecture
+func platformAsset() string {
+	name := fmt.Sprintf("axe-handle_%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// fetchRelease reads the release description from url
+func fetchRelease(ctx context.Context, url string) (*release, error) {
+	body, err := download(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for updates: %w", err)
+	}
+	defer body.Close()
+
+	var rel release
+	if err := json.NewDecoder(io.LimitReader(body, maxMetadataBytes)).Decode(&rel); err != nil {
+		return nil, fmt.Errorf("invalid release metadata from %s: %w", url, err)
+	}
+	if rel.TagName == "" {
+		return nil, fmt.Errorf("release metadata from %s has no tag", url)
+	}
+	return &rel, nil
+}
+
+// releaseChecksum returns the published SHA-256 of this platform's binary,
+// verifying the checksum file's signature when a public key is given
+func releaseChecksum(ctx context.Context, rel *release, publicKey string) (string, error) {
+	sums, err := fetchAsset(ctx, rel, checksumsAsset)
+	if err != nil {
+		return "", err
+	}
+
+	if publicKey != "" {
+		key, err := base64.StdEncoding.DecodeString(publicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return "", fmt.Errorf("invalid public key")
+		}
+		sig, err := fetchAsset(ctx, rel, signatureAsset)
+		if err != nil {
+			return "", err
+		}
+		rawSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+		if err != nil || !ed25519.Verify(key, sums, rawSig) {
+			return "", fmt.Errorf("signature check failed for %s", checksumsAsset)
+		}
+	}
+
+	// Lines are "<hex sha256>  <asset name>", as written by sha256sum
+	want := platformAsset()
+	scanner := bufio.NewScanner(strings.NewReader(string(sums)))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == want {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s lists no checksum for %s", checksumsAsset, want)
+}
+
+// fetchAsset downloads a small release asset into memory
+func fetchAsset(ctx context.Context, rel *release, name string) ([]byte, error) {
+	url, ok := rel.assetURL(name)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s", rel.TagName, name)
+	}
+	body, err := download(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	defer body.Close()
+	return io.ReadAll(io.LimitReader(body, maxMetadataBytes))
+}
+
+// installRelease downloads this platform's binary next to exePath, checks
+// it against want and renames it over the running executable
+func installRelease(ctx context.Context, rel *release, exePath, want string) error {
+	url, ok := rel.assetURL(platformAsset())
+	if !ok {
+		return fmt.Errorf("release %s has no binary for %s/%s", rel.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	body, err := download(ctx, url)
+	if err != nil {
+		return fmt.Errorf("failed to download update: %w", err)
+	}
+	defer body.Close()
+
+	// Stage in the same directory so the final rename is atomic
+	tmp, err := os.CreateTemp(filepath.Dir(exePath), ".axe-handle-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to stage update: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to download update: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to stage update: %w", err)
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); got != want {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", platformAsset(), got, want)
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return fmt.Errorf("failed to stage update: %w", err)
+	}
+
+	// Windows won't replace a running executable, but it will rename it
+	if runtime.GOOS == "windows" {
+		old := exePath + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(exePath, old); err != nil {
+			return fmt.Errorf("failed to move current binary aside: %w", err)
+		}
+	}
+	if err := os.Rename(tmp.Name(), exePath); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", exePath, err)
+	}
+	return nil
+}
+
+// download GETs url, failing on non-2xx responses
+func download(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "axe-handle/"+buildinfo.Get().Version)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
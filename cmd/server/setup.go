@@ -2,31 +2,80 @@
 package main
 
 import (
-	"encoding/json"
+	"bufio"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
-	"runtime"
+	"strings"
 )
 
-// ClaudeDesktopConfig represents the structure of Claude Desktop's configuration file
-type ClaudeDesktopConfig struct {
-	MCPServers map[string]MCPServerConfig `json:"mcpServers"`
+// defaultServerName is the entry name used in MCP client configurations
+const defaultServerName = "axe-handle"
+
+// setupOptions controls the setup subcommand
+type setupOptions struct {
+	client         string
+	configFile     string
+	serverName     string
+	nonInteractive bool
+	dryRun         bool // show what would change without writing anything
+	yes            bool // apply client configuration changes without asking
+	service        bool // also install a login service for SSE servers
+	in             *bufio.Reader
+	out            io.Writer
 }
 
-// MCPServerConfig represents a server configuration in Claude Desktop
-type MCPServerConfig struct {
-	Command string            `json:"command"`
-	Args    []string          `json:"args"`
-	Env     map[string]string `json:"env,omitempty"`
+// runSetupCommand parses the setup subcommand's flags and runs it
+func runSetupCommand(args []string) error {
+	fs := flag.NewFlagSet("setup", flag.ContinueOnError)
+	client := fs.String("client", "claude", "MCP client to configure: "+clientNames())
+	configFile := fs.String("config", getDefaultConfigPath(), "Path to configuration file (uses AXEHANDLE_CONFIG env var if set)")
+	serverName := fs.String("server-name", defaultServerName, "Name of the server entry in the client configuration")
+	nonInteractive := fs.Bool("non-interactive", false, "Use flag values and defaults without prompting")
+	dryRun := fs.Bool("dry-run", false, "Show the changes to the client configuration without writing them")
+	yes := fs.Bool("yes", false, "Apply changes to the client configuration without confirmation")
+	service := fs.Bool("service", false, "Install the server as a login service (launchd, systemd or Task Scheduler) when using the SSE transport")
+	restore := fs.Bool("restore", false, "Roll the client configuration back to the backup taken before the last change")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	if *restore {
+		target, ok := clientTargets[*client]
+		if !ok {
+			return fmt.Errorf("unknown client %q (choose from %s)", *client, clientNames())
+		}
+		return restoreClientConfig(target)
+	}
+
+	return runSetup(setupOptions{
+		client:         *client,
+		configFile:     *configFile,
+		serverName:     *serverName,
+		nonInteractive: *nonInteractive,
+		dryRun:         *dryRun,
+		yes:            *yes,
+		service:        *service,
+		in:             bufio.NewReader(os.Stdin),
+		out:            os.Stdout,
+	})
 }
 
 // runSetup performs the setup process for Axe Handle.
-// It configures both the local application and integrates with Claude Desktop.
-//
-//nolint:unused
-func runSetup(configFile string) error {
+// It configures both the local application and integrates with an MCP client.
+func runSetup(opts setupOptions) error {
+	target, ok := clientTargets[opts.client]
+	if !ok {
+		return fmt.Errorf("unknown client %q (choose from %s)", opts.client, clientNames())
+	}
+
 	// Get executable path
 	exePath, err := os.Executable()
 	if err != nil {
@@ -39,33 +88,96 @@ func runSetup(configFile string) error {
 
 	slog.Info("Using executable path", "path", exePath)
 
+	configFile := opts.ask("Configuration file", opts.configFile)
+	serverName := opts.ask("Server name in "+target.name, opts.serverName)
+
 	// Check and create local config
-	//nolint:unused
-	err = createDefaultConfig(configFile)
-	if err != nil {
+	if opts.dryRun {
+		fmt.Printf("Dry run: not creating %s\n", configFile)
+	} else if err := createDefaultConfig(configFile); err != nil {
 		return fmt.Errorf("failed to create default configuration: %w", err)
 	}
 
-	// Configure Claude Desktop
-	err = configureClaudeDesktop(exePath, configFile)
+	// Configure the client
+	applied, err := opts.configureClient(target, exePath, configFile, serverName)
 	if err != nil {
-		fmt.Printf("Warning: Failed to configure Claude Desktop automatically: %v\n", err)
-		fmt.Println("You'll need to configure Claude Desktop manually.")
-		printManualSetupInstructions(exePath, configFile)
+		fmt.Printf("Warning: Failed to configure %s automatically: %v\n", target.name, err)
+		fmt.Printf("You'll need to configure %s manually.\n", target.name)
+	}
+	if opts.service {
+		if err := opts.installService(exePath, configFile); err != nil {
+			fmt.Printf("Warning: Failed to install login service: %v\n", err)
+		}
+	}
+	if opts.dryRun {
+		return nil
+	}
+	if !applied {
+		printManualSetupInstructions(target, exePath, configFile, serverName)
+		return nil
 	}
 
 	// Print success message
 	fmt.Println("✅ Axe Handle setup complete!")
 	fmt.Println("Next steps:")
 	fmt.Println("1. Run 'axe-handle' to start the server")
-	fmt.Println("2. Open Claude Desktop to start using Axe Handle")
+	fmt.Printf("2. Open %s to start using Axe Handle\n", target.name)
 
 	return nil
 }
 
+// configureClient shows a diff of the change to the client's configuration
+// and writes it once confirmed, reporting whether the client is configured.
+// Non-interactive runs only write with --yes.
+func (o setupOptions) configureClient(target clientTarget, exePath, configFile, serverName string) (bool, error) {
+	current, proposed, err := planClientConfig(target, exePath, configFile, serverName)
+	if err != nil {
+		return false, err
+	}
+
+	path := target.configPath()
+	diff := unifiedDiff(path, path+" (proposed)", string(current), string(proposed))
+	if diff == "" {
+		fmt.Fprintf(o.out, "%s is already configured at %s\n", target.name, path)
+		return true, nil
+	}
+	fmt.Fprint(o.out, diff)
+
+	switch {
+	case o.dryRun:
+		fmt.Fprintln(o.out, "Dry run: no changes written")
+		return false, nil
+	case o.yes:
+	case o.nonInteractive:
+		fmt.Fprintln(o.out, "Not writing without confirmation; re-run with --yes to apply")
+		return false, nil
+	case !o.confirm("Apply these changes?"):
+		return false, nil
+	}
+	return true, writeClientConfig(target, current, proposed)
+}
+
+// ask prompts for a value, returning def when non-interactive or left blank
+func (o setupOptions) ask(question, def string) string {
+	if o.nonInteractive {
+		return def
+	}
+	fmt.Fprintf(o.out, "%s [%s]: ", question, def)
+	line, _ := o.in.ReadString('\n')
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer
+	}
+	return def
+}
+
+// confirm asks a yes/no question defaulting to yes; non-interactive runs
+// always proceed
+func (o setupOptions) confirm(question string) bool {
+	answer := strings.ToLower(o.ask(question+" (y/n)", "y"))
+	return answer == "y" || answer == "yes"
+}
+
 // createDefaultConfig creates a default configuration file if none exists
-//
-//nolint:unused
 func createDefaultConfig(configFile string) error {
 	// Check if config already exists
 	if _, err := os.Stat(configFile); err == nil {
@@ -79,7 +191,7 @@ func createDefaultConfig(configFile string) error {
 		return fmt.Errorf("failed to create configuration directory: %w", err)
 	}
 
-	// Create default config - using hardcoded YAML since config.Default() doesn't exist
+	// Create default config
 	defaultConfig := `server:
   name: "Axe Handle"
   version: "0.1.0"
@@ -102,121 +214,3 @@ transport:
 	fmt.Println("Default configuration file created successfully.")
 	return nil
 }
-
-// configureClaudeDesktop updates Claude Desktop's configuration to include Axe Handle
-// configureClaudeDesktop updates Claude Desktop's configuration to include Axe Handle
-//
-//nolint:unused
-func configureClaudeDesktop(exePath, configFile string) error {
-	// Determine Claude Desktop config path based on OS
-	claudeConfigPath := getClaudeConfigPath()
-
-	slog.Info("Claude Desktop config path", "path", claudeConfigPath)
-
-	// Create args for the server
-	args := []string{"--config", configFile}
-
-	// Build the server configuration
-	serverConfig := MCPServerConfig{
-		Command: exePath,
-		Args:    args,
-	}
-
-	// Read existing Claude config if it exists
-	var claudeConfig ClaudeDesktopConfig
-	if _, err := os.Stat(claudeConfigPath); err == nil {
-		data, err := os.ReadFile(claudeConfigPath)
-		if err != nil {
-			return fmt.Errorf("failed to read Claude Desktop configuration: %w", err)
-		}
-
-		if err := json.Unmarshal(data, &claudeConfig); err != nil {
-			// If the file exists but is invalid, create a new one
-			slog.Warn("Failed to parse existing Claude Desktop config, creating new one", "error", err)
-			claudeConfig = ClaudeDesktopConfig{
-				MCPServers: make(map[string]MCPServerConfig),
-			}
-		}
-	} else {
-		// Create new config if it doesn't exist
-		claudeConfig = ClaudeDesktopConfig{
-			MCPServers: make(map[string]MCPServerConfig),
-		}
-	}
-
-	// Fix any null Args fields - ensure all existing server configs have valid Args arrays
-	for name, server := range claudeConfig.MCPServers {
-		if server.Args == nil {
-			server.Args = []string{}
-			claudeConfig.MCPServers[name] = server
-			slog.Info("Fixed null Args field for server", "name", name)
-		}
-	}
-
-	// Add our server to the config
-	claudeConfig.MCPServers["axe-handle"] = serverConfig
-
-	// Write the updated config
-	data, err := json.MarshalIndent(claudeConfig, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal Claude Desktop configuration: %w", err)
-	}
-
-	// Ensure directory exists
-	claudeConfigDir := filepath.Dir(claudeConfigPath)
-	if err := os.MkdirAll(claudeConfigDir, 0755); err != nil {
-		return fmt.Errorf("failed to create Claude Desktop configuration directory: %w", err)
-	}
-
-	// Use more secure file permissions (0600 instead of 0644)
-	if err := os.WriteFile(claudeConfigPath, data, 0600); err != nil {
-		return fmt.Errorf("failed to write Claude Desktop configuration: %w", err)
-	}
-
-	fmt.Printf("Successfully configured Claude Desktop at %s\n", claudeConfigPath)
-	return nil
-}
-
-// getClaudeConfigPath returns the path to Claude Desktop's configuration file based on the OS
-//
-//nolint:unused
-func getClaudeConfigPath() string {
-	var configDir string
-
-	switch runtime.GOOS {
-	case "darwin":
-		homeDir, _ := os.UserHomeDir()
-		configDir = filepath.Join(homeDir, "Library", "Application Support", "Claude")
-	case "windows":
-		configDir = filepath.Join(os.Getenv("APPDATA"), "Claude")
-	default:
-		homeDir, _ := os.UserHomeDir()
-		configDir = filepath.Join(homeDir, ".config", "Claude")
-	}
-
-	return filepath.Join(configDir, "claude_desktop_config.json")
-}
-
-// printManualSetupInstructions prints instructions for manually configuring Claude Desktop
-//
-//nolint:unused
-func printManualSetupInstructions(exePath, configFile string) {
-	claudeConfigPath := getClaudeConfigPath()
-
-	fmt.Println("\n==== Manual Claude Desktop Configuration ====")
-	fmt.Printf("1. Create or edit the file at: %s\n", claudeConfigPath)
-	fmt.Println("2. Add the following configuration:")
-
-	configExample := fmt.Sprintf(`{
-  "mcpServers": {
-    "axe-handle": {
-      "command": "%s",
-      "args": ["--config", "%s"]
-    }
-  }
-}`, exePath, configFile)
-
-	fmt.Println(configExample)
-	fmt.Println("3. Restart Claude Desktop to apply the changes")
-	fmt.Println("==============================================")
-}
@@ -0,0 +1,76 @@
+// cmd/server/setup_backup.go
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Client config backups are written next to the file as <name>.bak-<time>.
+// An empty backup records that the file did not exist.
+const (
+	backupSuffix     = ".bak-"
+	backupTimeLayout = "20060102T150405.000Z"
+)
+
+// backupClientConfig saves current, the client's configuration before we
+// change it, and returns the backup's path
+func backupClientConfig(target clientTarget, current []byte) (string, error) {
+	path := target.configPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s configuration directory: %w", target.name, err)
+	}
+
+	backup := path + backupSuffix + time.Now().UTC().Format(backupTimeLayout)
+	if err := os.WriteFile(backup, current, 0600); err != nil {
+		return "", fmt.Errorf("failed to back up %s configuration: %w", target.name, err)
+	}
+	return backup, nil
+}
+
+// latestBackup returns the newest backup of the client's configuration
+func latestBackup(target clientTarget) (string, error) {
+	backups, err := filepath.Glob(target.configPath() + backupSuffix + "*")
+	if err != nil {
+		return "", err
+	}
+	if len(backups) == 0 {
+		return "", fmt.Errorf("no backup of the %s configuration found", target.name)
+	}
+	// The timestamp layout sorts chronologically
+	sort.Strings(backups)
+	return backups[len(backups)-1], nil
+}
+
+// restoreClientConfig rolls the client's configuration back to its newest
+// backup and removes that backup, so repeated restores step further back
+func restoreClientConfig(target clientTarget) error {
+	backup, err := latestBackup(target)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(backup)
+	if err != nil {
+		return fmt.Errorf("failed to read backup %s: %w", backup, err)
+	}
+
+	path := target.configPath()
+	if len(data) == 0 {
+		// Setup created the file, so undoing it removes the file
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove %s configuration: %w", target.name, err)
+		}
+	} else if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to restore %s configuration: %w", target.name, err)
+	}
+
+	if err := os.Remove(backup); err != nil {
+		return fmt.Errorf("failed to remove used backup %s: %w", backup, err)
+	}
+	fmt.Printf("Restored %s configuration at %s from %s\n", target.name, path, backup)
+	return nil
+}
@@ -0,0 +1,245 @@
+// cmd/server/setup_clients.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// MCPServerConfig is a stdio server entry in Claude Desktop, Cursor and
+// Windsurf configurations
+type MCPServerConfig struct {
+	Command string            `json:"command"`
+	Args    []string          `json:"args"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+// VSCodeServerConfig is a server entry in VS Code's mcp.json
+type VSCodeServerConfig struct {
+	Type    string   `json:"type"`
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+}
+
+// clientTarget describes where an MCP client keeps its server list
+type clientTarget struct {
+	name       string                                       // shown to the user
+	configPath func() string                                // per-OS location of the client's config file
+	serversKey string                                       // top-level key holding the server map
+	entry      func(exePath, configFile string) interface{} // our server's entry
+}
+
+// clientTargets are the clients setup can configure, by --client value
+var clientTargets = map[string]clientTarget{
+	"claude": {
+		name:       "Claude Desktop",
+		configPath: getClaudeConfigPath,
+		serversKey: "mcpServers",
+		entry:      stdioEntry,
+	},
+	"cursor": {
+		name:       "Cursor",
+		configPath: func() string { return filepath.Join(homeDir(), ".cursor", "mcp.json") },
+		serversKey: "mcpServers",
+		entry:      stdioEntry,
+	},
+	"windsurf": {
+		name:       "Windsurf",
+		configPath: func() string { return filepath.Join(homeDir(), ".codeium", "windsurf", "mcp_config.json") },
+		serversKey: "mcpServers",
+		entry:      stdioEntry,
+	},
+	"vscode": {
+		name:       "VS Code",
+		configPath: getVSCodeConfigPath,
+		serversKey: "servers",
+		entry: func(exePath, configFile string) interface{} {
+			return VSCodeServerConfig{Type: "stdio", Command: exePath, Args: []string{"--config", configFile}}
+		},
+	},
+}
+
+// clientNames lists the valid --client values
+func clientNames() string {
+	names := make([]string, 0, len(clientTargets))
+	for name := range clientTargets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// stdioEntry is the server entry shared by most clients
+func stdioEntry(exePath, configFile string) interface{} {
+	return MCPServerConfig{Command: exePath, Args: []string{"--config", configFile}}
+}
+
+// homeDir returns the user's home directory, or "" if it is unknown
+func homeDir() string {
+	home, _ := os.UserHomeDir()
+	return home
+}
+
+// getClaudeConfigPath returns the path to Claude Desktop's configuration file based on the OS
+func getClaudeConfigPath() string {
+	var configDir string
+
+	switch runtime.GOOS {
+	case "darwin":
+		configDir = filepath.Join(homeDir(), "Library", "Application Support", "Claude")
+	case "windows":
+		configDir = filepath.Join(os.Getenv("APPDATA"), "Claude")
+	default:
+		configDir = filepath.Join(homeDir(), ".config", "Claude")
+	}
+
+	return filepath.Join(configDir, "claude_desktop_config.json")
+}
+
+// getVSCodeConfigPath returns the path to VS Code's user mcp.json based on the OS
+func getVSCodeConfigPath() string {
+	var userDir string
+
+	switch runtime.GOOS {
+	case "darwin":
+		userDir = filepath.Join(homeDir(), "Library", "Application Support", "Code", "User")
+	case "windows":
+		userDir = filepath.Join(os.Getenv("APPDATA"), "Code", "User")
+	default:
+		userDir = filepath.Join(homeDir(), ".config", "Code", "User")
+	}
+
+	return filepath.Join(userDir, "mcp.json")
+}
+
+// planClientConfig returns the client's current configuration and the
+// configuration with Axe Handle added under serverName. Other servers and
+// settings in the file are preserved.
+func planClientConfig(target clientTarget, exePath, configFile, serverName string) (current, proposed []byte, err error) {
+	path := target.configPath()
+
+	// Decode loosely so keys we don't know about survive the rewrite
+	doc, docKeys := map[string]json.RawMessage{}, []string(nil)
+	if current, err = os.ReadFile(path); err == nil {
+		if docKeys, err = decodeObject(current, doc); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s configuration at %s: %w", target.name, path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("failed to read %s configuration: %w", target.name, err)
+	}
+
+	servers, serverKeys := map[string]json.RawMessage{}, []string(nil)
+	if raw, ok := doc[target.serversKey]; ok && string(raw) != "null" {
+		if serverKeys, err = decodeObject(raw, servers); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %q in %s: %w", target.serversKey, path, err)
+		}
+	}
+
+	entry, err := json.Marshal(target.entry(exePath, configFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal server entry: %w", err)
+	}
+	servers[serverName], serverKeys = entry, appendKey(serverKeys, serverName)
+	doc[target.serversKey], docKeys = encodeObject(servers, serverKeys), appendKey(docKeys, target.serversKey)
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, encodeObject(doc, docKeys), "", "  "); err != nil {
+		return nil, nil, fmt.Errorf("failed to format %s configuration: %w", target.name, err)
+	}
+	out.WriteByte('\n')
+	return current, out.Bytes(), nil
+}
+
+// decodeObject decodes a JSON object into fields and returns its keys in
+// file order, so rewriting it doesn't reshuffle other tools' settings
+func decodeObject(data []byte, fields map[string]json.RawMessage) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		fields[key], keys = value, appendKey(keys, key)
+	}
+	return keys, nil
+}
+
+// encodeObject writes fields as a JSON object in the order of keys
+func encodeObject(fields map[string]json.RawMessage, keys []string) json.RawMessage {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(fields[key])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+// appendKey appends key unless it is already present
+func appendKey(keys []string, key string) []string {
+	for _, k := range keys {
+		if k == key {
+			return keys
+		}
+	}
+	return append(keys, key)
+}
+
+// writeClientConfig replaces the client's configuration with data after
+// backing up current, its previous contents
+func writeClientConfig(target clientTarget, current, data []byte) error {
+	backup, err := backupClientConfig(target, current)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Backed up %s configuration to %s\n", target.name, backup)
+
+	path := target.configPath()
+	// Use more secure file permissions (0600 instead of 0644)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s configuration: %w", target.name, err)
+	}
+
+	fmt.Printf("Successfully configured %s at %s\n", target.name, path)
+	return nil
+}
+
+// printManualSetupInstructions prints instructions for manually configuring a client
+func printManualSetupInstructions(target clientTarget, exePath, configFile, serverName string) {
+	example, _ := json.MarshalIndent(map[string]interface{}{
+		target.serversKey: map[string]interface{}{
+			serverName: target.entry(exePath, configFile),
+		},
+	}, "", "  ")
+
+	fmt.Printf("\n==== Manual %s Configuration ====\n", target.name)
+	fmt.Printf("1. Create or edit the file at: %s\n", target.configPath())
+	fmt.Println("2. Add the following configuration:")
+	fmt.Println(string(example))
+	fmt.Printf("3. Restart %s to apply the changes\n", target.name)
+	fmt.Println("==============================================")
+}
@@ -0,0 +1,94 @@
+// cmd/server/setup_fixpaths.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// fixClientPaths points client entries for Axe Handle at exePath when the
+// binary they name no longer exists, e.g. after it was moved or updated
+func fixClientPaths(exePath string) {
+	names := make([]string, 0, len(clientTargets))
+	for name := range clientTargets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		target := clientTargets[name]
+		if err := fixClientPath(target, exePath); err != nil {
+			fmt.Printf("Warning: could not update %s configuration: %v\n", target.name, err)
+		}
+	}
+}
+
+// fixClientPath rewrites stale commands in one client's configuration
+func fixClientPath(target clientTarget, exePath string) error {
+	current, err := os.ReadFile(target.configPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	doc := map[string]json.RawMessage{}
+	docKeys, err := decodeObject(current, doc)
+	if err != nil {
+		return err
+	}
+	raw, ok := doc[target.serversKey]
+	if !ok || string(raw) == "null" {
+		return nil
+	}
+	servers := map[string]json.RawMessage{}
+	serverKeys, err := decodeObject(raw, servers)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for _, name := range serverKeys {
+		entry := map[string]json.RawMessage{}
+		entryKeys, err := decodeObject(servers[name], entry)
+		if err != nil {
+			continue // not an object; not ours
+		}
+		var command string
+		if json.Unmarshal(entry["command"], &command) != nil || !stale(command, exePath) {
+			continue
+		}
+
+		entry["command"], _ = json.Marshal(exePath)
+		servers[name] = encodeObject(entry, entryKeys)
+		changed = true
+		fmt.Printf("Updating %s entry %q: %s -> %s\n", target.name, name, command, exePath)
+	}
+	if !changed {
+		return nil
+	}
+
+	doc[target.serversKey] = encodeObject(servers, serverKeys)
+	var out bytes.Buffer
+	if err := json.Indent(&out, encodeObject(doc, docKeys), "", "  "); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	return writeClientConfig(target, current, out.Bytes())
+}
+
+// stale reports whether command is an old location of this binary: same
+// file name as exePath, different path, and no longer present
+func stale(command, exePath string) bool {
+	if command == "" || command == exePath || filepath.Base(command) != filepath.Base(exePath) {
+		return false
+	}
+	_, err := os.Stat(command)
+	return errors.Is(err, os.ErrNotExist)
+}
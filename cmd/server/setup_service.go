@@ -0,0 +1,184 @@
+// cmd/server/setup_service.go
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/dkoosis/axe-handle/internal/config"
+)
+
+// serviceLabel identifies the login service on every platform
+const serviceLabel = "com.dkoosis.axe-handle"
+
+// serviceInstaller describes how the server is registered to start at login
+type serviceInstaller struct {
+	kind     string     // human-readable service kind
+	path     string     // definition file to write; empty when none is needed
+	content  string     // contents of path
+	commands [][]string // commands that register and start the service
+	note     string     // printed after a successful install
+}
+
+// installService registers the server as a login service so it survives
+// reboots. Only SSE servers are installed; stdio servers are started by
+// their client.
+func (o setupOptions) installService(exePath, configFile string) error {
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.Transport.Type != "sse" {
+		fmt.Fprintf(o.out, "Not installing a login service: transport is %q; clients start stdio servers themselves\n", cfg.Transport.Type)
+		return nil
+	}
+
+	// Services start outside the current directory
+	if configFile, err = filepath.Abs(configFile); err != nil {
+		return fmt.Errorf("failed to get absolute configuration path: %w", err)
+	}
+	svc, err := newServiceInstaller(runtime.GOOS, exePath, configFile, logFilePath(cfg))
+	if err != nil {
+		return err
+	}
+
+	if svc.path != "" {
+		fmt.Fprintf(o.out, "%s definition for %s:\n\n%s\n", svc.kind, svc.path, svc.content)
+	}
+	for _, args := range svc.commands {
+		fmt.Fprintf(o.out, "  $ %s\n", strings.Join(args, " "))
+	}
+
+	switch {
+	case o.dryRun:
+		fmt.Fprintln(o.out, "Dry run: login service not installed")
+		return nil
+	case o.yes:
+	case o.nonInteractive:
+		fmt.Fprintln(o.out, "Not installing the login service without confirmation; re-run with --yes to apply")
+		return nil
+	case !o.confirm("Install the login service?"):
+		return nil
+	}
+
+	if svc.path != "" {
+		if err := os.MkdirAll(filepath.Dir(svc.path), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(svc.path), err)
+		}
+		if err := os.WriteFile(svc.path, []byte(svc.content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", svc.path, err)
+		}
+	}
+	for _, args := range svc.commands {
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdout = o.out
+		cmd.Stderr = o.out
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s failed: %w", strings.Join(args, " "), err)
+		}
+	}
+
+	fmt.Fprintf(o.out, "✅ Installed %s\n", svc.kind)
+	if svc.note != "" {
+		fmt.Fprintln(o.out, svc.note)
+	}
+	return nil
+}
+
+// newServiceInstaller builds the login service for goos
+func newServiceInstaller(goos, exePath, configFile, logFile string) (serviceInstaller, error) {
+	home := homeDir()
+	switch goos {
+	case "darwin":
+		path := filepath.Join(home, "Library", "LaunchAgents", serviceLabel+".plist")
+		return serviceInstaller{
+			kind:    "launchd agent",
+			path:    path,
+			content: launchdPlist(exePath, configFile, logFile),
+			commands: [][]string{
+				{"launchctl", "unload", path}, // harmless when not yet loaded
+				{"launchctl", "load", "-w", path},
+			},
+		}, nil
+	case "linux":
+		return serviceInstaller{
+			kind:    "systemd user unit",
+			path:    filepath.Join(home, ".config", "systemd", "user", "axe-handle.service"),
+			content: systemdUnit(exePath, configFile),
+			commands: [][]string{
+				{"systemctl", "--user", "daemon-reload"},
+				{"systemctl", "--user", "enable", "--now", "axe-handle.service"},
+			},
+			note: "To keep it running while you are logged out, run: loginctl enable-linger $USER",
+		}, nil
+	case "windows":
+		return serviceInstaller{
+			kind: "scheduled task",
+			commands: [][]string{
+				{"schtasks", "/Create", "/F", "/SC", "ONLOGON", "/RL", "LIMITED", "/TN", "axe-handle",
+					"/TR", fmt.Sprintf("%q serve --config %q", exePath, configFile)},
+				{"schtasks", "/Run", "/TN", "axe-handle"},
+			},
+		}, nil
+	default:
+		return serviceInstaller{}, fmt.Errorf("login services are not supported on %s", goos)
+	}
+}
+
+// launchdPlist renders a launch agent that keeps the server running
+func launchdPlist(exePath, configFile, logFile string) string {
+	esc := html.EscapeString
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>` + serviceLabel + `</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>` + esc(exePath) + `</string>
+		<string>serve</string>
+		<string>--config</string>
+		<string>` + esc(configFile) + `</string>
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<true/>
+	<key>StandardOutPath</key>
+	<string>` + esc(logFile) + `</string>
+	<key>StandardErrorPath</key>
+	<string>` + esc(logFile) + `</string>
+</dict>
+</plist>
+`
+}
+
+// systemdUnit renders a user unit that restarts the server on failure
+func systemdUnit(exePath, configFile string) string {
+	return fmt.Sprintf(`[Unit]
+Description=Axe Handle MCP server
+After=network.target
+
+[Service]
+ExecStart=%s serve --config %s
+Restart=on-failure
+
+[Install]
+WantedBy=default.target
+`, systemdQuote(exePath), systemdQuote(configFile))
+}
+
+// systemdQuote quotes a command-line word for an Exec= directive
+func systemdQuote(s string) string {
+	if !strings.ContainsAny(s, " \t\"'\\%$") {
+		return s
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `%`, `%%`, `$`, `$$`)
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,319 @@
+// cmd/server/start_providers.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/clock"
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/internal/mcp/server"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+	"github.com/dkoosis/axe-handle/internal/providers/calendar"
+	"github.com/dkoosis/axe-handle/internal/providers/issues"
+	"github.com/dkoosis/axe-handle/internal/providers/mail"
+	"github.com/dkoosis/axe-handle/internal/providers/memory"
+	"github.com/dkoosis/axe-handle/internal/providers/notify"
+	"github.com/dkoosis/axe-handle/internal/providers/s3"
+	"github.com/dkoosis/axe-handle/internal/providers/schedule"
+	"github.com/dkoosis/axe-handle/pkg/state"
+)
+
+// startCalendar serves the configured calendars
+func startCalendar(mcp *server.Server, cfg *config.Config) error {
+	cc := cfg.Providers.Calendar
+	if len(cc.Calendars) == 0 {
+		return nil
+	}
+	loc := time.Local
+	if cc.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(cc.Timezone); err != nil {
+			return fmt.Errorf("providers.calendar.timezone: %w", err)
+		}
+	}
+	sources := make([]calendar.Source, len(cc.Calendars))
+	for i, c := range cc.Calendars {
+		sources[i] = calendar.Source(c)
+	}
+	p, err := calendar.New(calendar.Config{
+		Calendars: sources,
+		Upcoming:  cc.Upcoming,
+		WorkHours: cc.WorkHours,
+		Location:  loc,
+		Timeout:   cc.Timeout,
+	})
+	if err != nil {
+		return err
+	}
+	mcp.RegisterResourceProvider(p)
+	p.Register(mcp.GetToolsManager())
+	slog.Info("Serving calendars", "calendars", p.Names())
+	return nil
+}
+
+// startMail serves the configured mailbox
+func startMail(mcp *server.Server, cfg *config.Config) error {
+	mc := cfg.Providers.Mail
+	if mc.IMAP.Host == "" && mc.SMTP.Host == "" {
+		return nil
+	}
+	password, err := mc.Password.Resolve()
+	if err != nil {
+		return fmt.Errorf("providers.mail.password: %w", err)
+	}
+	p, err := mail.New(mail.Config{
+		Address:  mc.Address,
+		Username: mc.Username,
+		Password: password,
+		IMAP:     mail.Server(mc.IMAP),
+		SMTP:     mail.Server(mc.SMTP),
+		Folders:  mc.Folders,
+		Recent:   mc.Recent,
+		Timeout:  mc.Timeout,
+	})
+	if err != nil {
+		return err
+	}
+	mcp.RegisterResourceProvider(p)
+	p.Register(mcp.GetToolsManager())
+	slog.Info("Serving mail", "imap", mc.IMAP.Host, "smtp", mc.SMTP.Host, "folders", mc.Folders)
+	return nil
+}
+
+// startSchedules calls the tools named in tools.schedules on their cron
+// schedules and serves each one's latest result as a resource
+func startSchedules(mcp *server.Server, cfg *config.Config) error {
+	if len(cfg.Tools.Schedules) == 0 {
+		return nil
+	}
+	jobs := make([]schedule.Job, 0, len(cfg.Tools.Schedules))
+	for _, sc := range cfg.Tools.Schedules {
+		job := schedule.Job{Name: sc.Name, Cron: sc.Cron, Tool: sc.Tool}
+		if len(sc.Arguments) > 0 {
+			args, err := json.Marshal(sc.Arguments)
+			if err != nil {
+				return fmt.Errorf("tools.schedules %s: %w", sc.Name, err)
+			}
+			job.Arguments = args
+		}
+		jobs = append(jobs, job)
+	}
+	p, err := schedule.New(mcp.GetToolsManager(), clock.Real, jobs)
+	if err != nil {
+		return err
+	}
+	mcp.RegisterResourceProvider(p)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	mcp.OnShutdown(func(context.Context) error {
+		cancel()
+		<-done
+		return nil
+	})
+	go func() {
+		defer close(done)
+		p.Run(ctx)
+	}()
+	slog.Info("Running scheduled tools", "count", len(jobs))
+	return nil
+}
+
+// startNotify serves notify.slack and notify.webhook for the configured
+// endpoints
+func startNotify(mcp *server.Server, cfg *config.Config) error {
+	nc := cfg.Providers.Notify
+	if len(nc.Slack) == 0 && len(nc.Webhooks) == 0 {
+		return nil
+	}
+	slack, err := notifyEndpoints("providers.notify.slack", nc.Slack)
+	if err != nil {
+		return err
+	}
+	webhooks, err := notifyEndpoints("providers.notify.webhooks", nc.Webhooks)
+	if err != nil {
+		return err
+	}
+	// Tenants post within rate limits of their own; every instance serves
+	// the same endpoints
+	var slackNames, webhookNames []string
+	err = mcp.RegisterPerTenant(func(_ *state.Store, m *manager.ToolsManager) error {
+		instance, err := notify.New(notify.Config{
+			Slack:    slack,
+			Webhooks: webhooks,
+			Limit:    nc.Limit,
+			Window:   nc.Window,
+			Timeout:  nc.Timeout,
+		}, nil)
+		if err != nil {
+			return err
+		}
+		instance.Register(m)
+		slackNames, webhookNames = instance.SlackNames(), instance.WebhookNames()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	slog.Info("Serving notifications", "slack", slackNames, "webhooks", webhookNames)
+	return nil
+}
+
+// notifyEndpoints resolves the secrets of configured notify endpoints
+func notifyEndpoints(key string, list []config.NotifyEndpointConfig) ([]notify.Endpoint, error) {
+	endpoints := make([]notify.Endpoint, len(list))
+	for i, c := range list {
+		url, err := c.URL.Resolve()
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s.url: %w", key, c.Name, err)
+		}
+		secret, err := c.Secret.Resolve()
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s.secret: %w", key, c.Name, err)
+		}
+		endpoints[i] = notify.Endpoint{Name: c.Name, URL: url, Headers: c.Headers, Secret: secret, Template: c.Template, Limit: c.Limit}
+	}
+	return endpoints, nil
+}
+
+// startS3 serves the configured buckets of S3-compatible storage
+func startS3(mcp *server.Server, cfg *config.Config) error {
+	sc := cfg.Providers.S3
+	if len(sc.Buckets) == 0 {
+		return nil
+	}
+	secretKey, err := sc.SecretAccessKey.Resolve()
+	if err != nil {
+		return fmt.Errorf("providers.s3.secretAccessKey: %w", err)
+	}
+	sessionToken, err := sc.SessionToken.Resolve()
+	if err != nil {
+		return fmt.Errorf("providers.s3.sessionToken: %w", err)
+	}
+	p, err := s3.New(s3.Config{
+		Endpoint:        sc.Endpoint,
+		Region:          sc.Region,
+		PathStyle:       sc.PathStyle,
+		AccessKeyID:     os.ExpandEnv(sc.AccessKeyID),
+		SecretAccessKey: secretKey,
+		SessionToken:    sessionToken,
+		Buckets:         sc.Buckets,
+		Writable:        sc.Writable,
+		MaxList:         sc.MaxList,
+		Timeout:         sc.Timeout,
+	})
+	if err != nil {
+		return err
+	}
+	mcp.RegisterResourceProvider(p)
+	p.Register(mcp.GetToolsManager())
+	slog.Info("Serving object storage", "endpoint", sc.Endpoint, "buckets", sc.Buckets, "writable", sc.Writable)
+	return nil
+}
+
+// startMemory serves long-term memory from the state store
+func startMemory(mcp *server.Server, cfg *config.Config) error {
+	mc := cfg.Providers.Memory
+	if !mc.Enabled {
+		return nil
+	}
+	embedder, err := newEmbedder(mc.Embedder)
+	if err != nil {
+		return err
+	}
+	// Each tenant remembers in its own share of the store
+	var hint string
+	err = mcp.RegisterPerTenant(func(store *state.Store, m *manager.ToolsManager) error {
+		p, err := memory.New(store, memory.Config{
+			Embedder:  embedder,
+			ChunkSize: mc.ChunkSize,
+			Recall:    mc.Recall,
+		})
+		if err != nil {
+			return err
+		}
+		p.Register(m)
+		hint = p.UsageHint()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	mcp.AddUsageHint(hint)
+	slog.Info("Serving memory", "embedder", embedder.Name(), "tenants", mcp.Tenants())
+	return nil
+}
+
+// newEmbedder creates the configured embedder
+func newEmbedder(ec config.EmbedderConfig) (memory.Embedder, error) {
+	timeout := ec.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	client := &http.Client{Timeout: timeout}
+	switch ec.Type {
+	case "", "hash":
+		return memory.HashEmbedder{Dimensions: ec.Dimensions}, nil
+	case "ollama":
+		if ec.Model == "" {
+			return nil, fmt.Errorf("providers.memory.embedder.model is required for ollama")
+		}
+		base := ec.URL
+		if base == "" {
+			base = "http://localhost:11434"
+		}
+		return memory.OllamaEmbedder{URL: base, Model: ec.Model, Client: client}, nil
+	case "openai":
+		if ec.Model == "" {
+			return nil, fmt.Errorf("providers.memory.embedder.model is required for openai")
+		}
+		apiKey, err := ec.APIKey.Resolve()
+		if err != nil {
+			return nil, fmt.Errorf("providers.memory.embedder.apiKey: %w", err)
+		}
+		base := ec.URL
+		if base == "" {
+			base = "https://api.openai.com/v1"
+		}
+		return memory.OpenAIEmbedder{URL: base, Model: ec.Model, APIKey: apiKey, Client: client}, nil
+	}
+	return nil, fmt.Errorf("unknown providers.memory.embedder.type %q; use hash, ollama or openai", ec.Type)
+}
+
+// startIssues serves the configured issue trackers
+func startIssues(mcp *server.Server, cfg *config.Config) error {
+	ic := cfg.Providers.Issues
+	if len(ic.Trackers) == 0 {
+		return nil
+	}
+	trackers := make([]issues.Tracker, len(ic.Trackers))
+	for i, t := range ic.Trackers {
+		token, err := t.Token.Resolve()
+		if err != nil {
+			return fmt.Errorf("providers.issues.trackers.%s.token: %w", t.Name, err)
+		}
+		trackers[i] = issues.Tracker{
+			Name:      t.Name,
+			Type:      t.Type,
+			URL:       t.URL,
+			Project:   t.Project,
+			Token:     token,
+			User:      t.User,
+			IssueType: t.IssueType,
+		}
+	}
+	p, err := issues.New(issues.Config{Trackers: trackers, Recent: ic.Recent, Timeout: ic.Timeout})
+	if err != nil {
+		return err
+	}
+	mcp.RegisterResourceProvider(p)
+	p.Register(mcp.GetToolsManager())
+	slog.Info("Serving issues", "trackers", p.Names())
+	return nil
+}
@@ -0,0 +1,66 @@
+// cmd/server/start_services.go
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/dkoosis/axe-handle/internal/bridge"
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/internal/mcp/server"
+	"github.com/dkoosis/axe-handle/internal/metrics"
+)
+
+// startMetrics installs the alert webhook and serves /metrics when configured
+func startMetrics(mcp *server.Server, cfg *config.Config) {
+	if cfg.Metrics.Alert.WebhookURL != "" {
+		mcp.ErrorMetrics().SetAlertHook(metrics.AlertConfig{
+			Threshold:   cfg.Metrics.Alert.Threshold,
+			Window:      cfg.Metrics.Alert.Window,
+			MinRequests: cfg.Metrics.Alert.MinRequests,
+		}, metrics.NewWebhookAlertHook(cfg.Metrics.Alert.WebhookURL))
+	}
+
+	if cfg.Metrics.Addr != "" {
+		ctx, cancel := context.WithCancel(context.Background())
+		mcp.OnShutdown(func(context.Context) error {
+			cancel()
+			return nil
+		})
+		go func() {
+			if err := mcp.ErrorMetrics().Serve(ctx, cfg.Metrics.Addr); err != nil {
+				slog.Error("Metrics server error", "error", err)
+			}
+		}()
+	}
+}
+
+// startBridge serves the OpenAI-compatible tools bridge when configured
+func startBridge(mcp *server.Server, cfg *config.Config) {
+	openai := cfg.Bridge.OpenAI
+	if openai.Addr == "" {
+		return
+	}
+	if openai.Upstream == "" {
+		slog.Error("bridge.openai.upstream is required when bridge.openai.addr is set")
+		return
+	}
+
+	b := bridge.NewOpenAIBridge(mcp.GetToolsManager(), bridge.OpenAIConfig{
+		Upstream:  openai.Upstream,
+		APIKey:    os.ExpandEnv(openai.APIKey),
+		Token:     os.ExpandEnv(openai.Token),
+		MaxRounds: openai.MaxRounds,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	mcp.OnShutdown(func(context.Context) error {
+		cancel()
+		return nil
+	})
+	go func() {
+		if err := b.Serve(ctx, openai.Addr); err != nil {
+			slog.Error("OpenAI bridge error", "error", err)
+		}
+	}()
+}
@@ -0,0 +1,191 @@
+// cmd/server/start_tools.go
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/server"
+	"github.com/dkoosis/axe-handle/internal/providers/declarative"
+	"github.com/dkoosis/axe-handle/internal/providers/docker"
+	"github.com/dkoosis/axe-handle/internal/providers/graphql"
+	"github.com/dkoosis/axe-handle/internal/providers/plugin"
+	"github.com/dkoosis/axe-handle/internal/providers/promptdir"
+	"github.com/dkoosis/axe-handle/internal/providers/tabular"
+)
+
+// startPrompts serves the configured prompt directory, reloading it on
+// change when watching is enabled
+func startPrompts(mcp *server.Server, cfg *config.Config) error {
+	if cfg.Prompts.Dir == "" {
+		return nil
+	}
+
+	prompts, err := promptdir.New(cfg.Prompts.Dir)
+	if err != nil {
+		return err
+	}
+	mcp.RegisterPromptProvider(prompts)
+	slog.Info("Serving prompts", "dir", cfg.Prompts.Dir, "watch", cfg.Prompts.Watch)
+
+	if cfg.Prompts.Watch {
+		ctx, cancel := context.WithCancel(context.Background())
+		mcp.OnShutdown(func(context.Context) error {
+			cancel()
+			return nil
+		})
+		go func() {
+			if err := prompts.Watch(ctx, mcp); err != nil && ctx.Err() == nil {
+				slog.Error("Prompt watcher stopped", "error", err)
+			}
+		}()
+	}
+	return nil
+}
+
+// loadDeclarativeTools registers the tools declared in tools.imports, each
+// bound to its webhook or command, and the webhook tools in tools.webhooks
+func loadDeclarativeTools(mcp *server.Server, cfg *config.Config) error {
+	for _, imp := range cfg.Tools.Imports {
+		var exec declarative.Executor
+		switch {
+		case imp.Webhook.URL != "" && len(imp.Command) > 0:
+			return fmt.Errorf("tools.imports %s: set webhook or command, not both", imp.File)
+		case imp.Webhook.URL != "":
+			exec = newWebhookExecutor(imp.Webhook)
+		case len(imp.Command) > 0:
+			exec = &declarative.CommandExecutor{Command: imp.Command, Dir: imp.Dir}
+		default:
+			return fmt.Errorf("tools.imports %s: no webhook or command to execute the tools", imp.File)
+		}
+
+		tools, err := declarative.LoadOpenAIFile(imp.File)
+		if err != nil {
+			return err
+		}
+		declarative.Register(mcp.GetToolsManager(), tools, exec)
+		slog.Info("Imported tools", "file", imp.File, "count", len(tools))
+	}
+
+	for i, wt := range cfg.Tools.Webhooks {
+		if wt.Name == "" || wt.URL == "" {
+			return fmt.Errorf("tools.webhooks[%d]: name and url are required", i)
+		}
+		u, err := url.Parse(wt.URL)
+		if err != nil {
+			return fmt.Errorf("tools.webhooks %s: %w", wt.Name, err)
+		}
+		if u.Scheme != "https" && u.Hostname() != "localhost" && u.Hostname() != "127.0.0.1" {
+			slog.Warn("Tool webhook does not use HTTPS", "tool", wt.Name, "url", wt.URL)
+		}
+
+		schema := wt.InputSchema
+		if len(schema) == 0 {
+			schema = map[string]interface{}{"type": "object"}
+		}
+		tool := protocol.Tool{Name: wt.Name, Description: wt.Description, InputSchema: schema}
+		declarative.Register(mcp.GetToolsManager(), []protocol.Tool{tool}, newWebhookExecutor(wt.WebhookConfig))
+		slog.Info("Registered webhook tool", "tool", wt.Name, "url", wt.URL)
+	}
+	return nil
+}
+
+// newWebhookExecutor builds a webhook executor from configuration
+func newWebhookExecutor(wc config.WebhookConfig) *declarative.WebhookExecutor {
+	exec := declarative.NewWebhookExecutor(wc.URL, wc.Headers, wc.Timeout)
+	exec.Secret = os.ExpandEnv(wc.Secret)
+	exec.Retries = wc.Retries
+	return exec
+}
+
+// startGraphQL introspects the configured GraphQL endpoints and serves their
+// whitelisted fields as tools. An endpoint that cannot be introspected is
+// logged and skipped so one unavailable API does not keep the server down.
+func startGraphQL(mcp *server.Server, cfg *config.Config) {
+	for _, gc := range cfg.Providers.GraphQL {
+		p := graphql.New(graphql.Config{
+			Name:      gc.Name,
+			Endpoint:  gc.Endpoint,
+			Headers:   gc.Headers,
+			Queries:   gc.Queries,
+			Mutations: gc.Mutations,
+			Depth:     gc.Depth,
+			Timeout:   gc.Timeout,
+		})
+		if err := p.Load(context.Background()); err != nil {
+			slog.Error("Failed to load GraphQL provider", "name", gc.Name, "endpoint", gc.Endpoint, "error", err)
+			continue
+		}
+		p.Register(mcp.GetToolsManager())
+		mcp.RegisterResourceProvider(p)
+		slog.Info("Serving GraphQL tools", "name", gc.Name, "tools", p.ToolNames())
+	}
+}
+
+// startTables serves the configured data directory as table resources and
+// the table.query tool
+func startTables(mcp *server.Server, cfg *config.Config) {
+	tc := cfg.Providers.Tables
+	if tc.Dir == "" {
+		return
+	}
+	p := tabular.New(tabular.Config{Dir: tc.Dir, PageSize: tc.PageSize, MaxRows: tc.MaxRows})
+	mcp.RegisterResourceProvider(p)
+	p.Register(mcp.GetToolsManager())
+	slog.Info("Serving tables", "dir", tc.Dir)
+}
+
+// startDocker serves the containers of the configured engine. An engine
+// that does not answer is logged but still served, since it may be started
+// after the server.
+func startDocker(mcp *server.Server, cfg *config.Config) {
+	dc := cfg.Providers.Docker
+	if dc.Socket == "" {
+		return
+	}
+	p := docker.New(docker.Config{Socket: dc.Socket, Lifecycle: dc.Lifecycle, LogLines: dc.LogLines, Timeout: dc.Timeout})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := p.Ping(ctx); err != nil {
+		slog.Warn("Container engine not reachable", "socket", dc.Socket, "error", err)
+	}
+	mcp.RegisterResourceProvider(p)
+	p.Register(mcp.GetToolsManager())
+	slog.Info("Serving containers", "socket", dc.Socket, "tools", p.ToolNames())
+}
+
+// startPlugins runs the configured external MCP servers under supervision
+// and serves their tools
+func startPlugins(mcp *server.Server, cfg *config.Config) error {
+	for _, pc := range cfg.Plugins {
+		env := make([]string, 0, len(pc.Env))
+		for name, ref := range pc.Env {
+			value, err := ref.Resolve()
+			if err != nil {
+				return fmt.Errorf("plugins.%s.env.%s: %w", pc.Name, name, err)
+			}
+			env = append(env, name+"="+value)
+		}
+		p, err := plugin.New(plugin.Config{
+			Name:    pc.Name,
+			Command: pc.Command,
+			Dir:     pc.Dir,
+			Env:     env,
+			Timeout: pc.Timeout,
+		}, mcp.GetToolsManager())
+		if err != nil {
+			return err
+		}
+		if err := mcp.SuperviseProvider(p.Child()); err != nil {
+			return fmt.Errorf("plugins.%s: %w", pc.Name, err)
+		}
+		slog.Info("Supervising plugin", "plugin", pc.Name, "command", pc.Command)
+	}
+	return nil
+}
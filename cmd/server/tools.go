@@ -0,0 +1,76 @@
+// cmd/server/tools.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/internal/mcp/server"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/interop"
+)
+
+// runTools dispatches the tools subcommands
+func runTools(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: axe-handle tools export --format openai|anthropic")
+	}
+	switch args[0] {
+	case "export":
+		return runToolsExport(args[1:])
+	default:
+		return fmt.Errorf("unknown tools command %q (want export)", args[0])
+	}
+}
+
+// runToolsExport prints the registered tools in another API's tool format
+func runToolsExport(args []string) error {
+	fs := flag.NewFlagSet("tools export", flag.ContinueOnError)
+	configPath := fs.String("config", getDefaultConfigPath(), "Path to configuration file")
+	formatName := fs.String("format", string(interop.FormatOpenAI), "Output format: openai or anthropic")
+	output := fs.String("o", "", "Write the definitions to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	format, err := interop.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	mcp := server.NewServer(cfg)
+	defer func() { _ = mcp.Shutdown(context.Background()) }()
+	if err := loadDeclarativeTools(mcp, cfg); err != nil {
+		return err
+	}
+
+	tools := mcp.GetToolsManager().ListTools()
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	exported, err := interop.Export(tools, format)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(exported)
+}
@@ -0,0 +1,29 @@
+// cmd/server/version.go
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dkoosis/axe-handle/internal/buildinfo"
+)
+
+// runVersion prints the running build's version information
+func runVersion(args []string) error {
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "Print version information as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	info := buildinfo.Get()
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+	fmt.Printf("axe-handle %s\n", info)
+	return nil
+}
@@ -0,0 +1,337 @@
+// internal/bridge/openai.go
+
+// Package bridge exposes the server's tools to agents that do not speak MCP.
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/interop"
+)
+
+// Bridge defaults
+const (
+	DefaultMaxRounds = 8
+	upstreamTimeout  = 5 * time.Minute
+	maxRequestBytes  = 16 << 20
+)
+
+// ToolCaller is the part of the tools manager the bridge uses
+type ToolCaller interface {
+	ListTools() []protocol.Tool
+	CallTool(ctx context.Context, name string, args json.RawMessage, progressToken string) (protocol.ToolsCallResult, error)
+}
+
+// OpenAIConfig configures an OpenAIBridge
+type OpenAIConfig struct {
+	Upstream  string // base URL of an OpenAI-compatible API, e.g. https://api.openai.com/v1
+	APIKey    string // sent upstream; when empty and Token is unset the caller's Authorization header is forwarded
+	Token     string // bearer token callers must present; empty allows anyone who can connect
+	MaxRounds int    // tool-call round trips before the last response is returned as is
+}
+
+// OpenAIBridge serves /v1/chat/completions for OpenAI-compatible clients. It
+// adds the server's tools to each request, forwards it upstream, runs any
+// calls the model makes to those tools against the tools manager and sends
+// the results back until the model answers. Calls to tools the caller
+// supplied itself end the loop, so the caller can run them.
+type OpenAIBridge struct {
+	tools  ToolCaller
+	cfg    OpenAIConfig
+	client *http.Client
+}
+
+// NewOpenAIBridge creates a bridge that executes tool calls with tools
+func NewOpenAIBridge(tools ToolCaller, cfg OpenAIConfig) *OpenAIBridge {
+	if cfg.MaxRounds <= 0 {
+		cfg.MaxRounds = DefaultMaxRounds
+	}
+	cfg.Upstream = strings.TrimSuffix(cfg.Upstream, "/")
+	return &OpenAIBridge{
+		tools:  tools,
+		cfg:    cfg,
+		client: &http.Client{Timeout: upstreamTimeout},
+	}
+}
+
+// Handler serves /v1/chat/completions and, for discovery, /v1/tools
+func (b *OpenAIBridge) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", b.handleChat)
+	mux.HandleFunc("/v1/tools", b.handleTools)
+	return b.authorize(mux)
+}
+
+// Serve exposes the bridge on addr until ctx is done
+func (b *OpenAIBridge) Serve(ctx context.Context, addr string) error {
+	srv := &http.Server{Addr: addr, Handler: b.Handler()}
+
+	go func() {
+		<-ctx.Done()
+		_ = srv.Shutdown(context.Background())
+	}()
+
+	slog.Info("Serving OpenAI tools bridge", "address", addr, "upstream", b.cfg.Upstream)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("openai bridge failed: %w", err)
+	}
+	return nil
+}
+
+// authorize rejects callers without the configured bearer token
+func (b *OpenAIBridge) authorize(next http.Handler) http.Handler {
+	if b.cfg.Token == "" {
+		return next
+	}
+	want := []byte("Bearer " + b.cfg.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			writeError(w, http.StatusUnauthorized, "invalid_api_key", "missing or invalid bearer token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleTools lists the bridged tools in OpenAI format
+func (b *OpenAIBridge) handleTools(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "use GET")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"object": "list",
+		"data":   interop.ToOpenAI(b.tools.ListTools()),
+	})
+}
+
+// handleChat runs the tool loop for one chat completion request
+func (b *OpenAIBridge) handleChat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "use POST")
+		return
+	}
+
+	var req map[string]json.RawMessage
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid JSON body: "+err.Error())
+		return
+	}
+	var stream bool
+	_ = json.Unmarshal(req["stream"], &stream)
+	if stream {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "stream is not supported by the tools bridge")
+		return
+	}
+
+	var messages []json.RawMessage
+	if err := json.Unmarshal(req["messages"], &messages); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "messages must be an array")
+		return
+	}
+	ours, err := b.advertise(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+
+	// The caller's credentials only go upstream when they are not our own token
+	var auth string
+	switch {
+	case b.cfg.APIKey != "":
+		auth = "Bearer " + b.cfg.APIKey
+	case b.cfg.Token == "":
+		auth = r.Header.Get("Authorization")
+	}
+
+	for round := 0; ; round++ {
+		req["messages"], _ = json.Marshal(messages)
+		status, body, err := b.forward(r.Context(), req, auth)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, "upstream_error", err.Error())
+			return
+		}
+
+		message, calls := toolCalls(body)
+		if status != http.StatusOK || len(calls) == 0 || !allOurs(calls, ours) || round+1 >= b.cfg.MaxRounds {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = w.Write(body)
+			return
+		}
+
+		messages = append(messages, message)
+		for _, call := range calls {
+			messages = append(messages, b.execute(r.Context(), call))
+		}
+	}
+}
+
+// advertise adds the server's tools to req["tools"], leaving any tool the
+// caller defined under the same name alone. It returns the names it added.
+func (b *OpenAIBridge) advertise(req map[string]json.RawMessage) (map[string]bool, error) {
+	var tools []json.RawMessage
+	if raw, ok := req["tools"]; ok {
+		if err := json.Unmarshal(raw, &tools); err != nil {
+			return nil, fmt.Errorf("tools must be an array")
+		}
+	}
+
+	theirs := make(map[string]bool, len(tools))
+	for _, raw := range tools {
+		var t interop.OpenAITool
+		if json.Unmarshal(raw, &t) == nil {
+			theirs[t.Function.Name] = true
+		}
+	}
+
+	// Sorted so identical requests produce identical prompts upstream
+	list := b.tools.ListTools()
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	ours := make(map[string]bool)
+	for _, t := range interop.ToOpenAI(list) {
+		if theirs[t.Function.Name] {
+			continue
+		}
+		raw, err := json.Marshal(t)
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, raw)
+		ours[t.Function.Name] = true
+	}
+	if len(tools) > 0 {
+		req["tools"], _ = json.Marshal(tools)
+	}
+	return ours, nil
+}
+
+// forward posts req to the upstream chat completions endpoint
+func (b *OpenAIBridge) forward(ctx context.Context, req map[string]json.RawMessage, auth string) (int, []byte, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, b.cfg.Upstream+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if auth != "" {
+		httpReq.Header.Set("Authorization", auth)
+	}
+
+	resp, err := b.client.Do(httpReq)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, data, err
+}
+
+// toolCall is a function call requested by the model
+type toolCall struct {
+	ID       string `json:"id"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"` // JSON encoded as a string
+	} `json:"function"`
+}
+
+// toolCalls extracts the first choice's message and its tool calls
+func toolCalls(body []byte) (json.RawMessage, []toolCall) {
+	var resp struct {
+		Choices []struct {
+			Message json.RawMessage `json:"message"`
+		} `json:"choices"`
+	}
+	if json.Unmarshal(body, &resp) != nil || len(resp.Choices) == 0 {
+		return nil, nil
+	}
+
+	var msg struct {
+		ToolCalls []toolCall `json:"tool_calls"`
+	}
+	if json.Unmarshal(resp.Choices[0].Message, &msg) != nil {
+		return nil, nil
+	}
+	return resp.Choices[0].Message, msg.ToolCalls
+}
+
+// allOurs reports whether every call is to a tool the bridge executes
+func allOurs(calls []toolCall, ours map[string]bool) bool {
+	for _, call := range calls {
+		if !ours[call.Function.Name] {
+			return false
+		}
+	}
+	return true
+}
+
+// execute runs one call and returns the tool message reporting its result.
+// Failures are reported to the model as text so it can correct itself.
+func (b *OpenAIBridge) execute(ctx context.Context, call toolCall) json.RawMessage {
+	var content string
+	result, err := b.tools.CallTool(ctx, call.Function.Name, json.RawMessage(call.Function.Arguments), "")
+	if err != nil {
+		content = "Error: " + err.Error()
+	} else {
+		content = resultText(result)
+	}
+
+	msg, _ := json.Marshal(map[string]string{
+		"role":         "tool",
+		"tool_call_id": call.ID,
+		"content":      content,
+	})
+	return msg
+}
+
+// resultText flattens a tool result to the text a tool message carries
+func resultText(result protocol.ToolsCallResult) string {
+	var parts []string
+	for _, c := range result.Content {
+		switch {
+		case c.Text != "":
+			parts = append(parts, c.Text)
+		case c.Resource != nil && c.Resource.Text != "":
+			parts = append(parts, c.Resource.Text)
+		case c.Type == protocol.ContentImage:
+			parts = append(parts, fmt.Sprintf("[%s image omitted]", c.MimeType))
+		case c.Type == protocol.ContentAudio:
+			parts = append(parts, fmt.Sprintf("[%s audio omitted]", c.MimeType))
+		}
+	}
+	text := strings.Join(parts, "\n")
+	if result.IsError {
+		text = "Error: " + text
+	}
+	return text
+}
+
+// writeJSON writes v with status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error in the OpenAI error envelope
+func writeError(w http.ResponseWriter, status int, kind, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]string{"type": kind, "message": message},
+	})
+}
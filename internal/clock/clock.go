@@ -0,0 +1,64 @@
+// internal/clock/clock.go
+
+// Package clock abstracts time so timeouts, heartbeats and expirations can be
+// driven by a fake clock in tests.
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the time and schedules work
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+
+	// NewTicker delivers the time on C every d
+	NewTicker(d time.Duration) Ticker
+
+	// AfterFunc calls f once d has elapsed
+	AfterFunc(d time.Duration, f func()) Timer
+
+	// WithTimeout is context.WithTimeout measured by this clock
+	WithTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc)
+}
+
+// Ticker is a running ticker
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Timer is a pending AfterFunc call
+type Timer interface {
+	// Stop prevents the call, reporting whether it was still pending
+	Stop() bool
+}
+
+// Real is the system clock
+var Real Clock = realClock{}
+
+// realClock delegates to the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) Since(t time.Time) time.Duration { return time.Since(t) }
+
+func (realClock) NewTicker(d time.Duration) Ticker { return realTicker{time.NewTicker(d)} }
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer { return time.AfterFunc(d, f) }
+
+func (realClock) WithTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, d)
+}
+
+// realTicker adapts *time.Ticker to Ticker
+type realTicker struct {
+	t *time.Ticker
+}
+
+func (r realTicker) C() <-chan time.Time { return r.t.C }
+
+func (r realTicker) Stop() { r.t.Stop() }
@@ -0,0 +1,57 @@
+// internal/codec/codec.go
+
+// Package codec is the JSON codec used on the transport hot paths. It wraps
+// encoding/json by default; building with -tags jsoniter swaps in
+// json-iterator, which is considerably faster for large tool results.
+package codec
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Encoder writes JSON values to a stream
+type Encoder interface {
+	Encode(v interface{}) error
+}
+
+// Decoder reads JSON values from a stream
+type Decoder interface {
+	Decode(v interface{}) error
+}
+
+// Name returns the name of the compiled-in codec
+func Name() string {
+	return name
+}
+
+// Marshal encodes v with the active codec
+func Marshal(v interface{}) ([]byte, error) {
+	return marshal(v)
+}
+
+// Unmarshal decodes data into v with the active codec
+func Unmarshal(data []byte, v interface{}) error {
+	return unmarshal(data, v)
+}
+
+// NewEncoder returns an encoder writing newline-terminated values to w
+func NewEncoder(w io.Writer) Encoder {
+	return newEncoder(w)
+}
+
+// NewDecoder returns a decoder reading successive values from r
+func NewDecoder(r io.Reader) Decoder {
+	return newDecoder(r)
+}
+
+// Raw pre-encodes a reply payload with the active codec. jsonrpc2 marshals
+// results with encoding/json; handing it a RawMessage reduces that to a copy.
+// If encoding fails v is returned unchanged so the error surfaces at reply time.
+func Raw(v interface{}) interface{} {
+	data, err := marshal(v)
+	if err != nil {
+		return v
+	}
+	return json.RawMessage(data)
+}
@@ -0,0 +1,31 @@
+// internal/codec/codec_jsoniter.go
+//go:build jsoniter
+
+package codec
+
+import (
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const name = "jsoniter"
+
+// api matches encoding/json behaviour (HTML escaping, sorted map keys)
+var api = jsoniter.ConfigCompatibleWithStandardLibrary
+
+func marshal(v interface{}) ([]byte, error) {
+	return api.Marshal(v)
+}
+
+func unmarshal(data []byte, v interface{}) error {
+	return api.Unmarshal(data, v)
+}
+
+func newEncoder(w io.Writer) Encoder {
+	return api.NewEncoder(w)
+}
+
+func newDecoder(r io.Reader) Decoder {
+	return api.NewDecoder(r)
+}
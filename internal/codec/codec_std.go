@@ -0,0 +1,27 @@
+// internal/codec/codec_std.go
+//go:build !jsoniter
+
+package codec
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const name = "encoding/json"
+
+func marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func newEncoder(w io.Writer) Encoder {
+	return json.NewEncoder(w)
+}
+
+func newDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
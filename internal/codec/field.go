@@ -0,0 +1,222 @@
+// internal/codec/field.go
+package codec
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ErrFieldNotFound is returned when StringField cannot find the requested field
+var ErrFieldNotFound = errors.New("field not found")
+
+// StringField returns a reader over the unescaped value of the JSON string at
+// path within the object read from r, without holding the whole value in
+// memory. It lets providers stream large write-style payloads (for example
+// {"params":{"text":"..."}}) straight to their destination. Fields before
+// the target are decoded and skipped; anything after it is never read.
+func StringField(r io.Reader, path ...string) (io.Reader, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("empty field path")
+	}
+
+	dec := json.NewDecoder(r)
+	for i, key := range path {
+		if err := expectDelim(dec, '{'); err != nil {
+			return nil, err
+		}
+		if err := seekKey(dec, key); err != nil {
+			return nil, err
+		}
+		if i == len(path)-1 {
+			return newStringReader(io.MultiReader(dec.Buffered(), r))
+		}
+	}
+	return nil, ErrFieldNotFound // unreachable
+}
+
+// expectDelim reads the next token and checks it is delim
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %q, found %v", delim, tok)
+	}
+	return nil
+}
+
+// seekKey advances dec past the object key named key, skipping other members
+func seekKey(dec *json.Decoder, key string) error {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if d, ok := tok.(json.Delim); ok && d == '}' {
+			return fmt.Errorf("%w: %q", ErrFieldNotFound, key)
+		}
+		if name, _ := tok.(string); name == key {
+			return nil
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return err
+		}
+	}
+}
+
+// stringReader unescapes a JSON string value as it is read
+type stringReader struct {
+	r       *bufio.Reader
+	pending []byte // decoded bytes that did not fit in the last Read
+	done    bool
+	err     error
+}
+
+// newStringReader positions r at the start of a string value following a key
+func newStringReader(r io.Reader) (*stringReader, error) {
+	br := bufio.NewReader(r)
+	if err := skipSpaceAndExpect(br, ':'); err != nil {
+		return nil, err
+	}
+	if err := skipSpaceAndExpect(br, '"'); err != nil {
+		return nil, fmt.Errorf("field is not a string: %w", err)
+	}
+	return &stringReader{r: br}, nil
+}
+
+// skipSpaceAndExpect skips JSON whitespace and consumes want
+func skipSpaceAndExpect(r *bufio.Reader, want byte) error {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case want:
+			return nil
+		default:
+			return fmt.Errorf("expected %q, found %q", want, b)
+		}
+	}
+}
+
+// Read implements io.Reader
+func (s *stringReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(s.pending) > 0 {
+			c := copy(p[n:], s.pending)
+			s.pending = s.pending[c:]
+			n += c
+			continue
+		}
+		if s.done || s.err != nil {
+			break
+		}
+
+		b, err := s.r.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			s.err = err
+			break
+		}
+		switch {
+		case b == '"':
+			s.done = true
+		case b == '\\':
+			s.pending, s.err = s.unescape()
+		case b < 0x20:
+			s.err = fmt.Errorf("invalid control character %q in string", b)
+		default:
+			p[n] = b
+			n++
+		}
+	}
+
+	if n > 0 {
+		return n, nil
+	}
+	if s.err != nil {
+		return 0, s.err
+	}
+	return 0, io.EOF
+}
+
+// unescape decodes the escape sequence following a backslash
+func (s *stringReader) unescape() ([]byte, error) {
+	b, err := s.r.ReadByte()
+	if err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	switch b {
+	case '"', '\\', '/':
+		return []byte{b}, nil
+	case 'b':
+		return []byte{'\b'}, nil
+	case 'f':
+		return []byte{'\f'}, nil
+	case 'n':
+		return []byte{'\n'}, nil
+	case 'r':
+		return []byte{'\r'}, nil
+	case 't':
+		return []byte{'\t'}, nil
+	case 'u':
+		r, err := s.readHex()
+		if err != nil {
+			return nil, err
+		}
+		if utf16.IsSurrogate(r) {
+			if err := s.expect('\\', 'u'); err != nil {
+				return nil, err
+			}
+			low, err := s.readHex()
+			if err != nil {
+				return nil, err
+			}
+			r = utf16.DecodeRune(r, low)
+		}
+		return utf8.AppendRune(nil, r), nil
+	default:
+		return nil, fmt.Errorf("invalid escape sequence \\%c", b)
+	}
+}
+
+// expect consumes the given bytes
+func (s *stringReader) expect(want ...byte) error {
+	for _, w := range want {
+		b, err := s.r.ReadByte()
+		if err != nil {
+			return io.ErrUnexpectedEOF
+		}
+		if b != w {
+			return fmt.Errorf("expected %q in surrogate pair, found %q", w, b)
+		}
+	}
+	return nil
+}
+
+// readHex reads the four hex digits of a \u escape
+func (s *stringReader) readHex() (rune, error) {
+	var digits [4]byte
+	if _, err := io.ReadFull(s.r, digits[:]); err != nil {
+		return 0, io.ErrUnexpectedEOF
+	}
+	v, err := strconv.ParseUint(string(digits[:]), 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid \\u escape %q", digits[:])
+	}
+	return rune(v), nil
+}
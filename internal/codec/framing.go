@@ -0,0 +1,137 @@
+// internal/codec/framing.go
+package codec
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// Framing selects how messages are delimited on a byte stream
+type Framing string
+
+// Supported framings
+const (
+	FramingPlain  Framing = "plain"  // concatenated JSON values; whitespace between them is optional
+	FramingNDJSON Framing = "ndjson" // exactly one JSON value per line
+)
+
+// ParseFraming validates a configured framing name. The empty string selects
+// FramingPlain.
+func ParseFraming(name string) (Framing, error) {
+	switch Framing(name) {
+	case "", FramingPlain:
+		return FramingPlain, nil
+	case FramingNDJSON:
+		return FramingNDJSON, nil
+	default:
+		return "", fmt.Errorf("unknown framing %q (want %s or %s)", name, FramingPlain, FramingNDJSON)
+	}
+}
+
+// StreamOption configures an object stream
+type StreamOption func(*streamOptions)
+
+// streamOptions collects what the stream constructors were asked for
+type streamOptions struct {
+	capture bool // log malformed frames in full
+}
+
+// WithFrameCapture logs the frames of malformed messages along with the
+// error, for the capture mode of transports. Frames may hold anything a
+// client sent, so this is for development only.
+func WithFrameCapture(capture bool) StreamOption {
+	return func(o *streamOptions) {
+		o.capture = capture
+	}
+}
+
+// newStreamOptions applies opts
+func newStreamOptions(opts []StreamOption) streamOptions {
+	var o streamOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// NewFramedStream returns an object stream over conn using framing f
+func NewFramedStream(conn io.ReadWriteCloser, f Framing, opts ...StreamOption) jsonrpc2.ObjectStream {
+	if f == FramingNDJSON {
+		return newLineStream(conn, newStreamOptions(opts))
+	}
+	return NewObjectStream(conn, opts...)
+}
+
+// lineStream reads and writes one JSON-RPC object per line. Because each
+// message ends at a newline, a malformed line is answered with an error
+// response without losing the messages after it.
+type lineStream struct {
+	conn   io.Closer
+	w      io.Writer
+	r      *bufio.Reader
+	mu     sync.Mutex // serializes writes so lines never interleave
+	buffer []byte
+	opts   streamOptions
+}
+
+func newLineStream(conn io.ReadWriteCloser, opts streamOptions) *lineStream {
+	return &lineStream{conn: conn, w: conn, r: bufio.NewReader(conn), opts: opts}
+}
+
+// WriteObject implements jsonrpc2.ObjectStream. Compact JSON never contains
+// a raw newline, so the encoded object always fits on one line.
+func (s *lineStream) WriteObject(obj interface{}) error {
+	data, err := marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	return s.writeLine(data)
+}
+
+// writeLine writes data followed by a newline
+func (s *lineStream) writeLine(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buffer = append(append(s.buffer[:0], data...), '\n')
+	_, err := s.w.Write(s.buffer)
+	return err
+}
+
+// ReadObject implements jsonrpc2.ObjectStream. Blank lines are skipped, a
+// trailing carriage return is ignored and a final line without a newline is
+// still delivered. A line that is not a message gets a -32700 Parse error or
+// -32600 Invalid Request response with a null ID.
+func (s *lineStream) ReadObject(v interface{}) error {
+	for {
+		line, err := s.r.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			return err
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			uerr := unmarshal(line, v)
+			if uerr == nil {
+				return nil
+			}
+			LogMalformed(line, uerr, s.opts.capture)
+			if werr := s.writeLine(malformedReply(line)); werr != nil {
+				return werr
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Close implements jsonrpc2.ObjectStream
+func (s *lineStream) Close() error {
+	return s.conn.Close()
+}
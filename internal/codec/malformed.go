@@ -0,0 +1,64 @@
+// internal/codec/malformed.go
+package codec
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// maxLoggedFrame bounds how much of a malformed frame is logged
+const maxLoggedFrame = 1024
+
+// errorReply is a JSON-RPC error response to a message whose ID could not
+// be read. JSON-RPC 2.0 requires the ID to be null then.
+type errorReply struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *jsonrpc2.ID    `json:"id"`
+	Error   *jsonrpc2.Error `json:"error"`
+}
+
+// ParseErrorReply is the response to a frame that is not valid JSON
+func ParseErrorReply() []byte {
+	return errorReplyFor(jsonrpc2.CodeParseError, "Parse error")
+}
+
+// InvalidRequestReply is the response to valid JSON that is not a JSON-RPC
+// message
+func InvalidRequestReply() []byte {
+	return errorReplyFor(jsonrpc2.CodeInvalidRequest, "Invalid Request")
+}
+
+// errorReplyFor encodes an error response with a null ID
+func errorReplyFor(code int64, message string) []byte {
+	data, _ := json.Marshal(errorReply{
+		JSONRPC: "2.0",
+		Error:   &jsonrpc2.Error{Code: code, Message: message},
+	})
+	return data
+}
+
+// malformedReply returns the response to a frame that could not be read as
+// a message: a parse error for invalid JSON, otherwise an invalid request
+func malformedReply(frame []byte) []byte {
+	if !json.Valid(frame) {
+		return ParseErrorReply()
+	}
+	return InvalidRequestReply()
+}
+
+// LogMalformed reports a frame that could not be read as a message. The
+// frame itself may hold anything a client sent, so it is only logged, up to
+// maxLoggedFrame bytes, when the transport captures messages.
+func LogMalformed(frame []byte, err error, capture bool) {
+	if !capture || len(frame) == 0 {
+		slog.Warn("Rejecting malformed message", "error", err, "bytes", len(frame))
+		return
+	}
+	logged := frame
+	if len(logged) > maxLoggedFrame {
+		logged = logged[:maxLoggedFrame]
+	}
+	slog.Warn("Rejecting malformed message", "error", err, "bytes", len(frame), "frame", string(logged))
+}
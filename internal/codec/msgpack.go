@@ -0,0 +1,400 @@
+// internal/codec/msgpack.go
+package codec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// Encoding selects how messages are serialized on a connection
+type Encoding string
+
+// Supported encodings
+const (
+	EncodingJSON    Encoding = "json"
+	EncodingMsgpack Encoding = "msgpack" // MessagePack, negotiated per connection
+)
+
+// Subprotocol returns the WebSocket subprotocol name that selects e
+func (e Encoding) Subprotocol() string {
+	return "mcp." + string(e)
+}
+
+// ParseEncoding validates a configured encoding name. The empty string
+// selects EncodingJSON.
+func ParseEncoding(name string) (Encoding, error) {
+	switch Encoding(name) {
+	case "", EncodingJSON:
+		return EncodingJSON, nil
+	case EncodingMsgpack:
+		return EncodingMsgpack, nil
+	default:
+		return "", fmt.Errorf("unknown encoding %q (want %s or %s)", name, EncodingJSON, EncodingMsgpack)
+	}
+}
+
+// Negotiate picks the encoding for a connection from the names the peer
+// offered, in the peer's order of preference. Offers may be bare encoding
+// names or WebSocket subprotocols ("mcp.msgpack"). Only encodings in allowed
+// are considered; when nothing matches the result is EncodingJSON, which
+// every client speaks.
+func Negotiate(offered []string, allowed ...Encoding) Encoding {
+	for _, offer := range offered {
+		for _, e := range allowed {
+			if offer == string(e) || offer == e.Subprotocol() {
+				return e
+			}
+		}
+	}
+	return EncodingJSON
+}
+
+// NewEncodedStream returns an object stream over conn using encoding e.
+// Messages are self-delimiting in MessagePack, so framing only applies to
+// EncodingJSON.
+func NewEncodedStream(conn io.ReadWriteCloser, e Encoding, f Framing, opts ...StreamOption) jsonrpc2.ObjectStream {
+	if e == EncodingMsgpack {
+		return newMsgpackStream(conn)
+	}
+	return NewFramedStream(conn, f, opts...)
+}
+
+// msgpackStream carries JSON-RPC objects as MessagePack values. jsonrpc2
+// shapes its envelopes through MarshalJSON, so objects are transcoded from
+// their JSON form: the wire saves quoting, escaping and base-10 numbers,
+// which dominate the cost of relaying large tool results between servers.
+type msgpackStream struct {
+	conn   io.Closer
+	w      io.Writer
+	r      *bufio.Reader
+	mu     sync.Mutex // serializes writes so values never interleave
+	buffer []byte
+}
+
+func newMsgpackStream(conn io.ReadWriteCloser) *msgpackStream {
+	return &msgpackStream{conn: conn, w: conn, r: bufio.NewReader(conn)}
+}
+
+// WriteObject implements jsonrpc2.ObjectStream
+func (s *msgpackStream) WriteObject(obj interface{}) error {
+	data, err := marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buffer, err = JSONToMsgpack(s.buffer[:0], data)
+	if err != nil {
+		return err
+	}
+	_, err = s.w.Write(s.buffer)
+	return err
+}
+
+// ReadObject implements jsonrpc2.ObjectStream
+func (s *msgpackStream) ReadObject(v interface{}) error {
+	value, err := readMsgpack(s.r, 0)
+	if err != nil {
+		return err
+	}
+	data, err := marshal(value)
+	if err != nil {
+		return err
+	}
+	return unmarshal(data, v)
+}
+
+// Close implements jsonrpc2.ObjectStream
+func (s *msgpackStream) Close() error {
+	return s.conn.Close()
+}
+
+// JSONToMsgpack appends the MessagePack encoding of the JSON document data
+// to dst. Object keys are written in sorted order, matching encoding/json.
+func JSONToMsgpack(dst, data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return dst, err
+	}
+	return appendMsgpack(dst, value)
+}
+
+// MsgpackToJSON decodes one MessagePack value from data and returns it as JSON
+func MsgpackToJSON(data []byte) ([]byte, error) {
+	value, err := readMsgpack(bufio.NewReader(bytes.NewReader(data)), 0)
+	if err != nil {
+		return nil, err
+	}
+	return marshal(value)
+}
+
+// appendMsgpack encodes a value produced by a json.Decoder using UseNumber
+func appendMsgpack(b []byte, v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(b, 0xc0), nil
+	case bool:
+		if v {
+			return append(b, 0xc3), nil
+		}
+		return append(b, 0xc2), nil
+	case json.Number:
+		return appendNumber(b, v)
+	case string:
+		return appendString(b, v), nil
+	case []interface{}:
+		b = appendHeader(b, len(v), 0x90, 15, 0xdc)
+		for _, item := range v {
+			var err error
+			if b, err = appendMsgpack(b, item); err != nil {
+				return b, err
+			}
+		}
+		return b, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		b = appendHeader(b, len(v), 0x80, 15, 0xde)
+		for _, k := range keys {
+			b = appendString(b, k)
+			var err error
+			if b, err = appendMsgpack(b, v[k]); err != nil {
+				return b, err
+			}
+		}
+		return b, nil
+	default:
+		return b, fmt.Errorf("msgpack: unsupported type %T", v)
+	}
+}
+
+// appendNumber writes integers in their smallest form and everything else as float64
+func appendNumber(b []byte, n json.Number) ([]byte, error) {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		return appendInt(b, i), nil
+	}
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		return binary.BigEndian.AppendUint64(append(b, 0xcf), u), nil
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return b, fmt.Errorf("msgpack: invalid number %q", n)
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(f)), nil
+}
+
+func appendInt(b []byte, i int64) []byte {
+	switch {
+	case i >= 0 && i <= 0x7f:
+		return append(b, byte(i))
+	case i < 0 && i >= -32:
+		return append(b, byte(int8(i)))
+	case i >= math.MinInt8 && i <= math.MaxInt8:
+		return append(b, 0xd0, byte(int8(i)))
+	case i >= math.MinInt16 && i <= math.MaxInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(int16(i)))
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(int32(i)))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(i))
+	}
+}
+
+func appendString(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n <= 31:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xda), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xdb), uint32(n))
+	}
+	return append(b, s...)
+}
+
+// appendHeader writes an array or map header: the fix form when n fits in
+// fixMax, otherwise the 16- or 32-bit form starting at code16
+func appendHeader(b []byte, n int, fix byte, fixMax int, code16 byte) []byte {
+	switch {
+	case n <= fixMax:
+		return append(b, fix|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, code16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, code16+1), uint32(n))
+	}
+}
+
+// maxMsgpackDepth bounds nesting so a hostile peer cannot exhaust the stack
+const maxMsgpackDepth = 10000
+
+// errMsgpackDepth is returned when a value nests deeper than maxMsgpackDepth
+var errMsgpackDepth = errors.New("msgpack: value nested too deeply")
+
+// readMsgpack decodes one value into the shapes encoding/json produces, so
+// the result can be re-marshaled as JSON. Binary payloads become byte slices
+// (base64 strings in JSON); extension types are rejected.
+func readMsgpack(r *bufio.Reader, depth int) (interface{}, error) {
+	if depth > maxMsgpackDepth {
+		return nil, errMsgpackDepth
+	}
+	c, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xe0 == 0xa0:
+		return readString(r, int(c&0x1f))
+	case c&0xf0 == 0x90:
+		return readArray(r, int(c&0x0f), depth)
+	case c&0xf0 == 0x80:
+		return readMap(r, int(c&0x0f), depth)
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := readLength(r, c-0xc4)
+		if err != nil {
+			return nil, err
+		}
+		return readBytes(r, n)
+	case 0xca:
+		u, err := readUint(r, 4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case 0xcb:
+		u, err := readUint(r, 8)
+		return math.Float64frombits(u), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return readUint(r, 1<<(c-0xcc))
+	case 0xd0:
+		u, err := readUint(r, 1)
+		return int64(int8(u)), err
+	case 0xd1:
+		u, err := readUint(r, 2)
+		return int64(int16(u)), err
+	case 0xd2:
+		u, err := readUint(r, 4)
+		return int64(int32(u)), err
+	case 0xd3:
+		u, err := readUint(r, 8)
+		return int64(u), err
+	case 0xd9, 0xda, 0xdb:
+		n, err := readLength(r, c-0xd9)
+		if err != nil {
+			return nil, err
+		}
+		return readString(r, n)
+	case 0xdc, 0xdd:
+		n, err := readLength(r, c-0xdc+1)
+		if err != nil {
+			return nil, err
+		}
+		return readArray(r, n, depth)
+	case 0xde, 0xdf:
+		n, err := readLength(r, c-0xde+1)
+		if err != nil {
+			return nil, err
+		}
+		return readMap(r, n, depth)
+	default:
+		return nil, fmt.Errorf("msgpack: unsupported type code 0x%02x", c)
+	}
+}
+
+// readLength reads a 1-, 2- or 4-byte length selected by width 0, 1 or 2
+func readLength(r *bufio.Reader, width byte) (int, error) {
+	u, err := readUint(r, 1<<width)
+	return int(u), err
+}
+
+func readUint(r *bufio.Reader, size int) (uint64, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[8-size:]); err != nil {
+		return 0, unexpectedEOF(err)
+	}
+	return binary.BigEndian.Uint64(buf[:]), nil
+}
+
+// readBytes reads n bytes, growing the buffer as data arrives so a forged
+// length cannot force a huge allocation up front
+func readBytes(r *bufio.Reader, n int) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, r, int64(n)); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return buf.Bytes(), nil
+}
+
+func readString(r *bufio.Reader, n int) (string, error) {
+	b, err := readBytes(r, n)
+	return string(b), err
+}
+
+func readArray(r *bufio.Reader, n, depth int) ([]interface{}, error) {
+	items := make([]interface{}, 0, min(n, 1024))
+	for i := 0; i < n; i++ {
+		item, err := readMsgpack(r, depth+1)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func readMap(r *bufio.Reader, n, depth int) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, min(n, 1024))
+	for i := 0; i < n; i++ {
+		key, err := readMsgpack(r, depth+1)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		k, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("msgpack: map key is %T, want string", key)
+		}
+		if m[k], err = readMsgpack(r, depth+1); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+	}
+	return m, nil
+}
+
+// unexpectedEOF reports a stream that ends inside a value
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,128 @@
+// internal/codec/stream.go
+package codec
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// objectStream reads and writes newline-delimited JSON-RPC objects
+type objectStream struct {
+	conn    io.Closer
+	w       io.Writer
+	encoder Encoder
+	decoder Decoder
+	mu      sync.Mutex // serializes writes, including error replies from reads
+	opts    streamOptions
+}
+
+// NewObjectStream is jsonrpc2.NewPlainObjectStream using the active codec
+func NewObjectStream(conn io.ReadWriteCloser, opts ...StreamOption) jsonrpc2.ObjectStream {
+	return &objectStream{
+		opts:    newStreamOptions(opts),
+		conn:    conn,
+		w:       conn,
+		encoder: newEncoder(conn),
+		decoder: newDecoder(conn),
+	}
+}
+
+// WriteObject implements jsonrpc2.ObjectStream
+func (s *objectStream) WriteObject(obj interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.encoder.Encode(obj)
+}
+
+// ReadObject implements jsonrpc2.ObjectStream. A value that is not a
+// message gets a -32600 Invalid Request response and is skipped. Invalid
+// JSON gets a -32700 Parse error response, but without delimiters there is
+// no telling where the next message starts, so the stream then ends.
+func (s *objectStream) ReadObject(v interface{}) error {
+	for {
+		var raw json.RawMessage
+		if err := s.decoder.Decode(&raw); err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				LogMalformed(nil, err, s.opts.capture)
+				if werr := s.writeReply(ParseErrorReply()); werr != nil {
+					return werr
+				}
+			}
+			return err
+		}
+		err := unmarshal(raw, v)
+		if err == nil {
+			return nil
+		}
+		LogMalformed(raw, err, s.opts.capture)
+		if werr := s.writeReply(InvalidRequestReply()); werr != nil {
+			return werr
+		}
+	}
+}
+
+// writeReply writes a pre-encoded response followed by a newline
+func (s *objectStream) writeReply(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.w.Write(append(data, '\n'))
+	return err
+}
+
+// Close implements jsonrpc2.ObjectStream
+func (s *objectStream) Close() error {
+	return s.conn.Close()
+}
+
+// VSCodeObjectCodec is jsonrpc2.VSCodeObjectCodec using the active codec
+type VSCodeObjectCodec struct{}
+
+// Ensure VSCodeObjectCodec implements jsonrpc2.ObjectCodec
+var _ jsonrpc2.ObjectCodec = VSCodeObjectCodec{}
+
+// WriteObject writes obj with a Content-Length header
+func (VSCodeObjectCodec) WriteObject(stream io.Writer, obj interface{}) error {
+	data, err := marshal(obj)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(stream, "Content-Length: %d\r\n\r\n", len(data)); err != nil {
+		return err
+	}
+	_, err = stream.Write(data)
+	return err
+}
+
+// ReadObject reads the next Content-Length framed object into v
+func (VSCodeObjectCodec) ReadObject(stream *bufio.Reader, v interface{}) error {
+	var contentLength uint64
+	for {
+		line, err := stream.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if value, ok := strings.CutPrefix(line, "Content-Length: "); ok {
+			contentLength, err = strconv.ParseUint(strings.TrimSpace(value), 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid Content-Length: %w", err)
+			}
+		}
+	}
+	if contentLength == 0 {
+		return fmt.Errorf("no Content-Length header found")
+	}
+	return newDecoder(io.LimitReader(stream, int64(contentLength))).Decode(v)
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/knadh/koanf/parsers/json"
 	"github.com/knadh/koanf/parsers/yaml"
@@ -20,44 +21,258 @@ type ServerConfig struct {
 	Name     string `koanf:"name"`
 	Version  string `koanf:"version"`
 	LogLevel string `koanf:"logLevel"`
+	PIDFile  string `koanf:"pidFile"` // used in daemon mode; defaults next to the config file
+	LogFile  string `koanf:"logFile"` // daemon log destination; defaults next to the config file
+	Debug    bool   `koanf:"debug"`   // include error chains and stack traces in error responses
+
+	Instructions string `koanf:"instructions"` // text/template for the instructions sent at initialize; empty uses the built-in one
+
+	DeadLetterFile string `koanf:"deadLetterFile"` // persists undelivered notifications; empty keeps them in memory
+
+	Workers   int `koanf:"workers"`   // concurrent slow requests (tool calls, reads)
+	QueueSize int `koanf:"queueSize"` // tool calls, and separately reads, waiting for a worker before new ones are rejected
+
+	NotifyFlushInterval time.Duration `koanf:"notifyFlushInterval"` // how long progress and update notifications are held for merging
+	NotifyMaxBatch      int           `koanf:"notifyMaxBatch"`      // pending notifications that force an early flush
 }
 
 // TransportConfig holds transport-related configuration
 type TransportConfig struct {
-	Type string `koanf:"type"` // stdio or sse
-	SSE  struct {
-		Port int    `koanf:"port"`
-		Host string `koanf:"host"`
+	Type  string `koanf:"type"` // stdio or sse
+	Stdio struct {
+		Framing  string         `koanf:"framing"`  // plain (concatenated JSON) or ndjson (one message per line)
+		Timeouts TimeoutsConfig `koanf:"timeouts"` // read is the longest gap between inputs
+	} `koanf:"stdio"`
+	SSE struct {
+		Port           int            `koanf:"port"`
+		Host           string         `koanf:"host"`
+		DrainTimeout   time.Duration  `koanf:"drainTimeout"`   // how long old sessions may linger after a restart
+		Compression    bool           `koanf:"compression"`    // gzip/deflate responses for clients that accept it
+		MaxBodyBytes   int64          `koanf:"maxBodyBytes"`   // largest POSTed message accepted; 0 means unlimited
+		BasePath       string         `koanf:"basePath"`       // prefix for both endpoints, e.g. /api/mcp, when mounted behind a router
+		Path           string         `koanf:"path"`           // event stream endpoint; empty uses /sse
+		MessagePath    string         `koanf:"messagePath"`    // endpoint clients POST messages to; empty uses /messages
+		TrustedProxies []string       `koanf:"trustedProxies"` // IPs or CIDRs of reverse proxies whose X-Forwarded-* headers are believed
+		Timeouts       TimeoutsConfig `koanf:"timeouts"`       // bounds on slow or stalled clients
+		Tokens         AccessTokens   `koanf:"tokens"`         // bearer tokens clients must present; empty allows anyone
 	} `koanf:"sse"`
 }
 
+// AccessTokenConfig is a bearer token and the caller it identifies
+type AccessTokenConfig struct {
+	Identity string `koanf:"identity"` // who presents the token, e.g. a user or service name
+	Token    string `koanf:"token"`    // may be $VAR
+}
+
+// AccessTokens is a list of bearer tokens
+type AccessTokens []AccessTokenConfig
+
+// Identities maps each token, with $VARs expanded, to its identity
+func (a AccessTokens) Identities() (map[string]string, error) {
+	ids := make(map[string]string, len(a))
+	for _, at := range a {
+		token := os.ExpandEnv(at.Token)
+		if at.Identity == "" || token == "" {
+			return nil, fmt.Errorf("access token for %q: identity and token must not be empty", at.Identity)
+		}
+		if _, dup := ids[token]; dup {
+			return nil, fmt.Errorf("access token for %q is also used by %q", at.Identity, ids[token])
+		}
+		ids[token] = at.Identity
+	}
+	return ids, nil
+}
+
+// TimeoutsConfig bounds how long a transport waits on its peer; 0 disables
+// a timeout
+type TimeoutsConfig struct {
+	ReadHeader time.Duration `koanf:"readHeader"` // HTTP request headers
+	Read       time.Duration `koanf:"read"`       // an HTTP request body
+	Write      time.Duration `koanf:"write"`      // one message to the peer
+	Idle       time.Duration `koanf:"idle"`       // a kept-alive HTTP connection between requests
+}
+
+// SecretRef names a secret rather than holding it, so credentials stay out
+// of config files: env:NAME reads an environment variable and file:PATH
+// reads a file, such as a mounted container secret. Any other value is the
+// secret itself, with $VARs expanded.
+type SecretRef string
+
+// Resolve returns the secret r refers to
+func (r SecretRef) Resolve() (string, error) {
+	ref := string(r)
+	switch {
+	case strings.HasPrefix(ref, "env:"):
+		name := strings.TrimPrefix(ref, "env:")
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("secret %s: environment variable %s is not set", ref, name)
+		}
+		return v, nil
+	case strings.HasPrefix(ref, "file:"):
+		data, err := os.ReadFile(strings.TrimPrefix(ref, "file:"))
+		if err != nil {
+			return "", fmt.Errorf("secret %s: %w", ref, err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+	return os.ExpandEnv(ref), nil
+}
+
+// MetricsConfig holds metrics and alerting configuration
+type MetricsConfig struct {
+	Addr        string `koanf:"addr"`        // host:port for the /metrics endpoint; empty disables it
+	ClientStats bool   `koanf:"clientStats"` // opt in to counting client implementations (name and version only) and the methods and tools they use
+	Alert       struct {
+		WebhookURL  string        `koanf:"webhookURL"`  // POSTed when the error rate crosses the threshold
+		Threshold   float64       `koanf:"threshold"`   // error rate between 0 and 1
+		Window      time.Duration `koanf:"window"`      // sliding window for the error rate
+		MinRequests int           `koanf:"minRequests"` // requests needed in the window before alerting
+	} `koanf:"alert"`
+}
+
+// ResourcesConfig holds resource subscription settings
+type ResourcesConfig struct {
+	Debounce      time.Duration `koanf:"debounce"`      // at most one update per subscribed URI within this window
+	MaxUpdateRate float64       `koanf:"maxUpdateRate"` // updates per second per subscription; 0 means no extra cap
+	MaxReadBytes  int64         `koanf:"maxReadBytes"`  // largest single resources/read; longer content is truncated with a continuation offset
+	PageSize      int           `koanf:"pageSize"`      // resources per resources/list page
+	Cache         struct {
+		Enabled       bool          `koanf:"enabled"`
+		MaxBytes      int64         `koanf:"maxBytes"`      // total cached content; 0 uses the default
+		MaxEntryBytes int64         `koanf:"maxEntryBytes"` // larger resources are never cached; 0 uses the default
+		TTL           time.Duration `koanf:"ttl"`           // expiry for content whose provider has no ETags; 0 uses the default
+	} `koanf:"cache"`
+}
+
+// PromptsConfig holds prompt library settings
+type PromptsConfig struct {
+	Dir   string `koanf:"dir"`   // directory of prompt files; empty disables the prompt library
+	Watch bool   `koanf:"watch"` // reload prompts when their files change
+}
+
+// Modes the server runs in
+const (
+	ModeProduction  = "production"  // lenient: inconsistencies are logged, error internals stay private
+	ModeDevelopment = "development" // strict: surfaces every mismatch as early and loudly as possible
+)
+
 // Config holds the complete configuration
 type Config struct {
+	Mode      string          `koanf:"mode"` // development or production; empty means production
 	Server    ServerConfig    `koanf:"server"`
 	Transport TransportConfig `koanf:"transport"`
+	Metrics   MetricsConfig   `koanf:"metrics"`
+	Resources ResourcesConfig `koanf:"resources"`
+	Prompts   PromptsConfig   `koanf:"prompts"`
+	Tools     ToolsConfig     `koanf:"tools"`
+	Bridge    BridgeConfig    `koanf:"bridge"`
+	Providers ProvidersConfig `koanf:"providers"`
+	Storage   StorageConfig   `koanf:"storage"`
+	Profiles  []ProfileConfig `koanf:"profiles"`
+	Tenants   []TenantConfig  `koanf:"tenants"`
+	Plugins   []PluginConfig  `koanf:"plugins"`
+}
+
+// Development reports whether the server runs in development mode
+func (c *Config) Development() bool {
+	return c.Mode == ModeDevelopment
+}
+
+// ValidateMode checks that mode names a known mode
+func (c *Config) ValidateMode() error {
+	switch c.Mode {
+	case "", ModeProduction, ModeDevelopment:
+		return nil
+	}
+	return fmt.Errorf("mode %q must be %s or %s", c.Mode, ModeDevelopment, ModeProduction)
 }
 
 // Default configuration values
 var defaultConfig = Config{
+	Mode: ModeProduction,
 	Server: ServerConfig{
-		Name:     "axe-handle",
-		Version:  "0.1.0",
-		LogLevel: "info",
+		Name:      "axe-handle",
+		Version:   "0.1.0",
+		LogLevel:  "info",
+		Workers:   8,
+		QueueSize: 64,
+
+		NotifyFlushInterval: 50 * time.Millisecond,
+		NotifyMaxBatch:      32,
 	},
 	Transport: TransportConfig{
 		Type: "stdio", // Default to stdio
+		Stdio: struct {
+			Framing  string         `koanf:"framing"`  // plain (concatenated JSON) or ndjson (one message per line)
+			Timeouts TimeoutsConfig `koanf:"timeouts"` // read is the longest gap between inputs
+		}{
+			Framing:  "plain",
+			Timeouts: TimeoutsConfig{Write: time.Minute},
+		},
 		SSE: struct {
-			Port int    `koanf:"port"`
-			Host string `koanf:"host"`
+			Port           int            `koanf:"port"`
+			Host           string         `koanf:"host"`
+			DrainTimeout   time.Duration  `koanf:"drainTimeout"`   // how long old sessions may linger after a restart
+			Compression    bool           `koanf:"compression"`    // gzip/deflate responses for clients that accept it
+			MaxBodyBytes   int64          `koanf:"maxBodyBytes"`   // largest POSTed message accepted; 0 means unlimited
+			BasePath       string         `koanf:"basePath"`       // prefix for both endpoints, e.g. /api/mcp, when mounted behind a router
+			Path           string         `koanf:"path"`           // event stream endpoint; empty uses /sse
+			MessagePath    string         `koanf:"messagePath"`    // endpoint clients POST messages to; empty uses /messages
+			TrustedProxies []string       `koanf:"trustedProxies"` // IPs or CIDRs of reverse proxies whose X-Forwarded-* headers are believed
+			Timeouts       TimeoutsConfig `koanf:"timeouts"`       // bounds on slow or stalled clients
+			Tokens         AccessTokens   `koanf:"tokens"`         // bearer tokens clients must present; empty allows anyone
+		}{
+			Port:         8080,
+			Host:         "localhost",
+			DrainTimeout: 5 * time.Minute,
+			MaxBodyBytes: 16 << 20,
+			Timeouts: TimeoutsConfig{
+				ReadHeader: 10 * time.Second,
+				Read:       30 * time.Second,
+				Write:      30 * time.Second,
+				Idle:       2 * time.Minute,
+			},
+		},
+	},
+	Metrics: MetricsConfig{
+		Alert: struct {
+			WebhookURL  string        `koanf:"webhookURL"`  // POSTed when the error rate crosses the threshold
+			Threshold   float64       `koanf:"threshold"`   // error rate between 0 and 1
+			Window      time.Duration `koanf:"window"`      // sliding window for the error rate
+			MinRequests int           `koanf:"minRequests"` // requests needed in the window before alerting
 		}{
-			Port: 8080,
-			Host: "localhost",
+			Threshold:   0.5,
+			Window:      5 * time.Minute,
+			MinRequests: 20,
 		},
 	},
+	Resources: ResourcesConfig{
+		Debounce:     250 * time.Millisecond,
+		MaxReadBytes: 1 << 20,
+		PageSize:     100,
+	},
+	Prompts: PromptsConfig{
+		Watch: true,
+	},
+	Tools: ToolsConfig{
+		SchemaValidator: "draft-07",
+	},
+	Storage: StorageConfig{
+		SweepInterval: time.Hour,
+	},
+}
+
+// Default returns a copy of the built-in default configuration
+func Default() *Config {
+	cfg := defaultConfig
+	return &cfg
 }
 
-// Load loads the configuration from files and environment variables
-func Load() (*Config, error) {
+// Load loads the configuration from files and environment variables. The
+// given paths are tried before the standard locations; the first file that
+// exists is used.
+func Load(paths ...string) (*Config, error) {
 	k := koanf.New(".")
 
 	// Load default config
@@ -66,7 +281,7 @@ func Load() (*Config, error) {
 	}
 
 	// Load from config file
-	if err := loadConfigFile(k); err != nil {
+	if err := loadConfigFile(k, paths); err != nil {
 		slog.Warn("Error loading config file", "error", err)
 		// Continue without config file
 	}
@@ -97,30 +312,93 @@ func loadDefaults(k *koanf.Koanf) error {
 	if err := k.Set("server.logLevel", defaultConfig.Server.LogLevel); err != nil {
 		return err
 	}
+	if err := k.Set("server.workers", defaultConfig.Server.Workers); err != nil {
+		return err
+	}
+	if err := k.Set("server.queueSize", defaultConfig.Server.QueueSize); err != nil {
+		return err
+	}
+	if err := k.Set("server.notifyFlushInterval", defaultConfig.Server.NotifyFlushInterval); err != nil {
+		return err
+	}
+	if err := k.Set("server.notifyMaxBatch", defaultConfig.Server.NotifyMaxBatch); err != nil {
+		return err
+	}
 	if err := k.Set("transport.type", defaultConfig.Transport.Type); err != nil {
 		return err
 	}
+	if err := k.Set("transport.stdio.framing", defaultConfig.Transport.Stdio.Framing); err != nil {
+		return err
+	}
 	if err := k.Set("transport.sse.port", defaultConfig.Transport.SSE.Port); err != nil {
 		return err
 	}
 	if err := k.Set("transport.sse.host", defaultConfig.Transport.SSE.Host); err != nil {
 		return err
 	}
+	if err := k.Set("transport.sse.drainTimeout", defaultConfig.Transport.SSE.DrainTimeout); err != nil {
+		return err
+	}
+	if err := k.Set("transport.sse.maxBodyBytes", defaultConfig.Transport.SSE.MaxBodyBytes); err != nil {
+		return err
+	}
+	if err := k.Set("transport.sse.compression", defaultConfig.Transport.SSE.Compression); err != nil {
+		return err
+	}
+	if err := k.Set("transport.stdio.timeouts.write", defaultConfig.Transport.Stdio.Timeouts.Write); err != nil {
+		return err
+	}
+	if err := k.Set("transport.sse.timeouts.readHeader", defaultConfig.Transport.SSE.Timeouts.ReadHeader); err != nil {
+		return err
+	}
+	if err := k.Set("transport.sse.timeouts.read", defaultConfig.Transport.SSE.Timeouts.Read); err != nil {
+		return err
+	}
+	if err := k.Set("transport.sse.timeouts.write", defaultConfig.Transport.SSE.Timeouts.Write); err != nil {
+		return err
+	}
+	if err := k.Set("transport.sse.timeouts.idle", defaultConfig.Transport.SSE.Timeouts.Idle); err != nil {
+		return err
+	}
+	if err := k.Set("resources.debounce", defaultConfig.Resources.Debounce); err != nil {
+		return err
+	}
+	if err := k.Set("resources.maxReadBytes", defaultConfig.Resources.MaxReadBytes); err != nil {
+		return err
+	}
+	if err := k.Set("resources.pageSize", defaultConfig.Resources.PageSize); err != nil {
+		return err
+	}
+	if err := k.Set("prompts.watch", defaultConfig.Prompts.Watch); err != nil {
+		return err
+	}
+	if err := k.Set("tools.schemaValidator", defaultConfig.Tools.SchemaValidator); err != nil {
+		return err
+	}
+	if err := k.Set("metrics.alert.threshold", defaultConfig.Metrics.Alert.Threshold); err != nil {
+		return err
+	}
+	if err := k.Set("metrics.alert.window", defaultConfig.Metrics.Alert.Window); err != nil {
+		return err
+	}
+	if err := k.Set("metrics.alert.minRequests", defaultConfig.Metrics.Alert.MinRequests); err != nil {
+		return err
+	}
 
 	return nil
 }
 
-// loadConfigFile loads configuration from a file
-func loadConfigFile(k *koanf.Koanf) error {
-	// Check for config file in standard locations
-	configPaths := []string{
+// loadConfigFile loads configuration from the first of paths, then the
+// standard locations, that exists
+func loadConfigFile(k *koanf.Koanf, paths []string) error {
+	configPaths := append(append([]string{}, paths...),
 		"./config.yaml",
 		"./config.json",
 		filepath.Join(os.Getenv("HOME"), ".axe-handle", "config.yaml"),
 		filepath.Join(os.Getenv("HOME"), ".axe-handle", "config.json"),
 		"/etc/axe-handle/config.yaml",
 		"/etc/axe-handle/config.json",
-	}
+	)
 
 	for _, path := range configPaths {
 		if _, err := os.Stat(path); err == nil {
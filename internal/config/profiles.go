@@ -0,0 +1,89 @@
+// internal/config/profiles.go
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ProfileConfig is a named view of the server for one kind of client: the
+// tools, resources and prompts it sees and the instructions it is given.
+// Patterns are exact names or URIs, or prefixes ending in *, e.g.
+// "calendar.*" or "mail://*".
+type ProfileConfig struct {
+	Name         string   `koanf:"name"`         // selects the profile: /<name>/sse over SSE, --profile over stdio
+	Instructions string   `koanf:"instructions"` // replaces the generated instructions when set
+	Tools        []string `koanf:"tools"`        // tools the client may list and call; empty allows all
+	Resources    []string `koanf:"resources"`    // resources and templates the client may see; empty allows all
+	Prompts      []string `koanf:"prompts"`      // prompts the client may list and get; empty allows all
+}
+
+// TenantConfig is one team sharing a hosted server. Its clients are
+// recognized by their authenticated identity and get provider instances,
+// state, caches and a rate limit of their own.
+type TenantConfig struct {
+	Name       string        `koanf:"name"`       // names the tenant's share of the state store
+	Identities []string      `koanf:"identities"` // authenticated callers, as in transport.sse.tokens, that belong to the tenant
+	Profile    string        `koanf:"profile"`    // profile the tenant's clients are held to; empty lets them choose
+	Limit      int           `koanf:"limit"`      // requests per window across the tenant's clients; 0 means unlimited
+	Window     time.Duration `koanf:"window"`     // 0 uses 1m
+}
+
+// Profile returns the profile with the given name
+func (c *Config) Profile(name string) (ProfileConfig, bool) {
+	for _, p := range c.Profiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return ProfileConfig{}, false
+}
+
+// ValidateProfiles checks that every profile has a distinct name usable as
+// a URL path segment
+func (c *Config) ValidateProfiles() error {
+	seen := make(map[string]bool, len(c.Profiles))
+	for _, p := range c.Profiles {
+		if p.Name == "" || strings.ContainsAny(p.Name, "/?#%") || p.Name == "." || p.Name == ".." {
+			return fmt.Errorf("profile name %q must be a non-empty URL path segment", p.Name)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("profile %q is defined more than once", p.Name)
+		}
+		seen[p.Name] = true
+	}
+	return nil
+}
+
+// ValidateTenants checks that every tenant has a distinct name, that no
+// identity belongs to two tenants and that pinned profiles exist
+func (c *Config) ValidateTenants() error {
+	names := make(map[string]bool, len(c.Tenants))
+	owners := make(map[string]string)
+	for _, t := range c.Tenants {
+		if t.Name == "" || strings.Contains(t.Name, "/") {
+			return fmt.Errorf("tenant name %q must be non-empty and must not contain '/'", t.Name)
+		}
+		if names[t.Name] {
+			return fmt.Errorf("tenant %q is defined more than once", t.Name)
+		}
+		names[t.Name] = true
+		if len(t.Identities) == 0 {
+			return fmt.Errorf("tenant %q has no identities", t.Name)
+		}
+		for _, id := range t.Identities {
+			if id == "" {
+				return fmt.Errorf("tenant %q lists an empty identity", t.Name)
+			}
+			if owner, ok := owners[id]; ok {
+				return fmt.Errorf("identity %q belongs to both tenant %q and tenant %q", id, owner, t.Name)
+			}
+			owners[id] = t.Name
+		}
+		if _, ok := c.Profile(t.Profile); t.Profile != "" && !ok {
+			return fmt.Errorf("tenant %q uses undefined profile %q", t.Name, t.Profile)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+// internal/config/providers.go
+package config
+
+import "time"
+
+// ProvidersConfig holds settings for providers backed by external services
+type ProvidersConfig struct {
+	GraphQL  []GraphQLConfig `koanf:"graphql"`
+	Tables   TablesConfig    `koanf:"tables"`
+	Docker   DockerConfig    `koanf:"docker"`
+	Calendar CalendarConfig  `koanf:"calendar"`
+	Mail     MailConfig      `koanf:"mail"`
+	Notify   NotifyConfig    `koanf:"notify"`
+	S3       S3Config        `koanf:"s3"`
+	Memory   MemoryConfig    `koanf:"memory"`
+	Issues   IssuesConfig    `koanf:"issues"`
+}
+
+// TablesConfig serves tabular data files as resources and the table.query tool
+type TablesConfig struct {
+	Dir      string `koanf:"dir"`      // searched for .csv, .tsv and .parquet files; empty disables tables
+	PageSize int    `koanf:"pageSize"` // rows per table://name?page=N resource; 0 uses 100
+	MaxRows  int    `koanf:"maxRows"`  // rows loaded per table; 0 uses one million
+}
+
+// DockerConfig serves the containers of a Docker or Podman engine as
+// resources and docker.* tools
+type DockerConfig struct {
+	Socket    string        `koanf:"socket"`    // engine API socket, e.g. /var/run/docker.sock or $XDG_RUNTIME_DIR/podman/podman.sock; empty disables docker
+	Lifecycle bool          `koanf:"lifecycle"` // also serve docker.start, docker.stop and docker.restart
+	LogLines  int           `koanf:"logLines"`  // default lines returned by docker.logs; 0 uses 200
+	Timeout   time.Duration `koanf:"timeout"`   // per API request; 0 uses 30s
+}
+
+// CalendarConfig serves events from ICS files, ICS feeds and CalDAV
+// collections as resources and calendar.* tools
+type CalendarConfig struct {
+	Calendars []CalendarSourceConfig `koanf:"calendars"` // empty disables calendars
+	Upcoming  time.Duration          `koanf:"upcoming"`  // how far ahead events are listed as resources; 0 uses 168h
+	WorkHours string                 `koanf:"workHours"` // HH:MM-HH:MM on weekdays searched by calendar.find_slot; empty uses 09:00-17:00
+	Timezone  string                 `koanf:"timezone"`  // IANA zone for work hours and floating times; empty uses the server's
+	Timeout   time.Duration          `koanf:"timeout"`   // per request to a remote calendar; 0 uses 30s
+}
+
+// CalendarSourceConfig is one calendar; set exactly one of ics and caldav
+type CalendarSourceConfig struct {
+	Name     string `koanf:"name"`     // used in tool arguments and calendar:// URIs
+	ICS      string `koanf:"ics"`      // .ics file path, or http(s) URL of a read-only feed
+	CalDAV   string `koanf:"caldav"`   // URL of a CalDAV calendar collection
+	Username string `koanf:"username"` // may reference environment variables as $VAR
+	Password string `koanf:"password"` // may reference environment variables as $VAR
+	ReadOnly bool   `koanf:"readOnly"` // refuse calendar.create_event
+}
+
+// MailConfig lists recent messages from an IMAP mailbox as resources and
+// sends mail over SMTP with the mail.send tool
+type MailConfig struct {
+	Address  string           `koanf:"address"`  // sender of mail.send, e.g. "Axe <axe@example.com>"
+	Username string           `koanf:"username"` // for both servers; empty uses the address
+	Password SecretRef        `koanf:"password"` // env:NAME, file:PATH or the password itself
+	IMAP     MailServerConfig `koanf:"imap"`     // empty host disables reading mail
+	SMTP     MailServerConfig `koanf:"smtp"`     // empty host disables mail.send
+	Folders  []string         `koanf:"folders"`  // listed as resources; empty uses INBOX
+	Recent   int              `koanf:"recent"`   // messages listed per folder; 0 uses 20
+	Timeout  time.Duration    `koanf:"timeout"`  // per server conversation; 0 uses 30s
+}
+
+// MailServerConfig locates an IMAP or SMTP server
+type MailServerConfig struct {
+	Host     string `koanf:"host"`
+	Port     int    `koanf:"port"`     // 0 uses the standard port for the protocol and security
+	Security string `koanf:"security"` // tls, starttls or none; empty uses tls for IMAP and starttls for SMTP
+}
+
+// NotifyConfig serves notify.slack and notify.webhook, which post messages
+// to the configured endpoints
+type NotifyConfig struct {
+	Slack    []NotifyEndpointConfig `koanf:"slack"`    // Slack incoming webhooks, named by channel
+	Webhooks []NotifyEndpointConfig `koanf:"webhooks"` // other endpoints accepting JSON
+	Limit    int                    `koanf:"limit"`    // messages per endpoint per window; 0 uses 10
+	Window   time.Duration          `koanf:"window"`   // 0 uses 1h
+	Timeout  time.Duration          `koanf:"timeout"`  // per request; 0 uses 10s
+}
+
+// NotifyEndpointConfig is one endpoint messages are posted to
+type NotifyEndpointConfig struct {
+	Name     string            `koanf:"name"`     // chosen by the tool's channel or endpoint argument
+	URL      SecretRef         `koanf:"url"`      // env:NAME, file:PATH or the URL; Slack webhook URLs are credentials
+	Headers  map[string]string `koanf:"headers"`  // values may reference environment variables as $VAR
+	Secret   SecretRef         `koanf:"secret"`   // signs requests with X-Axe-Signature like tool webhooks
+	Template string            `koanf:"template"` // JSON payload with {{message}}, {{title}}, {{level}}, {{endpoint}} and {{time}}
+	Limit    int               `koanf:"limit"`    // overrides notify.limit for this endpoint
+}
+
+// S3Config serves objects in S3-compatible storage as resources with the
+// s3.presign and s3.put tools
+type S3Config struct {
+	Endpoint        string        `koanf:"endpoint"`        // e.g. http://localhost:9000 for MinIO; empty uses AWS in the region
+	Region          string        `koanf:"region"`          // empty uses us-east-1
+	PathStyle       bool          `koanf:"pathStyle"`       // address buckets as endpoint/bucket; most servers other than AWS need it
+	AccessKeyID     string        `koanf:"accessKeyId"`     // may reference environment variables as $VAR; empty sends unsigned requests
+	SecretAccessKey SecretRef     `koanf:"secretAccessKey"` // env:NAME, file:PATH or the key itself
+	SessionToken    SecretRef     `koanf:"sessionToken"`    // for temporary credentials
+	Buckets         []string      `koanf:"buckets"`         // bucket or bucket/prefix served; empty disables s3
+	Writable        bool          `koanf:"writable"`        // serve s3.put and presigned uploads
+	MaxList         int           `koanf:"maxList"`         // entries listed per prefix; 0 uses 1000
+	Timeout         time.Duration `koanf:"timeout"`         // per request; 0 uses 30s
+}
+
+// MemoryConfig serves memory.remember and memory.recall, which keep text in
+// the state store and find it again by similarity
+type MemoryConfig struct {
+	Enabled   bool           `koanf:"enabled"`   // needs storage.path
+	Embedder  EmbedderConfig `koanf:"embedder"`  // how text is turned into vectors
+	ChunkSize int            `koanf:"chunkSize"` // characters per stored chunk; 0 uses 1000
+	Recall    int            `koanf:"recall"`    // results memory.recall returns by default; 0 uses 5
+}
+
+// EmbedderConfig selects the embedder of the memory provider
+type EmbedderConfig struct {
+	Type       string        `koanf:"type"`       // hash (built in, matches words rather than meaning), ollama or openai; empty uses hash
+	URL        string        `koanf:"url"`        // ollama server or OpenAI-compatible API base URL; empty uses http://localhost:11434 or https://api.openai.com/v1
+	Model      string        `koanf:"model"`      // required for ollama and openai
+	APIKey     SecretRef     `koanf:"apiKey"`     // env:NAME, file:PATH or the key itself
+	Dimensions int           `koanf:"dimensions"` // vector size of the hash embedder; 0 uses 512
+	Timeout    time.Duration `koanf:"timeout"`    // per embedding request; 0 uses 30s
+}
+
+// IssuesConfig serves the issues of GitHub, GitLab and Jira projects as
+// resources and issues.* tools
+type IssuesConfig struct {
+	Trackers []IssueTrackerConfig `koanf:"trackers"` // empty disables issues
+	Recent   int                  `koanf:"recent"`   // open issues listed per tracker; 0 uses 20
+	Timeout  time.Duration        `koanf:"timeout"`  // per API request; 0 uses 30s
+}
+
+// IssueTrackerConfig is one project of an issue tracker
+type IssueTrackerConfig struct {
+	Name      string    `koanf:"name"`      // used in tool arguments and issues:// URIs
+	Type      string    `koanf:"type"`      // github, gitlab or jira
+	URL       string    `koanf:"url"`       // API base URL for self-hosted GitHub or GitLab, or the Jira site URL
+	Project   string    `koanf:"project"`   // owner/repo, group/project or a Jira project key
+	Token     SecretRef `koanf:"token"`     // env:NAME, file:PATH or the token itself
+	User      string    `koanf:"user"`      // Jira Cloud account email; empty sends the token as a bearer token
+	IssueType string    `koanf:"issueType"` // Jira issue type of created issues; empty uses Task
+}
+
+// GraphQLConfig exposes whitelisted fields of a GraphQL API as tools
+type GraphQLConfig struct {
+	Name      string            `koanf:"name"`      // tool name prefix and schema resource name
+	Endpoint  string            `koanf:"endpoint"`  // introspected at startup
+	Headers   map[string]string `koanf:"headers"`   // values may reference environment variables as $VAR
+	Queries   []string          `koanf:"queries"`   // query fields served as tools
+	Mutations []string          `koanf:"mutations"` // mutation fields served as tools
+	Depth     int               `koanf:"depth"`     // levels of nested objects selected from results; 0 uses 2
+	Timeout   time.Duration     `koanf:"timeout"`   // per request; 0 uses 30s
+}
+
+// PluginConfig runs an external MCP server over stdio and serves its tools
+// as <name>.<tool>
+type PluginConfig struct {
+	Name    string               `koanf:"name"`    // prefixes the plugin's tools
+	Command []string             `koanf:"command"` // program and arguments
+	Dir     string               `koanf:"dir"`     // working directory; empty uses the server's
+	Env     map[string]SecretRef `koanf:"env"`     // added to the server's environment
+	Timeout time.Duration        `koanf:"timeout"` // for starting and listing tools; 0 uses 30s
+}
+
+// StorageConfig configures the persistent state store shared by providers
+type StorageConfig struct {
+	Path          string        `koanf:"path"`          // bbolt file; empty runs providers without persistence
+	OpenTimeout   time.Duration `koanf:"openTimeout"`   // how long to wait for another process to release the file; 0 uses 1s
+	SweepInterval time.Duration `koanf:"sweepInterval"` // how often expired entries are removed; 0 only sweeps at startup
+}
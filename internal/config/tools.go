@@ -0,0 +1,86 @@
+// internal/config/tools.go
+package config
+
+import "time"
+
+// ToolsConfig holds tool execution settings
+type ToolsConfig struct {
+	SchemaValidator string              `koanf:"schemaValidator"` // draft-07 (gojsonschema) or 2020-12 for $defs, prefixItems and unevaluatedProperties
+	Imports         []ToolImportConfig  `koanf:"imports"`         // function definitions served as tools
+	Webhooks        []WebhookToolConfig `koanf:"webhooks"`        // tools implemented by HTTPS endpoints
+	Schedules       []ScheduleConfig    `koanf:"schedules"`       // tool calls made on cron schedules
+
+	ElicitationTimeout time.Duration `koanf:"elicitationTimeout"` // how long users have to answer a tool's question; 0 uses 5m
+	SamplingTimeout    time.Duration `koanf:"samplingTimeout"`    // how long the client's model has to answer; 0 uses 2m
+	Approval           string        `koanf:"approval"`           // tools annotated as not read-only: run (default), ask (the user, through elicitation) or deny
+
+	Jobs  JobsConfig      `koanf:"jobs"`
+	Cache ToolCacheConfig `koanf:"cache"`
+}
+
+// ToolCacheConfig caches the results of the listed tools, answering
+// repeated calls with identical arguments without running the tool
+type ToolCacheConfig struct {
+	MaxBytes      int64              `koanf:"maxBytes"`      // total cached results; 0 uses 16MiB
+	MaxEntryBytes int64              `koanf:"maxEntryBytes"` // larger results are never cached; 0 uses 1MiB
+	TTL           time.Duration      `koanf:"ttl"`           // for tools without their own; 0 uses 5m
+	Tools         []CachedToolConfig `koanf:"tools"`         // only these tools are cached
+}
+
+// CachedToolConfig opts one tool into result caching
+type CachedToolConfig struct {
+	Name string        `koanf:"name"`
+	TTL  time.Duration `koanf:"ttl"` // 0 uses tools.cache.ttl
+}
+
+// JobsConfig limits the background jobs of async tools
+type JobsConfig struct {
+	MaxRunning int           `koanf:"maxRunning"` // jobs executing at once; 0 uses 4
+	Retention  time.Duration `koanf:"retention"`  // how long finished jobs can be queried; 0 uses 1h
+}
+
+// ScheduleConfig calls a tool on a cron schedule and serves the latest
+// result as the resource schedule://<name>
+type ScheduleConfig struct {
+	Name      string                 `koanf:"name"`
+	Cron      string                 `koanf:"cron"`      // five fields, an @ shorthand such as @hourly, or "@every 10m"
+	Tool      string                 `koanf:"tool"`      // any registered tool
+	Arguments map[string]interface{} `koanf:"arguments"` // passed to the tool on every run
+}
+
+// WebhookConfig describes an HTTPS endpoint that executes tool calls
+type WebhookConfig struct {
+	URL     string            `koanf:"url"`     // receives {"name","arguments"} as a POST
+	Headers map[string]string `koanf:"headers"` // values may reference environment variables as $VAR
+	Secret  string            `koanf:"secret"`  // HMAC-SHA256 signing key, may be $VAR; empty sends unsigned requests
+	Timeout time.Duration     `koanf:"timeout"` // per attempt; 0 uses the tool timeout
+	Retries int               `koanf:"retries"` // extra attempts after network errors, 429 and 5xx
+}
+
+// WebhookToolConfig defines one tool implemented by a webhook
+type WebhookToolConfig struct {
+	Name          string                 `koanf:"name"`
+	Description   string                 `koanf:"description"`
+	InputSchema   map[string]interface{} `koanf:"inputSchema"` // JSON Schema for the arguments; empty accepts any object
+	WebhookConfig `koanf:",squash"`
+}
+
+// ToolImportConfig loads a file of OpenAI-style function definitions and
+// binds every function in it to one executor: a webhook or a command
+type ToolImportConfig struct {
+	File    string        `koanf:"file"` // JSON array of functions or tools, or an object with "tools"/"functions"
+	Webhook WebhookConfig `koanf:"webhook"`
+	Command []string      `koanf:"command"` // run per call with the arguments on stdin
+	Dir     string        `koanf:"dir"`     // working directory for command
+}
+
+// BridgeConfig holds endpoints that expose tools to non-MCP agents
+type BridgeConfig struct {
+	OpenAI struct {
+		Addr      string `koanf:"addr"`      // host:port for /v1/chat/completions; empty disables the bridge
+		Upstream  string `koanf:"upstream"`  // OpenAI-compatible API base URL the chat requests are forwarded to
+		APIKey    string `koanf:"apiKey"`    // upstream key, may be $VAR; empty forwards the caller's Authorization header
+		Token     string `koanf:"token"`     // bearer token callers must present, may be $VAR
+		MaxRounds int    `koanf:"maxRounds"` // tool-call round trips per request
+	} `koanf:"openai"`
+}
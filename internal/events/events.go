@@ -0,0 +1,124 @@
+// internal/events/events.go
+
+// Package events is a small in-process publish/subscribe bus. Subsystems
+// announce what happened (a tool was registered, a session connected, a
+// resource changed) and whoever cares subscribes, so the announcer does not
+// need to know who reacts.
+package events
+
+import (
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Kind names a type of event
+type Kind string
+
+// Events published by the server
+const (
+	ToolRegistered   Kind = "tool.registered"   // Subject is the tool name
+	ToolsChanged     Kind = "tools.changed"     // the tool list changed
+	ToolCalled       Kind = "tool.called"       // Subject is the tool name; Data is the caller's *session.Session, or nil
+	ResourceChanged  Kind = "resource.changed"  // Subject is the resource URI
+	ResourcesChanged Kind = "resources.changed" // the resource list changed
+	PromptsChanged   Kind = "prompts.changed"   // the prompt list changed
+	SessionConnected Kind = "session.connected" // Data is the *session.Session
+	SessionClosed    Kind = "session.closed"    // Data is the *session.Session
+	RequestReceived  Kind = "request.received"  // Subject is the method; Data is the sender's *session.Session, or nil
+	ErrorReplied     Kind = "error.replied"     // Subject is the method; Data is the int error code
+	Shutdown         Kind = "shutdown"          // the server began shutting down
+)
+
+// Event is something that happened
+type Event struct {
+	Kind    Kind
+	Subject string      // what the event is about, when it is named by a string
+	Data    interface{} // kind-specific payload
+	Time    time.Time
+}
+
+// Handler reacts to an event. Handlers run on the publisher's goroutine,
+// so they must be quick; slow work belongs on a goroutine of its own.
+type Handler func(Event)
+
+// subscriber is one registered handler
+type subscriber struct {
+	kind    Kind // empty for every kind
+	handler Handler
+}
+
+// Bus delivers published events to subscribers. The zero value is not
+// usable; create buses with NewBus.
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[*subscriber]struct{}
+	// order keeps delivery in subscription order
+	order []*subscriber
+}
+
+// NewBus creates an empty bus
+func NewBus() *Bus {
+	return &Bus{subs: make(map[*subscriber]struct{})}
+}
+
+// Subscribe calls h for every event of kind, or for every event when kind
+// is empty. The returned function removes the subscription.
+func (b *Bus) Subscribe(kind Kind, h Handler) (unsubscribe func()) {
+	sub := &subscriber{kind: kind, handler: h}
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.order = append(b.order, sub)
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs, sub)
+			for i, s := range b.order {
+				if s == sub {
+					b.order = append(b.order[:i:i], b.order[i+1:]...)
+					break
+				}
+			}
+		})
+	}
+}
+
+// Publish delivers e to its subscribers in subscription order and returns
+// when they have all run. A panicking handler is logged and skipped.
+func (b *Bus) Publish(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	targets := make([]*subscriber, 0, len(b.order))
+	for _, sub := range b.order {
+		if sub.kind == "" || sub.kind == e.Kind {
+			targets = append(targets, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range targets {
+		deliver(sub, e)
+	}
+}
+
+// deliver runs one handler, containing any panic
+func deliver(sub *subscriber, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Event handler panicked",
+				"kind", e.Kind,
+				"subject", e.Subject,
+				"panic", r,
+				"stack", string(debug.Stack()))
+		}
+	}()
+	sub.handler(e)
+}
@@ -0,0 +1,152 @@
+// internal/mcp/elicitation/elicitation.go
+
+// Package elicitation lets tool handlers ask the client's user for
+// structured input mid-call. The server sends elicitation/create with a
+// form schema, pauses the tool's timeout while the user answers, and checks
+// the answer against the schema before handing it back.
+package elicitation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+)
+
+// DefaultTimeout bounds how long the user has to answer
+const DefaultTimeout = 5 * time.Minute
+
+// Errors returned by Elicit
+var (
+	ErrUnsupported = errors.New("client does not support elicitation")
+	ErrTimeout     = errors.New("user did not answer in time")
+)
+
+// timeoutKey carries the answer timeout of a server in a context
+type timeoutKey struct{}
+
+// WithTimeout returns a context whose elicitations give users d to answer;
+// 0 or less keeps the default
+func WithTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey{}, d)
+}
+
+// timeout returns how long users have to answer for the request in ctx
+func timeout(ctx context.Context) time.Duration {
+	if d, ok := ctx.Value(timeoutKey{}).(time.Duration); ok && d > 0 {
+		return d
+	}
+	return DefaultTimeout
+}
+
+// Supported reports whether the client behind ctx can be asked for input
+func Supported(ctx context.Context) bool {
+	sess := session.FromContext(ctx)
+	return sess != nil && sess.Conn() != nil && sess.Supports(session.FeatureElicitation)
+}
+
+// Elicit asks the user for input matching schema and waits for the answer.
+// The result's Action says whether the user accepted, declined or
+// cancelled; accepted content has been validated against schema. When ctx
+// ends or the user takes too long, the request is cancelled on the client.
+func Elicit(ctx context.Context, message string, schema map[string]interface{}) (*protocol.ElicitResult, error) {
+	if !Supported(ctx) {
+		return nil, ErrUnsupported
+	}
+	if err := CheckSchema(schema); err != nil {
+		return nil, err
+	}
+
+	// The user's think time is not the tool's run time
+	resume := manager.PauseTimeout(ctx)
+	defer resume()
+
+	params := protocol.ElicitRequestParams{Message: message, RequestedSchema: schema}
+	result, err := session.Call[protocol.ElicitResult](ctx, session.FromContext(ctx), protocol.MethodElicitationCreate, params,
+		session.WithRequestTimeout(timeout(ctx)),
+		session.WithIDPrefix("elicit"))
+	switch {
+	case errors.Is(err, session.ErrClientTimeout):
+		return nil, ErrTimeout
+	case err != nil && ctx.Err() == nil:
+		return nil, fmt.Errorf("elicitation failed: %w", err)
+	case err != nil:
+		return nil, err
+	}
+
+	switch result.Action {
+	case protocol.ElicitAccept:
+		content, err := json.Marshal(result.Content)
+		if err != nil {
+			return nil, err
+		}
+		if err := (manager.Draft7Validator{}).Validate(schema, content); err != nil {
+			return nil, fmt.Errorf("client returned input that does not match the form: %w", err)
+		}
+	case protocol.ElicitDecline, protocol.ElicitCancel:
+		result.Content = nil
+	default:
+		return nil, fmt.Errorf("client returned unknown elicitation action %q", result.Action)
+	}
+	return result, nil
+}
+
+// CheckSchema reports whether schema is a form clients can render: an
+// object whose properties are strings, numbers, integers, booleans or
+// string enums, without nesting
+func CheckSchema(schema map[string]interface{}) error {
+	if t, _ := schema["type"].(string); t != "object" {
+		return fmt.Errorf("elicitation schema must have type object")
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	if len(properties) == 0 {
+		return fmt.Errorf("elicitation schema must define properties")
+	}
+	for name, raw := range properties {
+		prop, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("elicitation property %q must be a schema object", name)
+		}
+		switch t, _ := prop["type"].(string); t {
+		case "string", "number", "integer", "boolean":
+		default:
+			return fmt.Errorf("elicitation property %q has type %q; only string, number, integer and boolean are allowed", name, t)
+		}
+	}
+	return nil
+}
+
+// approvalSchema is the form shown to users asked to approve a tool call
+var approvalSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []string{"approve"},
+	"properties": map[string]interface{}{
+		"approve": map[string]interface{}{"type": "boolean", "title": "Approve", "description": "Let the tool run with these arguments"},
+	},
+}
+
+// Approve is a manager.Approver that asks the calling client's user
+// whether tool may run with args. Clients without elicitation cannot ask,
+// so their calls are refused.
+func Approve(ctx context.Context, tool protocol.Tool, args json.RawMessage) error {
+	message := fmt.Sprintf("Allow %s to run?", tool.Name)
+	if len(args) > 0 && string(args) != "{}" {
+		message += "\n\nArguments: " + string(args)
+	}
+	result, err := Elicit(ctx, message, approvalSchema)
+	if errors.Is(err, ErrUnsupported) {
+		return fmt.Errorf("%w: the client cannot ask its user", manager.ErrNotApproved)
+	}
+	if err != nil {
+		return err
+	}
+	if approve, _ := result.Content["approve"].(bool); result.Action != protocol.ElicitAccept || !approve {
+		return fmt.Errorf("%w by the user", manager.ErrNotApproved)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// internal/mcp/elicitation/elicitation_test.go
+package elicitation_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/internal/mcp/elicitation"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/pkg/mcptest"
+)
+
+// askTool asks the user for a name and reports the answer or a timeout
+func askTool() mcptest.Option {
+	tool := protocol.Tool{Name: "ask", InputSchema: json.RawMessage(`{"type":"object"}`)}
+	return mcptest.WithTool(tool, func(ctx context.Context, _ json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		result, err := elicitation.Elicit(ctx, "Who are you?", map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}},
+		})
+		if errors.Is(err, elicitation.ErrTimeout) {
+			return protocol.ToolsCallResult{Content: []protocol.Content{protocol.TextContent("timeout")}}, nil
+		}
+		if err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		name, _ := result.Content["name"].(string)
+		return protocol.ToolsCallResult{Content: []protocol.Content{protocol.TextContent(name)}}, nil
+	})
+}
+
+// slowUser connects to a server whose users have timeout to answer, and
+// answers after delay
+func slowUser(t *testing.T, timeout, delay time.Duration) *mcptest.Client {
+	c := mcptest.New(t, askTool(), mcptest.WithServerOptions(func(cfg *config.Config) {
+		cfg.Tools.ElicitationTimeout = timeout
+	}))
+	c.OnRequest(protocol.MethodElicitationCreate, func(json.RawMessage) (interface{}, error) {
+		time.Sleep(delay)
+		return protocol.ElicitResult{Action: protocol.ElicitAccept, Content: map[string]interface{}{"name": "Ada"}}, nil
+	})
+	c.InitializeWith(protocol.InitializeParams{
+		ProtocolVersion: protocol.LatestProtocolVersion,
+		Capabilities:    protocol.ClientCapabilities{Elicitation: &struct{}{}},
+		ClientInfo:      protocol.Implementation{Name: "test", Version: "0"},
+	})
+	return c
+}
+
+func TestTimeoutIsPerServer(t *testing.T) {
+	patient := slowUser(t, time.Minute, 200*time.Millisecond)
+	impatient := slowUser(t, 50*time.Millisecond, 200*time.Millisecond)
+
+	for _, tc := range []struct {
+		name   string
+		client *mcptest.Client
+		want   string
+	}{
+		{"patient", patient, "Ada"},
+		{"impatient", impatient, "timeout"},
+	} {
+		var result protocol.ToolsCallResult
+		tc.client.MustCall(protocol.MethodToolsCall, map[string]interface{}{"name": "ask", "arguments": map[string]interface{}{}}, &result)
+		if len(result.Content) != 1 || result.Content[0].Text != tc.want {
+			t.Errorf("%s server: ask = %+v, want %q", tc.name, result.Content, tc.want)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+// internal/mcp/jobs/jobs.go
+
+// Package jobs runs long tool calls in the background. A tool registered as
+// async answers tools/call at once with a job ID; the work continues after
+// the call returns, so it is not bound by the client's request timeout.
+// Clients follow it with the built-in jobs.status, jobs.result and
+// jobs.cancel tools, and receive progress notifications on the original
+// call's progress token.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/clock"
+	"github.com/dkoosis/axe-handle/internal/mcp/notify"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+)
+
+// Defaults
+const (
+	DefaultMaxRunning = 4
+	DefaultRetention  = time.Hour
+
+	// cancelSettle is how long jobs.cancel waits to report the final status
+	cancelSettle = 100 * time.Millisecond
+)
+
+// Built-in tool names
+const (
+	StatusTool = "jobs.status"
+	ResultTool = "jobs.result"
+	CancelTool = "jobs.cancel"
+)
+
+// Status is the state of a job
+type Status string
+
+// Job states
+const (
+	StatusQueued    Status = "queued"    // waiting for a free slot
+	StatusRunning   Status = "running"   // the handler is executing
+	StatusSucceeded Status = "succeeded" // the handler returned a result
+	StatusFailed    Status = "failed"    // the handler returned an error or an error result
+	StatusCancelled Status = "cancelled" // cancelled by the client or at shutdown
+)
+
+// Done reports whether the job has finished
+func (s Status) Done() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCancelled
+}
+
+// ErrUnknownJob is returned for job IDs that do not exist or have expired
+var ErrUnknownJob = errors.New("unknown or expired job")
+
+// Info describes a job in jobs.status replies
+type Info struct {
+	ID         string     `json:"jobId"`
+	Tool       string     `json:"tool"`
+	Status     Status     `json:"status"`
+	Progress   float64    `json:"progress,omitempty"` // percent, as reported by the handler
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// job is one background tool call
+type job struct {
+	info   Info
+	tenant string // tenant of the client that started it; "" for none
+	owner  string // session key of the client that started it; "" when anonymous
+	cancel context.CancelFunc
+	result protocol.ToolsCallResult
+	done   chan struct{}
+}
+
+// Config configures a Manager
+type Config struct {
+	MaxRunning int           // jobs executing at once; others wait in the queue
+	Retention  time.Duration // how long finished jobs stay queryable
+}
+
+// Manager runs and tracks jobs
+type Manager struct {
+	cfg       Config
+	ctx       context.Context // cancelled at shutdown
+	clock     clock.Clock
+	publisher notify.NotificationPublisher
+	slots     chan struct{}
+
+	mu   sync.Mutex
+	jobs map[string]*job
+
+	builtinsOnce sync.Once
+}
+
+// NewManager creates a manager whose jobs are cancelled when ctx ends
+func NewManager(ctx context.Context, cfg Config, clk clock.Clock, publisher notify.NotificationPublisher) *Manager {
+	if cfg.MaxRunning <= 0 {
+		cfg.MaxRunning = DefaultMaxRunning
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = DefaultRetention
+	}
+	return &Manager{
+		cfg:       cfg,
+		ctx:       ctx,
+		clock:     clk,
+		publisher: publisher,
+		slots:     make(chan struct{}, cfg.MaxRunning),
+		jobs:      make(map[string]*job),
+	}
+}
+
+// Register adds an async tool to m. Calls start a job running handler and
+// return its ID; the first registration also adds the jobs.* tools.
+func (jm *Manager) Register(m *manager.ToolsManager, tool protocol.Tool, handler manager.ToolHandler) {
+	jm.builtinsOnce.Do(func() { jm.registerBuiltins(m) })
+
+	if tool.Description != "" {
+		tool.Description += " "
+	}
+	tool.Description += fmt.Sprintf("Runs in the background: returns a jobId to pass to %s and %s.", StatusTool, ResultTool)
+
+	name := tool.Name
+	m.RegisterTool(tool, func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		info := jm.start(ctx, name, handler, args)
+		return jsonResult(info)
+	})
+}
+
+// start queues a job and returns at once
+func (jm *Manager) start(callCtx context.Context, tool string, handler manager.ToolHandler, args json.RawMessage) Info {
+	// The job outlives the call but keeps its session and progress token
+	ctx, cancel := context.WithCancel(context.WithoutCancel(callCtx))
+	stop := context.AfterFunc(jm.ctx, cancel)
+
+	j := &job{
+		info: Info{
+			ID:        newID(),
+			Tool:      tool,
+			Status:    StatusQueued,
+			CreatedAt: jm.clock.Now(),
+		},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	j.tenant, j.owner = ownerOf(callCtx)
+
+	jm.mu.Lock()
+	jm.prune()
+	jm.jobs[j.info.ID] = j
+	info := j.info
+	jm.mu.Unlock()
+
+	go func() {
+		defer stop()
+		defer cancel()
+		jm.run(ctx, j, handler, args)
+	}()
+
+	slog.Info("Started job", "job", info.ID, "tool", tool)
+	return info
+}
+
+// run waits for a slot, executes the handler and records the outcome
+func (jm *Manager) run(ctx context.Context, j *job, handler manager.ToolHandler, args json.RawMessage) {
+	defer close(j.done)
+
+	select {
+	case jm.slots <- struct{}{}:
+		defer func() { <-jm.slots }()
+	case <-ctx.Done():
+		jm.finish(j, protocol.ToolsCallResult{}, ctx.Err())
+		return
+	}
+
+	jm.mu.Lock()
+	if j.info.Status.Done() {
+		jm.mu.Unlock()
+		return
+	}
+	now := jm.clock.Now()
+	j.info.Status = StatusRunning
+	j.info.StartedAt = &now
+	jm.mu.Unlock()
+
+	progress := make(chan float64, 10)
+	reported := make(chan struct{})
+	go func() {
+		defer close(reported)
+		jm.trackProgress(ctx, j, progress)
+	}()
+
+	result, err := invoke(ctx, handler, args, progress)
+	close(progress)
+	<-reported
+
+	jm.finish(j, result, err)
+}
+
+// invoke calls handler, converting a panic into an error
+func invoke(ctx context.Context, handler manager.ToolHandler, args json.RawMessage, progress chan<- float64) (result protocol.ToolsCallResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Job handler panicked", "panic", r, "stack", string(debug.Stack()))
+			err = fmt.Errorf("job encountered an internal error")
+		}
+	}()
+	return handler(ctx, args, progress)
+}
+
+// trackProgress records the handler's progress and forwards it to the
+// client that started the job
+func (jm *Manager) trackProgress(ctx context.Context, j *job, progress <-chan float64) {
+	token := manager.ProgressToken(ctx)
+	for p := range progress {
+		jm.mu.Lock()
+		j.info.Progress = p
+		jm.mu.Unlock()
+		if token != "" && jm.publisher != nil {
+			jm.publisher.Progress(ctx, token, p, 100)
+		}
+	}
+}
+
+// finish records a job's outcome unless it already has one
+func (jm *Manager) finish(j *job, result protocol.ToolsCallResult, err error) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	if j.info.Status.Done() {
+		return
+	}
+
+	now := jm.clock.Now()
+	j.info.FinishedAt = &now
+	switch {
+	case errors.Is(err, context.Canceled):
+		j.info.Status = StatusCancelled
+		j.info.Error = "job was cancelled"
+	case err != nil:
+		j.info.Status = StatusFailed
+		j.info.Error = err.Error()
+		j.result = protocol.ToolsCallResult{
+			Content: []protocol.Content{protocol.TextContent("Tool execution failed: " + err.Error())},
+			IsError: true,
+		}
+	case result.IsError:
+		j.info.Status = StatusFailed
+		j.result = result
+	default:
+		j.info.Status = StatusSucceeded
+		j.info.Progress = 100
+		j.result = result
+	}
+	slog.Info("Job finished", "job", j.info.ID, "tool", j.info.Tool, "status", j.info.Status)
+}
+
+// Cancel stops a queued or running job. Cancelling a finished job is a no-op.
+func (jm *Manager) Cancel(ctx context.Context, id string) (Info, error) {
+	j, err := jm.lookup(ctx, id)
+	if err != nil {
+		return Info{}, err
+	}
+	j.cancel()
+	// Queued jobs and handlers that honour ctx settle promptly; report the
+	// outcome if it is already known
+	timer := time.NewTimer(cancelSettle)
+	defer timer.Stop()
+	select {
+	case <-j.done:
+	case <-timer.C:
+	}
+	return jm.Status(ctx, id)
+}
+
+// Status returns a snapshot of a job
+func (jm *Manager) Status(ctx context.Context, id string) (Info, error) {
+	j, err := jm.lookup(ctx, id)
+	if err != nil {
+		return Info{}, err
+	}
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	return j.info, nil
+}
+
+// Result returns a finished job's tool result
+func (jm *Manager) Result(ctx context.Context, id string) (protocol.ToolsCallResult, Info, error) {
+	j, err := jm.lookup(ctx, id)
+	if err != nil {
+		return protocol.ToolsCallResult{}, Info{}, err
+	}
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	return j.result, j.info, nil
+}
+
+// lookup finds a job the client in ctx may see: one started in the same
+// tenant under the same session key. Anonymous clients only see jobs
+// started anonymously.
+func (jm *Manager) lookup(ctx context.Context, id string) (*job, error) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	jm.prune()
+
+	j, ok := jm.jobs[id]
+	if !ok {
+		return nil, ErrUnknownJob
+	}
+	if tenant, owner := ownerOf(ctx); tenant != j.tenant || owner != j.owner {
+		return nil, ErrUnknownJob
+	}
+	return j, nil
+}
+
+// ownerOf returns the tenant and session key of the client in ctx; both
+// are "" when it is anonymous
+func ownerOf(ctx context.Context) (tenant, key string) {
+	if sess := session.FromContext(ctx); sess != nil {
+		return sess.Tenant(), sess.Key()
+	}
+	return "", ""
+}
+
+// prune forgets jobs that finished longer ago than the retention period;
+// jm.mu must be held
+func (jm *Manager) prune() {
+	cutoff := jm.clock.Now().Add(-jm.cfg.Retention)
+	for id, j := range jm.jobs {
+		if j.info.FinishedAt != nil && j.info.FinishedAt.Before(cutoff) {
+			delete(jm.jobs, id)
+		}
+	}
+}
+
+// newID returns a random job ID
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
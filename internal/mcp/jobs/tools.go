@@ -0,0 +1,103 @@
+// internal/mcp/jobs/tools.go
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+)
+
+// jobIDSchema is the input schema of the jobs.* tools
+var jobIDSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []string{"jobId"},
+	"properties": map[string]interface{}{
+		"jobId": map[string]interface{}{"type": "string", "description": "ID returned when the job was started"},
+	},
+}
+
+// jobIDArgs are the arguments of the jobs.* tools
+type jobIDArgs struct {
+	JobID string `json:"jobId"`
+}
+
+// registerBuiltins adds the tools clients use to follow jobs
+func (jm *Manager) registerBuiltins(m *manager.ToolsManager) {
+	m.RegisterTool(protocol.Tool{
+		Name:        StatusTool,
+		Description: "Report whether a background job is queued, running or finished, and its progress.",
+		InputSchema: jobIDSchema,
+	}, jm.withJobID(func(ctx context.Context, id string) (protocol.ToolsCallResult, error) {
+		info, err := jm.Status(ctx, id)
+		if err != nil {
+			return errorResult(err), nil
+		}
+		return jsonResult(info)
+	}))
+
+	m.RegisterTool(protocol.Tool{
+		Name:        ResultTool,
+		Description: "Return the result of a finished background job. Fails while the job is still queued or running.",
+		InputSchema: jobIDSchema,
+	}, jm.withJobID(func(ctx context.Context, id string) (protocol.ToolsCallResult, error) {
+		result, info, err := jm.Result(ctx, id)
+		if err != nil {
+			return errorResult(err), nil
+		}
+		if !info.Status.Done() {
+			return protocol.ToolsCallResult{
+				Content: []protocol.Content{protocol.TextContent("Job " + id + " is still " + string(info.Status) + "; check " + StatusTool + " later.")},
+				IsError: true,
+			}, nil
+		}
+		if info.Status == StatusCancelled {
+			return protocol.ToolsCallResult{
+				Content: []protocol.Content{protocol.TextContent("Job " + id + " was cancelled.")},
+				IsError: true,
+			}, nil
+		}
+		return result, nil
+	}))
+
+	m.RegisterTool(protocol.Tool{
+		Name:        CancelTool,
+		Description: "Cancel a queued or running background job.",
+		InputSchema: jobIDSchema,
+	}, jm.withJobID(func(ctx context.Context, id string) (protocol.ToolsCallResult, error) {
+		info, err := jm.Cancel(ctx, id)
+		if err != nil {
+			return errorResult(err), nil
+		}
+		return jsonResult(info)
+	}))
+}
+
+// withJobID adapts a function of a job ID to a tool handler
+func (jm *Manager) withJobID(fn func(ctx context.Context, id string) (protocol.ToolsCallResult, error)) manager.ToolHandler {
+	return func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		var a jobIDArgs
+		if err := json.Unmarshal(args, &a); err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		return fn(ctx, a.JobID)
+	}
+}
+
+// jsonResult returns v as indented JSON text
+func jsonResult(v interface{}) (protocol.ToolsCallResult, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return protocol.ToolsCallResult{}, err
+	}
+	return protocol.ToolsCallResult{Content: []protocol.Content{protocol.TextContent(string(data))}}, nil
+}
+
+// errorResult reports err to the model as a tool error
+func errorResult(err error) protocol.ToolsCallResult {
+	return protocol.ToolsCallResult{
+		Content: []protocol.Content{protocol.TextContent(err.Error())},
+		IsError: true,
+	}
+}
@@ -0,0 +1,42 @@
+// internal/mcp/notify/notify.go
+
+// Package notify defines how providers push notifications to clients.
+// Providers never see sessions: the server's publisher fans each
+// notification out to the sessions it concerns and drops it for clients
+// that did not negotiate the feature.
+package notify
+
+import (
+	"context"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+)
+
+// NotificationPublisher sends provider notifications to clients
+type NotificationPublisher interface {
+	// ResourceUpdated tells clients subscribed to uri that it changed
+	ResourceUpdated(uri string)
+
+	// ResourcesChanged tells clients the resource list changed
+	ResourcesChanged()
+
+	// ToolsChanged tells clients the tool list changed
+	ToolsChanged()
+
+	// PromptsChanged tells clients the prompt list changed
+	PromptsChanged()
+
+	// Log sends a log message to the client of the request in ctx, or to
+	// every client when ctx carries none. Each client's level filter applies.
+	Log(ctx context.Context, level protocol.LoggingLevel, logger string, data interface{})
+
+	// Progress reports progress on the request in ctx that carried token.
+	// Total is 0 when unknown.
+	Progress(ctx context.Context, token string, progress, total float64)
+}
+
+// PublisherAware is implemented by providers that push notifications. The
+// server hands them its publisher when they are registered.
+type PublisherAware interface {
+	SetNotificationPublisher(p NotificationPublisher)
+}
@@ -0,0 +1,317 @@
+// internal/mcp/openrpc/openrpc.go
+
+// Package openrpc describes the server's JSON-RPC surface as an OpenRPC 1.3
+// document, so API gateways and documentation tooling can introspect it.
+// Registered tools are included: each input schema is a component that the
+// arguments of tools/call refer to.
+package openrpc
+
+import (
+	"sort"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/pkg/mcperrors"
+)
+
+// Version is the OpenRPC specification version documents conform to
+const Version = "1.3.2"
+
+// Document is an OpenRPC document
+type Document struct {
+	OpenRPC    string     `json:"openrpc"`
+	Info       Info       `json:"info"`
+	Methods    []Method   `json:"methods"`
+	Components Components `json:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Method describes one request or notification method
+type Method struct {
+	Name           string              `json:"name"`
+	Summary        string              `json:"summary,omitempty"`
+	ParamStructure string              `json:"paramStructure,omitempty"`
+	Params         []ContentDescriptor `json:"params"`
+	Result         *ContentDescriptor  `json:"result,omitempty"` // omitted for notifications
+	Errors         []Error             `json:"errors,omitempty"`
+}
+
+// ContentDescriptor names and types a parameter or result
+type ContentDescriptor struct {
+	Name     string      `json:"name"`
+	Summary  string      `json:"summary,omitempty"`
+	Required bool        `json:"required,omitempty"`
+	Schema   interface{} `json:"schema"`
+}
+
+// Error is an application error a method may return
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Components holds schemas referenced from methods
+type Components struct {
+	Schemas map[string]interface{} `json:"schemas"`
+}
+
+// schema is shorthand for a JSON Schema object
+type schema = map[string]interface{}
+
+// ref points at a component schema
+func ref(name string) schema {
+	return schema{"$ref": "#/components/schemas/" + name}
+}
+
+// object builds an object schema from property schemas
+func object(props schema, required ...string) schema {
+	s := schema{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// param describes a by-name parameter
+func param(name string, s interface{}, required bool) ContentDescriptor {
+	return ContentDescriptor{Name: name, Schema: s, Required: required}
+}
+
+// result describes a method result
+func result(name string, s interface{}) *ContentDescriptor {
+	return &ContentDescriptor{Name: name, Schema: s}
+}
+
+// errorsOf converts mcperrors codes to OpenRPC errors
+func errorsOf(codes ...mcperrors.ErrorCode) []Error {
+	out := make([]Error, len(codes))
+	for i, ec := range codes {
+		out[i] = Error{Code: ec.Code, Message: ec.Message}
+	}
+	return out
+}
+
+// toolSchemaName is the component name holding a tool's input schema
+func toolSchemaName(tool string) string {
+	return "tool." + tool
+}
+
+// Generate describes the MCP methods the server handles plus the given tools
+func Generate(info Info, tools []protocol.Tool) Document {
+	tools = append([]protocol.Tool(nil), tools...)
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+
+	components := Components{Schemas: map[string]interface{}{
+		"Implementation": object(schema{
+			"name":    schema{"type": "string"},
+			"version": schema{"type": "string"},
+		}, "name", "version"),
+		"Content": object(schema{
+			"type":     schema{"enum": []string{protocol.ContentText, protocol.ContentImage, protocol.ContentAudio, protocol.ContentResource}},
+			"text":     schema{"type": "string"},
+			"data":     schema{"type": "string", "contentEncoding": "base64"},
+			"mimeType": schema{"type": "string"},
+			"resource": ref("ResourceContents"),
+		}, "type"),
+		"ResourceContents": object(schema{
+			"uri":      schema{"type": "string"},
+			"mimeType": schema{"type": "string"},
+			"text":     schema{"type": "string"},
+			"blob":     schema{"type": "string", "contentEncoding": "base64"},
+			"size":     schema{"type": "integer"},
+		}, "uri"),
+		"Tool": object(schema{
+			"name":        schema{"type": "string"},
+			"description": schema{"type": "string"},
+			"inputSchema": schema{"type": "object"},
+		}, "name", "inputSchema"),
+		"Resource": object(schema{
+			"uri":         schema{"type": "string"},
+			"name":        schema{"type": "string"},
+			"description": schema{"type": "string"},
+			"mimeType":    schema{"type": "string"},
+		}, "uri", "name"),
+		"Prompt": object(schema{
+			"name":        schema{"type": "string"},
+			"description": schema{"type": "string"},
+			"arguments":   schema{"type": "array", "items": schema{"type": "object"}},
+		}, "name"),
+		"ToolsCallResult": object(schema{
+			"content": schema{"type": "array", "items": ref("Content")},
+			"isError": schema{"type": "boolean"},
+		}, "content"),
+	}}
+
+	// Each tool's input schema becomes a component tools/call can refer to
+	for _, tool := range tools {
+		components.Schemas[toolSchemaName(tool.Name)] = tool.InputSchema
+	}
+	toolNameSchema := schema{"type": "string"}
+	if len(tools) > 0 {
+		names := make([]string, len(tools))
+		for i, tool := range tools {
+			names[i] = tool.Name
+		}
+		toolNameSchema = schema{"enum": names}
+	}
+
+	listParams := []ContentDescriptor{param("cursor", schema{"type": "string"}, false)}
+	uriParam := param("uri", schema{"type": "string"}, true)
+
+	methods := []Method{
+		{
+			Name:    protocol.MethodInitialize,
+			Summary: "Negotiate the protocol version and exchange capabilities",
+			Params: []ContentDescriptor{
+				param("protocolVersion", schema{"type": "string"}, true),
+				param("capabilities", schema{"type": "object"}, true),
+				param("clientInfo", ref("Implementation"), true),
+			},
+			Result: result("InitializeResult", object(schema{
+				"protocolVersion": schema{"type": "string"},
+				"capabilities":    schema{"type": "object"},
+				"serverInfo":      ref("Implementation"),
+				"instructions":    schema{"type": "string"},
+			}, "protocolVersion", "capabilities", "serverInfo")),
+			Errors: errorsOf(mcperrors.ErrInvalidParams),
+		},
+		{
+			Name:    protocol.MethodPing,
+			Summary: "Check that the server is responsive",
+			Params:  []ContentDescriptor{},
+			Result:  result("EmptyResult", schema{"type": "object"}),
+		},
+		{
+			Name:    protocol.MethodToolsList,
+			Summary: "List the tools the server offers",
+			Params:  listParams,
+			Result: result("ToolsListResult", object(schema{
+				"tools":      schema{"type": "array", "items": ref("Tool")},
+				"nextCursor": schema{"type": "string"},
+			}, "tools")),
+		},
+		{
+			Name:    protocol.MethodToolsCall,
+			Summary: "Invoke a tool",
+			Params: []ContentDescriptor{
+				param("name", toolNameSchema, true),
+				param("arguments", toolArgumentsSchema(tools), false),
+			},
+			Result: result("ToolsCallResult", ref("ToolsCallResult")),
+			Errors: errorsOf(mcperrors.ErrInvalidParams, mcperrors.ErrToolNotFound, mcperrors.ErrRequestTimeout, mcperrors.ErrRateLimited),
+		},
+		{
+			Name:    protocol.MethodResourcesList,
+			Summary: "List available resources, one page at a time",
+			Params:  listParams,
+			Result: result("ResourcesListResult", object(schema{
+				"resources":  schema{"type": "array", "items": ref("Resource")},
+				"nextCursor": schema{"type": "string"},
+			}, "resources")),
+			Errors: errorsOf(mcperrors.ErrInvalidParams),
+		},
+		{
+			Name:    protocol.MethodResourcesRead,
+			Summary: "Read a resource, optionally a byte range of it",
+			Params: []ContentDescriptor{
+				uriParam,
+				param("offset", schema{"type": "integer", "minimum": 0}, false),
+				param("length", schema{"type": "integer", "minimum": 0}, false),
+			},
+			Result: result("ResourcesReadResult", object(schema{
+				"contents": schema{"type": "array", "items": ref("ResourceContents")},
+				"_meta":    schema{"type": "object"},
+			}, "contents")),
+			Errors: errorsOf(mcperrors.ErrInvalidParams, mcperrors.ErrResourceNotFound, mcperrors.ErrRateLimited),
+		},
+		{
+			Name:    protocol.MethodResourcesSubscribe,
+			Summary: "Receive notifications when a resource changes",
+			Params:  []ContentDescriptor{uriParam},
+			Result:  result("EmptyResult", schema{"type": "object"}),
+			Errors:  errorsOf(mcperrors.ErrInvalidParams, mcperrors.ErrResourceNotFound),
+		},
+		{
+			Name:    protocol.MethodResourcesUnsubscribe,
+			Summary: "Stop notifications for a resource",
+			Params:  []ContentDescriptor{uriParam},
+			Result:  result("EmptyResult", schema{"type": "object"}),
+			Errors:  errorsOf(mcperrors.ErrInvalidParams),
+		},
+		{
+			Name:    protocol.MethodPromptsList,
+			Summary: "List available prompts",
+			Params:  listParams,
+			Result: result("PromptsListResult", object(schema{
+				"prompts": schema{"type": "array", "items": ref("Prompt")},
+			}, "prompts")),
+		},
+		{
+			Name:    protocol.MethodPromptsGet,
+			Summary: "Render a prompt with arguments",
+			Params: []ContentDescriptor{
+				param("name", schema{"type": "string"}, true),
+				param("arguments", schema{"type": "object", "additionalProperties": schema{"type": "string"}}, false),
+			},
+			Result: result("PromptsGetResult", object(schema{
+				"description": schema{"type": "string"},
+				"messages":    schema{"type": "array", "items": schema{"type": "object"}},
+			}, "messages")),
+			Errors: errorsOf(mcperrors.ErrInvalidParams, mcperrors.ErrPromptNotFound),
+		},
+		{
+			Name:    protocol.MethodLoggingSetLevel,
+			Summary: "Set the minimum level of log notifications sent to this client",
+			Params: []ContentDescriptor{
+				param("level", schema{"enum": []protocol.LoggingLevel{
+					protocol.LoggingLevelDebug, protocol.LoggingLevelInfo, protocol.LoggingLevelNotice,
+					protocol.LoggingLevelWarning, protocol.LoggingLevelError, protocol.LoggingLevelCritical,
+					protocol.LoggingLevelAlert, protocol.LoggingLevelEmergency,
+				}}, true),
+			},
+			Result: result("EmptyResult", schema{"type": "object"}),
+			Errors: errorsOf(mcperrors.ErrInvalidParams),
+		},
+		{
+			Name:    protocol.NotificationInitialized,
+			Summary: "Notification: the client has finished initialization",
+			Params:  []ContentDescriptor{},
+		},
+		{
+			Name:    protocol.NotificationRootsListChanged,
+			Summary: "Notification: the client's roots have changed",
+			Params:  []ContentDescriptor{},
+		},
+	}
+	for i := range methods {
+		methods[i].ParamStructure = "by-name"
+	}
+
+	return Document{
+		OpenRPC:    Version,
+		Info:       info,
+		Methods:    methods,
+		Components: components,
+	}
+}
+
+// toolArgumentsSchema accepts the arguments of any registered tool. Which
+// branch applies depends on the name parameter, which OpenRPC cannot relate
+// to a sibling parameter, so x-tools maps each name to its schema as well.
+func toolArgumentsSchema(tools []protocol.Tool) schema {
+	if len(tools) == 0 {
+		return schema{"type": "object"}
+	}
+	anyOf := make([]interface{}, len(tools))
+	byName := make(schema, len(tools))
+	for i, tool := range tools {
+		anyOf[i] = ref(toolSchemaName(tool.Name))
+		byName[tool.Name] = ref(toolSchemaName(tool.Name))
+	}
+	return schema{"anyOf": anyOf, "x-tools": byName}
+}
@@ -0,0 +1,190 @@
+// internal/mcp/prompts/api/prompts.go
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/prompts"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/pkg/mcperrors"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// ServerHandler provides an interface to the main server functionality
+type ServerHandler interface {
+	CheckInitialized() error
+	ListPrompts(ctx context.Context) ([]protocol.Prompt, error)
+	GetPrompt(ctx context.Context, name string, args map[string]string) (interface{}, error)
+	ReadResource(ctx context.Context, params protocol.ResourcesReadParams) (*protocol.ResourcesReadResult, error)
+}
+
+// ErrorSender replies to req with err
+type ErrorSender func(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request, err error)
+
+// PromptsHandler handles prompts-related requests
+type PromptsHandler struct {
+	server    ServerHandler
+	sendError ErrorSender
+}
+
+// NewPromptsHandler creates a new prompts handler
+func NewPromptsHandler(server ServerHandler, sendError ErrorSender) *PromptsHandler {
+	return &PromptsHandler{
+		server:    server,
+		sendError: sendError,
+	}
+}
+
+// HandlePromptsList handles the prompts/list request
+func (h *PromptsHandler) HandlePromptsList(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request) {
+	if err := h.server.CheckInitialized(); err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	list, err := h.server.ListPrompts(ctx)
+	if err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	if err := conn.Reply(ctx, req.ID, protocol.PromptsListResult{Prompts: list}); err != nil {
+		slog.Error("Failed to send prompts list response", "error", err)
+	}
+}
+
+// HandlePromptsGet handles the prompts/get request. Arguments are checked
+// against the prompt's declared arguments before the provider sees them.
+func (h *PromptsHandler) HandlePromptsGet(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request) {
+	var params protocol.PromptsGetParams
+	if req.Params == nil {
+		h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(fmt.Errorf("missing params")))
+		return
+	}
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(err))
+		return
+	}
+	if params.Name == "" {
+		h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(fmt.Errorf("missing name")))
+		return
+	}
+
+	if err := h.server.CheckInitialized(); err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	prompt, err := h.findPrompt(ctx, params.Name)
+	if err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+	args, err := validateArguments(prompt, params.Arguments)
+	if err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	result, err := h.server.GetPrompt(ctx, params.Name, args)
+	if err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+	result, err = h.render(ctx, result)
+	if err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	if err := conn.Reply(ctx, req.ID, result); err != nil {
+		slog.Error("Failed to send prompt response", "prompt", params.Name, "error", err)
+	}
+}
+
+// findPrompt returns the declaration of the named prompt
+func (h *PromptsHandler) findPrompt(ctx context.Context, name string) (protocol.Prompt, error) {
+	list, err := h.server.ListPrompts(ctx)
+	if err != nil {
+		return protocol.Prompt{}, err
+	}
+	for _, p := range list {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return protocol.Prompt{}, fmt.Errorf("%w: %s", prompts.ErrPromptNotFound, name)
+}
+
+// validateArguments checks args against the prompt's declared arguments.
+// Missing required arguments are rejected; undeclared ones are logged and
+// dropped so providers only ever see arguments they declared.
+func validateArguments(prompt protocol.Prompt, args map[string]string) (map[string]string, error) {
+	declared := make(map[string]bool, len(prompt.Arguments))
+	for _, arg := range prompt.Arguments {
+		declared[arg.Name] = true
+		if _, ok := args[arg.Name]; arg.Required && !ok {
+			return nil, fmt.Errorf("%w: %s requires %q", prompts.ErrMissingArgument, prompt.Name, arg.Name)
+		}
+	}
+
+	valid := make(map[string]string, len(args))
+	for name, value := range args {
+		if !declared[name] {
+			slog.Warn("Ignoring undeclared prompt argument", "prompt", prompt.Name, "argument", name)
+			continue
+		}
+		valid[name] = value
+	}
+	return valid, nil
+}
+
+// render normalizes typed provider results into a prompts/get result,
+// checks their messages and fills in embedded resources that providers left
+// as bare references (a resource content with only a URI) by reading them
+// through the server. Untyped results are passed through unchanged.
+func (h *PromptsHandler) render(ctx context.Context, result interface{}) (interface{}, error) {
+	var rendered protocol.PromptsGetResult
+	switch r := result.(type) {
+	case protocol.PromptsGetResult:
+		rendered = r
+	case *protocol.PromptsGetResult:
+		rendered = *r
+	case []protocol.PromptMessage:
+		rendered.Messages = r
+	default:
+		return result, nil
+	}
+	if err := rendered.Validate(); err != nil {
+		return nil, fmt.Errorf("provider returned an invalid prompt: %w", err)
+	}
+
+	// Copy the messages; providers may hand out shared templates
+	messages := make([]protocol.PromptMessage, len(rendered.Messages))
+	copy(messages, rendered.Messages)
+	for i, msg := range messages {
+		ref := msg.Content.Resource
+		if msg.Content.Type != protocol.ContentResource || ref == nil || ref.Text != "" || ref.Blob != "" {
+			continue
+		}
+
+		read, err := h.server.ReadResource(ctx, protocol.ResourcesReadParams{URI: ref.URI})
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve prompt resource %s: %w", ref.URI, err)
+		}
+		if len(read.Contents) == 0 {
+			return nil, fmt.Errorf("failed to resolve prompt resource %s: no content", ref.URI)
+		}
+		if read.Meta != nil && read.Meta.Chunk != nil && read.Meta.Chunk.Truncated {
+			slog.Warn("Embedded prompt resource truncated", "uri", ref.URI, "size", read.Meta.Chunk.TotalSize)
+		}
+
+		contents := read.Contents[0]
+		messages[i].Content.Resource = &contents
+	}
+	rendered.Messages = messages
+	return rendered, nil
+}
@@ -1,9 +1,22 @@
 // internal/mcp/prompts/errors.go
 package prompts
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/dkoosis/axe-handle/pkg/mcperrors"
+)
 
 var (
 	// ErrPromptNotFound is returned when a requested prompt cannot be found
 	ErrPromptNotFound = errors.New("prompt not found")
+
+	// ErrMissingArgument is returned when a required prompt argument is absent
+	ErrMissingArgument = errors.New("missing required prompt argument")
 )
+
+// init maps this package's sentinel errors to their JSON-RPC error codes
+func init() {
+	mcperrors.RegisterMapping(ErrPromptNotFound, mcperrors.ErrPromptNotFound)
+	mcperrors.RegisterMapping(ErrMissingArgument, mcperrors.ErrInvalidParams)
+}
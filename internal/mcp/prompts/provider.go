@@ -20,6 +20,8 @@ type Provider interface {
 	// ListPrompts returns a list of available prompts
 	ListPrompts() ([]Prompt, error)
 
-	// GetPrompt returns a prompt template with the given arguments
+	// GetPrompt returns a prompt template with the given arguments. A
+	// protocol.PromptsGetResult may embed resources by URI alone; the server
+	// reads them through the resource providers before replying.
 	GetPrompt(name string, args map[string]string) (interface{}, error)
 }
@@ -0,0 +1,43 @@
+// internal/mcp/protocol/completion.go
+package protocol
+
+// MaxCompletionValues is the most values a completion result may carry
+const MaxCompletionValues = 100
+
+// Completion reference types
+const (
+	RefResource = "ref/resource" // a resource template, by URI template
+	RefPrompt   = "ref/prompt"   // a prompt, by name
+)
+
+// CompletionRef identifies what is being completed
+type CompletionRef struct {
+	Type string `json:"type"`
+	URI  string `json:"uri,omitempty"`  // for ref/resource
+	Name string `json:"name,omitempty"` // for ref/prompt
+}
+
+// CompletionArgument is the variable being completed and its partial value
+type CompletionArgument struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CompleteParams represents parameters for the completion/complete request
+type CompleteParams struct {
+	Ref      CompletionRef      `json:"ref"`
+	Argument CompletionArgument `json:"argument"`
+}
+
+// Completion holds ranked suggestions. Total counts every match and HasMore
+// is set when Values was cut to MaxCompletionValues.
+type Completion struct {
+	Values  []string `json:"values"`
+	Total   int      `json:"total,omitempty"`
+	HasMore bool     `json:"hasMore,omitempty"`
+}
+
+// CompleteResult represents the result of a completion/complete request
+type CompleteResult struct {
+	Completion Completion `json:"completion"`
+}
@@ -0,0 +1,152 @@
+// internal/mcp/protocol/downgrade.go
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// Downgrader is implemented by results that can hold constructs newer than
+// some protocol versions. Downgrade returns the result as a client that
+// negotiated version understands: newer fields are dropped or translated,
+// never sent to a client that may choke on them.
+type Downgrader interface {
+	Downgrade(version string) interface{}
+}
+
+// Downgrade returns result for a client that negotiated version. Results
+// for current clients, or before a version is negotiated, are unchanged.
+func Downgrade(version string, result interface{}) interface{} {
+	d, ok := result.(Downgrader)
+	if !ok || version == "" || !before(version, LatestProtocolVersion) {
+		return result
+	}
+	return d.Downgrade(version)
+}
+
+// before reports whether version predates since
+func before(version, since string) bool {
+	return version < since
+}
+
+// downgradingConn downgrades every result it replies with
+type downgradingConn struct {
+	ResponderConn
+	version string
+}
+
+// NewDowngradingConn returns conn for a client that negotiated version,
+// downgrading the results replied through it. Results that are already
+// encoded, such as codec.Raw ones, pass unchanged, so Downgrade them
+// before encoding.
+func NewDowngradingConn(conn ResponderConn, version string) ResponderConn {
+	if version == "" || !before(version, LatestProtocolVersion) {
+		return conn
+	}
+	return downgradingConn{ResponderConn: conn, version: version}
+}
+
+// Reply implements ResponderConn
+func (c downgradingConn) Reply(ctx context.Context, id jsonrpc2.ID, result interface{}) error {
+	return c.ResponderConn.Reply(ctx, id, Downgrade(c.version, result))
+}
+
+// Downgrade implements Downgrader. Structured content is new in 2025-06-18;
+// older clients get it as JSON text when the tool sent no text of its own,
+// as the spec asks tools to do anyway.
+func (r ToolsCallResult) Downgrade(version string) interface{} {
+	if before(version, ProtocolVersion20250618) && r.StructuredContent != nil {
+		if len(r.Content) == 0 {
+			if data, err := json.Marshal(r.StructuredContent); err == nil {
+				r.Content = []Content{TextContent(string(data))}
+			}
+		}
+		r.StructuredContent = nil
+	}
+	r.Content = DowngradeContent(version, r.Content)
+	return r
+}
+
+// Downgrade implements Downgrader
+func (r PromptsGetResult) Downgrade(version string) interface{} {
+	messages := make([]PromptMessage, len(r.Messages))
+	for i, msg := range r.Messages {
+		msg.Content = downgradeContent(version, msg.Content)
+		messages[i] = msg
+	}
+	r.Messages = messages
+	return r
+}
+
+// Downgrade implements Downgrader
+func (r ResourcesListResult) Downgrade(version string) interface{} {
+	list := make([]Resource, len(r.Resources))
+	for i, res := range r.Resources {
+		res.Annotations = DowngradeAnnotations(version, res.Annotations)
+		list[i] = res
+	}
+	r.Resources = list
+	return r
+}
+
+// Downgrade implements Downgrader
+func (r ResourceTemplatesListResult) Downgrade(version string) interface{} {
+	list := make([]ResourceTemplate, len(r.ResourceTemplates))
+	for i, t := range r.ResourceTemplates {
+		t.Annotations = DowngradeAnnotations(version, t.Annotations)
+		list[i] = t
+	}
+	r.ResourceTemplates = list
+	return r
+}
+
+// DowngradeTool returns tool for a client that negotiated version. Tool
+// annotations are new in 2025-03-26 and output schemas in 2025-06-18.
+func DowngradeTool(version string, tool Tool) Tool {
+	if before(version, ProtocolVersion20250326) {
+		tool.Annotations = nil
+	}
+	if before(version, ProtocolVersion20250618) {
+		tool.OutputSchema = nil
+	}
+	return tool
+}
+
+// DowngradeContent returns a copy of content for a client that negotiated
+// version
+func DowngradeContent(version string, content []Content) []Content {
+	if content == nil {
+		return nil
+	}
+	out := make([]Content, len(content))
+	for i, c := range content {
+		out[i] = downgradeContent(version, c)
+	}
+	return out
+}
+
+// downgradeContent translates one piece of content. Audio is new in
+// 2025-03-26; older clients are told what was left out instead.
+func downgradeContent(version string, c Content) Content {
+	if c.Type == ContentAudio && before(version, ProtocolVersion20250326) {
+		return TextContent(fmt.Sprintf("[%s audio omitted]", c.MimeType))
+	}
+	c.Annotations = DowngradeAnnotations(version, c.Annotations)
+	return c
+}
+
+// DowngradeAnnotations returns a for a client that negotiated version.
+// lastModified is new in 2025-06-18; audience and priority are as old as
+// the protocol.
+func DowngradeAnnotations(version string, a *Annotations) *Annotations {
+	if a == nil || a.LastModified == nil || !before(version, ProtocolVersion20250618) {
+		return a
+	}
+	if len(a.Audience) == 0 && a.Priority == nil {
+		return nil
+	}
+	return &Annotations{Audience: a.Audience, Priority: a.Priority}
+}
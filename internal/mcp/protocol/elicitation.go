@@ -0,0 +1,29 @@
+// internal/mcp/protocol/elicitation.go
+package protocol
+
+// Elicitation response actions
+const (
+	ElicitAccept  = "accept"  // the user submitted the form
+	ElicitDecline = "decline" // the user explicitly refused
+	ElicitCancel  = "cancel"  // the user dismissed the request without choosing
+)
+
+// ElicitRequestParams asks the client's user for structured input. The
+// schema is a flat object whose properties are strings, numbers, integers,
+// booleans or string enums.
+type ElicitRequestParams struct {
+	Message         string                 `json:"message"`
+	RequestedSchema map[string]interface{} `json:"requestedSchema"`
+}
+
+// ElicitResult is the client's answer to elicitation/create
+type ElicitResult struct {
+	Action  string                 `json:"action"`
+	Content map[string]interface{} `json:"content,omitempty"` // present when Action is accept
+}
+
+// CancelledParams tells the other side to stop working on a request
+type CancelledParams struct {
+	RequestID interface{} `json:"requestId"`
+	Reason    string      `json:"reason,omitempty"`
+}
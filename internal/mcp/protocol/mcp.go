@@ -2,17 +2,42 @@
 package protocol
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/dkoosis/axe-handle/pkg/mcperrors"
 	"github.com/sourcegraph/jsonrpc2"
 )
 
+// Protocol versions the server speaks. Versions are dates, so they order
+// as strings.
 const (
+	ProtocolVersion20241105 = "2024-11-05"
+	ProtocolVersion20250326 = "2025-03-26" // adds tool annotations and audio content
+	ProtocolVersion20250618 = "2025-06-18" // adds structured tool output and lastModified
+
 	// Latest protocol version
-	LatestProtocolVersion = "2024-11-05"
+	LatestProtocolVersion = ProtocolVersion20250618
 )
 
+// SupportedProtocolVersions are the versions clients may negotiate, newest
+// first. Results are downgraded for clients on older ones.
+var SupportedProtocolVersions = []string{
+	ProtocolVersion20250618,
+	ProtocolVersion20250326,
+	ProtocolVersion20241105,
+}
+
+// SupportsProtocolVersion reports whether clients may negotiate version
+func SupportsProtocolVersion(version string) bool {
+	for _, v := range SupportedProtocolVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
 // Implementation describes the name and version of an MCP implementation
 type Implementation struct {
 	Name    string `json:"name"`
@@ -25,8 +50,9 @@ type ClientCapabilities struct {
 	Roots        *struct {
 		ListChanged bool `json:"listChanged,omitempty"`
 	} `json:"roots,omitempty"`
-	Sampling *struct{} `json:"sampling,omitempty"`
-	Logging  *struct{} `json:"logging,omitempty"`
+	Sampling    *struct{} `json:"sampling,omitempty"`
+	Elicitation *struct{} `json:"elicitation,omitempty"`
+	Logging     *struct{} `json:"logging,omitempty"`
 }
 
 // ServerCapabilities represents capabilities that a server may support
@@ -43,18 +69,27 @@ type ServerCapabilities struct {
 	Tools *struct {
 		ListChanged bool `json:"listChanged,omitempty"`
 	} `json:"tools,omitempty"`
+	Completions *struct{} `json:"completions,omitempty"`
 }
 
 // MCP request method names
 const (
-	MethodInitialize    = "initialize"
-	MethodPing          = "ping"
-	MethodToolsList     = "tools/list"
-	MethodToolsCall     = "tools/call"
-	MethodResourcesList = "resources/list"
-	MethodResourcesRead = "resources/read"
-	MethodPromptsList   = "prompts/list"
-	MethodPromptsGet    = "prompts/get"
+	MethodInitialize           = "initialize"
+	MethodPing                 = "ping"
+	MethodToolsList            = "tools/list"
+	MethodToolsCall            = "tools/call"
+	MethodResourcesList        = "resources/list"
+	MethodResourcesRead        = "resources/read"
+	MethodResourcesSubscribe   = "resources/subscribe"
+	MethodResourcesUnsubscribe = "resources/unsubscribe"
+	MethodResourcesTemplates   = "resources/templates/list"
+	MethodPromptsList          = "prompts/list"
+	MethodPromptsGet           = "prompts/get"
+	MethodLoggingSetLevel      = "logging/setLevel"
+	MethodCompletionComplete   = "completion/complete"
+	MethodRootsList            = "roots/list"             // sent by the server to the client
+	MethodElicitationCreate    = "elicitation/create"     // sent by the server to the client
+	MethodSamplingCreate       = "sampling/createMessage" // sent by the server to the client
 )
 
 // MCP notification method names
@@ -66,6 +101,8 @@ const (
 	NotificationToolsListChanged     = "notifications/tools/list_changed"
 	NotificationPromptsListChanged   = "notifications/prompts/list_changed"
 	NotificationLoggingMessage       = "notifications/message"
+	NotificationRootsListChanged     = "notifications/roots/list_changed"
+	NotificationCancelled            = "notifications/cancelled"
 )
 
 // LoggingLevel defines the level of log message
@@ -83,6 +120,34 @@ const (
 	LoggingLevelEmergency LoggingLevel = "emergency"
 )
 
+// loggingLevelSeverity orders logging levels from least to most severe (RFC 5424)
+var loggingLevelSeverity = map[LoggingLevel]int{
+	LoggingLevelDebug:     0,
+	LoggingLevelInfo:      1,
+	LoggingLevelNotice:    2,
+	LoggingLevelWarning:   3,
+	LoggingLevelError:     4,
+	LoggingLevelCritical:  5,
+	LoggingLevelAlert:     6,
+	LoggingLevelEmergency: 7,
+}
+
+// Valid reports whether l is one of the defined logging levels
+func (l LoggingLevel) Valid() bool {
+	_, ok := loggingLevelSeverity[l]
+	return ok
+}
+
+// AtLeast reports whether l is as severe as or more severe than min
+func (l LoggingLevel) AtLeast(min LoggingLevel) bool {
+	return loggingLevelSeverity[l] >= loggingLevelSeverity[min]
+}
+
+// SetLevelParams represents parameters for the logging/setLevel request
+type SetLevelParams struct {
+	Level LoggingLevel `json:"level"`
+}
+
 // LoggingMessageParams represents parameters for a logging message notification
 type LoggingMessageParams struct {
 	Level  LoggingLevel `json:"level"`
@@ -90,11 +155,24 @@ type LoggingMessageParams struct {
 	Data   interface{}  `json:"data"`
 }
 
+// ProgressParams represents parameters for a progress notification
+type ProgressParams struct {
+	ProgressToken interface{} `json:"progressToken"`
+	Progress      float64     `json:"progress"`
+	Total         float64     `json:"total,omitempty"` // omitted when unknown
+}
+
 // InitializeParams defines parameters for the initialize request
 type InitializeParams struct {
 	ProtocolVersion string             `json:"protocolVersion"`
 	Capabilities    ClientCapabilities `json:"capabilities"`
 	ClientInfo      Implementation     `json:"clientInfo"`
+	Meta            *ClientMeta        `json:"_meta,omitempty"`
+}
+
+// ClientMeta carries client details beyond the spec's clientInfo
+type ClientMeta struct {
+	ResumeToken string `json:"resumeToken,omitempty"` // picks up missed notifications and jobs across reconnects
 }
 
 // InitializeResult is the server's response to an initialize request
@@ -103,34 +181,83 @@ type InitializeResult struct {
 	Capabilities    ServerCapabilities `json:"capabilities"`
 	ServerInfo      Implementation     `json:"serverInfo"`
 	Instructions    string             `json:"instructions,omitempty"`
+	Meta            *InitializeMeta    `json:"_meta,omitempty"`
+}
+
+// InitializeMeta carries server details beyond the spec's serverInfo
+type InitializeMeta struct {
+	Build interface{} `json:"build,omitempty"` // version, commit, build date and Go version
 }
 
-// Content represents a piece of content for a tool result
+// Content types
+const (
+	ContentText     = "text"
+	ContentImage    = "image"
+	ContentAudio    = "audio"
+	ContentResource = "resource"
+)
+
+// Content represents a piece of content for a tool result or prompt message.
+// Images and audio carry base64 Data and a MimeType; embedded resources
+// carry Resource.
 type Content struct {
-	Type string `json:"type"`
-	Text string `json:"text,omitempty"`
+	Type        string            `json:"type"`
+	Text        string            `json:"text,omitempty"`
+	Data        string            `json:"data,omitempty"`
+	MimeType    string            `json:"mimeType,omitempty"`
+	Resource    *ResourceContents `json:"resource,omitempty"`
+	Annotations *Annotations      `json:"annotations,omitempty"`
 }
 
 // ToolsCallResult represents the result of a tool call
 type ToolsCallResult struct {
-	Content []Content `json:"content"`
-	IsError bool      `json:"isError,omitempty"`
+	Content           []Content   `json:"content"`
+	StructuredContent interface{} `json:"structuredContent,omitempty"` // matches the tool's OutputSchema
+	IsError           bool        `json:"isError,omitempty"`
 }
 
 // Tool represents a tool definition
 type Tool struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description,omitempty"`
-	InputSchema interface{} `json:"inputSchema"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description,omitempty"`
+	InputSchema  interface{}      `json:"inputSchema"`
+	OutputSchema interface{}      `json:"outputSchema,omitempty"` // schema of StructuredContent, when the tool returns it
+	Annotations  *ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations describe a tool's behavior so clients can decide how
+// much confirmation to ask for before calling it. They are hints: clients
+// must not rely on them for safety. Unset hints take the spec's defaults,
+// which assume the worst: not read-only, destructive and open-world.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`    // the tool does not change its environment
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"` // changes may delete or overwrite; only meaningful when not read-only
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`  // repeating a call with the same arguments has no further effect
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`   // the tool reaches systems beyond the server
+}
+
+// ReadOnly returns annotations for a tool that only reads
+func ReadOnly() *ToolAnnotations {
+	t := true
+	return &ToolAnnotations{ReadOnlyHint: &t}
+}
+
+// Destructive returns annotations for a tool that may delete, stop or
+// overwrite things; idempotent says whether repeating a call is harmless
+func Destructive(idempotent bool) *ToolAnnotations {
+	t, f := true, false
+	return &ToolAnnotations{ReadOnlyHint: &f, DestructiveHint: &t, IdempotentHint: &idempotent}
 }
 
-// ErrorConverter converts Go errors to jsonrpc2.Error objects
-func ErrorConverter(err error) *jsonrpc2.Error {
+// ErrorConverter converts Go errors returned while serving the request in
+// ctx to jsonrpc2.Error objects
+func ErrorConverter(ctx context.Context, err error) *jsonrpc2.Error {
 	if err == nil {
 		return nil
 	}
 
-	rpcErr := mcperrors.FromError(err)
+	rpcErr := mcperrors.FromError(ctx, err)
 
 	// Create a properly typed data field
 	var jsonData *json.RawMessage
@@ -149,3 +276,14 @@ func ErrorConverter(err error) *jsonrpc2.Error {
 		Data:    jsonData, // Now properly typed as *json.RawMessage
 	}
 }
+
+// ResponderConn is the part of a JSON-RPC connection that request handlers
+// use. *jsonrpc2.Conn implements it; tests can substitute a recording mock.
+type ResponderConn interface {
+	Reply(ctx context.Context, id jsonrpc2.ID, result interface{}) error
+	ReplyWithError(ctx context.Context, id jsonrpc2.ID, respErr *jsonrpc2.Error) error
+	Notify(ctx context.Context, method string, params interface{}, opts ...jsonrpc2.CallOption) error
+}
+
+// Ensure *jsonrpc2.Conn implements ResponderConn
+var _ ResponderConn = (*jsonrpc2.Conn)(nil)
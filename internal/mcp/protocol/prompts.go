@@ -0,0 +1,104 @@
+// internal/mcp/protocol/prompts.go
+package protocol
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// Prompt describes a prompt template offered by the server
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+// PromptArgument describes an argument a prompt template accepts
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// PromptsListResult represents the result of a prompts/list request
+type PromptsListResult struct {
+	Prompts []Prompt `json:"prompts"`
+}
+
+// PromptsGetParams represents the parameters for a prompts/get request
+type PromptsGetParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+// PromptMessage is one message of a rendered prompt
+type PromptMessage struct {
+	Role    Role    `json:"role"`
+	Content Content `json:"content"`
+}
+
+// PromptsGetResult represents the result of a prompts/get request
+type PromptsGetResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// TextContent returns text content
+func TextContent(text string) Content {
+	return Content{Type: ContentText, Text: text}
+}
+
+// ImageContent returns image content, base64 encoding data
+func ImageContent(data []byte, mimeType string) Content {
+	return Content{Type: ContentImage, Data: base64.StdEncoding.EncodeToString(data), MimeType: mimeType}
+}
+
+// AudioContent returns audio content, base64 encoding data
+func AudioContent(data []byte, mimeType string) Content {
+	return Content{Type: ContentAudio, Data: base64.StdEncoding.EncodeToString(data), MimeType: mimeType}
+}
+
+// ResourceContent embeds a resource. Contents with only a URI are a
+// reference the server resolves when the prompt is fetched.
+func ResourceContent(contents ResourceContents) Content {
+	return Content{Type: ContentResource, Resource: &contents}
+}
+
+// ResourceRef embeds the resource at uri, read when the prompt is fetched
+func ResourceRef(uri string) Content {
+	return ResourceContent(ResourceContents{URI: uri})
+}
+
+// UserMessage returns a prompt message from the user
+func UserMessage(content Content) PromptMessage {
+	return PromptMessage{Role: RoleUser, Content: content}
+}
+
+// AssistantMessage returns a prompt message from the assistant
+func AssistantMessage(content Content) PromptMessage {
+	return PromptMessage{Role: RoleAssistant, Content: content}
+}
+
+// Validate reports the first message with an unknown role or malformed content
+func (r *PromptsGetResult) Validate() error {
+	for i, msg := range r.Messages {
+		if msg.Role != RoleUser && msg.Role != RoleAssistant {
+			return fmt.Errorf("message %d: invalid role %q", i, msg.Role)
+		}
+		c := msg.Content
+		switch c.Type {
+		case ContentText:
+		case ContentImage, ContentAudio:
+			if c.Data == "" || c.MimeType == "" {
+				return fmt.Errorf("message %d: %s needs data and mimeType", i, c.Type)
+			}
+		case ContentResource:
+			if c.Resource == nil || c.Resource.URI == "" {
+				return fmt.Errorf("message %d: resource needs a uri", i)
+			}
+		default:
+			return fmt.Errorf("message %d: invalid content type %q", i, c.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// internal/mcp/protocol/resources.go
+package protocol
+
+import "time"
+
+// Role identifies the user or the model as the audience of an object
+type Role string
+
+// Audience roles
+const (
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+// Annotations tell clients how to use or display an object
+type Annotations struct {
+	Audience     []Role     `json:"audience,omitempty"`
+	Priority     *float64   `json:"priority,omitempty"`     // 0 (least) to 1 (most important)
+	LastModified *time.Time `json:"lastModified,omitempty"` // ISO 8601
+}
+
+// Resource describes a resource in a resources/list response
+type Resource struct {
+	URI         string       `json:"uri"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	MimeType    string       `json:"mimeType,omitempty"`
+	Annotations *Annotations `json:"annotations,omitempty"`
+}
+
+// ResourcesListParams represents parameters for the resources/list request
+type ResourcesListParams struct {
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// ResourcesListResult represents the result of a resources/list request
+type ResourcesListResult struct {
+	Resources  []Resource `json:"resources"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
+
+// ResourceTemplate describes a resource template in a
+// resources/templates/list response
+type ResourceTemplate struct {
+	URITemplate string       `json:"uriTemplate"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	MimeType    string       `json:"mimeType,omitempty"`
+	Annotations *Annotations `json:"annotations,omitempty"`
+}
+
+// ResourceTemplatesListResult represents the result of a
+// resources/templates/list request
+type ResourceTemplatesListResult struct {
+	ResourceTemplates []ResourceTemplate `json:"resourceTemplates"`
+	NextCursor        string             `json:"nextCursor,omitempty"`
+}
+
+// ResourcesReadParams represents parameters for the resources/read request.
+// Offset and Length are an axe-handle extension for reading large resources
+// in chunks; clients that omit them get as much as the read limit allows.
+type ResourcesReadParams struct {
+	URI    string `json:"uri"`
+	Offset int64  `json:"offset,omitempty"`
+	Length int64  `json:"length,omitempty"`
+}
+
+// ResourceContents is the content of a single resource. It carries either
+// Text (TextResourceContents) or base64-encoded Blob (BlobResourceContents).
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"` // base64 (standard encoding)
+	Size     int64  `json:"size,omitempty"` // total size in bytes, when known
+}
+
+// ChunkMeta describes which part of a resource a chunked read returned
+type ChunkMeta struct {
+	Offset     int64  `json:"offset"`
+	Length     int64  `json:"length"`
+	TotalSize  int64  `json:"totalSize,omitempty"` // omitted when unknown
+	NextOffset int64  `json:"nextOffset,omitempty"`
+	EOF        bool   `json:"eof"`
+	Truncated  bool   `json:"truncated,omitempty"` // content continues past this chunk
+	Hint       string `json:"hint,omitempty"`      // how to fetch the rest when truncated
+}
+
+// ResourcesReadResult represents the result of a resources/read request
+type ResourcesReadResult struct {
+	Contents []ResourceContents `json:"contents"`
+	Meta     *ResultMeta        `json:"_meta,omitempty"`
+}
+
+// ResultMeta carries axe-handle metadata on results
+type ResultMeta struct {
+	Chunk *ChunkMeta `json:"chunk,omitempty"`
+	ETag  string     `json:"etag,omitempty"` // resource version, when the provider reports one
+}
+
+// ResourcesSubscribeParams represents parameters for resources/subscribe and
+// resources/unsubscribe
+type ResourcesSubscribeParams struct {
+	URI string `json:"uri"`
+}
+
+// ResourceUpdatedParams represents parameters for notifications/resources/updated
+type ResourceUpdatedParams struct {
+	URI string `json:"uri"`
+}
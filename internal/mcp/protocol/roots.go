@@ -0,0 +1,13 @@
+// internal/mcp/protocol/roots.go
+package protocol
+
+// Root is a directory or file the client has exposed to the server
+type Root struct {
+	URI  string `json:"uri"` // must be a file:// URI
+	Name string `json:"name,omitempty"`
+}
+
+// ListRootsResult represents the client's response to roots/list
+type ListRootsResult struct {
+	Roots []Root `json:"roots"`
+}
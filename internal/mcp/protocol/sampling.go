@@ -0,0 +1,50 @@
+// internal/mcp/protocol/sampling.go
+package protocol
+
+// Context a client may add to a sampling request
+const (
+	IncludeNone       = "none"
+	IncludeThisServer = "thisServer"
+	IncludeAllServers = "allServers"
+)
+
+// SamplingMessage is one turn of the conversation sent for sampling
+type SamplingMessage struct {
+	Role    Role    `json:"role"`
+	Content Content `json:"content"` // text or image
+}
+
+// ModelHint suggests a model by (partial) name, such as "claude" or "sonnet"
+type ModelHint struct {
+	Name string `json:"name,omitempty"`
+}
+
+// ModelPreferences guide the client's choice of model. Priorities range
+// from 0 to 1; hints are tried in order.
+type ModelPreferences struct {
+	Hints                []ModelHint `json:"hints,omitempty"`
+	CostPriority         *float64    `json:"costPriority,omitempty"`
+	SpeedPriority        *float64    `json:"speedPriority,omitempty"`
+	IntelligencePriority *float64    `json:"intelligencePriority,omitempty"`
+}
+
+// CreateMessageParams represents parameters for the sampling/createMessage
+// request the server sends to the client
+type CreateMessageParams struct {
+	Messages         []SamplingMessage      `json:"messages"`
+	ModelPreferences *ModelPreferences      `json:"modelPreferences,omitempty"`
+	SystemPrompt     string                 `json:"systemPrompt,omitempty"`
+	IncludeContext   string                 `json:"includeContext,omitempty"`
+	Temperature      *float64               `json:"temperature,omitempty"`
+	MaxTokens        int                    `json:"maxTokens"`
+	StopSequences    []string               `json:"stopSequences,omitempty"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// CreateMessageResult is the client's answer to sampling/createMessage
+type CreateMessageResult struct {
+	Role       Role    `json:"role"`
+	Content    Content `json:"content"`
+	Model      string  `json:"model"`
+	StopReason string  `json:"stopReason,omitempty"` // endTurn, stopSequence, maxTokens or client specific
+}
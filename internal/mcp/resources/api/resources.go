@@ -0,0 +1,200 @@
+// internal/mcp/resources/api/resources.go
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/dkoosis/axe-handle/internal/codec"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/pkg/mcperrors"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// ServerHandler provides an interface to the main server functionality
+type ServerHandler interface {
+	CheckInitialized() error
+	ListResources(ctx context.Context, cursor string) ([]protocol.Resource, string, error)
+	ReadResource(ctx context.Context, params protocol.ResourcesReadParams) (*protocol.ResourcesReadResult, error)
+	Subscribe(ctx context.Context, uri string) error
+	Unsubscribe(ctx context.Context, uri string) error
+	ListResourceTemplates(ctx context.Context) ([]protocol.ResourceTemplate, error)
+	Complete(ctx context.Context, params protocol.CompleteParams) (*protocol.CompleteResult, error)
+}
+
+// ErrorSender replies to req with err
+type ErrorSender func(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request, err error)
+
+// ResourcesHandler handles resources-related requests
+type ResourcesHandler struct {
+	server    ServerHandler
+	sendError ErrorSender
+}
+
+// NewResourcesHandler creates a new resources handler
+func NewResourcesHandler(server ServerHandler, sendError ErrorSender) *ResourcesHandler {
+	return &ResourcesHandler{
+		server:    server,
+		sendError: sendError,
+	}
+}
+
+// HandleResourcesList handles the resources/list request
+func (h *ResourcesHandler) HandleResourcesList(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request) {
+	var params protocol.ResourcesListParams
+	if req.Params != nil {
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(err))
+			return
+		}
+	}
+
+	if err := h.server.CheckInitialized(); err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	list, next, err := h.server.ListResources(ctx, params.Cursor)
+	if err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	result := protocol.ResourcesListResult{
+		Resources:  list,
+		NextCursor: next,
+	}
+	if err := conn.Reply(ctx, req.ID, result); err != nil {
+		slog.Error("Failed to send resources list response", "error", err)
+	}
+}
+
+// HandleResourcesRead handles the resources/read request. Large resources
+// are returned one chunk at a time; clients follow _meta.chunk.nextOffset
+// until eof is set.
+func (h *ResourcesHandler) HandleResourcesRead(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request) {
+	var params protocol.ResourcesReadParams
+	if req.Params == nil {
+		h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(fmt.Errorf("missing params")))
+		return
+	}
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(err))
+		return
+	}
+	if params.URI == "" {
+		h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(fmt.Errorf("missing uri")))
+		return
+	}
+
+	if err := h.server.CheckInitialized(); err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	result, err := h.server.ReadResource(ctx, params)
+	if err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	if err := conn.Reply(ctx, req.ID, codec.Raw(result)); err != nil {
+		slog.Error("Failed to send resource read response", "error", err)
+	}
+}
+
+// HandleResourceTemplatesList handles the resources/templates/list request.
+// Templates are few, so they are returned in a single page.
+func (h *ResourcesHandler) HandleResourceTemplatesList(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request) {
+	if err := h.server.CheckInitialized(); err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	list, err := h.server.ListResourceTemplates(ctx)
+	if err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	result := protocol.ResourceTemplatesListResult{ResourceTemplates: list}
+	if err := conn.Reply(ctx, req.ID, result); err != nil {
+		slog.Error("Failed to send resource templates response", "error", err)
+	}
+}
+
+// HandleComplete handles the completion/complete request
+func (h *ResourcesHandler) HandleComplete(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request) {
+	var params protocol.CompleteParams
+	if req.Params == nil {
+		h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(fmt.Errorf("missing params")))
+		return
+	}
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(err))
+		return
+	}
+	if params.Argument.Name == "" {
+		h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(fmt.Errorf("missing argument name")))
+		return
+	}
+
+	if err := h.server.CheckInitialized(); err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	result, err := h.server.Complete(ctx, params)
+	if err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	if err := conn.Reply(ctx, req.ID, result); err != nil {
+		slog.Error("Failed to send completion response", "error", err)
+	}
+}
+
+// HandleResourcesSubscribe handles the resources/subscribe request
+func (h *ResourcesHandler) HandleResourcesSubscribe(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request) {
+	h.handleSubscription(ctx, conn, req, h.server.Subscribe)
+}
+
+// HandleResourcesUnsubscribe handles the resources/unsubscribe request
+func (h *ResourcesHandler) HandleResourcesUnsubscribe(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request) {
+	h.handleSubscription(ctx, conn, req, h.server.Unsubscribe)
+}
+
+// handleSubscription parses a subscription request and applies change
+func (h *ResourcesHandler) handleSubscription(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request,
+	change func(ctx context.Context, uri string) error) {
+	var params protocol.ResourcesSubscribeParams
+	if req.Params == nil {
+		h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(fmt.Errorf("missing params")))
+		return
+	}
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(err))
+		return
+	}
+	if params.URI == "" {
+		h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(fmt.Errorf("missing uri")))
+		return
+	}
+
+	if err := h.server.CheckInitialized(); err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	if err := change(ctx, params.URI); err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	if err := conn.Reply(ctx, req.ID, struct{}{}); err != nil {
+		slog.Error("Failed to send subscription response", "method", req.Method, "error", err)
+	}
+}
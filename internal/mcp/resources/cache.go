@@ -0,0 +1,140 @@
+// internal/mcp/resources/cache.go
+package resources
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache defaults
+const (
+	DefaultCacheMaxBytes      = 64 << 20
+	DefaultCacheMaxEntryBytes = 4 << 20
+	DefaultCacheTTL           = 5 * time.Minute
+)
+
+// ETagProvider is implemented by providers that can cheaply report a version
+// tag (an ETag, mtime or row version) for a resource without reading it.
+// Cached content is reused only while the tag is unchanged.
+type ETagProvider interface {
+	ResourceETag(uri string) (string, error)
+}
+
+// CachedContent is a resource held in the cache
+type CachedContent struct {
+	URI      string
+	ETag     string // empty when the provider has no ETag support
+	MimeType string
+	Data     []byte
+	StoredAt time.Time
+}
+
+// Cache is a size-bounded LRU of resource contents keyed by URI
+type Cache struct {
+	maxBytes      int64
+	maxEntryBytes int64
+	ttl           time.Duration
+
+	entries map[string]*list.Element
+	order   *list.List // front is most recently used
+	bytes   int64
+	mu      sync.Mutex
+}
+
+// NewCache creates a cache holding at most maxBytes of content, skipping
+// entries over maxEntryBytes. Entries without an ETag expire after ttl.
+func NewCache(maxBytes, maxEntryBytes int64, ttl time.Duration) *Cache {
+	if maxBytes <= 0 {
+		maxBytes = DefaultCacheMaxBytes
+	}
+	if maxEntryBytes <= 0 {
+		maxEntryBytes = DefaultCacheMaxEntryBytes
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &Cache{
+		maxBytes:      maxBytes,
+		maxEntryBytes: maxEntryBytes,
+		ttl:           ttl,
+		entries:       make(map[string]*list.Element),
+		order:         list.New(),
+	}
+}
+
+// Get returns the cached content for uri if it is still valid for etag. An
+// empty etag means the provider cannot report one, so the TTL applies.
+func (c *Cache) Get(uri, etag string) (*CachedContent, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[uri]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*CachedContent)
+	stale := entry.ETag != etag
+	if etag == "" && time.Since(entry.StoredAt) > c.ttl {
+		stale = true
+	}
+	if stale {
+		c.removeElement(el)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return entry, true
+}
+
+// Put stores content, evicting the least recently used entries as needed.
+// It reports whether the content was small enough to cache.
+func (c *Cache) Put(content *CachedContent) bool {
+	size := int64(len(content.Data))
+	if !c.Fits(size) {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[content.URI]; ok {
+		c.removeElement(el)
+	}
+	content.StoredAt = time.Now()
+	c.entries[content.URI] = c.order.PushFront(content)
+	c.bytes += size
+
+	for c.bytes > c.maxBytes {
+		c.removeElement(c.order.Back())
+	}
+	return true
+}
+
+// Fits reports whether content of size bytes would be cached
+func (c *Cache) Fits(size int64) bool {
+	return size >= 0 && size <= c.maxEntryBytes && size <= c.maxBytes
+}
+
+// Invalidate drops the entry for uri
+func (c *Cache) Invalidate(uri string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[uri]; ok {
+		c.removeElement(el)
+	}
+}
+
+// Len returns the number of cached entries
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// removeElement unlinks an entry; c.mu must be held
+func (c *Cache) removeElement(el *list.Element) {
+	entry := el.Value.(*CachedContent)
+	c.order.Remove(el)
+	delete(c.entries, entry.URI)
+	c.bytes -= int64(len(entry.Data))
+}
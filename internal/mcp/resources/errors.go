@@ -1,9 +1,19 @@
 // internal/mcp/resources/errors.go
 package resources
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/dkoosis/axe-handle/pkg/mcperrors"
+)
 
 var (
 	// ErrResourceNotFound is returned when a requested resource cannot be found
 	ErrResourceNotFound = errors.New("resource not found")
 )
+
+// init maps this package's sentinel errors to their JSON-RPC error codes
+func init() {
+	mcperrors.RegisterMapping(ErrResourceNotFound, mcperrors.ErrResourceNotFound)
+	mcperrors.RegisterMapping(ErrInvalidRange, mcperrors.ErrInvalidParams)
+}
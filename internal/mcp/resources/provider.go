@@ -1,12 +1,20 @@
 // internal/mcp/resources/provider.go
 package resources
 
+import (
+	"context"
+	"io"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+)
+
 // Resource represents a resource that can be accessed by clients
 type Resource struct {
 	URI         string
 	Name        string
 	Description string
 	MimeType    string
+	Annotations *protocol.Annotations // optional audience, priority and freshness hints
 }
 
 // Provider defines the interface for resource providers
@@ -17,3 +25,51 @@ type Provider interface {
 	// GetResource returns the content of a specific resource
 	GetResource(uri string) (interface{}, error)
 }
+
+// Stream is resource content read incrementally. Size is -1 when unknown.
+// Content whose MimeType is not textual is served as a base64 blob.
+type Stream struct {
+	io.ReadCloser
+	Size     int64
+	MimeType string
+	ETag     string // optional version tag used for caching
+}
+
+// StreamProvider is implemented by providers whose resources are too large
+// to hold in memory. The server reads them in chunks.
+type StreamProvider interface {
+	Provider
+
+	// OpenResource opens the resource for reading; the caller closes it
+	OpenResource(uri string) (*Stream, error)
+}
+
+// ResourceTemplate describes a family of resources by an RFC 6570 URI
+// template such as file:///{path}
+type ResourceTemplate struct {
+	URITemplate string
+	Name        string
+	Description string
+	MimeType    string
+}
+
+// TemplateProvider is implemented by providers whose resources are
+// addressed by template rather than listed one by one
+type TemplateProvider interface {
+	Provider
+
+	// ListResourceTemplates returns the templates the provider serves
+	ListResourceTemplates() ([]ResourceTemplate, error)
+}
+
+// CompletionProvider is implemented by providers that can suggest values
+// for the variables of their templates
+type CompletionProvider interface {
+	Provider
+
+	// CompleteTemplateVariable returns candidate values for variable in
+	// template given what the user has typed so far. Candidates need not
+	// be filtered or ordered; the server ranks them against partial.
+	// Providers return nil for templates they do not serve.
+	CompleteTemplateVariable(ctx context.Context, template, variable, partial string) ([]string, error)
+}
@@ -0,0 +1,126 @@
+// internal/mcp/resources/stream.go
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultMaxReadBytes caps a single read when no limit is configured
+const DefaultMaxReadBytes = 1 << 20
+
+// ErrInvalidRange is returned when a read offset or length is out of range
+var ErrInvalidRange = errors.New("invalid resource range")
+
+// Chunk is one ranged read of a stream
+type Chunk struct {
+	Data       []byte
+	Offset     int64
+	NextOffset int64
+	EOF        bool
+}
+
+// IsText reports whether content of mimeType is served as text rather than
+// a base64 blob. An empty type is treated as text.
+func IsText(mimeType string) bool {
+	mediaType, _, _ := strings.Cut(mimeType, ";")
+	mediaType = strings.TrimSpace(strings.ToLower(mediaType))
+	switch {
+	case mediaType == "", strings.HasPrefix(mediaType, "text/"):
+		return true
+	case strings.HasSuffix(mediaType, "+json"), strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript",
+		"application/x-yaml", "application/yaml", "application/toml",
+		"application/x-sh", "image/svg+xml":
+		return true
+	}
+	return false
+}
+
+// ReadChunk reads up to length bytes of s starting at offset, never more
+// than maxBytes; a zero length reads as much as maxBytes allows. Text chunks
+// are trimmed back to a UTF-8 boundary so they can be returned as JSON
+// strings; NextOffset accounts for any bytes held back.
+func ReadChunk(s *Stream, offset, length, maxBytes int64) (*Chunk, error) {
+	if offset < 0 || length < 0 {
+		return nil, fmt.Errorf("%w: offset %d, length %d", ErrInvalidRange, offset, length)
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxReadBytes
+	}
+	if length == 0 || length > maxBytes {
+		length = maxBytes
+	}
+	if s.Size >= 0 && offset > s.Size {
+		return nil, fmt.Errorf("%w: offset %d beyond size %d", ErrInvalidRange, offset, s.Size)
+	}
+
+	if err := skipTo(s.ReadCloser, offset); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, length)
+	n, err := io.ReadFull(s.ReadCloser, buf)
+	eof := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	if err != nil && !eof {
+		return nil, fmt.Errorf("failed to read resource: %w", err)
+	}
+	data := buf[:n]
+
+	// Hold back a split multi-byte rune of text for the next chunk
+	if !eof && IsText(s.MimeType) {
+		data = trimToRuneBoundary(data)
+	}
+	if !eof && s.Size >= 0 && offset+int64(len(data)) >= s.Size {
+		eof = true
+	}
+
+	return &Chunk{
+		Data:       data,
+		Offset:     offset,
+		NextOffset: offset + int64(len(data)),
+		EOF:        eof,
+	}, nil
+}
+
+// skipTo advances r to offset, seeking when the reader supports it
+func skipTo(r io.Reader, offset int64) error {
+	if offset == 0 {
+		return nil
+	}
+	if seeker, ok := r.(io.Seeker); ok {
+		if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+			return fmt.Errorf("failed to seek resource: %w", err)
+		}
+		return nil
+	}
+	skipped, err := io.CopyN(io.Discard, r, offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to skip to offset %d: %w", offset, err)
+	}
+	if skipped < offset {
+		return fmt.Errorf("%w: offset %d beyond end of resource", ErrInvalidRange, offset)
+	}
+	return nil
+}
+
+// trimToRuneBoundary drops a trailing incomplete UTF-8 sequence
+func trimToRuneBoundary(data []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		b := data[len(data)-i]
+		if !utf8.RuneStart(b) {
+			continue
+		}
+		if !utf8.FullRune(data[len(data)-i:]) {
+			return data[:len(data)-i]
+		}
+		return data
+	}
+	return data
+}
@@ -0,0 +1,166 @@
+// internal/mcp/resources/watch/watch.go
+
+// Package watch turns filesystem events into MCP resource notifications so
+// file-backed providers don't each need their own polling loop.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Notifier receives resource change notifications; *server.Server implements it
+type Notifier interface {
+	NotifyResourceUpdated(uri string)
+	NotifyResourcesListChanged()
+}
+
+// Option configures a Watcher
+type Option func(*Watcher)
+
+// WithURIFunc sets how file paths map to resource URIs (file:// URLs by default)
+func WithURIFunc(fn func(path string) string) Option {
+	return func(w *Watcher) {
+		w.uriFor = fn
+	}
+}
+
+// WithFilter limits notifications to paths for which keep returns true
+func WithFilter(keep func(path string) bool) Option {
+	return func(w *Watcher) {
+		w.keep = keep
+	}
+}
+
+// WithRecursive watches subdirectories of added directories, including ones
+// created later
+func WithRecursive(recursive bool) Option {
+	return func(w *Watcher) {
+		w.recursive = recursive
+	}
+}
+
+// Watcher maps filesystem events to notifications: writes become
+// resources/updated for the file, and creates, removes and renames become
+// resources/list_changed.
+type Watcher struct {
+	fs        *fsnotify.Watcher
+	notifier  Notifier
+	uriFor    func(path string) string
+	keep      func(path string) bool
+	recursive bool
+}
+
+// NewWatcher creates a watcher that reports to notifier
+func NewWatcher(notifier Notifier, opts ...Option) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create filesystem watcher: %w", err)
+	}
+
+	w := &Watcher{
+		fs:       fsw,
+		notifier: notifier,
+		uriFor:   FileURI,
+		keep:     func(string) bool { return true },
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w, nil
+}
+
+// FileURI returns the file:// URI for path
+func FileURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// Add starts watching path; directories are walked when recursive
+func (w *Watcher) Add(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", path, err)
+	}
+	if !info.IsDir() || !w.recursive {
+		return w.fs.Add(path)
+	}
+
+	return filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if err := w.fs.Add(p); err != nil {
+				return fmt.Errorf("failed to watch %s: %w", p, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Run delivers notifications until ctx is done or the watcher is closed
+func (w *Watcher) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				return nil
+			}
+			w.handle(event)
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return nil
+			}
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				// Events were lost, so clients must assume anything changed
+				slog.Warn("Filesystem watcher overflowed, reporting list change")
+				w.notifier.NotifyResourcesListChanged()
+				continue
+			}
+			slog.Error("Filesystem watcher error", "error", err)
+		}
+	}
+}
+
+// Close stops watching
+func (w *Watcher) Close() error {
+	return w.fs.Close()
+}
+
+// handle translates one event
+func (w *Watcher) handle(event fsnotify.Event) {
+	if !w.keep(event.Name) {
+		return
+	}
+	slog.Debug("Filesystem event", "path", event.Name, "op", event.Op.String())
+
+	switch {
+	case event.Has(fsnotify.Create):
+		if w.recursive {
+			if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+				if err := w.Add(event.Name); err != nil {
+					slog.Warn("Failed to watch new directory", "path", event.Name, "error", err)
+				}
+			}
+		}
+		w.notifier.NotifyResourcesListChanged()
+	case event.Has(fsnotify.Remove), event.Has(fsnotify.Rename):
+		w.notifier.NotifyResourcesListChanged()
+		w.notifier.NotifyResourceUpdated(w.uriFor(event.Name))
+	case event.Has(fsnotify.Write):
+		w.notifier.NotifyResourceUpdated(w.uriFor(event.Name))
+	}
+}
@@ -0,0 +1,148 @@
+// internal/mcp/sampling/sampling.go
+
+// Package sampling lets providers ask a model for completions without
+// bundling an API key of their own. The default Sampler forwards requests
+// to the connected client with sampling/createMessage, so the client's
+// model does the work and the client's user stays in control of it.
+package sampling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+)
+
+// DefaultTimeout bounds how long the client's model has to answer
+const DefaultTimeout = 2 * time.Minute
+
+// Errors returned by samplers
+var (
+	ErrUnsupported = errors.New("client does not support sampling")
+	ErrTimeout     = errors.New("model did not answer in time")
+)
+
+// Sampler asks a model for the next message of a conversation
+type Sampler interface {
+	CreateMessage(ctx context.Context, params protocol.CreateMessageParams) (*protocol.CreateMessageResult, error)
+}
+
+// SamplerFunc adapts a function to a Sampler
+type SamplerFunc func(ctx context.Context, params protocol.CreateMessageParams) (*protocol.CreateMessageResult, error)
+
+// CreateMessage calls f
+func (f SamplerFunc) CreateMessage(ctx context.Context, params protocol.CreateMessageParams) (*protocol.CreateMessageResult, error) {
+	return f(ctx, params)
+}
+
+// timeoutKey carries the answer timeout of a server in a context
+type timeoutKey struct{}
+
+// WithTimeout returns a context whose sampling requests give the client's
+// model d to answer; 0 or less keeps the default
+func WithTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey{}, d)
+}
+
+// timeout returns how long the model has to answer for the request in ctx
+func timeout(ctx context.Context) time.Duration {
+	if d, ok := ctx.Value(timeoutKey{}).(time.Duration); ok && d > 0 {
+		return d
+	}
+	return DefaultTimeout
+}
+
+// samplerKey carries an injected Sampler in a context
+type samplerKey struct{}
+
+// WithSampler returns a context whose requests use s instead of the
+// client, for tests or servers with a model of their own
+func WithSampler(ctx context.Context, s Sampler) context.Context {
+	return context.WithValue(ctx, samplerKey{}, s)
+}
+
+// FromContext returns the Sampler for the request in ctx: an injected one
+// if present, otherwise the client's model. ErrUnsupported is returned when
+// neither is available.
+func FromContext(ctx context.Context) (Sampler, error) {
+	if s, ok := ctx.Value(samplerKey{}).(Sampler); ok {
+		return s, nil
+	}
+	sess := session.FromContext(ctx)
+	if sess == nil || sess.Conn() == nil || !sess.Supports(session.FeatureSampling) {
+		return nil, ErrUnsupported
+	}
+	return clientSampler{sess: sess}, nil
+}
+
+// Supported reports whether a Sampler is available for the request in ctx
+func Supported(ctx context.Context) bool {
+	_, err := FromContext(ctx)
+	return err == nil
+}
+
+// CreateMessage asks the Sampler for the request in ctx for a message
+func CreateMessage(ctx context.Context, params protocol.CreateMessageParams) (*protocol.CreateMessageResult, error) {
+	s, err := FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.CreateMessage(ctx, params)
+}
+
+// Text sends prompt as a single user message and returns the model's text
+// reply. It covers the common case of a provider wanting a summary,
+// translation or classification.
+func Text(ctx context.Context, systemPrompt, prompt string, maxTokens int) (string, error) {
+	result, err := CreateMessage(ctx, protocol.CreateMessageParams{
+		Messages: []protocol.SamplingMessage{{
+			Role:    protocol.RoleUser,
+			Content: protocol.TextContent(prompt),
+		}},
+		SystemPrompt: systemPrompt,
+		MaxTokens:    maxTokens,
+	})
+	if err != nil {
+		return "", err
+	}
+	if result.Content.Type != protocol.ContentText {
+		return "", fmt.Errorf("model replied with %s content, not text", result.Content.Type)
+	}
+	return result.Content.Text, nil
+}
+
+// clientSampler forwards requests to a session's client
+type clientSampler struct {
+	sess *session.Session
+}
+
+// CreateMessage sends sampling/createMessage to the client and waits for
+// the reply. Clients may show the request to their user before running it,
+// so the tool's timeout is paused meanwhile; the sampling timeout applies
+// instead.
+func (c clientSampler) CreateMessage(ctx context.Context, params protocol.CreateMessageParams) (*protocol.CreateMessageResult, error) {
+	if len(params.Messages) == 0 {
+		return nil, fmt.Errorf("sampling request needs at least one message")
+	}
+	if params.MaxTokens <= 0 {
+		return nil, fmt.Errorf("sampling request needs a positive maxTokens")
+	}
+
+	resume := manager.PauseTimeout(ctx)
+	defer resume()
+
+	result, err := session.Call[protocol.CreateMessageResult](ctx, c.sess, protocol.MethodSamplingCreate, params,
+		session.WithRequestTimeout(timeout(ctx)),
+		session.WithIDPrefix("sample"))
+	switch {
+	case errors.Is(err, session.ErrClientTimeout):
+		return nil, ErrTimeout
+	case err != nil && ctx.Err() == nil:
+		return nil, fmt.Errorf("sampling failed: %w", err)
+	}
+	return result, err
+}
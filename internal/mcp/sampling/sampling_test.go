@@ -0,0 +1,69 @@
+// internal/mcp/sampling/sampling_test.go
+package sampling_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/sampling"
+	"github.com/dkoosis/axe-handle/pkg/mcptest"
+)
+
+// summarizeTool asks the client's model for text and reports the reply or
+// a timeout
+func summarizeTool() mcptest.Option {
+	tool := protocol.Tool{Name: "summarize", InputSchema: json.RawMessage(`{"type":"object"}`)}
+	return mcptest.WithTool(tool, func(ctx context.Context, _ json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		text, err := sampling.Text(ctx, "", "Summarize", 10)
+		if errors.Is(err, sampling.ErrTimeout) {
+			text, err = "timeout", nil
+		}
+		if err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		return protocol.ToolsCallResult{Content: []protocol.Content{protocol.TextContent(text)}}, nil
+	})
+}
+
+// slowModel connects to a server whose clients' models have timeout to
+// answer, with a model that answers after delay
+func slowModel(t *testing.T, timeout, delay time.Duration) *mcptest.Client {
+	c := mcptest.New(t, summarizeTool(), mcptest.WithServerOptions(func(cfg *config.Config) {
+		cfg.Tools.SamplingTimeout = timeout
+	}))
+	c.OnRequest(protocol.MethodSamplingCreate, func(json.RawMessage) (interface{}, error) {
+		time.Sleep(delay)
+		return protocol.CreateMessageResult{Role: protocol.RoleAssistant, Content: protocol.TextContent("short"), Model: "test"}, nil
+	})
+	c.InitializeWith(protocol.InitializeParams{
+		ProtocolVersion: protocol.LatestProtocolVersion,
+		Capabilities:    protocol.ClientCapabilities{Sampling: &struct{}{}},
+		ClientInfo:      protocol.Implementation{Name: "test", Version: "0"},
+	})
+	return c
+}
+
+func TestTimeoutIsPerServer(t *testing.T) {
+	patient := slowModel(t, time.Minute, 200*time.Millisecond)
+	impatient := slowModel(t, 50*time.Millisecond, 200*time.Millisecond)
+
+	for _, tc := range []struct {
+		name   string
+		client *mcptest.Client
+		want   string
+	}{
+		{"patient", patient, "short"},
+		{"impatient", impatient, "timeout"},
+	} {
+		var result protocol.ToolsCallResult
+		tc.client.MustCall(protocol.MethodToolsCall, map[string]interface{}{"name": "summarize", "arguments": map[string]interface{}{}}, &result)
+		if len(result.Content) != 1 || result.Content[0].Text != tc.want {
+			t.Errorf("%s server: summarize = %+v, want %q", tc.name, result.Content, tc.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// internal/mcp/server/capabilities.go
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/dkoosis/axe-handle/internal/buildinfo"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+)
+
+// CapabilitiesResourceURI is the URI of the built-in catalog resource
+const CapabilitiesResourceURI = "axe://capabilities"
+
+// Catalog is everything the server offers, in the shapes the list methods
+// return, so one read replaces paging through tools/list, resources/list,
+// resources/templates/list and prompts/list
+type Catalog struct {
+	ServerInfo        protocol.Implementation     `json:"serverInfo"`
+	Build             buildinfo.Info              `json:"build"`
+	ProtocolVersion   string                      `json:"protocolVersion"`
+	Capabilities      protocol.ServerCapabilities `json:"capabilities"`
+	Tools             []protocol.Tool             `json:"tools"`
+	Resources         []protocol.Resource         `json:"resources"`
+	ResourceTemplates []protocol.ResourceTemplate `json:"resourceTemplates"`
+	Prompts           []protocol.Prompt           `json:"prompts"`
+}
+
+// Catalog describes the tools, resources, templates and prompts registered
+// right now, with their schemas and annotations. Like the OpenRPC document
+// it covers the whole server, whatever a client's roots or profile.
+func (s *Server) Catalog(ctx context.Context) (Catalog, error) {
+	catalog := Catalog{
+		ServerInfo: protocol.Implementation{
+			Name:    s.config.Server.Name,
+			Version: s.config.Server.Version,
+		},
+		Build:           buildinfo.Get(),
+		ProtocolVersion: protocol.LatestProtocolVersion,
+		Capabilities:    s.capabilities,
+		Tools:           s.toolsManager.ListTools(),
+	}
+	sort.Slice(catalog.Tools, func(i, j int) bool { return catalog.Tools[i].Name < catalog.Tools[j].Name })
+
+	list, err := s.providerRegistry.ListResources(ctx)
+	if err != nil {
+		return Catalog{}, err
+	}
+	catalog.Resources = make([]protocol.Resource, 0, len(list))
+	for _, r := range list {
+		catalog.Resources = append(catalog.Resources, protocol.Resource{
+			URI:         r.URI,
+			Name:        r.Name,
+			Description: r.Description,
+			MimeType:    r.MimeType,
+			Annotations: r.Annotations,
+		})
+	}
+
+	if catalog.ResourceTemplates, err = s.ListResourceTemplates(ctx); err != nil {
+		return Catalog{}, err
+	}
+	if catalog.Prompts, err = s.ListPrompts(ctx); err != nil {
+		return Catalog{}, err
+	}
+	return catalog, nil
+}
+
+// capabilitiesProvider exposes the catalog as a read-only resource
+type capabilitiesProvider struct {
+	server *Server
+}
+
+// Ensure capabilitiesProvider implements resources.Provider
+var _ resources.Provider = (*capabilitiesProvider)(nil)
+
+// ListResources returns the catalog resource
+func (p *capabilitiesProvider) ListResources() ([]resources.Resource, error) {
+	return []resources.Resource{
+		{
+			URI:         CapabilitiesResourceURI,
+			Name:        "Capabilities",
+			Description: "Every tool, resource, resource template and prompt, with schemas and annotations, in one document",
+			MimeType:    "application/json",
+		},
+	}, nil
+}
+
+// GetResource builds the catalog from what is registered right now
+func (p *capabilitiesProvider) GetResource(uri string) (interface{}, error) {
+	if uri != CapabilitiesResourceURI {
+		return nil, resources.ErrResourceNotFound
+	}
+	catalog, err := p.server.Catalog(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
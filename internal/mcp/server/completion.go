@@ -0,0 +1,105 @@
+// internal/mcp/server/completion.go
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/pkg/mcperrors"
+)
+
+// ListResourceTemplates returns the resource templates of all providers
+func (s *Server) ListResourceTemplates(ctx context.Context) ([]protocol.ResourceTemplate, error) {
+	list, err := s.providerRegistry.ListResourceTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]protocol.ResourceTemplate, 0, len(list))
+	for _, t := range list {
+		result = append(result, protocol.ResourceTemplate{
+			URITemplate: t.URITemplate,
+			Name:        t.Name,
+			Description: t.Description,
+			MimeType:    t.MimeType,
+		})
+	}
+	return result, nil
+}
+
+// Complete answers completion/complete. Resource template variables are
+// completed by their providers; prompts have no completions yet, so they
+// get an empty result rather than an error.
+func (s *Server) Complete(ctx context.Context, params protocol.CompleteParams) (*protocol.CompleteResult, error) {
+	var candidates []string
+	switch params.Ref.Type {
+	case protocol.RefResource:
+		if params.Ref.URI == "" {
+			return nil, mcperrors.NewInvalidParamsError(fmt.Errorf("ref/resource needs a uri"))
+		}
+		values, err := s.providerRegistry.CompleteTemplateVariable(ctx, params.Ref.URI, params.Argument.Name, params.Argument.Value)
+		if err != nil {
+			return nil, err
+		}
+		candidates = values
+	case protocol.RefPrompt:
+	default:
+		return nil, mcperrors.NewInvalidParamsError(fmt.Errorf("unknown completion ref type %q", params.Ref.Type))
+	}
+
+	values := rankCompletions(candidates, params.Argument.Value)
+	completion := protocol.Completion{Values: values, Total: len(values)}
+	if len(values) > protocol.MaxCompletionValues {
+		completion.Values = values[:protocol.MaxCompletionValues]
+		completion.HasMore = true
+	}
+	return &protocol.CompleteResult{Completion: completion}, nil
+}
+
+// rankCompletions drops duplicates and candidates that do not contain
+// partial, then orders the rest: exact-case prefix matches, then
+// case-insensitive prefix matches, then substring matches, shorter and
+// alphabetically first within each group
+func rankCompletions(candidates []string, partial string) []string {
+	lower := strings.ToLower(partial)
+	rank := func(v string) int {
+		switch {
+		case strings.HasPrefix(v, partial):
+			return 0
+		case strings.HasPrefix(strings.ToLower(v), lower):
+			return 1
+		case strings.Contains(strings.ToLower(v), lower):
+			return 2
+		}
+		return -1
+	}
+
+	seen := make(map[string]bool, len(candidates))
+	ranks := make(map[string]int, len(candidates))
+	values := make([]string, 0, len(candidates))
+	for _, v := range candidates {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		if r := rank(v); r >= 0 {
+			ranks[v] = r
+			values = append(values, v)
+		}
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		a, b := values[i], values[j]
+		if ranks[a] != ranks[b] {
+			return ranks[a] < ranks[b]
+		}
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	})
+	return values
+}
@@ -0,0 +1,112 @@
+// internal/mcp/server/connections.go
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+)
+
+// SessionInfo describes a client for connection hooks
+type SessionInfo struct {
+	ID              string                  // unique to the connection
+	Client          protocol.Implementation // name and version from initialize
+	ProtocolVersion string                  // negotiated at initialize
+	Addr            string                  // client IP; empty for stdio
+	Identity        string                  // authenticated caller; empty when anonymous
+	Tenant          string                  // team the caller belongs to; empty outside tenants
+	ConnectedAt     time.Time
+	Duration        time.Duration // how long the client stayed; set on disconnect
+}
+
+// ConnectionHook reacts to a client connecting or disconnecting. The
+// context carries the client's session.
+type ConnectionHook func(ctx context.Context, info SessionInfo)
+
+// ConnectionObserver is implemented by providers that keep per-client
+// state. The server calls it for every client once registered.
+type ConnectionObserver interface {
+	OnConnect(ctx context.Context, info SessionInfo)
+	OnDisconnect(ctx context.Context, info SessionInfo)
+}
+
+// OnConnect registers a hook to run when a client has initialized. Hooks
+// run in registration order, off the request path, so they may warm caches
+// or open per-client resources without delaying the client.
+func (s *Server) OnConnect(hook ConnectionHook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.connectHooks = append(s.connectHooks, hook)
+}
+
+// OnDisconnect registers a hook to run when an initialized client's
+// connection closes, to release whatever OnConnect hooks acquired.
+func (s *Server) OnDisconnect(hook ConnectionHook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.disconnectHooks = append(s.disconnectHooks, hook)
+}
+
+// observeConnections registers providers that want connection hooks
+func (s *Server) observeConnections(provider interface{}) {
+	if observer, ok := provider.(ConnectionObserver); ok {
+		s.OnConnect(observer.OnConnect)
+		s.OnDisconnect(observer.OnDisconnect)
+	}
+}
+
+// clientConnected runs the connect hooks for an initialized session
+func (s *Server) clientConnected(sess *session.Session) {
+	s.mu.RLock()
+	hooks := append([]ConnectionHook(nil), s.connectHooks...)
+	s.mu.RUnlock()
+	runConnectionHooks(session.WithSession(s.ctx, sess), "connect", hooks, sessionInfo(sess))
+}
+
+// clientDisconnected runs the disconnect hooks for a closed session. Hooks
+// still run during shutdown, so their context is not cancelled with the
+// server's.
+func (s *Server) clientDisconnected(sess *session.Session) {
+	info := sessionInfo(sess)
+	info.Duration = time.Since(info.ConnectedAt)
+	slog.Info("Client disconnected",
+		"session", info.ID,
+		"client_name", info.Client.Name,
+		"duration", info.Duration.Round(time.Millisecond))
+
+	s.mu.RLock()
+	hooks := append([]ConnectionHook(nil), s.disconnectHooks...)
+	s.mu.RUnlock()
+	runConnectionHooks(session.WithSession(context.WithoutCancel(s.ctx), sess), "disconnect", hooks, info)
+}
+
+// runConnectionHooks runs hooks in order; a panicking hook is logged and
+// does not stop the others
+func runConnectionHooks(ctx context.Context, event string, hooks []ConnectionHook, info SessionInfo) {
+	for i, hook := range hooks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					slog.Error("Connection hook panicked", "event", event, "hook", i, "session", info.ID, "panic", r)
+				}
+			}()
+			hook(ctx, info)
+		}()
+	}
+}
+
+// sessionInfo snapshots what hooks are told about sess
+func sessionInfo(sess *session.Session) SessionInfo {
+	return SessionInfo{
+		ID:              sess.ID(),
+		Client:          sess.ClientInfo(),
+		ProtocolVersion: sess.ProtocolVersion(),
+		Addr:            sess.RemoteAddr(),
+		Identity:        sess.Identity(),
+		Tenant:          sess.Tenant(),
+		ConnectedAt:     sess.ConnectedAt(),
+	}
+}
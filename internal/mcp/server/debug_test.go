@@ -0,0 +1,27 @@
+// internal/mcp/server/debug_test.go
+package server_test
+
+import (
+	"testing"
+
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/pkg/mcperrors"
+	"github.com/dkoosis/axe-handle/pkg/mcptest"
+)
+
+func TestServersKeepTheirOwnDebugSetting(t *testing.T) {
+	debug := mcptest.New(t, mcptest.WithServerOptions(func(cfg *config.Config) {
+		cfg.Server.Debug = true
+	}))
+	debug.Initialize()
+	production := mcptest.New(t)
+	production.Initialize()
+
+	// Starting the second server does not turn the first one's details off
+	if err := debug.ExpectError("no/such", nil, int64(mcperrors.MethodNotFound)); err.Data == nil {
+		t.Error("debug server hid error details")
+	}
+	if err := production.ExpectError("no/such", nil, int64(mcperrors.MethodNotFound)); err.Data != nil {
+		t.Errorf("production server exposed error details: %s", *err.Data)
+	}
+}
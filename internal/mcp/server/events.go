@@ -0,0 +1,76 @@
+// internal/mcp/server/events.go
+package server
+
+import (
+	"github.com/dkoosis/axe-handle/internal/events"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+)
+
+// Events returns the bus the server publishes on. Extensions subscribe to
+// it to react to tools, sessions and resources without hooks of their own.
+func (s *Server) Events() *events.Bus {
+	return s.events
+}
+
+// subscribeEvents wires the server's own subsystems to the bus: health and
+// metrics count requests, the cache and subscriptions follow resource
+// changes, clients hear about list changes, and closed sessions are
+// cleaned up.
+func (s *Server) subscribeEvents() {
+	bus := s.events
+
+	bus.Subscribe(events.RequestReceived, func(e events.Event) {
+		s.health.record(1, 0)
+		s.errorMetrics.RecordRequest(e.Subject)
+		if client, ok := initializedClient(e); ok {
+			s.clientStats.RecordMethod(client.Name, client.Version, e.Subject)
+		}
+	})
+	bus.Subscribe(events.ToolCalled, func(e events.Event) {
+		if client, ok := initializedClient(e); ok {
+			s.clientStats.RecordTool(client.Name, client.Version, e.Subject)
+		}
+	})
+	bus.Subscribe(events.ErrorReplied, func(e events.Event) {
+		code, _ := e.Data.(int)
+		s.health.record(0, 1)
+		s.errorMetrics.RecordError(e.Subject, code)
+	})
+
+	bus.Subscribe(events.ResourceChanged, func(e events.Event) {
+		s.invalidateResource(e.Subject)
+		s.subscriptions.updated(e.Subject)
+	})
+	bus.Subscribe(events.ResourcesChanged, func(events.Event) {
+		s.broadcast(protocol.NotificationResourcesListChanged, struct{}{})
+	})
+	bus.Subscribe(events.PromptsChanged, func(events.Event) {
+		s.broadcast(protocol.NotificationPromptsListChanged, struct{}{})
+	})
+	toolsChanged := func(events.Event) {
+		s.broadcast(protocol.NotificationToolsListChanged, struct{}{})
+	}
+	bus.Subscribe(events.ToolsChanged, toolsChanged)
+	bus.Subscribe(events.ToolRegistered, toolsChanged)
+
+	bus.Subscribe(events.SessionClosed, func(e events.Event) {
+		sess, ok := e.Data.(*session.Session)
+		if !ok {
+			return
+		}
+		s.subscriptions.removeSession(sess)
+		// Anything still pending is undeliverable and goes to the dead-letter queue
+		_ = sess.Coalescer().Close()
+	})
+}
+
+// initializedClient returns the implementation of the client whose session
+// is e's Data, once it has introduced itself in initialize
+func initializedClient(e events.Event) (protocol.Implementation, bool) {
+	sess, ok := e.Data.(*session.Session)
+	if !ok || sess == nil || sess.ProtocolVersion() == "" {
+		return protocol.Implementation{}, false
+	}
+	return sess.ClientInfo(), true
+}
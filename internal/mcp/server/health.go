@@ -0,0 +1,234 @@
+// internal/mcp/server/health.go
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/clock"
+	"github.com/dkoosis/axe-handle/internal/events"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+	"github.com/dkoosis/axe-handle/internal/metrics"
+)
+
+// Health reporting settings
+const (
+	heartbeatInterval = 5 * time.Minute
+	healthWindow      = 5 * time.Minute
+	healthBucketSize  = time.Minute
+
+	// StatusResourceURI is the URI of the built-in server status resource
+	StatusResourceURI = "axe-handle://status"
+)
+
+// Health is a point-in-time snapshot of server health
+type Health struct {
+	Status        string    `json:"status"`
+	StartedAt     time.Time `json:"startedAt"`
+	UptimeSeconds int64     `json:"uptimeSeconds"`
+	Sessions      int       `json:"sessions"`
+	Requests      int       `json:"requests"`  // within the recent window
+	Errors        int       `json:"errors"`    // within the recent window
+	ErrorRate     float64   `json:"errorRate"` // Errors / Requests within the recent window
+	WindowSeconds int64     `json:"windowSeconds"`
+
+	Clients []metrics.ClientUsage `json:"clients,omitempty"` // only with metrics.clientStats
+}
+
+// healthBucket counts requests and errors for one slice of the window
+type healthBucket struct {
+	start    time.Time
+	requests int
+	errors   int
+}
+
+// healthTracker records start time and recent request outcomes
+type healthTracker struct {
+	clock     clock.Clock
+	startedAt time.Time
+	buckets   []healthBucket
+	mu        sync.Mutex
+}
+
+// newHealthTracker creates a tracker whose uptime starts now
+func newHealthTracker(c clock.Clock) *healthTracker {
+	return &healthTracker{
+		clock:     c,
+		startedAt: c.Now(),
+		buckets:   make([]healthBucket, int(healthWindow/healthBucketSize)),
+	}
+}
+
+// record adds request and error counts to the current bucket
+func (h *healthTracker) record(requests, errors int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	b := h.bucket(h.clock.Now())
+	b.requests += requests
+	b.errors += errors
+}
+
+// bucket returns the bucket for t, resetting it if it belongs to an older slice
+func (h *healthTracker) bucket(t time.Time) *healthBucket {
+	start := t.Truncate(healthBucketSize)
+	idx := int(start.Unix()/int64(healthBucketSize.Seconds())) % len(h.buckets)
+	b := &h.buckets[idx]
+	if !b.start.Equal(start) {
+		*b = healthBucket{start: start}
+	}
+	return b
+}
+
+// totals sums requests and errors for buckets still inside the window
+func (h *healthTracker) totals(now time.Time) (requests, errors int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cutoff := now.Add(-healthWindow)
+	for _, b := range h.buckets {
+		if b.start.After(cutoff) {
+			requests += b.requests
+			errors += b.errors
+		}
+	}
+	return requests, errors
+}
+
+// RecordRequest announces an incoming message from sess; health reporting
+// and metrics count it.
+func (s *Server) RecordRequest(sess *session.Session, method string) {
+	s.events.Publish(events.Event{Kind: events.RequestReceived, Subject: method, Data: sess})
+}
+
+// RecordError announces an error response sent to a client.
+func (s *Server) RecordError(method string, code int) {
+	s.events.Publish(events.Event{Kind: events.ErrorReplied, Subject: method, Data: code})
+}
+
+// ClientStats returns the usage counts per client implementation, or nil
+// unless metrics.clientStats opted in.
+func (s *Server) ClientStats() *metrics.ClientStats {
+	return s.clientStats
+}
+
+// ErrorMetrics returns the per-method error counters.
+func (s *Server) ErrorMetrics() *metrics.ErrorMetrics {
+	return s.errorMetrics
+}
+
+// Health returns a snapshot of the server's uptime, sessions and recent errors.
+func (s *Server) Health() Health {
+	now := s.clock.Now()
+	requests, errors := s.health.totals(now)
+
+	s.mu.RLock()
+	sessions := len(s.sessions)
+	shuttingDown := s.shutdownStarted
+	s.mu.RUnlock()
+
+	status := "running"
+	if shuttingDown {
+		status = "shutting_down"
+	}
+
+	var rate float64
+	if requests > 0 {
+		rate = float64(errors) / float64(requests)
+	}
+
+	return Health{
+		Status:        status,
+		StartedAt:     s.health.startedAt,
+		UptimeSeconds: int64(now.Sub(s.health.startedAt).Seconds()),
+		Sessions:      sessions,
+		Requests:      requests,
+		Errors:        errors,
+		ErrorRate:     rate,
+		WindowSeconds: int64(healthWindow.Seconds()),
+		Clients:       s.clientStats.Snapshot(),
+	}
+}
+
+// heartbeatService periodically logs server health for monitoring.
+func (s *Server) heartbeatService() {
+	ticker := s.clock.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C():
+			h := s.Health()
+			slog.Info("Server heartbeat",
+				"status", h.Status,
+				"uptime", time.Duration(h.UptimeSeconds)*time.Second,
+				"sessions", h.Sessions,
+				"requests", h.Requests,
+				"errors", h.Errors,
+				"error_rate", h.ErrorRate,
+			)
+		}
+	}
+}
+
+// statusProvider exposes server health as a read-only resource
+type statusProvider struct {
+	server *Server
+}
+
+// Ensure statusProvider implements resources.Provider and resources.ETagProvider
+var (
+	_ resources.Provider     = (*statusProvider)(nil)
+	_ resources.ETagProvider = (*statusProvider)(nil)
+)
+
+// ListResources returns the status resource
+func (p *statusProvider) ListResources() ([]resources.Resource, error) {
+	now := p.server.clock.Now().UTC()
+	return []resources.Resource{
+		{
+			URI:         StatusResourceURI,
+			Name:        "Server status",
+			Description: "Uptime, connected sessions and recent error rate",
+			MimeType:    "application/json",
+			Annotations: &protocol.Annotations{
+				Audience:     []protocol.Role{protocol.RoleUser},
+				LastModified: &now,
+			},
+		},
+	}, nil
+}
+
+// GetResource returns the current health snapshot as JSON
+func (p *statusProvider) GetResource(uri string) (interface{}, error) {
+	if uri != StatusResourceURI {
+		return nil, resources.ErrResourceNotFound
+	}
+	data, err := json.MarshalIndent(p.server.Health(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// ResourceETag changes every second so a cached status is never older than that
+func (p *statusProvider) ResourceETag(uri string) (string, error) {
+	if uri != StatusResourceURI {
+		return "", resources.ErrResourceNotFound
+	}
+	return strconv.FormatInt(p.server.clock.Now().Unix(), 10), nil
+}
+
+// startHeartbeat starts the heartbeat service once per server lifetime.
+func (s *Server) startHeartbeat() {
+	s.heartbeatOnce.Do(func() {
+		go s.heartbeatService()
+	})
+}
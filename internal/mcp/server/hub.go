@@ -0,0 +1,90 @@
+// internal/mcp/server/hub.go
+package server
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/notify"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+)
+
+// hub is the server's notify.NotificationPublisher. Everything goes through
+// Notify or Session.Log, which gate on each client's negotiated features.
+type hub struct {
+	s *Server
+}
+
+// Ensure hub implements notify.NotificationPublisher
+var _ notify.NotificationPublisher = hub{}
+
+// Publisher returns the publisher providers use to notify clients
+func (s *Server) Publisher() notify.NotificationPublisher {
+	return hub{s: s}
+}
+
+// attachPublisher hands the publisher to providers that want one
+func (s *Server) attachPublisher(provider interface{}) {
+	if aware, ok := provider.(notify.PublisherAware); ok {
+		aware.SetNotificationPublisher(s.Publisher())
+	}
+}
+
+// ResourceUpdated notifies the subscribers of uri
+func (h hub) ResourceUpdated(uri string) {
+	h.s.NotifyResourceUpdated(uri)
+}
+
+// ResourcesChanged notifies every client of a resource list change
+func (h hub) ResourcesChanged() {
+	h.s.NotifyResourcesListChanged()
+}
+
+// ToolsChanged notifies every client of a tool list change
+func (h hub) ToolsChanged() {
+	h.s.notifyToolsListChanged()
+}
+
+// PromptsChanged notifies every client of a prompt list change
+func (h hub) PromptsChanged() {
+	h.s.NotifyPromptsListChanged()
+}
+
+// Log sends a log message to the session in ctx, or to every session
+func (h hub) Log(ctx context.Context, level protocol.LoggingLevel, logger string, data interface{}) {
+	if !level.Valid() {
+		level = protocol.LoggingLevelInfo
+	}
+	// The message outlives the request that produced it
+	ctx = context.WithoutCancel(ctx)
+	for _, sess := range h.targets(ctx) {
+		sess.Log(ctx, level, logger, data)
+	}
+}
+
+// Progress sends a progress notification to the session in ctx. Tokens
+// belong to one client, so without a session there is no one to tell.
+func (h hub) Progress(ctx context.Context, token string, progress, total float64) {
+	sess := session.FromContext(ctx)
+	if sess == nil || token == "" {
+		slog.Debug("Dropped progress without a requesting client", "token", token)
+		return
+	}
+	params := protocol.ProgressParams{ProgressToken: token, Progress: progress, Total: total}
+	_ = h.s.Notify(context.WithoutCancel(ctx), sess, protocol.NotificationProgress, params)
+}
+
+// targets returns the session in ctx, or every session when there is none
+func (h hub) targets(ctx context.Context) []*session.Session {
+	if sess := session.FromContext(ctx); sess != nil {
+		return []*session.Session{sess}
+	}
+	h.s.mu.RLock()
+	defer h.s.mu.RUnlock()
+	sessions := make([]*session.Session, 0, len(h.s.sessions))
+	for _, sess := range h.s.sessions {
+		sessions = append(sessions, sess)
+	}
+	return sessions
+}
@@ -0,0 +1,247 @@
+// internal/mcp/server/instructions.go
+package server
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+	"github.com/dkoosis/axe-handle/internal/transport"
+)
+
+// Bounds on how much of the server the instructions describe; clients list
+// the rest themselves
+const (
+	maxInstructionTools     = 25
+	maxInstructionResources = 10
+	maxInstructionPrompts   = 10
+
+	// instructionsListTimeout bounds listing resources, which some
+	// providers fetch over the network, so initialize stays fast
+	instructionsListTimeout = time.Second
+)
+
+// InstructionsItem is a tool, resource, template or prompt named in the
+// instructions
+type InstructionsItem struct {
+	Name        string
+	URI         string // resource URI or URI template; empty for tools and prompts
+	Description string // first sentence only
+}
+
+// InstructionsData is what the instructions template is executed with.
+// Lists hold what the client's profile allows, most notable first.
+type InstructionsData struct {
+	Name      string
+	Version   string
+	Profile   string // "" for the whole server
+	Tools     []InstructionsItem
+	Resources []InstructionsItem
+	Templates []InstructionsItem
+	Prompts   []InstructionsItem
+
+	// How many of each were left out
+	MoreTools     int
+	MoreResources int
+	MorePrompts   int
+
+	Hints []string // advice from providers on using them
+}
+
+// defaultInstructions is used unless server.instructions sets a template
+var defaultInstructions = template.Must(template.New("instructions").Parse(
+	`{{.Name}} {{.Version}}{{with .Profile}} (profile {{.}}){{end}} is a Model Context Protocol server.
+{{- if .Tools}}
+
+Tools:
+{{- range .Tools}}
+- {{.Name}}{{with .Description}}: {{.}}{{end}}
+{{- end}}
+{{- with .MoreTools}}
+- and {{.}} more, see tools/list
+{{- end}}
+{{- end}}
+{{- if .Resources}}
+
+Resources:
+{{- range .Resources}}
+- {{.URI}}{{with .Description}}: {{.}}{{end}}
+{{- end}}
+{{- with .MoreResources}}
+- and {{.}} more, see resources/list
+{{- end}}
+{{- end}}
+{{- if .Templates}}
+
+Resource templates:
+{{- range .Templates}}
+- {{.URI}}{{with .Description}}: {{.}}{{end}}
+{{- end}}
+{{- end}}
+{{- if .Prompts}}
+
+Prompts:
+{{- range .Prompts}}
+- {{.Name}}{{with .Description}}: {{.}}{{end}}
+{{- end}}
+{{- with .MorePrompts}}
+- and {{.}} more, see prompts/list
+{{- end}}
+{{- end}}
+{{- if .Hints}}
+
+Usage notes:
+{{- range .Hints}}
+- {{.}}
+{{- end}}
+{{- end}}`))
+
+// newInstructions parses the operator's instructions template, falling
+// back to the default when there is none or it does not parse
+func newInstructions(text string) *template.Template {
+	if text == "" {
+		return defaultInstructions
+	}
+	t, err := template.New("instructions").Parse(text)
+	if err != nil {
+		slog.Warn("Ignoring server.instructions", "error", err)
+		return defaultInstructions
+	}
+	return t
+}
+
+// AddUsageHint adds advice on using the server to the instructions, for
+// providers that register tools without registering a provider
+func (s *Server) AddUsageHint(hint string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usageHints = append(s.usageHints, hint)
+}
+
+// generateInstructions describes what the client in ctx can use, by
+// executing the instructions template over the server's tools, resources
+// and prompts and its providers' hints
+func (s *Server) generateInstructions(ctx context.Context) string {
+	data := s.instructionsData(ctx)
+
+	var b strings.Builder
+	if err := s.instructions.Execute(&b, data); err != nil {
+		slog.Warn("Failed to execute instructions template, using default", "error", err)
+		b.Reset()
+		_ = defaultInstructions.Execute(&b, data)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// instructionsData gathers what the instructions describe
+func (s *Server) instructionsData(ctx context.Context) InstructionsData {
+	var p *session.Profile
+	if sess := session.FromContext(ctx); sess != nil {
+		p = sess.Profile()
+	}
+
+	data := InstructionsData{
+		Name:    s.config.Server.Name,
+		Version: s.config.Server.Version,
+		Profile: transport.Profile(ctx),
+	}
+
+	tools := s.toolsManager.ListTools()
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	for _, tool := range tools {
+		if !p.AllowsTool(tool.Name) {
+			continue
+		}
+		if len(data.Tools) == maxInstructionTools {
+			data.MoreTools++
+			continue
+		}
+		data.Tools = append(data.Tools, InstructionsItem{Name: tool.Name, Description: firstSentence(tool.Description)})
+	}
+
+	list := s.listResourcesBriefly(ctx)
+	sort.SliceStable(list, func(i, j int) bool { return priority(list[i]) > priority(list[j]) })
+	for _, res := range list {
+		if len(data.Resources) == maxInstructionResources {
+			data.MoreResources++
+			continue
+		}
+		desc := firstSentence(res.Description)
+		if desc == "" {
+			desc = res.Name
+		}
+		data.Resources = append(data.Resources, InstructionsItem{Name: res.Name, URI: res.URI, Description: desc})
+	}
+
+	if templates, err := s.providerRegistry.ListResourceTemplates(ctx); err == nil {
+		for _, t := range templates {
+			desc := firstSentence(t.Description)
+			if desc == "" {
+				desc = t.Name
+			}
+			data.Templates = append(data.Templates, InstructionsItem{Name: t.Name, URI: t.URITemplate, Description: desc})
+		}
+	}
+
+	if prompts, err := s.providerRegistry.ListPrompts(ctx); err == nil {
+		for _, pr := range prompts {
+			if len(data.Prompts) == maxInstructionPrompts {
+				data.MorePrompts++
+				continue
+			}
+			data.Prompts = append(data.Prompts, InstructionsItem{Name: pr.Name, Description: firstSentence(pr.Description)})
+		}
+	}
+
+	data.Hints = s.providerRegistry.UsageHints()
+	s.mu.RLock()
+	data.Hints = append(data.Hints, s.usageHints...)
+	s.mu.RUnlock()
+	return data
+}
+
+// listResourcesBriefly lists the resources the client can see, giving up
+// after instructionsListTimeout
+func (s *Server) listResourcesBriefly(ctx context.Context) []resources.Resource {
+	ctx, cancel := s.clock.WithTimeout(ctx, instructionsListTimeout)
+	defer cancel()
+
+	done := make(chan []resources.Resource, 1)
+	go func() {
+		list, err := s.providerRegistry.ListResources(ctx)
+		if err != nil {
+			slog.Debug("Leaving resources out of instructions", "error", err)
+		}
+		done <- list
+	}()
+
+	select {
+	case list := <-done:
+		return list
+	case <-ctx.Done():
+		slog.Debug("Leaving resources out of instructions, listing is slow")
+		return nil
+	}
+}
+
+// priority returns a resource's annotated priority, 0 when it has none
+func priority(res resources.Resource) float64 {
+	if res.Annotations == nil || res.Annotations.Priority == nil {
+		return 0
+	}
+	return *res.Annotations.Priority
+}
+
+// firstSentence returns the first line of text up to its first full stop
+func firstSentence(text string) string {
+	text, _, _ = strings.Cut(strings.TrimSpace(text), "\n")
+	if i := strings.Index(text, ". "); i >= 0 {
+		text = text[:i+1]
+	}
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,80 @@
+// internal/mcp/server/jsonrpc/cancel.go
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// requestKey identifies a request on its connection
+type requestKey struct {
+	conn *jsonrpc2.Conn
+	id   jsonrpc2.ID
+}
+
+// inflight holds the cancel funcs of queued and running requests, so a
+// notifications/cancelled from the client can stop them
+type inflight struct {
+	mu      sync.Mutex
+	cancels map[requestKey]context.CancelFunc
+}
+
+// track derives a cancellable context for req and remembers how to cancel
+// it until untrack
+func (f *inflight) track(ctx context.Context, conn *jsonrpc2.Conn, id jsonrpc2.ID) context.Context {
+	ctx, cancel := context.WithCancel(ctx)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.cancels == nil {
+		f.cancels = make(map[requestKey]context.CancelFunc)
+	}
+	f.cancels[requestKey{conn, id}] = cancel
+	return ctx
+}
+
+// untrack forgets a finished request and releases its context
+func (f *inflight) untrack(conn *jsonrpc2.Conn, id jsonrpc2.ID) {
+	f.mu.Lock()
+	cancel, ok := f.cancels[requestKey{conn, id}]
+	delete(f.cancels, requestKey{conn, id})
+	f.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+// cancel cancels the context of the request with id on conn, reporting
+// whether it was still queued or running
+func (f *inflight) cancel(conn *jsonrpc2.Conn, id jsonrpc2.ID) bool {
+	f.mu.Lock()
+	cancel, ok := f.cancels[requestKey{conn, id}]
+	f.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// handleCancelled stops the request a notifications/cancelled names. A
+// request that already finished, or never existed, is ignored as the spec
+// asks.
+func (h *Handler) handleCancelled(conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	var params struct {
+		RequestID jsonrpc2.ID `json:"requestId"`
+		Reason    string      `json:"reason,omitempty"`
+	}
+	if req.Params == nil {
+		return
+	}
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		slog.Debug("Ignoring malformed cancellation", "error", err)
+		return
+	}
+	if h.inflight.cancel(conn, params.RequestID) {
+		slog.Info("Request cancelled by client", "id", params.RequestID, "reason", params.Reason)
+	}
+}
@@ -0,0 +1,112 @@
+// internal/mcp/server/jsonrpc/cancel_test.go
+package jsonrpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/pkg/mcptest"
+)
+
+func TestCancelledToolCallStops(t *testing.T) {
+	started := make(chan struct{})
+	stopped := make(chan struct{})
+	wait := protocol.Tool{Name: "wait", InputSchema: json.RawMessage(`{"type":"object"}`)}
+	w := mcptest.Dial(mcptest.WithTool(wait, func(ctx context.Context, _ json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		close(started)
+		<-ctx.Done()
+		close(stopped)
+		return protocol.ToolsCallResult{}, ctx.Err()
+	}))
+	defer w.Close()
+
+	send := func(msg string) {
+		t.Helper()
+		if err := w.Send([]byte(msg)); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+	if _, err := w.RoundTrip([]byte(`{"jsonrpc":"2.0","id":"init","method":"initialize","params":{"protocolVersion":"` +
+		protocol.LatestProtocolVersion + `","capabilities":{},"clientInfo":{"name":"test","version":"0"}}}`)); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+	send(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"wait","arguments":{}}}`)
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tool call never started")
+	}
+
+	send(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7,"reason":"user gave up"}}`)
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelled tool call kept running")
+	}
+
+	// The connection keeps serving after a cancellation
+	if _, err := w.RoundTrip([]byte(`{"jsonrpc":"2.0","id":8,"method":"ping"}`)); err != nil {
+		t.Fatalf("ping after cancel: %v", err)
+	}
+}
+
+func TestCancelledQueuedCallNeverRuns(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	probes := make(chan string, 4)
+	schema := json.RawMessage(`{"type":"object"}`)
+	w := mcptest.Dial(
+		mcptest.WithServerOptions(func(c *config.Config) { c.Server.Workers = 1 }),
+		mcptest.WithTool(protocol.Tool{Name: "block", InputSchema: schema}, func(ctx context.Context, _ json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+			close(started)
+			<-release
+			return protocol.ToolsCallResult{}, nil
+		}),
+		mcptest.WithTool(protocol.Tool{Name: "probe", InputSchema: schema}, func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+			probes <- string(args)
+			return protocol.ToolsCallResult{}, nil
+		}),
+	)
+	defer w.Close()
+
+	send := func(msg string) {
+		t.Helper()
+		if err := w.Send([]byte(msg)); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+	if _, err := w.RoundTrip([]byte(`{"jsonrpc":"2.0","id":"init","method":"initialize","params":{"protocolVersion":"` +
+		protocol.LatestProtocolVersion + `","capabilities":{},"clientInfo":{"name":"test","version":"0"}}}`)); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+	// The only worker is busy, so the first probe waits in the queue
+	send(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"block","arguments":{}}}`)
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("blocking call never started")
+	}
+	send(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"probe","arguments":{"n":"cancelled"}}}`)
+	send(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":2}}`)
+	close(release)
+
+	// Calls run in order, so once the second probe answers the first was
+	// dropped or ran
+	if _, err := w.RoundTrip([]byte(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"probe","arguments":{"n":"kept"}}}`)); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	close(probes)
+	for got := range probes {
+		if got != `{"n":"kept"}` {
+			t.Errorf("probe ran with %s, want only the uncancelled call", got)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+// internal/mcp/server/jsonrpc/dispatch.go
+package jsonrpc
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/pkg/mcperrors"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// Default worker pool sizing
+const (
+	DefaultWorkers   = 8
+	DefaultQueueSize = 64
+)
+
+// lane is the priority with which a method is scheduled
+type lane int
+
+const (
+	// laneControl methods are cheap and run inline so they are never stuck
+	// behind slow work (ping, cancellation, logging/setLevel, lifecycle)
+	laneControl lane = iota
+
+	// laneQuery methods are quick reads that workers take ahead of queued
+	// tool calls
+	laneQuery
+
+	// laneCall methods may run for minutes
+	laneCall
+
+	numLanes
+)
+
+// String names the lane in logs
+func (l lane) String() string {
+	switch l {
+	case laneControl:
+		return "control"
+	case laneQuery:
+		return "query"
+	default:
+		return "call"
+	}
+}
+
+// methodLanes lists methods dispatched to the worker pool; everything else
+// is a control message
+var methodLanes = map[string]lane{
+	protocol.MethodToolsCall:          laneCall,
+	protocol.MethodToolsList:          laneQuery,
+	protocol.MethodResourcesList:      laneQuery,
+	protocol.MethodResourcesRead:      laneQuery,
+	protocol.MethodResourcesTemplates: laneQuery,
+	protocol.MethodCompletionComplete: laneQuery,
+	protocol.MethodPromptsList:        laneQuery,
+	protocol.MethodPromptsGet:         laneQuery,
+}
+
+// classify returns the lane for a request; notifications are always
+// control messages, so a notifications/cancelled overtakes the call it
+// cancels whether that call is running or still queued
+func classify(req *jsonrpc2.Request) lane {
+	if req.Notif {
+		return laneControl
+	}
+	if l, ok := methodLanes[req.Method]; ok {
+		return l
+	}
+	return laneControl
+}
+
+// job is a request waiting for a worker
+type job struct {
+	ctx  context.Context
+	conn *jsonrpc2.Conn
+	req  *jsonrpc2.Request
+}
+
+// workerPool runs queued requests with bounded concurrency. Each lane has
+// its own queue, so a backlog of tool calls neither delays nor crowds out
+// quick reads: workers always take queries first, and with more than one
+// worker, one is reserved for queries so they are served even while every
+// other worker is busy with a long call.
+type workerPool struct {
+	queues [numLanes]chan job // indexed by lane; the control lane has none
+	run    func(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request)
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// newWorkerPool starts workers that call run for each queued job; each
+// lane queues up to queueSize jobs
+func newWorkerPool(workers, queueSize int, run func(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request)) *workerPool {
+	p := &workerPool{run: run}
+	for l := laneQuery; l < numLanes; l++ {
+		p.queues[l] = make(chan job, queueSize)
+	}
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go p.worker(workers > 1 && i == 0)
+	}
+	return p
+}
+
+// worker processes jobs until the pool is closed, preferring queries. A
+// reserved worker never takes tool calls.
+func (p *workerPool) worker(reserved bool) {
+	defer p.wg.Done()
+	queries, calls := p.queues[laneQuery], p.queues[laneCall]
+	if reserved {
+		calls = nil
+	}
+	for queries != nil || calls != nil {
+		if queries != nil {
+			select {
+			case j, ok := <-queries:
+				if !ok {
+					queries = nil
+					continue
+				}
+				p.run(j.ctx, j.conn, j.req)
+				continue
+			default:
+			}
+		}
+		// A nil channel never delivers, so a drained lane drops out
+		select {
+		case j, ok := <-queries:
+			if !ok {
+				queries = nil
+				continue
+			}
+			p.run(j.ctx, j.conn, j.req)
+		case j, ok := <-calls:
+			if !ok {
+				calls = nil
+				continue
+			}
+			p.run(j.ctx, j.conn, j.req)
+		}
+	}
+}
+
+// submit queues a job in its lane, reporting false if the lane is full
+func (p *workerPool) submit(l lane, j job) bool {
+	select {
+	case p.queues[l] <- j:
+		return true
+	default:
+		return false
+	}
+}
+
+// close stops accepting jobs and waits for queued ones to finish
+func (p *workerPool) close() {
+	p.once.Do(func() {
+		for l := laneQuery; l < numLanes; l++ {
+			close(p.queues[l])
+		}
+	})
+	p.wg.Wait()
+}
+
+// HandlerOption configures a Handler
+type HandlerOption func(*Handler)
+
+// WithWorkers sets the worker pool size and the queue depth of each lane
+func WithWorkers(workers, queueSize int) HandlerOption {
+	return func(h *Handler) {
+		if workers > 0 {
+			h.workers = workers
+		}
+		if queueSize >= 0 {
+			h.queueSize = queueSize
+		}
+	}
+}
+
+// WithStrict makes the handler panic on inconsistencies, including panics
+// it would otherwise recover from, after answering the request at hand.
+// Meant for development, where a crash with a stack beats a quiet error.
+func WithStrict(strict bool) HandlerOption {
+	return func(h *Handler) {
+		h.strict = strict
+	}
+}
+
+// dispatch runs control messages inline and queues the rest in their lane.
+// When the lane is full, or the caller's tenant has used up its rate limit,
+// the request is rejected so the caller can retry later.
+func (h *Handler) dispatch(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	l := classify(req)
+	if l == laneControl {
+		h.handle(ctx, conn, req)
+		return
+	}
+
+	if err := h.server.Admit(h.server.Session(conn), req.Method); err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	// Queued and running requests can be cancelled by the client
+	jobCtx := h.inflight.track(ctx, conn, req.ID)
+	if !h.pool.submit(l, job{ctx: jobCtx, conn: conn, req: req}) {
+		h.inflight.untrack(conn, req.ID)
+		slog.Warn("Worker queue full, rejecting request", "method", req.Method, "id", req.ID, "lane", l)
+		h.sendError(ctx, conn, req, mcperrors.NewRateLimitedError(0))
+	}
+}
+
+// handleQueued handles a request taken from the pool, then forgets it. A
+// request cancelled while it waited is dropped without a reply, which the
+// client no longer expects.
+func (h *Handler) handleQueued(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	defer h.inflight.untrack(conn, req.ID)
+	if ctx.Err() != nil {
+		slog.Debug("Dropping request cancelled in queue", "method", req.Method, "id", req.ID)
+		return
+	}
+	h.handle(ctx, conn, req)
+}
+
+// Close stops the worker pool after draining queued requests
+func (h *Handler) Close() error {
+	h.pool.close()
+	return nil
+}
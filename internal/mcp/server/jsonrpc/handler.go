@@ -6,8 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"runtime/debug"
 
+	promptsapi "github.com/dkoosis/axe-handle/internal/mcp/prompts/api"
 	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	resourcesapi "github.com/dkoosis/axe-handle/internal/mcp/resources/api"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
 	"github.com/dkoosis/axe-handle/internal/mcp/tools/api"
 	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
 	"github.com/dkoosis/axe-handle/pkg/mcperrors"
@@ -18,65 +22,195 @@ import (
 type ServerInterface interface {
 	Initialize(ctx context.Context, params protocol.InitializeParams) (*protocol.InitializeResult, error)
 	Initialized(ctx context.Context) error
+	RootsListChanged(ctx context.Context) error
 	CheckInitialized() error
 	GetToolsManager() *manager.ToolsManager
+	Session(conn *jsonrpc2.Conn) *session.Session
+	SetLogLevel(ctx context.Context, level protocol.LoggingLevel) error
+	RecordRequest(sess *session.Session, method string)
+	Admit(sess *session.Session, method string) error
+	RequestContext(ctx context.Context) context.Context
+	RecordError(method string, code int)
+	ListResources(ctx context.Context, cursor string) ([]protocol.Resource, string, error)
+	ReadResource(ctx context.Context, params protocol.ResourcesReadParams) (*protocol.ResourcesReadResult, error)
+	Subscribe(ctx context.Context, uri string) error
+	Unsubscribe(ctx context.Context, uri string) error
+	ListResourceTemplates(ctx context.Context) ([]protocol.ResourceTemplate, error)
+	Complete(ctx context.Context, params protocol.CompleteParams) (*protocol.CompleteResult, error)
+	ListPrompts(ctx context.Context) ([]protocol.Prompt, error)
+	GetPrompt(ctx context.Context, name string, args map[string]string) (interface{}, error)
 }
 
+// methodHandler handles a single JSON-RPC request method
+type methodHandler func(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request)
+
 // Handler implements the jsonrpc2.Handler interface
 type Handler struct {
-	server       ServerInterface
-	toolsHandler *api.ToolsHandler
-	// You would add other handlers here (resources, prompts, etc.)
+	server           ServerInterface
+	toolsHandler     *api.ToolsHandler
+	resourcesHandler *resourcesapi.ResourcesHandler
+	promptsHandler   *promptsapi.PromptsHandler
+
+	// routes maps request methods to their handlers
+	routes map[string]methodHandler
+
+	// pool runs slow methods so control messages stay responsive
+	pool      *workerPool
+	workers   int
+	queueSize int
+
+	// strict panics on inconsistencies instead of papering over them
+	strict bool
+
+	// inflight lets clients cancel queued and running requests
+	inflight inflight
 }
 
 // NewHandler creates a new jsonrpc2 handler that delegates to the MCP server
-func NewHandler(server ServerInterface) *Handler {
-	return &Handler{
-		server:       server,
-		toolsHandler: api.NewToolsHandler(server),
+func NewHandler(server ServerInterface, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		server:    server,
+		workers:   DefaultWorkers,
+		queueSize: DefaultQueueSize,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.toolsHandler = api.NewToolsHandler(server, h.sendError)
+	h.resourcesHandler = resourcesapi.NewResourcesHandler(server, h.sendError)
+	h.promptsHandler = promptsapi.NewPromptsHandler(server, h.sendError)
+	h.pool = newWorkerPool(h.workers, h.queueSize, h.handleQueued)
+	h.routes = map[string]methodHandler{
+		protocol.MethodInitialize:           h.handleInitialize,
+		protocol.MethodPing:                 h.handlePing,
+		protocol.MethodToolsList:            h.toolsHandler.HandleToolsList,
+		protocol.MethodToolsCall:            h.toolsHandler.HandleToolsCall,
+		protocol.MethodResourcesList:        h.resourcesHandler.HandleResourcesList,
+		protocol.MethodResourcesRead:        h.resourcesHandler.HandleResourcesRead,
+		protocol.MethodResourcesSubscribe:   h.resourcesHandler.HandleResourcesSubscribe,
+		protocol.MethodResourcesUnsubscribe: h.resourcesHandler.HandleResourcesUnsubscribe,
+		protocol.MethodResourcesTemplates:   h.resourcesHandler.HandleResourceTemplatesList,
+		protocol.MethodCompletionComplete:   h.resourcesHandler.HandleComplete,
+		protocol.MethodPromptsList:          h.promptsHandler.HandlePromptsList,
+		protocol.MethodPromptsGet:           h.promptsHandler.HandlePromptsGet,
+		protocol.MethodLoggingSetLevel:      h.handleSetLevel,
 	}
+	return h
 }
 
-// Handle handles JSON-RPC 2.0 requests and notifications
+// Handle handles JSON-RPC 2.0 requests and notifications, handing slow
+// methods to the worker pool so control messages are answered promptly.
 func (h *Handler) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
 	// Log the incoming request
 	slog.Debug("Received request",
 		"method", req.Method,
 		"id", req.ID)
 
+	h.server.RecordRequest(h.server.Session(conn), req.Method)
+	h.dispatch(ctx, conn, req)
+}
+
+// handle processes a single request or notification
+func (h *Handler) handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	// Never let a panicking handler take down the connection
+	defer h.recoverPanic(ctx, conn, req)
+
+	// Make the connection's session available to everything downstream
+	sess := h.server.Session(conn)
+	if sess == nil {
+		h.inconsistent("no session for connection", "method", req.Method)
+	}
+	ctx = h.server.RequestContext(session.WithSession(ctx, sess))
+
+	// Clients on older protocol versions get results they understand
+	var responder protocol.ResponderConn = conn
+	if sess != nil {
+		responder = protocol.NewDowngradingConn(conn, sess.ProtocolVersion())
+	}
+
+	// Notifications never get a reply, whatever their method
+	if req.Notif {
+		if req.Method == protocol.NotificationCancelled {
+			h.handleCancelled(conn, req)
+			return
+		}
+		h.handleNotification(ctx, responder, req)
+		return
+	}
+
 	// Handle the request based on its method
+	route, ok := h.routes[req.Method]
+	if !ok {
+		suggestions := suggestMethods(req.Method, h.methods())
+		h.sendError(ctx, conn, req, mcperrors.NewMethodNotFoundError(req.Method, suggestions...))
+		return
+	}
+	route(ctx, responder, req)
+}
+
+// methods returns the names of all routed request methods
+func (h *Handler) methods() []string {
+	methods := make([]string, 0, len(h.routes))
+	for method := range h.routes {
+		methods = append(methods, method)
+	}
+	return methods
+}
+
+// inconsistent reports a state the server should never reach. Strict
+// handlers panic so the bug is fixed rather than worked around; otherwise
+// it is logged and handling carries on.
+func (h *Handler) inconsistent(msg string, args ...any) {
+	if h.strict {
+		panic(fmt.Sprintf("inconsistency: %s %v", msg, args))
+	}
+	slog.Error("Inconsistency: "+msg, args...)
+}
+
+// recoverPanic converts a panic during Handle into an InternalError reply.
+// The stack is logged; the client only sees a sanitized message. Strict
+// handlers re-panic once the client has its reply.
+func (h *Handler) recoverPanic(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	slog.Error("Panic while handling request",
+		"method", req.Method,
+		"id", req.ID,
+		"panic", r,
+		"stack", string(debug.Stack()))
+
+	h.sendError(ctx, conn, req, mcperrors.NewInternalError(fmt.Errorf("internal error while handling %s", req.Method)))
+	if h.strict {
+		panic(r)
+	}
+}
+
+// handleNotification dispatches notifications, which must not be answered
+func (h *Handler) handleNotification(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request) {
 	switch req.Method {
-	case protocol.MethodInitialize:
-		h.handleInitialize(ctx, conn, req)
-	case protocol.MethodPing:
-		h.handlePing(ctx, conn, req)
-	case protocol.MethodToolsList:
-		h.toolsHandler.HandleToolsList(ctx, conn, req)
-	case protocol.MethodToolsCall:
-		h.toolsHandler.HandleToolsCall(ctx, conn, req)
 	case protocol.NotificationInitialized:
 		h.handleInitialized(ctx, conn, req)
-	default:
-		err := mcperrors.NewMethodNotFoundError(req.Method)
-		// Check if request has a valid ID (meaning it's not a notification)
-		if isValidID(req.ID) {
-			// Only send error for requests, not notifications
-			if err := conn.ReplyWithError(ctx, req.ID, protocol.ErrorConverter(err)); err != nil {
-				slog.Error("Failed to send error response", "error", err)
-			}
+	case protocol.NotificationRootsListChanged:
+		if err := h.server.RootsListChanged(ctx); err != nil {
+			slog.Error("Error handling roots change", "error", err)
 		}
+	default:
+		slog.Debug("Ignoring unhandled notification", "method", req.Method)
 	}
 }
 
 // handleInitialize processes the initialize request
 // In internal/mcp/server/jsonrpc/handler.go -> handleInitialize
 
-func (h *Handler) handleInitialize(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+func (h *Handler) handleInitialize(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request) {
 	var params protocol.InitializeParams
 	slog.Debug("Attempting to unmarshal Initialize params") // <-- Add
 	if err := json.Unmarshal(*req.Params, &params); err != nil {
 		slog.Error("Failed to unmarshal Initialize params", "error", err) // <-- Add info
-		h.sendError(ctx, conn, req.ID, mcperrors.NewInvalidParamsError(err))
+		h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(err))
 		return
 	}
 
@@ -85,14 +219,14 @@ func (h *Handler) handleInitialize(ctx context.Context, conn *jsonrpc2.Conn, req
 	slog.Debug("Returned from server.Initialize", "error", err) // <-- Add log
 	if err != nil {
 		slog.Error("server.Initialize returned error", "error", err) // <-- Add info
-		h.sendError(ctx, conn, req.ID, err)
+		h.sendError(ctx, conn, req, err)
 		return
 	}
 
 	// Check if result is nil just in case, though Initialize shouldn't return nil result on success
 	if result == nil {
-		slog.Error("server.Initialize returned nil result with nil error")
-		h.sendError(ctx, conn, req.ID, mcperrors.NewInternalError(fmt.Errorf("unexpected nil result from Initialize")))
+		h.inconsistent("server.Initialize returned nil result with nil error")
+		h.sendError(ctx, conn, req, mcperrors.NewInternalError(fmt.Errorf("unexpected nil result from Initialize")))
 		return
 	}
 
@@ -107,7 +241,7 @@ func (h *Handler) handleInitialize(ctx context.Context, conn *jsonrpc2.Conn, req
 }
 
 // handleInitialized processes the initialized notification
-func (h *Handler) handleInitialized(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+func (h *Handler) handleInitialized(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request) {
 	// This is a notification, so no response is needed
 	err := h.server.Initialized(ctx)
 	if err != nil {
@@ -116,29 +250,45 @@ func (h *Handler) handleInitialized(ctx context.Context, conn *jsonrpc2.Conn, re
 }
 
 // handlePing processes the ping request
-func (h *Handler) handlePing(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+func (h *Handler) handlePing(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request) {
 	// Simply reply with an empty object
 	if err := conn.Reply(ctx, req.ID, struct{}{}); err != nil {
 		slog.Error("Failed to send ping response", "error", err)
 	}
 }
 
-// sendError sends an error response
-func (h *Handler) sendError(ctx context.Context, conn *jsonrpc2.Conn, id jsonrpc2.ID, err error) {
-	// Only send error if ID is valid
-	if isValidID(id) {
-		if err := conn.ReplyWithError(ctx, id, protocol.ErrorConverter(err)); err != nil {
-			slog.Error("Failed to send error response", "error", err)
-		}
+// handleSetLevel processes the logging/setLevel request
+func (h *Handler) handleSetLevel(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request) {
+	var params protocol.SetLevelParams
+	if req.Params == nil {
+		h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(fmt.Errorf("missing params")))
+		return
+	}
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(err))
+		return
+	}
+
+	if err := h.server.SetLogLevel(ctx, params.Level); err != nil {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	if err := conn.Reply(ctx, req.ID, struct{}{}); err != nil {
+		slog.Error("Failed to send setLevel response", "error", err)
 	}
 }
 
-// isValidID checks if the ID is valid for responding
-func isValidID(id jsonrpc2.ID) bool {
-	// For string IDs, check if it's not empty
-	if id.IsString {
-		return id.Str != ""
+// sendError sends an error response unless req is a notification
+func (h *Handler) sendError(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request, err error) {
+	if req.Notif {
+		slog.Debug("Not replying to notification with error", "method", req.Method, "error", err)
+		return
+	}
+
+	rpcErr := protocol.ErrorConverter(ctx, err)
+	h.server.RecordError(req.Method, int(rpcErr.Code))
+	if err := conn.ReplyWithError(ctx, req.ID, rpcErr); err != nil {
+		slog.Error("Failed to send error response", "error", err)
 	}
-	// For numeric IDs, check if it's not zero
-	return id.Num != 0
 }
@@ -0,0 +1,72 @@
+// internal/mcp/server/jsonrpc/suggest.go
+package jsonrpc
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestions caps the "did you mean" list in method-not-found errors
+const maxSuggestions = 3
+
+// suggestMethods returns the known methods closest to method by edit
+// distance, nearest first. Methods too far away to be a typo are omitted.
+func suggestMethods(method string, known []string) []string {
+	type candidate struct {
+		method   string
+		distance int
+	}
+
+	// Allow roughly one edit per three characters, but at least two
+	threshold := len(method) / 3
+	if threshold < 2 {
+		threshold = 2
+	}
+
+	lower := strings.ToLower(method)
+	var candidates []candidate
+	for _, k := range known {
+		if d := levenshtein(lower, strings.ToLower(k)); d <= threshold {
+			candidates = append(candidates, candidate{method: k, distance: d})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].method < candidates[j].method
+	})
+
+	suggestions := make([]string, 0, maxSuggestions)
+	for _, c := range candidates {
+		if len(suggestions) == maxSuggestions {
+			break
+		}
+		suggestions = append(suggestions, c.method)
+	}
+	return suggestions
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,134 @@
+// internal/mcp/server/notify.go
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/dkoosis/axe-handle/internal/events"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/resources/watch"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+)
+
+// Ensure Server can receive filesystem watcher notifications
+var _ watch.Notifier = (*Server)(nil)
+
+// newDeadLetterQueue creates the dead-letter queue, falling back to memory
+// only if the persisted file cannot be loaded.
+func newDeadLetterQueue(path string) *session.DeadLetterQueue {
+	q, err := session.NewDeadLetterQueue(session.DefaultDeadLetterCapacity, path)
+	if err != nil {
+		slog.Error("Failed to load dead letters, starting empty", "path", path, "error", err)
+		q, _ = session.NewDeadLetterQueue(session.DefaultDeadLetterCapacity, "")
+	}
+	return q
+}
+
+// Notify sends a notification to a session. Notifications the client has
+// not negotiated are dropped. High-frequency kinds are coalesced and sent
+// on the session's next flush; anything else first flushes what is pending
+// so ordering is preserved.
+func (s *Server) Notify(ctx context.Context, sess *session.Session, method string, params interface{}) error {
+	if !s.allows(sess, method) {
+		slog.Debug("Suppressed notification the client did not negotiate", "method", method)
+		return nil
+	}
+
+	c := sess.Coalescer()
+	if c == nil {
+		return s.deliver(ctx, sess, method, params)
+	}
+	if session.Coalesces(method) {
+		return c.Add(method, params)
+	}
+	if err := c.Flush(); err != nil {
+		slog.Debug("Flushed pending notifications with errors", "error", err)
+	}
+	return s.deliver(ctx, sess, method, params)
+}
+
+// allows is the central check for server-to-client traffic: the client
+// must support the method's feature, and list changes are only announced
+// for lists the server said would announce them
+func (s *Server) allows(sess *session.Session, method string) bool {
+	if !sess.Allows(method) {
+		return false
+	}
+	caps := s.capabilities
+	switch method {
+	case protocol.NotificationToolsListChanged:
+		return caps.Tools != nil && caps.Tools.ListChanged
+	case protocol.NotificationResourcesListChanged:
+		return caps.Resources != nil && caps.Resources.ListChanged
+	case protocol.NotificationPromptsListChanged:
+		return caps.Prompts != nil && caps.Prompts.ListChanged
+	}
+	return true
+}
+
+// deliver writes a notification to the session's connection. Undeliverable
+// notifications of retained kinds (resource updates, progress) are kept for
+// redelivery when the client resumes.
+func (s *Server) deliver(ctx context.Context, sess *session.Session, method string, params interface{}) error {
+	err := sess.Conn().Notify(ctx, method, params)
+	if err == nil {
+		return nil
+	}
+
+	slog.Error("Failed to send notification", "method", method, "error", err)
+	if session.Retains(method) && sess.Key() != "" {
+		s.deadLetters.Add(sess.Tenant(), sess.Key(), method, params, err)
+	}
+	return err
+}
+
+// NotifyResourceUpdated announces that uri changed. Subscribers are
+// notified, debounced per subscriber, and any cached content is dropped.
+func (s *Server) NotifyResourceUpdated(uri string) {
+	s.events.Publish(events.Event{Kind: events.ResourceChanged, Subject: uri})
+}
+
+// NotifyResourcesListChanged announces that the resource list changed.
+func (s *Server) NotifyResourcesListChanged() {
+	s.events.Publish(events.Event{Kind: events.ResourcesChanged})
+}
+
+// NotifyPromptsListChanged announces that the prompt list changed.
+func (s *Server) NotifyPromptsListChanged() {
+	s.events.Publish(events.Event{Kind: events.PromptsChanged})
+}
+
+// notifyToolsListChanged announces that the tool list changed.
+func (s *Server) notifyToolsListChanged() {
+	s.events.Publish(events.Event{Kind: events.ToolsChanged})
+}
+
+// broadcast sends a notification to every connected session.
+func (s *Server) broadcast(method string, params interface{}) {
+	s.mu.RLock()
+	sessions := make([]*session.Session, 0, len(s.sessions))
+	for _, sess := range s.sessions {
+		sessions = append(sessions, sess)
+	}
+	s.mu.RUnlock()
+
+	for _, sess := range sessions {
+		_ = s.Notify(context.Background(), sess, method, params)
+	}
+}
+
+// redeliver sends a resumed session the notifications it missed. Letters
+// that fail again go back into the queue.
+func (s *Server) redeliver(sess *session.Session) {
+	letters := s.deadLetters.Take(sess.Tenant(), sess.Key())
+	if len(letters) == 0 {
+		return
+	}
+
+	slog.Info("Redelivering missed notifications", "client", sess.Key(), "count", len(letters))
+	for _, l := range letters {
+		_ = s.Notify(context.Background(), sess, l.Method, json.RawMessage(l.Params))
+	}
+}
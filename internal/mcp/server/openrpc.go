@@ -0,0 +1,53 @@
+// internal/mcp/server/openrpc.go
+package server
+
+import (
+	"encoding/json"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/openrpc"
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+)
+
+// OpenRPCResourceURI is the URI of the built-in OpenRPC description resource
+const OpenRPCResourceURI = "axe-handle://openrpc"
+
+// OpenRPC describes the server's JSON-RPC methods and registered tools
+func (s *Server) OpenRPC() openrpc.Document {
+	return openrpc.Generate(openrpc.Info{
+		Title:       s.config.Server.Name,
+		Version:     s.config.Server.Version,
+		Description: "Model Context Protocol server",
+	}, s.toolsManager.ListTools())
+}
+
+// openrpcProvider exposes the OpenRPC document as a read-only resource
+type openrpcProvider struct {
+	server *Server
+}
+
+// Ensure openrpcProvider implements resources.Provider
+var _ resources.Provider = (*openrpcProvider)(nil)
+
+// ListResources returns the OpenRPC resource
+func (p *openrpcProvider) ListResources() ([]resources.Resource, error) {
+	return []resources.Resource{
+		{
+			URI:         OpenRPCResourceURI,
+			Name:        "OpenRPC description",
+			Description: "JSON-RPC methods, parameters and tool schemas in OpenRPC format",
+			MimeType:    "application/json",
+		},
+	}, nil
+}
+
+// GetResource generates the document from the tools registered right now
+func (p *openrpcProvider) GetResource(uri string) (interface{}, error) {
+	if uri != OpenRPCResourceURI {
+		return nil, resources.ErrResourceNotFound
+	}
+	data, err := json.MarshalIndent(p.server.OpenRPC(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,39 @@
+// internal/mcp/server/prompts.go
+package server
+
+import (
+	"context"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+)
+
+// ListPrompts returns the prompts of all registered providers
+func (s *Server) ListPrompts(ctx context.Context) ([]protocol.Prompt, error) {
+	list, err := s.providerRegistry.ListPrompts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]protocol.Prompt, 0, len(list))
+	for _, p := range list {
+		args := make([]protocol.PromptArgument, 0, len(p.Arguments))
+		for _, a := range p.Arguments {
+			args = append(args, protocol.PromptArgument{
+				Name:        a.Name,
+				Description: a.Description,
+				Required:    a.Required,
+			})
+		}
+		result = append(result, protocol.Prompt{
+			Name:        p.Name,
+			Description: p.Description,
+			Arguments:   args,
+		})
+	}
+	return result, nil
+}
+
+// GetPrompt renders a prompt with already validated arguments
+func (s *Server) GetPrompt(ctx context.Context, name string, args map[string]string) (interface{}, error) {
+	return s.providerRegistry.GetPrompt(ctx, name, args)
+}
@@ -0,0 +1,55 @@
+// internal/mcp/server/provider/cursor.go
+package provider
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dkoosis/axe-handle/pkg/mcperrors"
+)
+
+// DefaultPageSize is the page size used when none is configured
+const DefaultPageSize = 100
+
+// ErrInvalidCursor is returned for cursors this registry did not issue
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// init maps this package's sentinel errors to their JSON-RPC error codes
+func init() {
+	mcperrors.RegisterMapping(ErrInvalidCursor, mcperrors.ErrInvalidParams)
+}
+
+// cursor marks a position in the listing: providers are walked in
+// registration order, and Offset counts items already returned from the
+// provider at index Provider.
+type cursor struct {
+	Provider int `json:"p"`
+	Offset   int `json:"o"`
+}
+
+// encode returns the opaque form of c handed to clients
+func (c cursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses an opaque cursor; the empty string is the first page
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	if s == "" {
+		return c, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if c.Provider < 0 || c.Offset < 0 {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,72 @@
+// internal/mcp/server/provider/profile.go
+package provider
+
+import (
+	"context"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/prompts"
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+)
+
+// profile returns the profile of the client in ctx, or nil
+func profile(ctx context.Context) *session.Profile {
+	if sess := session.FromContext(ctx); sess != nil {
+		return sess.Profile()
+	}
+	return nil
+}
+
+// Visible reports whether uri is within the client's roots and profile
+func Visible(ctx context.Context, uri string) bool {
+	return InRoots(ctx, uri) && profile(ctx).AllowsResource(uri)
+}
+
+// filterVisible drops the resources outside the client's roots or profile
+func filterVisible(ctx context.Context, list []resources.Resource) []resources.Resource {
+	list = filterRoots(ctx, list)
+	p := profile(ctx)
+	if p == nil {
+		return list
+	}
+
+	filtered := make([]resources.Resource, 0, len(list))
+	for _, res := range list {
+		if p.AllowsResource(res.URI) {
+			filtered = append(filtered, res)
+		}
+	}
+	return filtered
+}
+
+// filterTemplates drops the templates outside the client's profile
+func filterTemplates(ctx context.Context, list []resources.ResourceTemplate) []resources.ResourceTemplate {
+	p := profile(ctx)
+	if p == nil {
+		return list
+	}
+
+	filtered := make([]resources.ResourceTemplate, 0, len(list))
+	for _, t := range list {
+		if p.AllowsResource(t.URITemplate) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// filterPrompts drops the prompts outside the client's profile
+func filterPrompts(ctx context.Context, list []prompts.Prompt) []prompts.Prompt {
+	p := profile(ctx)
+	if p == nil {
+		return list
+	}
+
+	filtered := make([]prompts.Prompt, 0, len(list))
+	for _, pr := range list {
+		if p.AllowsPrompt(pr.Name) {
+			filtered = append(filtered, pr)
+		}
+	}
+	return filtered
+}
@@ -59,17 +59,73 @@ func (r *Registry) ListResources(ctx context.Context) ([]resources.Resource, err
 		if err != nil {
 			return nil, err
 		}
-		allResources = append(allResources, resources...)
+		allResources = append(allResources, filterVisible(ctx, resources)...)
 	}
 	return allResources, nil
 }
 
-// GetResource retrieves a resource from the appropriate provider
-func (r *Registry) GetResource(ctx context.Context, uri string) (interface{}, error) {
+// ListResourcesPage returns up to pageSize resources starting at cursor,
+// plus the cursor for the next page ("" on the last page). Providers are
+// listed in registration order so pages stay stable between calls.
+func (r *Registry) ListResourcesPage(ctx context.Context, cursorStr string, pageSize int) ([]resources.Resource, string, error) {
+	c, err := decodeCursor(cursorStr)
+	if err != nil {
+		return nil, "", err
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	for _, provider := range r.resourceProviders {
+	if c.Provider > len(r.resourceProviders) {
+		return nil, "", ErrInvalidCursor
+	}
+
+	page := make([]resources.Resource, 0, pageSize)
+	for p := c.Provider; p < len(r.resourceProviders); p++ {
+		list, err := r.resourceProviders[p].ListResources()
+		if err != nil {
+			return nil, "", err
+		}
+		list = filterVisible(ctx, list)
+
+		offset := 0
+		if p == c.Provider {
+			offset = min(c.Offset, len(list))
+		}
+		remaining := list[offset:]
+		room := pageSize - len(page)
+		if len(remaining) > room {
+			page = append(page, remaining[:room]...)
+			return page, cursor{Provider: p, Offset: offset + room}.encode(), nil
+		}
+		page = append(page, remaining...)
+		if len(page) == pageSize && p+1 < len(r.resourceProviders) {
+			return page, cursor{Provider: p + 1}.encode(), nil
+		}
+	}
+	return page, "", nil
+}
+
+// snapshotResourceProviders returns the resource providers registered so
+// far. Reads go through a snapshot rather than under the lock, as some
+// providers, such as the server's capabilities document, list the registry
+// while reading.
+func (r *Registry) snapshotResourceProviders() []resources.Provider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]resources.Provider(nil), r.resourceProviders...)
+}
+
+// GetResource retrieves a resource from the appropriate provider
+func (r *Registry) GetResource(ctx context.Context, uri string) (interface{}, error) {
+	if !Visible(ctx, uri) {
+		return nil, resources.ErrResourceNotFound
+	}
+
+	for _, provider := range r.snapshotResourceProviders() {
 		resource, err := provider.GetResource(uri)
 		if err == nil {
 			return resource, nil
@@ -79,6 +135,136 @@ func (r *Registry) GetResource(ctx context.Context, uri string) (interface{}, er
 	return nil, resources.ErrResourceNotFound
 }
 
+// OpenResource opens a resource for chunked reading from the first streaming
+// provider that has it
+func (r *Registry) OpenResource(ctx context.Context, uri string) (*resources.Stream, error) {
+	if !Visible(ctx, uri) {
+		return nil, resources.ErrResourceNotFound
+	}
+
+	for _, provider := range r.snapshotResourceProviders() {
+		streamer, ok := provider.(resources.StreamProvider)
+		if !ok {
+			continue
+		}
+		stream, err := streamer.OpenResource(uri)
+		if err == nil {
+			return stream, nil
+		}
+		// If provider returns error, try the next one
+	}
+	return nil, resources.ErrResourceNotFound
+}
+
+// ResourceETag returns the version tag of a resource from the first provider
+// that supports ETags and knows it; an empty tag means none is available
+func (r *Registry) ResourceETag(ctx context.Context, uri string) string {
+	if !Visible(ctx, uri) {
+		return ""
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, provider := range r.resourceProviders {
+		tagger, ok := provider.(resources.ETagProvider)
+		if !ok {
+			continue
+		}
+		if etag, err := tagger.ResourceETag(uri); err == nil && etag != "" {
+			return etag
+		}
+	}
+	return ""
+}
+
+// ListResourceTemplates aggregates the templates of all template providers
+func (r *Registry) ListResourceTemplates(ctx context.Context) ([]resources.ResourceTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var all []resources.ResourceTemplate
+	for _, provider := range r.resourceProviders {
+		templater, ok := provider.(resources.TemplateProvider)
+		if !ok {
+			continue
+		}
+		templates, err := templater.ListResourceTemplates()
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, filterTemplates(ctx, templates)...)
+	}
+	return all, nil
+}
+
+// CompleteTemplateVariable gathers candidate values for a template
+// variable from every provider that can complete it. A provider's error is
+// returned only when no provider offered candidates.
+func (r *Registry) CompleteTemplateVariable(ctx context.Context, template, variable, partial string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var values []string
+	var firstErr error
+	for _, provider := range r.resourceProviders {
+		completer, ok := provider.(resources.CompletionProvider)
+		if !ok {
+			continue
+		}
+		candidates, err := completer.CompleteTemplateVariable(ctx, template, variable, partial)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		values = append(values, candidates...)
+	}
+	if len(values) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return values, nil
+}
+
+// Hinter is implemented by providers with advice on how to use them, such
+// as which tool to call first. Hints are part of the instructions clients
+// receive when they initialize.
+type Hinter interface {
+	UsageHint() string
+}
+
+// UsageHints returns the hints of all registered providers in
+// registration order, each once however many kinds a provider is
+// registered for
+func (r *Registry) UsageHints() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var hints []string
+	seen := make(map[string]bool)
+	add := func(p interface{}) {
+		h, ok := p.(Hinter)
+		if !ok {
+			return
+		}
+		if hint := h.UsageHint(); hint != "" && !seen[hint] {
+			seen[hint] = true
+			hints = append(hints, hint)
+		}
+	}
+	for _, p := range r.resourceProviders {
+		add(p)
+	}
+	for _, p := range r.toolProviders {
+		add(p)
+	}
+	for _, p := range r.promptProviders {
+		add(p)
+	}
+	return hints
+}
+
 // ListTools aggregates tools from all registered tool providers
 func (r *Registry) ListTools(ctx context.Context) ([]tools.Tool, error) {
 	r.mu.RLock()
@@ -122,13 +308,17 @@ func (r *Registry) ListPrompts(ctx context.Context) ([]prompts.Prompt, error) {
 		if err != nil {
 			return nil, err
 		}
-		allPrompts = append(allPrompts, prompts...)
+		allPrompts = append(allPrompts, filterPrompts(ctx, prompts)...)
 	}
 	return allPrompts, nil
 }
 
 // GetPrompt retrieves a prompt from the appropriate provider
 func (r *Registry) GetPrompt(ctx context.Context, name string, args map[string]string) (interface{}, error) {
+	if !profile(ctx).AllowsPrompt(name) {
+		return nil, prompts.ErrPromptNotFound
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
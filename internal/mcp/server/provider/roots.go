@@ -0,0 +1,35 @@
+// internal/mcp/server/provider/roots.go
+package provider
+
+import (
+	"context"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+)
+
+// InRoots reports whether uri is visible to the client in ctx. Providers
+// never see requests outside the client's roots, so they need not check.
+func InRoots(ctx context.Context, uri string) bool {
+	sess := session.FromContext(ctx)
+	return sess == nil || sess.InRoots(uri)
+}
+
+// filterRoots drops the resources outside the client's roots
+func filterRoots(ctx context.Context, list []resources.Resource) []resources.Resource {
+	sess := session.FromContext(ctx)
+	if sess == nil {
+		return list
+	}
+	if _, known := sess.Roots(); !known {
+		return list
+	}
+
+	filtered := make([]resources.Resource, 0, len(list))
+	for _, res := range list {
+		if sess.InRoots(res.URI) {
+			filtered = append(filtered, res)
+		}
+	}
+	return filtered
+}
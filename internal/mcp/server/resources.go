@@ -0,0 +1,199 @@
+// internal/mcp/server/resources.go
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+	"github.com/dkoosis/axe-handle/internal/mcp/server/provider"
+)
+
+// ListResources returns one page of the resources of all registered
+// providers and the cursor for the next page
+func (s *Server) ListResources(ctx context.Context, cursor string) ([]protocol.Resource, string, error) {
+	list, next, err := s.providerRegistry.ListResourcesPage(ctx, cursor, s.config.Resources.PageSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result := make([]protocol.Resource, 0, len(list))
+	for _, r := range list {
+		result = append(result, protocol.Resource{
+			URI:         r.URI,
+			Name:        r.Name,
+			Description: r.Description,
+			MimeType:    r.MimeType,
+			Annotations: r.Annotations,
+		})
+	}
+	return result, next, nil
+}
+
+// ReadResource reads one chunk of a resource. Streaming providers are read
+// incrementally; other providers' content is chunked in memory so clients
+// see the same paging either way.
+func (s *Server) ReadResource(ctx context.Context, params protocol.ResourcesReadParams) (*protocol.ResourcesReadResult, error) {
+	stream, err := s.openResource(ctx, params.URI)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	chunk, err := resources.ReadChunk(stream, params.Offset, params.Length, s.config.Resources.MaxReadBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &protocol.ChunkMeta{
+		Offset: chunk.Offset,
+		Length: int64(len(chunk.Data)),
+		EOF:    chunk.EOF,
+	}
+	if stream.Size >= 0 {
+		meta.TotalSize = stream.Size
+	}
+	if !chunk.EOF {
+		meta.NextOffset = chunk.NextOffset
+		meta.Truncated = true
+		meta.Hint = fmt.Sprintf("content truncated; call resources/read with offset %d to continue", chunk.NextOffset)
+	}
+
+	contents := protocol.ResourceContents{
+		URI:      params.URI,
+		MimeType: stream.MimeType,
+	}
+	if stream.Size >= 0 {
+		contents.Size = stream.Size
+	}
+	if resources.IsText(stream.MimeType) {
+		contents.Text = string(chunk.Data)
+	} else {
+		contents.Blob = base64.StdEncoding.EncodeToString(chunk.Data)
+	}
+
+	return &protocol.ResourcesReadResult{
+		Contents: []protocol.ResourceContents{contents},
+		Meta:     &protocol.ResultMeta{Chunk: meta, ETag: stream.ETag},
+	}, nil
+}
+
+// openResource opens uri, serving it from the cache when enabled and the
+// cached copy is still current.
+func (s *Server) openResource(ctx context.Context, uri string) (*resources.Stream, error) {
+	// The cache is shared between clients, so scope by roots and profile before it
+	if !provider.Visible(ctx, uri) {
+		return nil, resources.ErrResourceNotFound
+	}
+	cache := s.resourceCacheFor(ctx)
+	if cache == nil {
+		return s.loadResource(ctx, uri)
+	}
+
+	etag := s.providerRegistry.ResourceETag(ctx, uri)
+	if cached, ok := cache.Get(uri, etag); ok {
+		return cachedStream(cached), nil
+	}
+
+	stream, err := s.loadResource(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	if etag != "" {
+		stream.ETag = etag
+	}
+	if !cache.Fits(stream.Size) {
+		return stream, nil
+	}
+
+	defer stream.Close()
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource: %w", err)
+	}
+	content := &resources.CachedContent{
+		URI: uri,
+		// Only provider-reported tags can be revalidated; stream tags are informational
+		ETag:     etag,
+		MimeType: stream.MimeType,
+		Data:     data,
+	}
+	cache.Put(content)
+
+	result := cachedStream(content)
+	result.ETag = stream.ETag
+	return result, nil
+}
+
+// cachedStream wraps cached content as a stream
+func cachedStream(c *resources.CachedContent) *resources.Stream {
+	return &resources.Stream{
+		ReadCloser: io.NopCloser(bytes.NewReader(c.Data)),
+		Size:       int64(len(c.Data)),
+		MimeType:   c.MimeType,
+		ETag:       c.ETag,
+	}
+}
+
+// loadResource opens uri as a stream, falling back to in-memory content
+func (s *Server) loadResource(ctx context.Context, uri string) (*resources.Stream, error) {
+	if stream, err := s.providerRegistry.OpenResource(ctx, uri); err == nil {
+		return stream, nil
+	}
+
+	content, err := s.providerRegistry.GetResource(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	data, err := resourceBytes(content)
+	if err != nil {
+		return nil, err
+	}
+
+	mimeType := s.resourceMimeType(ctx, uri)
+	if _, isBytes := content.([]byte); isBytes && mimeType == "" && !utf8.Valid(data) {
+		mimeType = "application/octet-stream"
+	}
+	return &resources.Stream{
+		ReadCloser: io.NopCloser(bytes.NewReader(data)),
+		Size:       int64(len(data)),
+		MimeType:   mimeType,
+	}, nil
+}
+
+// resourceBytes converts provider content to bytes; values other than
+// strings and byte slices are encoded as JSON
+func resourceBytes(content interface{}) ([]byte, error) {
+	switch c := content.(type) {
+	case string:
+		return []byte(c), nil
+	case []byte:
+		return c, nil
+	default:
+		data, err := json.Marshal(c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode resource content: %w", err)
+		}
+		return data, nil
+	}
+}
+
+// resourceMimeType looks up the MIME type a provider listed for uri
+func (s *Server) resourceMimeType(ctx context.Context, uri string) string {
+	list, err := s.providerRegistry.ListResources(ctx)
+	if err != nil {
+		return ""
+	}
+	for _, r := range list {
+		if r.URI == uri {
+			return r.MimeType
+		}
+	}
+	return ""
+}
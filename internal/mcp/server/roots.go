@@ -0,0 +1,52 @@
+// internal/mcp/server/roots.go
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+)
+
+// rootsTimeout bounds how long we wait for a client to answer roots/list
+const rootsTimeout = 10 * time.Second
+
+// RootsListChanged handles notifications/roots/list_changed by fetching the
+// client's new roots
+func (s *Server) RootsListChanged(ctx context.Context) error {
+	sess := session.FromContext(ctx)
+	if sess == nil {
+		return fmt.Errorf("no session for roots change")
+	}
+	go s.refreshRoots(sess)
+	return nil
+}
+
+// refreshRoots asks the client for its roots and scopes the session's
+// resources to them. Clients without the roots capability are left
+// unrestricted.
+func (s *Server) refreshRoots(sess *session.Session) {
+	if !sess.Supports(session.FeatureRoots) || sess.Conn() == nil {
+		return
+	}
+
+	ctx := context.Background()
+	result, err := session.Call[protocol.ListRootsResult](ctx, sess, protocol.MethodRootsList, nil,
+		session.WithRequestTimeout(rootsTimeout),
+		session.WithIDPrefix("roots"))
+	if err != nil {
+		slog.Warn("Failed to list client roots", "error", err)
+		return
+	}
+
+	sess.SetRoots(result.Roots)
+	slog.Debug("Client roots updated", "roots", len(result.Roots))
+
+	// What the client can see may have changed
+	if err := s.Notify(ctx, sess, protocol.NotificationResourcesListChanged, struct{}{}); err != nil {
+		slog.Debug("Failed to send resource list change", "error", err)
+	}
+}
@@ -3,58 +3,118 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
+	"text/template"
 	"time"
 
+	"github.com/dkoosis/axe-handle/internal/buildinfo"
+	"github.com/dkoosis/axe-handle/internal/clock"
 	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/internal/events"
+	"github.com/dkoosis/axe-handle/internal/mcp/elicitation"
+	"github.com/dkoosis/axe-handle/internal/mcp/jobs"
 	"github.com/dkoosis/axe-handle/internal/mcp/prompts"
 	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
 	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+	"github.com/dkoosis/axe-handle/internal/mcp/sampling"
 	"github.com/dkoosis/axe-handle/internal/mcp/server/provider"
+	"github.com/dkoosis/axe-handle/internal/mcp/server/supervisor"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
 	"github.com/dkoosis/axe-handle/internal/mcp/tools"
 	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+	"github.com/dkoosis/axe-handle/internal/metrics"
+	"github.com/dkoosis/axe-handle/internal/transport"
 	"github.com/dkoosis/axe-handle/pkg/mcperrors"
+	"github.com/dkoosis/axe-handle/pkg/state"
 	"github.com/sourcegraph/jsonrpc2"
 )
 
 // Server represents an MCP server implementation.
 type Server struct {
-	config             *config.Config
-	capabilities       protocol.ServerCapabilities
-	clientCapabilities protocol.ClientCapabilities
-	providerRegistry   *provider.Registry
-	toolsManager       *manager.ToolsManager
+	config           *config.Config
+	capabilities     protocol.ServerCapabilities
+	providerRegistry *provider.Registry
+	toolsManager     *manager.ToolsManager
+	supervisor       *supervisor.Supervisor
+	health           *healthTracker
+	errorMetrics     *metrics.ErrorMetrics
+	clientStats      *metrics.ClientStats // nil unless opted in
+	deadLetters      *session.DeadLetterQueue
+	subscriptions    *subscriptionManager
+	resourceCache    *resources.Cache // nil when caching is disabled
+	events           *events.Bus
+	state            *state.Store // nil when storage is not configured
+	jobs             *jobs.Manager
+	clock            clock.Clock
+	heartbeatOnce    sync.Once
+	profiles         map[string]*session.Profile // by name
+	tenants          map[string]*tenant          // by name
+	tenantOf         map[string]*tenant          // by identity
+	instructions     *template.Template          // executed with InstructionsData
+	usageHints       []string                    // from AddUsageHint
 
 	// Connection management
 	conn            *jsonrpc2.Conn
+	sessions        map[*jsonrpc2.Conn]*session.Session
 	initialized     bool
 	shutdownStarted bool
+	released        bool // shutdown hooks have run and the state store is closed
 
 	// Context management
 	ctx    context.Context
 	cancel context.CancelFunc
 
 	// Shutdown hooks
-	shutdownFuncs []func()
+	shutdownHooks       []ShutdownHook
+	shutdownHookTimeout time.Duration
+
+	// Connection hooks
+	connectHooks    []ConnectionHook
+	disconnectHooks []ConnectionHook
 
 	// Concurrency protection
 	mu sync.RWMutex
 }
 
+// Option configures a Server at construction.
+type Option func(*Server)
+
+// WithClock sets the clock used for tool timeouts, heartbeats and health
+// reporting. Tests pass a fake clock to control time.
+func WithClock(c clock.Clock) Option {
+	return func(s *Server) {
+		s.clock = c
+	}
+}
+
+// WithEventBus makes the server publish to bus instead of a bus of its
+// own, so several subsystems can share one.
+func WithEventBus(bus *events.Bus) Option {
+	return func(s *Server) {
+		s.events = bus
+	}
+}
+
 // NewServer creates a new MCP server with the provided configuration.
-func NewServer(cfg *config.Config) *Server {
+func NewServer(cfg *config.Config, opts ...Option) *Server {
 	// Create base context for server lifetime
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &Server{
-		config:           cfg,
-		providerRegistry: provider.NewRegistry(),
-		toolsManager:     manager.NewToolsManager(),
-		ctx:              ctx,
-		cancel:           cancel,
-		shutdownFuncs:    make([]func(), 0),
+	s := &Server{
+		config:              cfg,
+		providerRegistry:    provider.NewRegistry(),
+		toolsManager:        manager.NewToolsManager(),
+		sessions:            make(map[*jsonrpc2.Conn]*session.Session),
+		ctx:                 ctx,
+		cancel:              cancel,
+		shutdownHooks:       make([]ShutdownHook, 0),
+		shutdownHookTimeout: DefaultShutdownHookTimeout,
+		clock:               clock.Real,
+		events:              events.NewBus(),
 		capabilities: protocol.ServerCapabilities{
 			Logging: &struct{}{},
 			Tools: &struct {
@@ -74,25 +134,93 @@ func NewServer(cfg *config.Config) *Server {
 			}{
 				ListChanged: true,
 			},
+			Completions: &struct{}{},
 		},
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.toolsManager.SetClock(s.clock)
+	s.toolsManager.SetEventBus(s.events)
+	s.toolsManager.SetProgressReporter(func(ctx context.Context, _ string, token string, progress, total float64) {
+		s.Publisher().Progress(ctx, token, progress, total)
+	})
+	if validator, err := manager.NewValidator(cfg.Tools.SchemaValidator); err != nil {
+		slog.Warn("Ignoring tools.schemaValidator", "error", err)
+	} else {
+		s.toolsManager.SetValidator(validator)
+	}
+	s.toolsManager.SetResultCache(newResultCache(cfg.Tools.Cache, s.clock))
+	if approver, err := newApprover(cfg.Tools.Approval); err != nil {
+		slog.Warn("Ignoring tools.approval", "error", err)
+	} else {
+		s.toolsManager.SetApprover(approver)
+	}
+	s.supervisor = supervisor.NewSupervisor(s.notifyToolsListChanged)
+	s.health = newHealthTracker(s.clock)
+	s.errorMetrics = metrics.NewErrorMetrics()
+	if cfg.Metrics.ClientStats {
+		s.clientStats = metrics.NewClientStats()
+		s.errorMetrics.AddCollector(s.clientStats)
+	}
+	s.deadLetters = newDeadLetterQueue(cfg.Server.DeadLetterFile)
+	s.state = openStateStore(cfg.Storage)
+	s.jobs = jobs.NewManager(s.ctx, jobs.Config{
+		MaxRunning: cfg.Tools.Jobs.MaxRunning,
+		Retention:  cfg.Tools.Jobs.Retention,
+	}, s.clock, s.Publisher())
+	go s.sweepState()
+	if cfg.Resources.Cache.Enabled {
+		s.resourceCache = resources.NewCache(cfg.Resources.Cache.MaxBytes, cfg.Resources.Cache.MaxEntryBytes, cfg.Resources.Cache.TTL)
+	}
+	s.subscriptions = newSubscriptionManager(cfg.Resources.Debounce, cfg.Resources.MaxUpdateRate, s.sendResourceUpdated)
+	s.profiles = newProfiles(cfg.Profiles)
+	s.newTenants(cfg.Tenants)
+	s.instructions = newInstructions(cfg.Server.Instructions)
+	s.providerRegistry.RegisterResourceProvider(&statusProvider{server: s})
+	s.providerRegistry.RegisterResourceProvider(&openrpcProvider{server: s})
+	s.providerRegistry.RegisterResourceProvider(&capabilitiesProvider{server: s})
+	s.subscribeEvents()
+	return s
 }
 
 // RegisterResourceProvider registers a resource provider with the server.
 func (s *Server) RegisterResourceProvider(provider resources.Provider) {
+	s.attachPublisher(provider)
+	s.observeConnections(provider)
 	s.providerRegistry.RegisterResourceProvider(provider)
 }
 
-// RegisterToolProvider registers a tool provider with the server.
+// RegisterToolProvider registers a tool provider with the server. The
+// tools it lists are served through the tools manager like any other, so
+// clients list and call them with tools/list and tools/call.
 func (s *Server) RegisterToolProvider(provider tools.Provider) {
+	s.attachPublisher(provider)
+	s.observeConnections(provider)
 	s.providerRegistry.RegisterToolProvider(provider)
+	s.registerProviderTools(provider)
 }
 
 // RegisterPromptProvider registers a prompt provider with the server.
 func (s *Server) RegisterPromptProvider(provider prompts.Provider) {
+	s.attachPublisher(provider)
+	s.observeConnections(provider)
 	s.providerRegistry.RegisterPromptProvider(provider)
 }
 
+// RegisterAsyncTool registers a tool whose calls run as background jobs.
+// Clients get a job ID at once and follow it with the jobs.* tools, so the
+// work is not cut short by client request timeouts.
+func (s *Server) RegisterAsyncTool(tool protocol.Tool, handler manager.ToolHandler) {
+	s.jobs.Register(s.toolsManager, tool, handler)
+}
+
+// SuperviseProvider runs a subprocess-backed provider for the server's
+// lifetime, restarting it with backoff when it crashes.
+func (s *Server) SuperviseProvider(child supervisor.Child) error {
+	return s.supervisor.Supervise(s.ctx, child)
+}
+
 // SetConnection sets the jsonrpc2 connection for the server.
 func (s *Server) SetConnection(conn *jsonrpc2.Conn) {
 	s.mu.Lock()
@@ -100,13 +228,124 @@ func (s *Server) SetConnection(conn *jsonrpc2.Conn) {
 	s.conn = conn
 }
 
+// sessionKey identifies a client across reconnects by its authenticated
+// identity, its tenant and the resume token it sends, or its name when it
+// sends none. Anonymous clients get no key: a name is no proof of who is
+// asking, so nothing they miss or start can be claimed by another client.
+func sessionKey(identity, tenant string, params protocol.InitializeParams) string {
+	if identity == "" {
+		return ""
+	}
+	resume := params.ClientInfo.Name
+	if params.Meta != nil && params.Meta.ResumeToken != "" {
+		resume = params.Meta.ResumeToken
+	}
+	return tenant + "/" + identity + "/" + resume
+}
+
+// RequestContext adds the settings of this server that code serving a
+// request reads from its context, such as how long users have to answer
+// an elicitation, the client's model a sampling request and whether error
+// internals may reach the client. Several servers in one process keep
+// their own.
+func (s *Server) RequestContext(ctx context.Context) context.Context {
+	ctx = elicitation.WithTimeout(ctx, s.config.Tools.ElicitationTimeout)
+	ctx = sampling.WithTimeout(ctx, s.config.Tools.SamplingTimeout)
+	// Only expose error internals to clients when debugging or developing
+	return mcperrors.WithDebug(ctx, s.config.Server.Debug || s.config.Development())
+}
+
+// Session returns the session for conn, creating it on first use. The session
+// is dropped when the connection disconnects.
+func (s *Server) Session(conn *jsonrpc2.Conn) *session.Session {
+	s.mu.Lock()
+	if sess, ok := s.sessions[conn]; ok {
+		s.mu.Unlock()
+		return sess
+	}
+
+	sess := session.New(conn, s.config.Server.Name)
+	sess.SetCoalescer(session.NewCoalescer(
+		s.config.Server.NotifyFlushInterval,
+		s.config.Server.NotifyMaxBatch,
+		func(method string, params interface{}) error {
+			return s.deliver(context.Background(), sess, method, params)
+		}))
+	s.sessions[conn] = sess
+	s.mu.Unlock()
+
+	// Publish without the lock so subscribers may call back into the server
+	s.events.Publish(events.Event{Kind: events.SessionConnected, Data: sess})
+
+	go func() {
+		<-conn.DisconnectNotify()
+		s.mu.Lock()
+		delete(s.sessions, conn)
+		s.mu.Unlock()
+		s.events.Publish(events.Event{Kind: events.SessionClosed, Data: sess})
+		if sess.ProtocolVersion() != "" {
+			s.clientDisconnected(sess)
+		}
+	}()
+
+	return sess
+}
+
+// SetLogLevel handles the logging/setLevel request for the session in ctx.
+func (s *Server) SetLogLevel(ctx context.Context, level protocol.LoggingLevel) error {
+	if !level.Valid() {
+		return mcperrors.NewInvalidParamsError(fmt.Errorf("unknown logging level: %q", level))
+	}
+
+	sess := session.FromContext(ctx)
+	if sess == nil {
+		return mcperrors.NewInternalError(fmt.Errorf("no session for logging/setLevel"))
+	}
+
+	sess.SetLogLevel(level)
+	slog.Debug("Client logging level changed", "level", level)
+	return nil
+}
+
 // Initialize handles the initialize request from the client.
 func (s *Server) Initialize(ctx context.Context, params protocol.InitializeParams) (*protocol.InitializeResult, error) {
+	profile, err := s.initializeSession(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate instructions based on available providers, unless the
+	// client's profile brings its own. Providers are consulted without the
+	// server lock held.
+	var instructions string
+	if profile != nil && profile.Instructions != "" {
+		instructions = profile.Instructions
+	} else {
+		instructions = s.generateInstructions(ctx)
+	}
+
+	// Return server info and capabilities
+	return &protocol.InitializeResult{
+		ProtocolVersion: params.ProtocolVersion,
+		Capabilities:    s.capabilities,
+		ServerInfo: protocol.Implementation{
+			Name:    s.config.Server.Name,
+			Version: s.config.Server.Version,
+		},
+		Instructions: instructions,
+		Meta:         &protocol.InitializeMeta{Build: buildinfo.Get()},
+	}, nil
+}
+
+// initializeSession checks an initialize request and records the client in
+// its session, returning the client's profile
+func (s *Server) initializeSession(ctx context.Context, params protocol.InitializeParams) (*session.Profile, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Prevent double initialization
-	if s.initialized {
+	// Prevent double initialization; each session initializes once
+	sess := session.FromContext(ctx)
+	if (sess == nil && s.initialized) || (sess != nil && sess.ProtocolVersion() != "") {
 		return nil, mcperrors.NewInvalidRequestError(fmt.Errorf("server already initialized"))
 	}
 
@@ -115,43 +354,55 @@ func (s *Server) Initialize(ctx context.Context, params protocol.InitializeParam
 		return nil, mcperrors.NewInvalidRequestError(fmt.Errorf("server is shutting down"))
 	}
 
-	// Check protocol version compatibility
-	if !isProtocolVersionCompatible(params.ProtocolVersion, protocol.LatestProtocolVersion) {
+	// Clients may negotiate any supported version; older ones get
+	// downgraded results
+	if !protocol.SupportsProtocolVersion(params.ProtocolVersion) {
 		return nil, mcperrors.NewInvalidRequestError(
-			fmt.Errorf("incompatible protocol version: client=%s, server=%s",
-				params.ProtocolVersion, protocol.LatestProtocolVersion))
+			fmt.Errorf("incompatible protocol version: client=%s, server supports %s",
+				params.ProtocolVersion, strings.Join(protocol.SupportedProtocolVersions, ", ")))
 	}
 
-	// Store client capabilities for later use
-	s.clientCapabilities = params.Capabilities
+	// Identify the client so undelivered notifications can follow it across
+	// reconnects, and record what it can receive, which profile it uses and
+	// which tenant it belongs to
+	team, profile, err := s.resolveTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantName := ""
+	if team != nil {
+		tenantName = team.name
+	}
+	if sess != nil {
+		sess.SetKey(sessionKey(transport.Identity(ctx), tenantName, params))
+		sess.SetClientInfo(params.ClientInfo)
+		sess.SetRemoteAddr(transport.ClientAddr(ctx))
+		sess.SetIdentity(transport.Identity(ctx))
+		sess.SetProfile(profile)
+		sess.SetTenant(tenantName)
+		sess.SetCapabilities(params.Capabilities)
+		sess.SetProtocolVersion(params.ProtocolVersion)
+	}
+	s.clientStats.RecordSession(params.ClientInfo.Name, params.ClientInfo.Version, params.ProtocolVersion)
 
 	// Log successful initialization
 	slog.Info("Client connected and initialized",
 		"client_name", params.ClientInfo.Name,
 		"client_version", params.ClientInfo.Version,
 		"protocol_version", params.ProtocolVersion,
+		"profile", transport.Profile(ctx),
+		"tenant", tenantName,
 		"server_name", s.config.Server.Name,
-		"server_version", s.config.Server.Version)
-
-	// Set up shutdown hook to clean up resources
-	s.setupShutdownHook()
+		"server_version", s.config.Server.Version,
+		"build", buildinfo.Get().String())
 
 	// Mark as initialized
 	s.initialized = true
+	if sess != nil {
+		go s.clientConnected(sess)
+	}
 
-	// Generate instructions based on available providers
-	instructions := s.generateInstructions()
-
-	// Return server info and capabilities
-	return &protocol.InitializeResult{
-		ProtocolVersion: protocol.LatestProtocolVersion,
-		Capabilities:    s.capabilities,
-		ServerInfo: protocol.Implementation{
-			Name:    s.config.Server.Name,
-			Version: s.config.Server.Version,
-		},
-		Instructions: instructions,
-	}, nil
+	return profile, nil
 }
 
 // Initialized handles the initialized notification from the client.
@@ -167,20 +418,24 @@ func (s *Server) Initialized(ctx context.Context) error {
 	// Start any background services that should begin only after initialization
 	s.startBackgroundServices()
 
-	// Send logging notification if the client supports it
-	if s.hasLoggingCapability() {
-		s.sendLogMessage(ctx, "info", "Server fully initialized and ready")
+	// Deliver anything this client missed while it was disconnected
+	if sess := session.FromContext(ctx); sess != nil {
+		go s.redeliver(sess)
+		go s.refreshRoots(sess)
 	}
 
+	// Sessions drop this unless the client supports logging
+	s.sendLogMessage(ctx, "info", "Server fully initialized and ready")
+
 	return nil
 }
 
-// Shutdown initiates graceful server shutdown.
+// Shutdown initiates graceful server shutdown. Registered shutdown hooks run
+// in reverse registration order; their errors are joined and returned.
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	if s.shutdownStarted {
+		s.mu.Unlock()
 		return nil // Already shutting down
 	}
 
@@ -192,13 +447,37 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		s.cancel()
 	}
 
-	// Run all registered shutdown functions
-	for _, fn := range s.shutdownFuncs {
-		fn()
-	}
-
 	s.initialized = false
-	return nil
+	s.mu.Unlock()
+
+	s.events.Publish(events.Event{Kind: events.Shutdown})
+	return s.Release(ctx)
+}
+
+// Release runs the shutdown hooks and closes the state store but leaves
+// sessions connected. A restart calls it before handing the listener to a
+// replacement process, which can then open the store while this process
+// drains. Shutdown afterwards only cancels what is left.
+func (s *Server) Release(ctx context.Context) error {
+	s.mu.Lock()
+	if s.released {
+		s.mu.Unlock()
+		return nil
+	}
+	s.released = true
+	hooks := make([]ShutdownHook, len(s.shutdownHooks))
+	copy(hooks, s.shutdownHooks)
+	timeout := s.shutdownHookTimeout
+	s.mu.Unlock()
+
+	// Hooks run without the lock held so they may call back into the server
+	err := runShutdownHooks(ctx, hooks, timeout)
+
+	// Providers may persist state from their hooks, so the store closes last
+	if closeErr := s.closeState(); closeErr != nil {
+		err = errors.Join(err, fmt.Errorf("closing state store: %w", closeErr))
+	}
+	return err
 }
 
 // Exit requests immediate termination of the connection.
@@ -237,98 +516,81 @@ func (s *Server) GetToolsManager() *manager.ToolsManager {
 	return s.toolsManager
 }
 
-// isProtocolVersionCompatible checks if the client's protocol version is compatible with the server's.
-func isProtocolVersionCompatible(clientVersion, serverVersion string) bool {
-	// For now, just check for exact match
-	// In the future, we could implement more sophisticated version compatibility checking
-	return clientVersion == serverVersion
-}
-
-// setupShutdownHook registers a function to be called on server shutdown.
-func (s *Server) setupShutdownHook() {
-	s.shutdownFuncs = append(s.shutdownFuncs, func() {
-		slog.Info("Performing graceful shutdown cleanup")
-		// Add any specific cleanup logic here
-	})
-}
-
 // startBackgroundServices starts any background services needed by the server.
 func (s *Server) startBackgroundServices() {
-	// Example: Start a heartbeat service
-	go s.heartbeatService()
+	s.startHeartbeat()
 }
 
-// heartbeatService periodically logs server status for health monitoring.
-func (s *Server) heartbeatService() {
-	ticker := time.NewTicker(5 * time.Minute)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-ticker.C:
-			slog.Debug("Server heartbeat",
-				"status", "running",
-				"uptime", time.Since(time.Now()),
-			)
-		}
-	}
-}
-
-// sendLogMessage sends a log message to the client if logging is supported.
+// sendLogMessage sends a log message to the session in ctx, or to every
+// session when ctx carries none. Each session applies its own level filter.
 func (s *Server) sendLogMessage(ctx context.Context, level string, message string) {
-	s.mu.RLock()
-	conn := s.conn
-	s.mu.RUnlock()
-
-	if conn == nil {
-		return
-	}
-
 	// Convert level string to proper LoggingLevel type
-	var loggingLevel protocol.LoggingLevel
-	switch level {
-	case "debug":
-		loggingLevel = protocol.LoggingLevelDebug
-	case "info":
-		loggingLevel = protocol.LoggingLevelInfo
-	case "warning":
-		loggingLevel = protocol.LoggingLevelWarning
-	case "error":
-		loggingLevel = protocol.LoggingLevelError
-	default:
+	loggingLevel := protocol.LoggingLevel(level)
+	if !loggingLevel.Valid() {
 		loggingLevel = protocol.LoggingLevelInfo
 	}
 
-	// Create notification params
-	params := protocol.LoggingMessageParams{
-		Level:  loggingLevel,
-		Logger: s.config.Server.Name,
-		Data:   message,
+	targets := []*session.Session{}
+	if sess := session.FromContext(ctx); sess != nil {
+		targets = append(targets, sess)
+	} else {
+		s.mu.RLock()
+		for _, sess := range s.sessions {
+			targets = append(targets, sess)
+		}
+		s.mu.RUnlock()
 	}
 
-	// Send notification asynchronously to not block
+	// Send notifications asynchronously to not block
 	go func() {
-		err := conn.Notify(context.Background(), protocol.NotificationLoggingMessage, params)
-		if err != nil {
-			slog.Error("Failed to send log message notification", "error", err)
+		for _, sess := range targets {
+			sess.Log(context.Background(), loggingLevel, s.config.Server.Name, message)
 		}
 	}()
 }
 
-// hasLoggingCapability checks if the client supports the logging capability.
-func (s *Server) hasLoggingCapability() bool {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// newProfiles indexes the configured profiles by name
+func newProfiles(list []config.ProfileConfig) map[string]*session.Profile {
+	profiles := make(map[string]*session.Profile, len(list))
+	for _, p := range list {
+		profiles[p.Name] = &session.Profile{
+			Name:         p.Name,
+			Instructions: p.Instructions,
+			Tools:        p.Tools,
+			Resources:    p.Resources,
+			Prompts:      p.Prompts,
+		}
+	}
+	return profiles
+}
 
-	return s.initialized && s.clientCapabilities.Logging != nil
+// newApprover returns the approval hook for a tools.approval mode
+func newApprover(mode string) (manager.Approver, error) {
+	switch mode {
+	case "", "run":
+		return nil, nil
+	case "ask":
+		return elicitation.Approve, nil
+	case "deny":
+		return manager.DenyAll, nil
+	}
+	return nil, fmt.Errorf("unknown approval mode %q; use run, ask or deny", mode)
 }
 
-// generateInstructions creates instructions text based on available providers.
-func (s *Server) generateInstructions() string {
-	return fmt.Sprintf("Axe Handle MCP Server - A reference implementation (version %s)\n\n"+
-		"This server provides access to various resources, tools, and prompts.\n"+
-		"For more information, please refer to the Model Context Protocol documentation.",
-		s.config.Server.Version)
+// newResultCache creates the tool result cache with the tools configured
+// for caching enabled
+func newResultCache(cfg config.ToolCacheConfig, clk clock.Clock) *manager.ResultCache {
+	cache := manager.NewResultCache(cfg.MaxBytes, cfg.MaxEntryBytes, clk)
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		ttl = manager.DefaultResultCacheTTL
+	}
+	for _, t := range cfg.Tools {
+		if t.TTL > 0 {
+			cache.Enable(t.Name, t.TTL)
+		} else {
+			cache.Enable(t.Name, ttl)
+		}
+	}
+	return cache
 }
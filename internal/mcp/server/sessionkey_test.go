@@ -0,0 +1,95 @@
+// internal/mcp/server/sessionkey_test.go
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/jobs"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/pkg/mcpserver"
+	"github.com/dkoosis/axe-handle/pkg/mcptest"
+)
+
+// sseCall posts a request on st and returns the result of its reply
+func sseCall(t *testing.T, st *mcptest.SSEStream, id int, method string, params interface{}) json.RawMessage {
+	t.Helper()
+	st.Post(t, map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": method, "params": params})
+	for {
+		var reply struct {
+			ID     *int            `json:"id"`
+			Result json.RawMessage `json:"result"`
+			Error  json.RawMessage `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(st.Next(t).Data), &reply); err != nil || reply.ID == nil || *reply.ID != id {
+			continue
+		}
+		if reply.Error != nil {
+			t.Fatalf("%s failed: %s", method, reply.Error)
+		}
+		return reply.Result
+	}
+}
+
+// connect opens an authenticated session that calls itself clientName
+func connect(t *testing.T, srv *mcptest.SSEServer, token, clientName string) *mcptest.SSEStream {
+	t.Helper()
+	st := srv.ConnectAs(t, token)
+	sseCall(t, st, 0, protocol.MethodInitialize, protocol.InitializeParams{
+		ProtocolVersion: protocol.LatestProtocolVersion,
+		ClientInfo:      protocol.Implementation{Name: clientName, Version: "1.0"},
+	})
+	st.Post(t, map[string]interface{}{"jsonrpc": "2.0", "method": protocol.NotificationInitialized})
+	return st
+}
+
+// callTool calls a tool and returns its text and whether it is an error
+func callTool(t *testing.T, st *mcptest.SSEStream, id int, name string, args interface{}) (string, bool) {
+	t.Helper()
+	var result protocol.ToolsCallResult
+	raw := sseCall(t, st, id, protocol.MethodToolsCall, map[string]interface{}{"name": name, "arguments": args})
+	if err := json.Unmarshal(raw, &result); err != nil || len(result.Content) == 0 {
+		t.Fatalf("%s returned %s", name, raw)
+	}
+	return result.Content[0].Text, result.IsError
+}
+
+// startJob starts the slow job and returns its ID
+func startJob(t *testing.T, st *mcptest.SSEStream, id int) string {
+	t.Helper()
+	text, _ := callTool(t, st, id, "slow", map[string]interface{}{})
+	var info jobs.Info
+	if err := json.Unmarshal([]byte(text), &info); err != nil || info.ID == "" {
+		t.Fatalf("slow did not start a job: %s", text)
+	}
+	return info.ID
+}
+
+// newJobServer serves a background tool that runs until cancelled to alice
+// and bob
+func newJobServer(t *testing.T, opts ...mcpserver.Option) *mcptest.SSEServer {
+	opts = append(opts, mcpserver.WithBearerToken("alice", "alice-token"), mcpserver.WithBearerToken("bob", "bob-token"))
+	srv := mcptest.NewSSEServer(t, mcptest.WithServerOptions(opts...))
+	srv.Server.RegisterAsyncTool(protocol.Tool{Name: "slow", InputSchema: json.RawMessage(`{"type":"object"}`)},
+		func(ctx context.Context, _ json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+			<-ctx.Done()
+			return protocol.ToolsCallResult{}, ctx.Err()
+		})
+	return srv
+}
+
+func TestClientNameDoesNotIdentifyCaller(t *testing.T) {
+	srv := newJobServer(t)
+	alice := connect(t, srv, "alice-token", "claude-desktop")
+	bob := connect(t, srv, "bob-token", "claude-desktop")
+
+	job := startJob(t, alice, 1)
+	if text, isErr := callTool(t, bob, 1, jobs.CancelTool, map[string]string{"jobId": job}); !isErr || !strings.Contains(text, jobs.ErrUnknownJob.Error()) {
+		t.Errorf("bob cancelled alice's job: %s", text)
+	}
+	if text, isErr := callTool(t, alice, 2, jobs.StatusTool, map[string]string{"jobId": job}); isErr {
+		t.Errorf("alice cannot see their own job: %s", text)
+	}
+}
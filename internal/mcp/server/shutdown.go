@@ -0,0 +1,68 @@
+// internal/mcp/server/shutdown.go
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// DefaultShutdownHookTimeout bounds how long a single shutdown hook may run
+const DefaultShutdownHookTimeout = 10 * time.Second
+
+// ShutdownHook releases resources when the server shuts down. The context
+// is cancelled when the hook's timeout expires.
+type ShutdownHook func(ctx context.Context) error
+
+// OnShutdown registers a hook to run during Shutdown. Providers and
+// transports use this to flush caches, close pools and the like.
+func (s *Server) OnShutdown(hook ShutdownHook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shutdownHooks = append(s.shutdownHooks, hook)
+}
+
+// SetShutdownHookTimeout sets the per-hook timeout used during Shutdown.
+func (s *Server) SetShutdownHookTimeout(timeout time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shutdownHookTimeout = timeout
+}
+
+// runShutdownHooks runs hooks last-registered first, each under its own
+// timeout, and joins any errors they report.
+func runShutdownHooks(ctx context.Context, hooks []ShutdownHook, timeout time.Duration) error {
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := runShutdownHook(ctx, hooks[i], timeout); err != nil {
+			slog.Error("Shutdown hook failed", "hook", i, "error", err)
+			errs = append(errs, fmt.Errorf("shutdown hook %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runShutdownHook runs a single hook, abandoning it if it outlives its timeout.
+func runShutdownHook(ctx context.Context, hook ShutdownHook, timeout time.Duration) error {
+	hookCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- hook(hookCtx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-hookCtx.Done():
+		return fmt.Errorf("timed out: %w", hookCtx.Err())
+	}
+}
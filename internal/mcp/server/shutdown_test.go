@@ -0,0 +1,54 @@
+// internal/mcp/server/shutdown_test.go
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/dkoosis/axe-handle/internal/config"
+)
+
+func TestReleaseHandsStateToReplacement(t *testing.T) {
+	cfg := config.Default()
+	cfg.Storage.Path = filepath.Join(t.TempDir(), "state.db")
+
+	old := NewServer(cfg)
+	if old.State() == nil {
+		t.Fatal("server runs without persistence")
+	}
+	flushes := 0
+	old.OnShutdown(func(context.Context) error {
+		ns, err := old.State().Namespace("test")
+		if err != nil {
+			return err
+		}
+		flushes++
+		return ns.Put("flushed", []byte("yes"))
+	})
+
+	// A restart releases the store before the replacement starts
+	if err := old.Release(context.Background()); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	replacement := NewServer(cfg)
+	defer replacement.Shutdown(context.Background())
+	if replacement.State() == nil {
+		t.Fatal("replacement runs without persistence")
+	}
+	ns, err := replacement.State().Namespace("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ns.Get("flushed"); err != nil || string(got) != "yes" {
+		t.Errorf("replacement reads %q, %v; want what the old server flushed", got, err)
+	}
+
+	// Once the old server drains, its hooks do not run again
+	if err := old.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+	if flushes != 1 {
+		t.Errorf("shutdown hooks ran %d times, want 1", flushes)
+	}
+}
@@ -0,0 +1,65 @@
+// internal/mcp/server/state.go
+package server
+
+import (
+	"log/slog"
+
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/pkg/state"
+)
+
+// openStateStore opens the configured state store; without a path, or when
+// it cannot be opened, providers run without persistence
+func openStateStore(cfg config.StorageConfig) *state.Store {
+	if cfg.Path == "" {
+		return nil
+	}
+	store, err := state.Open(cfg.Path, cfg.OpenTimeout)
+	if err != nil {
+		slog.Error("Failed to open state store, continuing without persistence", "path", cfg.Path, "error", err)
+		return nil
+	}
+	if removed, err := store.Sweep(); err != nil {
+		slog.Warn("Failed to sweep expired state", "error", err)
+	} else if removed > 0 {
+		slog.Debug("Swept expired state", "entries", removed)
+	}
+	return store
+}
+
+// State returns the store providers keep persistent data in, or nil when
+// storage is not configured. Each provider should work in a namespace of
+// its own name.
+func (s *Server) State() *state.Store {
+	return s.state
+}
+
+// sweepState periodically removes expired entries until shutdown
+func (s *Server) sweepState() {
+	interval := s.config.Storage.SweepInterval
+	if s.state == nil || interval <= 0 {
+		return
+	}
+	ticker := s.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C():
+			if _, err := s.state.Sweep(); err != nil {
+				slog.Warn("Failed to sweep expired state", "error", err)
+			}
+		}
+	}
+}
+
+// closeState closes the store once shutdown hooks have had their chance
+// to write to it
+func (s *Server) closeState() error {
+	if s.state == nil {
+		return nil
+	}
+	return s.state.Close()
+}
@@ -0,0 +1,159 @@
+// internal/mcp/server/subscriptions.go
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+	"github.com/dkoosis/axe-handle/pkg/mcperrors"
+)
+
+// subscriptionKey identifies one session's subscription to one URI
+type subscriptionKey struct {
+	sess *session.Session
+	uri  string
+}
+
+// subscription tracks when a subscriber was last notified and whether an
+// update is waiting for the end of the current window
+type subscription struct {
+	lastSent time.Time
+	timer    *time.Timer // pending trailing update, if any
+}
+
+// subscriptionManager sends notifications/resources/updated to subscribed
+// sessions, at most once per interval per (session, uri). Updates inside the
+// window collapse into one trailing notification at its end.
+type subscriptionManager struct {
+	interval time.Duration
+	send     func(sess *session.Session, uri string)
+
+	subs map[subscriptionKey]*subscription
+	mu   sync.Mutex
+}
+
+// newSubscriptionManager creates a manager whose minimum interval is the
+// larger of debounce and 1/maxRate.
+func newSubscriptionManager(debounce time.Duration, maxRate float64, send func(*session.Session, string)) *subscriptionManager {
+	interval := debounce
+	if maxRate > 0 {
+		if byRate := time.Duration(float64(time.Second) / maxRate); byRate > interval {
+			interval = byRate
+		}
+	}
+	return &subscriptionManager{
+		interval: interval,
+		send:     send,
+		subs:     make(map[subscriptionKey]*subscription),
+	}
+}
+
+// subscribe registers sess for updates to uri
+func (m *subscriptionManager) subscribe(sess *session.Session, uri string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := subscriptionKey{sess, uri}
+	if _, ok := m.subs[key]; !ok {
+		m.subs[key] = &subscription{}
+	}
+}
+
+// unsubscribe removes sess's subscription to uri
+func (m *subscriptionManager) unsubscribe(sess *session.Session, uri string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.remove(subscriptionKey{sess, uri})
+}
+
+// removeSession drops every subscription held by sess
+func (m *subscriptionManager) removeSession(sess *session.Session) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key := range m.subs {
+		if key.sess == sess {
+			m.remove(key)
+		}
+	}
+}
+
+// remove deletes a subscription and its pending update; m.mu must be held
+func (m *subscriptionManager) remove(key subscriptionKey) {
+	if sub, ok := m.subs[key]; ok {
+		if sub.timer != nil {
+			sub.timer.Stop()
+		}
+		delete(m.subs, key)
+	}
+}
+
+// updated notifies uri's subscribers now, or at the end of their window if
+// they were notified too recently
+func (m *subscriptionManager) updated(uri string) {
+	m.mu.Lock()
+	var due []*session.Session
+	now := time.Now()
+	for key, sub := range m.subs {
+		if key.uri != uri || sub.timer != nil {
+			continue // not subscribed, or an update is already pending
+		}
+		wait := m.interval - now.Sub(sub.lastSent)
+		if wait <= 0 {
+			sub.lastSent = now
+			due = append(due, key.sess)
+			continue
+		}
+		sub.timer = time.AfterFunc(wait, func() { m.flush(key) })
+	}
+	m.mu.Unlock()
+
+	for _, sess := range due {
+		m.send(sess, uri)
+	}
+}
+
+// flush sends a trailing update once its window has passed
+func (m *subscriptionManager) flush(key subscriptionKey) {
+	m.mu.Lock()
+	sub, ok := m.subs[key]
+	if !ok {
+		m.mu.Unlock()
+		return
+	}
+	sub.timer = nil
+	sub.lastSent = time.Now()
+	m.mu.Unlock()
+
+	m.send(key.sess, key.uri)
+}
+
+// Subscribe handles resources/subscribe for the session in ctx.
+func (s *Server) Subscribe(ctx context.Context, uri string) error {
+	sess := session.FromContext(ctx)
+	if sess == nil {
+		return mcperrors.NewInternalError(fmt.Errorf("no session for resources/subscribe"))
+	}
+	if !sess.Profile().AllowsResource(uri) {
+		return mcperrors.NewResourceNotFoundError(uri)
+	}
+	s.subscriptions.subscribe(sess, uri)
+	return nil
+}
+
+// Unsubscribe handles resources/unsubscribe for the session in ctx.
+func (s *Server) Unsubscribe(ctx context.Context, uri string) error {
+	sess := session.FromContext(ctx)
+	if sess == nil {
+		return mcperrors.NewInternalError(fmt.Errorf("no session for resources/unsubscribe"))
+	}
+	s.subscriptions.unsubscribe(sess, uri)
+	return nil
+}
+
+// sendResourceUpdated delivers one resources/updated notification
+func (s *Server) sendResourceUpdated(sess *session.Session, uri string) {
+	_ = s.Notify(context.Background(), sess, protocol.NotificationResourcesUpdated, protocol.ResourceUpdatedParams{URI: uri})
+}
@@ -0,0 +1,208 @@
+// internal/mcp/server/supervisor/supervisor.go
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// Default backoff settings for restarting crashed children
+const (
+	DefaultMinBackoff  = 1 * time.Second
+	DefaultMaxBackoff  = 1 * time.Minute
+	DefaultStableAfter = 1 * time.Minute
+)
+
+// Child describes a subprocess-backed provider under supervision
+type Child struct {
+	// Name identifies the child in logs and status reports
+	Name string
+
+	// Command builds a fresh command for each (re)start
+	Command func() *exec.Cmd
+
+	// OnStart is called after the process has started, typically to
+	// register its tools. Returning an error kills the process.
+	OnStart func(cmd *exec.Cmd) error
+
+	// OnStop is called after the process has exited, typically to mark its
+	// tools unavailable until the next start
+	OnStop func()
+}
+
+// Status describes the current state of a supervised child
+type Status struct {
+	Name      string
+	Running   bool
+	Restarts  int
+	LastError string
+}
+
+// Supervisor restarts crashed subprocess providers with exponential backoff
+type Supervisor struct {
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	stableAfter time.Duration
+
+	// onChange is called whenever a child comes up or goes down
+	onChange func()
+
+	status map[string]*Status
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+}
+
+// NewSupervisor creates a supervisor; onChange may be nil
+func NewSupervisor(onChange func()) *Supervisor {
+	return &Supervisor{
+		minBackoff:  DefaultMinBackoff,
+		maxBackoff:  DefaultMaxBackoff,
+		stableAfter: DefaultStableAfter,
+		onChange:    onChange,
+		status:      make(map[string]*Status),
+	}
+}
+
+// SetBackoff configures the restart backoff range
+func (s *Supervisor) SetBackoff(min, max time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.minBackoff = min
+	s.maxBackoff = max
+}
+
+// Supervise starts the child and keeps restarting it until ctx is done
+func (s *Supervisor) Supervise(ctx context.Context, child Child) error {
+	if child.Name == "" || child.Command == nil {
+		return fmt.Errorf("supervised child needs a name and a command")
+	}
+
+	s.mu.Lock()
+	if _, exists := s.status[child.Name]; exists {
+		s.mu.Unlock()
+		return fmt.Errorf("child %q is already supervised", child.Name)
+	}
+	s.status[child.Name] = &Status{Name: child.Name}
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.run(ctx, child)
+	return nil
+}
+
+// Status returns a snapshot of all supervised children
+func (s *Supervisor) Status() []Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]Status, 0, len(s.status))
+	for _, st := range s.status {
+		result = append(result, *st)
+	}
+	return result
+}
+
+// Wait blocks until all supervision loops have exited
+func (s *Supervisor) Wait() {
+	s.wg.Wait()
+}
+
+// run is the supervision loop for a single child
+func (s *Supervisor) run(ctx context.Context, child Child) {
+	defer s.wg.Done()
+
+	s.mu.RLock()
+	backoff := s.minBackoff
+	s.mu.RUnlock()
+
+	for {
+		started := time.Now()
+		err := s.runOnce(ctx, child)
+
+		if ctx.Err() != nil {
+			slog.Info("Stopped supervised provider", "name", child.Name)
+			return
+		}
+
+		s.mu.Lock()
+		st := s.status[child.Name]
+		st.Restarts++
+		if err != nil {
+			st.LastError = err.Error()
+		}
+		// A child that stayed up for a while earns a fresh backoff
+		if time.Since(started) >= s.stableAfter {
+			backoff = s.minBackoff
+		}
+		maxBackoff := s.maxBackoff
+		s.mu.Unlock()
+
+		slog.Warn("Supervised provider exited, restarting",
+			"name", child.Name,
+			"error", err,
+			"backoff", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// runOnce starts the child and blocks until it exits or ctx is done
+func (s *Supervisor) runOnce(ctx context.Context, child Child) error {
+	cmd := child.Command()
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start: %w", err)
+	}
+
+	if child.OnStart != nil {
+		if err := child.OnStart(cmd); err != nil {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+			return fmt.Errorf("start hook failed: %w", err)
+		}
+	}
+
+	s.setRunning(child.Name, true)
+	slog.Info("Supervised provider started", "name", child.Name, "pid", cmd.Process.Pid)
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	var err error
+	select {
+	case err = <-exited:
+	case <-ctx.Done():
+		_ = cmd.Process.Kill()
+		err = <-exited
+	}
+
+	s.setRunning(child.Name, false)
+	if child.OnStop != nil {
+		child.OnStop()
+	}
+	return err
+}
+
+// setRunning records the child's state and reports the change
+func (s *Supervisor) setRunning(name string, running bool) {
+	s.mu.Lock()
+	s.status[name].Running = running
+	onChange := s.onChange
+	s.mu.Unlock()
+
+	if onChange != nil {
+		onChange()
+	}
+}
@@ -0,0 +1,203 @@
+// internal/mcp/server/tenants.go
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+	"github.com/dkoosis/axe-handle/internal/transport"
+	"github.com/dkoosis/axe-handle/pkg/mcperrors"
+	"github.com/dkoosis/axe-handle/pkg/state"
+)
+
+// defaultTenantWindow is the rate limit window of tenants that set none
+const defaultTenantWindow = time.Minute
+
+// tenant is one team sharing the server. Its sessions get a view of the
+// state store, caches and a rate limit of their own, and their own
+// instances of the providers registered with RegisterPerTenant.
+type tenant struct {
+	name      string
+	profile   *session.Profile // clients are held to it; nil lets them choose
+	state     *state.Store     // nil when storage is not configured
+	resources *resources.Cache // nil when caching is disabled
+	limit     int              // requests per window; 0 means unlimited
+	window    time.Duration
+
+	mu     sync.Mutex
+	recent []time.Time // admitted requests still inside the window
+}
+
+// newTenants sets up the configured tenants and indexes them by name and
+// by the identities that belong to them. Profiles and the state store must
+// already be in place.
+func (s *Server) newTenants(list []config.TenantConfig) {
+	s.tenants = make(map[string]*tenant, len(list))
+	s.tenantOf = make(map[string]*tenant)
+	for _, tc := range list {
+		t := &tenant{
+			name:    tc.Name,
+			profile: s.profiles[tc.Profile],
+			limit:   tc.Limit,
+			window:  tc.Window,
+		}
+		if t.window <= 0 {
+			t.window = defaultTenantWindow
+		}
+		if s.state != nil {
+			view, err := s.state.Tenant(tc.Name)
+			if err != nil {
+				slog.Error("Tenant runs without persistence", "tenant", tc.Name, "error", err)
+			}
+			t.state = view
+		}
+		if s.config.Resources.Cache.Enabled {
+			rc := s.config.Resources.Cache
+			t.resources = resources.NewCache(rc.MaxBytes, rc.MaxEntryBytes, rc.TTL)
+		}
+		s.toolsManager.SetTenantResultCache(tc.Name, newResultCache(s.config.Tools.Cache, s.clock))
+
+		s.tenants[tc.Name] = t
+		for _, id := range tc.Identities {
+			s.tenantOf[id] = t
+		}
+	}
+}
+
+// Tenants returns the names of the configured tenants
+func (s *Server) Tenants() []string {
+	names := make([]string, 0, len(s.tenants))
+	for name := range s.tenants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// tenantFor returns the tenant sess belongs to, or nil
+func (s *Server) tenantFor(sess *session.Session) *tenant {
+	if sess == nil || sess.Tenant() == "" {
+		return nil
+	}
+	return s.tenants[sess.Tenant()]
+}
+
+// resolveTenant finds the tenant of the caller in ctx and the profile it
+// is served with. A tenant that pins a profile refuses the endpoints of
+// the others.
+func (s *Server) resolveTenant(ctx context.Context) (*tenant, *session.Profile, error) {
+	name := transport.Profile(ctx)
+	t := s.tenantOf[transport.Identity(ctx)]
+	if t == nil || t.profile == nil {
+		return t, s.profiles[name], nil
+	}
+	if name != "" && name != t.profile.Name {
+		return nil, nil, mcperrors.NewInvalidRequestError(
+			fmt.Errorf("profile %q is not available to tenant %q", name, t.name))
+	}
+	return t, t.profile, nil
+}
+
+// Admit checks a request from sess against the rate limit of its tenant,
+// so one team's burst cannot starve the others. Clients outside tenants
+// are not limited here.
+func (s *Server) Admit(sess *session.Session, method string) error {
+	t := s.tenantFor(sess)
+	if t == nil {
+		return nil
+	}
+	if retryAfter, ok := t.take(s.clock.Now()); !ok {
+		slog.Warn("Tenant rate limit exceeded", "tenant", t.name, "method", method, "retry_after", retryAfter)
+		return mcperrors.NewRateLimitedError(retryAfter)
+	}
+	return nil
+}
+
+// take records a request at now, or reports how long until one is allowed
+// when the tenant's limit for the current window is used up
+func (t *tenant) take(now time.Time) (time.Duration, bool) {
+	if t.limit <= 0 {
+		return 0, true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for len(t.recent) > 0 && now.Sub(t.recent[0]) >= t.window {
+		t.recent = t.recent[1:]
+	}
+	if len(t.recent) >= t.limit {
+		return t.window - now.Sub(t.recent[0]), false
+	}
+	t.recent = append(t.recent, now)
+	return 0, true
+}
+
+// resourceCacheFor returns the resource cache of the client in ctx, or nil
+// when caching is disabled
+func (s *Server) resourceCacheFor(ctx context.Context) *resources.Cache {
+	if t := s.tenantFor(session.FromContext(ctx)); t != nil {
+		return t.resources
+	}
+	return s.resourceCache
+}
+
+// invalidateResource drops uri from every resource cache
+func (s *Server) invalidateResource(uri string) {
+	if s.resourceCache != nil {
+		s.resourceCache.Invalidate(uri)
+	}
+	for _, t := range s.tenants {
+		if t.resources != nil {
+			t.resources.Invalidate(uri)
+		}
+	}
+}
+
+// ToolsBuilder creates an instance of a provider over store and registers
+// its tools with m; store is nil when storage is not configured
+type ToolsBuilder func(store *state.Store, m *manager.ToolsManager) error
+
+// RegisterPerTenant builds one instance of a provider for the clients
+// outside tenants and one more for each tenant over the tenant's view of
+// the state store, then serves the tools of the first instance. Each call
+// is answered by the instance of the caller's tenant, so tenants share no
+// provider state: not stored data, not caches and not rate limits.
+func (s *Server) RegisterPerTenant(build ToolsBuilder) error {
+	shared := manager.NewToolsManager()
+	if err := build(s.state, shared); err != nil {
+		return err
+	}
+	instances := make(map[string]*manager.ToolsManager, len(s.tenants))
+	for name, t := range s.tenants {
+		m := manager.NewToolsManager()
+		if err := build(t.state, m); err != nil {
+			return fmt.Errorf("tenant %s: %w", name, err)
+		}
+		instances[name] = m
+	}
+
+	for _, tool := range shared.ListTools() {
+		name := tool.Name
+		s.toolsManager.RegisterTool(tool, func(ctx context.Context, args json.RawMessage, progressCh chan<- float64) (protocol.ToolsCallResult, error) {
+			m := shared
+			if t := s.tenantFor(session.FromContext(ctx)); t != nil {
+				m = instances[t.name]
+			}
+			handler, ok := m.Handler(name)
+			if !ok {
+				return protocol.ToolsCallResult{}, fmt.Errorf("tool %s has no instance for this tenant", name)
+			}
+			return handler(ctx, args, progressCh)
+		})
+	}
+	return nil
+}
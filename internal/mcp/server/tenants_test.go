@@ -0,0 +1,32 @@
+// internal/mcp/server/tenants_test.go
+package server_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/internal/mcp/jobs"
+)
+
+func TestTenantsDoNotShareJobs(t *testing.T) {
+	srv := newJobServer(t, func(cfg *config.Config) {
+		cfg.Tenants = []config.TenantConfig{
+			{Name: "red", Identities: []string{"alice"}},
+			{Name: "blue", Identities: []string{"bob"}},
+		}
+	})
+	// Both teams run the same client, which resumes with the same token
+	red := connect(t, srv, "alice-token", "claude-desktop")
+	blue := connect(t, srv, "bob-token", "claude-desktop")
+
+	job := startJob(t, red, 1)
+	for i, tool := range []string{jobs.StatusTool, jobs.ResultTool, jobs.CancelTool} {
+		if text, isErr := callTool(t, blue, i+1, tool, map[string]string{"jobId": job}); !isErr || !strings.Contains(text, jobs.ErrUnknownJob.Error()) {
+			t.Errorf("blue reached red's job with %s: %s", tool, text)
+		}
+	}
+	if text, isErr := callTool(t, red, 2, jobs.StatusTool, map[string]string{"jobId": job}); isErr {
+		t.Errorf("red cannot see its own job: %s", text)
+	}
+}
@@ -0,0 +1,70 @@
+// internal/mcp/server/toolproviders.go
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools"
+)
+
+// registerProviderTools registers the tools provider lists with the tools
+// manager, each answered by the provider's ExecuteTool
+func (s *Server) registerProviderTools(provider tools.Provider) {
+	list, err := provider.ListTools()
+	if err != nil {
+		slog.Error("Failed to list provider tools", "provider", fmt.Sprintf("%T", provider), "error", err)
+		return
+	}
+	for _, t := range list {
+		schema := t.InputSchema
+		if schema == nil {
+			schema = map[string]interface{}{"type": "object"}
+		}
+		name := t.Name
+		s.toolsManager.RegisterTool(protocol.Tool{
+			Name:        name,
+			Description: t.Description,
+			InputSchema: schema,
+		}, func(_ context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+			params := map[string]interface{}{}
+			if len(args) > 0 {
+				if err := json.Unmarshal(args, &params); err != nil {
+					return protocol.ToolsCallResult{}, fmt.Errorf("%w: %v", tools.ErrInvalidToolArguments, err)
+				}
+			}
+			result, err := provider.ExecuteTool(name, params)
+			if err != nil {
+				return protocol.ToolsCallResult{}, err
+			}
+			return providerToolResult(result)
+		})
+	}
+}
+
+// providerToolResult converts what a provider's ExecuteTool returned into
+// a tool result: results and content pass through, strings become text
+// and anything else is sent as JSON text
+func providerToolResult(v interface{}) (protocol.ToolsCallResult, error) {
+	switch r := v.(type) {
+	case protocol.ToolsCallResult:
+		return r, nil
+	case *protocol.ToolsCallResult:
+		if r != nil {
+			return *r, nil
+		}
+		return protocol.ToolsCallResult{Content: []protocol.Content{}}, nil
+	case []protocol.Content:
+		return protocol.ToolsCallResult{Content: r}, nil
+	case string:
+		return protocol.ToolsCallResult{Content: []protocol.Content{protocol.TextContent(r)}}, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return protocol.ToolsCallResult{}, fmt.Errorf("failed to encode tool result: %w", err)
+	}
+	return protocol.ToolsCallResult{Content: []protocol.Content{protocol.TextContent(string(data))}}, nil
+}
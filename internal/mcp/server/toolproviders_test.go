@@ -0,0 +1,86 @@
+// internal/mcp/server/toolproviders_test.go
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools"
+)
+
+// echoProvider serves one tool that echoes its text argument
+type echoProvider struct{}
+
+func (echoProvider) ListTools() ([]tools.Tool, error) {
+	return []tools.Tool{{
+		Name:        "echo",
+		Description: "Echoes text",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"text": map[string]interface{}{"type": "string"}},
+			"required":   []string{"text"},
+		},
+	}}, nil
+}
+
+func (echoProvider) ExecuteTool(name string, args map[string]interface{}) (interface{}, error) {
+	if name != "echo" {
+		return nil, tools.ErrToolNotFound
+	}
+	text, ok := args["text"].(string)
+	if !ok {
+		return nil, tools.ErrInvalidToolArguments
+	}
+	return text, nil
+}
+
+func TestRegisterToolProvider(t *testing.T) {
+	s := NewServer(config.Default())
+	s.RegisterToolProvider(echoProvider{})
+
+	var listed bool
+	for _, tool := range s.GetToolsManager().ListTools() {
+		if tool.Name == "echo" {
+			listed = true
+		}
+	}
+	if !listed {
+		t.Fatal("provider tool echo is not listed")
+	}
+
+	result, err := s.GetToolsManager().CallTool(context.Background(), "echo", json.RawMessage(`{"text":"hi"}`), "")
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if result.IsError || len(result.Content) != 1 || result.Content[0].Text != "hi" {
+		t.Errorf("CallTool() = %+v, want text %q", result, "hi")
+	}
+}
+
+func TestProviderToolResult(t *testing.T) {
+	tests := []struct {
+		name string
+		in   interface{}
+		want string
+	}{
+		{name: "string", in: "plain", want: "plain"},
+		{name: "value", in: map[string]int{"n": 1}, want: `{"n":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := providerToolResult(tt.in)
+			if err != nil {
+				t.Fatalf("providerToolResult() error = %v", err)
+			}
+			if len(got.Content) != 1 || got.Content[0].Text != tt.want {
+				t.Errorf("providerToolResult() = %+v, want text %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := providerToolResult(make(chan int)); err == nil {
+		t.Errorf("providerToolResult(chan) error = %v, want an encoding error", err)
+	}
+}
@@ -0,0 +1,61 @@
+// internal/mcp/server/transport.go
+package server
+
+import (
+	"fmt"
+
+	"github.com/dkoosis/axe-handle/internal/codec"
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/internal/transport"
+)
+
+// NewTransport builds the transport cfg selects with every option it sets,
+// so the server binary and embedded servers serve clients alike. Stdio
+// serves profile, or the whole server when it is empty; SSE serves each
+// configured profile at its own path. Development mode captures traffic.
+func NewTransport(cfg *config.Config, profile string) (transport.Transport, error) {
+	dev := cfg.Development()
+	switch cfg.Transport.Type {
+	case "stdio":
+		framing, err := codec.ParseFraming(cfg.Transport.Stdio.Framing)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transport.stdio.framing: %w", err)
+		}
+		return transport.NewStdioTransport(
+			transport.WithFraming(framing),
+			transport.WithStdioTimeouts(transport.Timeouts(cfg.Transport.Stdio.Timeouts)),
+			transport.WithStdioProfile(profile),
+			transport.WithStdioCapture(dev),
+		), nil
+	case "sse":
+		proxies, err := transport.ParseTrustedProxies(cfg.Transport.SSE.TrustedProxies)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transport.sse.trustedProxies: %w", err)
+		}
+		tokens, err := cfg.Transport.SSE.Tokens.Identities()
+		if err != nil {
+			return nil, fmt.Errorf("invalid transport.sse.tokens: %w", err)
+		}
+		return transport.NewSSETransport(cfg.Transport.SSE.Host, cfg.Transport.SSE.Port,
+			transport.WithTrustedProxies(proxies),
+			transport.WithAuthenticator(transport.BearerTokens(tokens)),
+			transport.WithCompression(cfg.Transport.SSE.Compression),
+			transport.WithMaxBodyBytes(cfg.Transport.SSE.MaxBodyBytes),
+			transport.WithTimeouts(transport.Timeouts(cfg.Transport.SSE.Timeouts)),
+			transport.WithBasePath(cfg.Transport.SSE.BasePath),
+			transport.WithEndpoints(cfg.Transport.SSE.Path, cfg.Transport.SSE.MessagePath),
+			transport.WithProfiles(ProfileNames(cfg)...),
+			transport.WithCapture(dev)), nil
+	default:
+		return nil, fmt.Errorf("unsupported transport type: %s", cfg.Transport.Type)
+	}
+}
+
+// ProfileNames returns the names of the profiles cfg configures
+func ProfileNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Profiles))
+	for _, p := range cfg.Profiles {
+		names = append(names, p.Name)
+	}
+	return names
+}
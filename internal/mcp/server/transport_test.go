@@ -0,0 +1,61 @@
+// internal/mcp/server/transport_test.go
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/internal/mcp/server"
+	"github.com/dkoosis/axe-handle/internal/transport"
+	"github.com/dkoosis/axe-handle/pkg/mcptest"
+)
+
+func TestTransportServesConfiguredProfiles(t *testing.T) {
+	srv := mcptest.NewSSEServer(t, mcptest.WithServerOptions(func(cfg *config.Config) {
+		cfg.Profiles = []config.ProfileConfig{{Name: "ops"}}
+	}))
+
+	for path, want := range map[string]int{
+		srv.Transport.ProfileStreamPath("ops"):   http.StatusOK,
+		srv.Transport.ProfileStreamPath("other"): http.StatusNotFound,
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		cancel()
+		if resp.StatusCode != want {
+			t.Errorf("GET %s = %d, want %d", path, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestTransportFollowsConfig(t *testing.T) {
+	cfg := config.Default()
+	cfg.Transport.Type = "stdio"
+	if tr, err := server.NewTransport(cfg, ""); err != nil {
+		t.Fatal(err)
+	} else if _, ok := tr.(*transport.StdioTransport); !ok {
+		t.Errorf("stdio config built %T", tr)
+	}
+
+	cfg.Transport.Type = "sse"
+	cfg.Transport.SSE.TrustedProxies = []string{"not an address"}
+	if _, err := server.NewTransport(cfg, ""); err == nil {
+		t.Error("invalid trusted proxies were accepted")
+	}
+
+	cfg.Transport.Type = "carrier-pigeon"
+	if _, err := server.NewTransport(cfg, ""); err == nil {
+		t.Error("unknown transport type was accepted")
+	}
+}
@@ -0,0 +1,152 @@
+// internal/mcp/session/coalesce.go
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+)
+
+// Coalescing defaults
+const (
+	DefaultFlushInterval = 50 * time.Millisecond
+	DefaultMaxBatch      = 32
+)
+
+// Sender delivers a single notification
+type Sender func(method string, params interface{}) error
+
+// pendingNotification is a notification waiting for the next flush
+type pendingNotification struct {
+	method string
+	params interface{}
+}
+
+// Coalescer batches high-frequency notifications for one session. Pending
+// notifications that supersede each other (progress for the same token,
+// updates for the same resource, repeated list_changed) are merged so only
+// the latest is sent.
+type Coalescer struct {
+	send     Sender
+	interval time.Duration
+	maxBatch int
+
+	pending []pendingNotification
+	index   map[string]int // coalescing key -> position in pending
+	timer   *time.Timer
+	closed  bool
+	mu      sync.Mutex
+}
+
+// NewCoalescer creates a coalescer that flushes through send every interval,
+// or as soon as maxBatch distinct notifications are pending.
+func NewCoalescer(interval time.Duration, maxBatch int, send Sender) *Coalescer {
+	if interval <= 0 {
+		interval = DefaultFlushInterval
+	}
+	if maxBatch <= 0 {
+		maxBatch = DefaultMaxBatch
+	}
+	return &Coalescer{
+		send:     send,
+		interval: interval,
+		maxBatch: maxBatch,
+		index:    make(map[string]int),
+	}
+}
+
+// Coalesces reports whether notifications of this method are batched
+func Coalesces(method string) bool {
+	switch method {
+	case protocol.NotificationProgress,
+		protocol.NotificationResourcesUpdated,
+		protocol.NotificationResourcesListChanged,
+		protocol.NotificationToolsListChanged,
+		protocol.NotificationPromptsListChanged:
+		return true
+	}
+	return false
+}
+
+// Add queues a notification, replacing any pending one it supersedes
+func (c *Coalescer) Add(method string, params interface{}) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return c.send(method, params)
+	}
+
+	key := coalesceKey(method, params)
+	if i, ok := c.index[key]; ok {
+		c.pending[i].params = params
+		c.mu.Unlock()
+		return nil
+	}
+
+	c.index[key] = len(c.pending)
+	c.pending = append(c.pending, pendingNotification{method: method, params: params})
+	full := len(c.pending) >= c.maxBatch
+	if !full && c.timer == nil {
+		c.timer = time.AfterFunc(c.interval, func() { _ = c.Flush() })
+	}
+	c.mu.Unlock()
+
+	if full {
+		return c.Flush()
+	}
+	return nil
+}
+
+// Flush sends every pending notification now, in the order first queued
+func (c *Coalescer) Flush() error {
+	c.mu.Lock()
+	batch := c.pending
+	c.pending = nil
+	c.index = make(map[string]int)
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	c.mu.Unlock()
+
+	var errs []error
+	for _, n := range batch {
+		if err := c.send(n.method, n.params); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close flushes pending notifications; later ones are sent immediately
+func (c *Coalescer) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	return c.Flush()
+}
+
+// coalesceKey identifies which pending notifications a new one supersedes
+func coalesceKey(method string, params interface{}) string {
+	var fields struct {
+		ProgressToken interface{} `json:"progressToken"`
+		URI           string      `json:"uri"`
+	}
+	switch method {
+	case protocol.NotificationProgress, protocol.NotificationResourcesUpdated:
+		data, err := json.Marshal(params)
+		if err != nil {
+			return method
+		}
+		_ = json.Unmarshal(data, &fields)
+	}
+
+	if fields.ProgressToken != nil {
+		token, _ := json.Marshal(fields.ProgressToken)
+		return method + "\x00" + string(token)
+	}
+	return method + "\x00" + fields.URI
+}
@@ -0,0 +1,167 @@
+// internal/mcp/session/deadletter.go
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+)
+
+// DefaultDeadLetterCapacity bounds how many undelivered notifications are
+// kept for each tenant
+const DefaultDeadLetterCapacity = 1000
+
+// retainedMethods lists notifications worth redelivering; the rest are
+// stale by the time a client comes back and are dropped
+var retainedMethods = map[string]bool{
+	protocol.NotificationResourcesUpdated: true,
+	protocol.NotificationProgress:         true,
+}
+
+// DeadLetter is a notification that could not be delivered
+type DeadLetter struct {
+	Tenant     string          `json:"tenant,omitempty"`
+	SessionKey string          `json:"sessionKey"`
+	Method     string          `json:"method"`
+	Params     json.RawMessage `json:"params,omitempty"`
+	FailedAt   time.Time       `json:"failedAt"`
+	Error      string          `json:"error"`
+}
+
+// DeadLetterQueue holds undeliverable notifications until the client they
+// were meant for resumes. If a path is set the queue survives restarts.
+// Tenants are kept apart: each has its own capacity, so one team's backlog
+// never evicts another's, and letters are only handed to their own tenant.
+type DeadLetterQueue struct {
+	capacity int
+	path     string
+	letters  []DeadLetter
+	mu       sync.Mutex
+}
+
+// NewDeadLetterQueue creates a queue, loading persisted letters from path if
+// it is non-empty and exists
+func NewDeadLetterQueue(capacity int, path string) (*DeadLetterQueue, error) {
+	q := &DeadLetterQueue{capacity: capacity, path: path}
+	if path == "" {
+		return q, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dead-letter file: %w", err)
+	}
+	if err := json.Unmarshal(data, &q.letters); err != nil {
+		return nil, fmt.Errorf("failed to parse dead-letter file: %w", err)
+	}
+	return q, nil
+}
+
+// Retains reports whether notifications with this method are dead-lettered
+func Retains(method string) bool {
+	return retainedMethods[method]
+}
+
+// Add stores a failed notification for a session of tenant, evicting the
+// tenant's oldest when it has used its capacity
+func (q *DeadLetterQueue) Add(tenant, sessionKey, method string, params interface{}, sendErr error) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		slog.Error("Failed to encode dead letter", "method", method, "error", err)
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.letters = append(q.letters, DeadLetter{
+		Tenant:     tenant,
+		SessionKey: sessionKey,
+		Method:     method,
+		Params:     raw,
+		FailedAt:   time.Now(),
+		Error:      sendErr.Error(),
+	})
+	q.evict(tenant)
+	q.persist()
+}
+
+// evict drops tenant's oldest letters beyond the capacity; callers hold q.mu
+func (q *DeadLetterQueue) evict(tenant string) {
+	over := -q.capacity
+	for _, l := range q.letters {
+		if l.Tenant == tenant {
+			over++
+		}
+	}
+	if over <= 0 {
+		return
+	}
+
+	slog.Warn("Dead-letter queue full, dropping oldest", "tenant", tenant, "dropped", over)
+	kept := q.letters[:0]
+	for _, l := range q.letters {
+		if l.Tenant == tenant && over > 0 {
+			over--
+			continue
+		}
+		kept = append(kept, l)
+	}
+	q.letters = kept
+}
+
+// Take removes and returns every letter queued for sessionKey of tenant
+func (q *DeadLetterQueue) Take(tenant, sessionKey string) []DeadLetter {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var taken, kept []DeadLetter
+	for _, l := range q.letters {
+		if l.Tenant == tenant && l.SessionKey == sessionKey {
+			taken = append(taken, l)
+		} else {
+			kept = append(kept, l)
+		}
+	}
+	if len(taken) > 0 {
+		q.letters = kept
+		q.persist()
+	}
+	return taken
+}
+
+// Len returns the number of queued letters
+func (q *DeadLetterQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.letters)
+}
+
+// persist writes the queue to disk; callers hold q.mu
+func (q *DeadLetterQueue) persist() {
+	if q.path == "" {
+		return
+	}
+
+	data, err := json.Marshal(q.letters)
+	if err != nil {
+		slog.Error("Failed to encode dead-letter queue", "error", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(q.path), 0755); err != nil {
+		slog.Error("Failed to create dead-letter directory", "error", err)
+		return
+	}
+	if err := os.WriteFile(q.path, data, 0600); err != nil {
+		slog.Error("Failed to write dead-letter file", "error", err)
+	}
+}
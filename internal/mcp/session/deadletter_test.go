@@ -0,0 +1,43 @@
+// internal/mcp/session/deadletter_test.go
+package session_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+)
+
+func TestDeadLettersAreKeptPerTenant(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "letters.json")
+	q, err := session.NewDeadLetterQueue(2, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sendErr := errors.New("connection lost")
+	q.Add("blue", "client", protocol.NotificationProgress, map[string]int{"progress": 1}, sendErr)
+	// Red filling its share does not evict blue's letter
+	for i := 0; i < 5; i++ {
+		q.Add("red", "client", protocol.NotificationProgress, map[string]int{"progress": i}, sendErr)
+	}
+	if got := q.Len(); got != 3 {
+		t.Fatalf("queue holds %d letters, want 2 of red's and 1 of blue's", got)
+	}
+
+	// The same session key in another tenant is another client
+	reloaded, err := session.NewDeadLetterQueue(2, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Take("green", "client"); len(got) != 0 {
+		t.Errorf("green took %d letters of other tenants", len(got))
+	}
+	if got := reloaded.Take("blue", "client"); len(got) != 1 || string(got[0].Params) != `{"progress":1}` {
+		t.Errorf("blue took %+v, want its one letter", got)
+	}
+	if got := reloaded.Take("red", "client"); len(got) != 2 || string(got[0].Params) != `{"progress":3}` {
+		t.Errorf("red took %+v, want its two newest letters", got)
+	}
+}
@@ -0,0 +1,100 @@
+// internal/mcp/session/features.go
+package session
+
+import (
+	"strings"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+)
+
+// Feature is a kind of server-to-client traffic a client must be ready for
+type Feature string
+
+// Features the server gates per client
+const (
+	FeatureLogging     Feature = "logging"     // notifications/message
+	FeatureProgress    Feature = "progress"    // notifications/progress
+	FeatureListChanged Feature = "listChanged" // notifications/{tools,resources,prompts}/list_changed
+	FeatureSampling    Feature = "sampling"    // sampling/createMessage requests
+	FeatureElicitation Feature = "elicitation" // elicitation/create requests
+	FeatureRoots       Feature = "roots"       // roots/list requests
+)
+
+// featureSince is the first protocol version that defines each feature.
+// Versions are dates, so they order as strings.
+var featureSince = map[Feature]string{
+	FeatureLogging:     "2024-11-05",
+	FeatureProgress:    "2024-11-05",
+	FeatureListChanged: "2024-11-05",
+	FeatureSampling:    "2024-11-05",
+	FeatureElicitation: "2025-06-18",
+	FeatureRoots:       "2024-11-05",
+}
+
+// featureFor maps server-to-client methods to the feature they belong to
+var featureFor = map[string]Feature{
+	protocol.NotificationLoggingMessage:       FeatureLogging,
+	protocol.NotificationProgress:             FeatureProgress,
+	protocol.NotificationToolsListChanged:     FeatureListChanged,
+	protocol.NotificationResourcesListChanged: FeatureListChanged,
+	protocol.NotificationPromptsListChanged:   FeatureListChanged,
+	protocol.MethodElicitationCreate:          FeatureElicitation,
+	protocol.MethodRootsList:                  FeatureRoots,
+	protocol.MethodSamplingCreate:             FeatureSampling,
+}
+
+// FeatureOf returns the feature a server-to-client method belongs to.
+// Methods without one, such as ping or resource updates, are not gated.
+func FeatureOf(method string) (Feature, bool) {
+	f, ok := featureFor[method]
+	return f, ok
+}
+
+// Supports reports whether the client may be sent traffic of feature f.
+// Nothing is sent before initialize. Features behind a client capability
+// need the client to have declared it; a declaration is the client's
+// promise, so it is honoured whatever version was negotiated. Calling
+// logging/setLevel counts as declaring logging. The other features need a
+// negotiated version that defines them.
+func (s *Session) Supports(f Feature) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.protocolVersion == "" {
+		return false
+	}
+
+	caps := s.capabilities
+	switch f {
+	case FeatureLogging:
+		return caps.Logging != nil || s.logLevelSet
+	case FeatureSampling:
+		return caps.Sampling != nil
+	case FeatureElicitation:
+		return caps.Elicitation != nil
+	case FeatureRoots:
+		return caps.Roots != nil
+	}
+
+	since, ok := featureSince[f]
+	return ok && strings.Compare(s.protocolVersion, since) >= 0
+}
+
+// Allows reports whether method may be sent to the client
+func (s *Session) Allows(method string) bool {
+	f, ok := FeatureOf(method)
+	return !ok || s.Supports(f)
+}
+
+// ProtocolVersion returns the version negotiated at initialize, or "" before it
+func (s *Session) ProtocolVersion() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.protocolVersion
+}
+
+// SetProtocolVersion records the version negotiated at initialize
+func (s *Session) SetProtocolVersion(version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.protocolVersion = version
+}
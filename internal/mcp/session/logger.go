@@ -0,0 +1,55 @@
+// internal/mcp/session/logger.go
+package session
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+)
+
+// Logger is a session-aware logger for providers. Messages go to the server
+// log and, subject to the client's logging/setLevel, to the client itself.
+type Logger struct {
+	ctx     context.Context
+	session *Session
+	name    string
+}
+
+// LoggerFromContext returns a logger for the session in ctx. Without a
+// session the logger only writes to the server log.
+func LoggerFromContext(ctx context.Context, name string) *Logger {
+	return &Logger{
+		ctx:     ctx,
+		session: FromContext(ctx),
+		name:    name,
+	}
+}
+
+// Debug logs at debug level
+func (l *Logger) Debug(msg string) {
+	l.log(protocol.LoggingLevelDebug, slog.LevelDebug, msg)
+}
+
+// Info logs at info level
+func (l *Logger) Info(msg string) {
+	l.log(protocol.LoggingLevelInfo, slog.LevelInfo, msg)
+}
+
+// Warning logs at warning level
+func (l *Logger) Warning(msg string) {
+	l.log(protocol.LoggingLevelWarning, slog.LevelWarn, msg)
+}
+
+// Error logs at error level
+func (l *Logger) Error(msg string) {
+	l.log(protocol.LoggingLevelError, slog.LevelError, msg)
+}
+
+// log writes msg to the server log and forwards it to the client
+func (l *Logger) log(level protocol.LoggingLevel, slogLevel slog.Level, msg string) {
+	slog.Log(l.ctx, slogLevel, msg, "logger", l.name)
+	if l.session != nil {
+		l.session.Log(l.ctx, level, l.name, msg)
+	}
+}
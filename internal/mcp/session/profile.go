@@ -0,0 +1,64 @@
+// internal/mcp/session/profile.go
+package session
+
+import "strings"
+
+// Profile is a named view of the server. A session using it sees only the
+// tools, resources and prompts its patterns allow and gets its instructions.
+// Each pattern is an exact name or URI, or a prefix ending in *; an empty
+// list allows everything. A nil profile allows everything.
+type Profile struct {
+	Name         string
+	Instructions string // replaces the generated instructions when set
+	Tools        []string
+	Resources    []string
+	Prompts      []string
+}
+
+// AllowsTool reports whether the profile includes the named tool
+func (p *Profile) AllowsTool(name string) bool {
+	return p == nil || matchAny(p.Tools, name)
+}
+
+// AllowsResource reports whether the profile includes uri. Resource
+// templates are matched by their URI template.
+func (p *Profile) AllowsResource(uri string) bool {
+	return p == nil || matchAny(p.Resources, uri)
+}
+
+// AllowsPrompt reports whether the profile includes the named prompt
+func (p *Profile) AllowsPrompt(name string) bool {
+	return p == nil || matchAny(p.Prompts, name)
+}
+
+// matchAny reports whether s matches one of patterns, or patterns is empty
+func matchAny(patterns []string, s string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(s, prefix) {
+				return true
+			}
+		} else if pattern == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Profile returns the profile the client connected with, or nil when it
+// sees the whole server
+func (s *Session) Profile() *Profile {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.profile
+}
+
+// SetProfile restricts the session to a profile
+func (s *Session) SetProfile(p *Profile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profile = p
+}
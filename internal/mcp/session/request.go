@@ -0,0 +1,114 @@
+// internal/mcp/session/request.go
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// DefaultRequestTimeout bounds server-to-client requests that do not set a
+// timeout of their own
+const DefaultRequestTimeout = 30 * time.Second
+
+// cancelNotifyTimeout bounds sending notifications/cancelled for a request
+// that was given up on
+const cancelNotifyTimeout = 5 * time.Second
+
+// Errors returned by Request
+var (
+	ErrNoConnection  = errors.New("session has no connection")
+	ErrNotSupported  = errors.New("client did not negotiate this request")
+	ErrClientTimeout = errors.New("client did not answer in time")
+)
+
+// requestConfig holds the settings of one server-to-client request
+type requestConfig struct {
+	timeout time.Duration
+	prefix  string
+}
+
+// RequestOption configures a server-to-client request
+type RequestOption func(*requestConfig)
+
+// WithRequestTimeout sets how long the client has to answer; 0 or less
+// waits until ctx ends
+func WithRequestTimeout(d time.Duration) RequestOption {
+	return func(c *requestConfig) {
+		c.timeout = d
+	}
+}
+
+// WithIDPrefix names the request's ID, e.g. "sample" for sample-1, so
+// requests are recognizable in client logs
+func WithIDPrefix(prefix string) RequestOption {
+	return func(c *requestConfig) {
+		c.prefix = prefix
+	}
+}
+
+// Request sends method to the client and decodes its result into result,
+// which may be nil. Every request gets an ID of its own on this session.
+// If ctx ends or the client takes longer than the timeout, the client is
+// sent notifications/cancelled for the request and ctx's error or
+// ErrClientTimeout is returned. Methods the client did not negotiate fail
+// with ErrNotSupported without being sent. An error reply from the client
+// is returned as a *jsonrpc2.Error.
+func (s *Session) Request(ctx context.Context, method string, params, result interface{}, opts ...RequestOption) error {
+	cfg := requestConfig{timeout: DefaultRequestTimeout, prefix: "srv"}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if s.conn == nil {
+		return ErrNoConnection
+	}
+	if !s.Allows(method) {
+		return fmt.Errorf("%s: %w", method, ErrNotSupported)
+	}
+
+	callCtx, cancel := ctx, context.CancelFunc(func() {})
+	if cfg.timeout > 0 {
+		callCtx, cancel = context.WithTimeout(ctx, cfg.timeout)
+	}
+	defer cancel()
+
+	id := jsonrpc2.ID{Str: cfg.prefix + "-" + strconv.FormatInt(s.requestSeq.Add(1), 10), IsString: true}
+	err := s.conn.Call(callCtx, method, params, result, jsonrpc2.PickID(id))
+	if err == nil || callCtx.Err() == nil {
+		return err
+	}
+
+	reason := "request cancelled"
+	if ctx.Err() == nil {
+		reason, err = "timed out waiting for the client", ErrClientTimeout
+	} else {
+		err = ctx.Err()
+	}
+	s.cancelRequest(id, reason)
+	return err
+}
+
+// Call sends method to the client in a Request and returns its typed result
+func Call[T any](ctx context.Context, s *Session, method string, params interface{}, opts ...RequestOption) (*T, error) {
+	var result T
+	if err := s.Request(ctx, method, params, &result, opts...); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// cancelRequest tells the client to abandon request id
+func (s *Session) cancelRequest(id jsonrpc2.ID, reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), cancelNotifyTimeout)
+	defer cancel()
+	params := protocol.CancelledParams{RequestID: id.Str, Reason: reason}
+	if err := s.conn.Notify(ctx, protocol.NotificationCancelled, params); err != nil {
+		slog.Debug("Failed to cancel client request", "id", id.Str, "error", err)
+	}
+}
@@ -0,0 +1,72 @@
+// internal/mcp/session/roots.go
+package session
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+)
+
+// SetCapabilities records what the client declared in initialize
+func (s *Session) SetCapabilities(c protocol.ClientCapabilities) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.capabilities = c
+}
+
+// Capabilities returns what the client declared in initialize
+func (s *Session) Capabilities() protocol.ClientCapabilities {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.capabilities
+}
+
+// SetRoots records the client's roots, restricting file resources to them
+func (s *Session) SetRoots(roots []protocol.Root) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.roots = roots
+	s.rootsKnown = true
+}
+
+// Roots returns the client's roots and whether it has reported any
+func (s *Session) Roots() ([]protocol.Root, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.roots, s.rootsKnown
+}
+
+// InRoots reports whether uri may be served to this client. Only file://
+// URIs are scoped; until the client reports roots everything is allowed.
+func (s *Session) InRoots(uri string) bool {
+	roots, known := s.Roots()
+	if !known {
+		return true
+	}
+
+	target, ok := filePath(uri)
+	if !ok {
+		return true
+	}
+	for _, root := range roots {
+		rootPath, ok := filePath(root.URI)
+		if !ok {
+			continue
+		}
+		if target == rootPath || strings.HasPrefix(target, strings.TrimSuffix(rootPath, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// filePath returns the cleaned path of a file:// URI
+func filePath(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+	return path.Clean("/" + u.Path), true
+}
@@ -0,0 +1,212 @@
+// internal/mcp/session/session.go
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// DefaultLogLevel is the minimum level sent to a client that has not called logging/setLevel
+const DefaultLogLevel = protocol.LoggingLevelInfo
+
+// Session holds per-connection state for a connected client
+type Session struct {
+	conn        *jsonrpc2.Conn
+	loggerName  string
+	id          string // unique to this connection
+	key         string // identifies the client across reconnects
+	connectedAt time.Time
+	remoteAddr  string                  // client IP, when the transport knows it
+	identity    string                  // authenticated caller, when the transport authenticates
+	tenant      string                  // team the caller belongs to in a shared deployment; "" for none
+	requestSeq  atomic.Int64            // numbers server-to-client requests
+	client      protocol.Implementation // reported at initialize; empty before it
+	profile     *Profile                // restricts what the client sees; nil for the whole server
+
+	logLevel    protocol.LoggingLevel
+	logLevelSet bool       // the client called logging/setLevel, which opts it into logging
+	coalescer   *Coalescer // batches high-frequency notifications; nil sends directly
+
+	capabilities    protocol.ClientCapabilities
+	protocolVersion string // negotiated at initialize; empty before it
+	roots           []protocol.Root
+	rootsKnown      bool // false until the client has reported its roots
+
+	mu sync.RWMutex
+}
+
+// New creates a session for the given connection
+func New(conn *jsonrpc2.Conn, loggerName string) *Session {
+	return &Session{
+		conn:        conn,
+		loggerName:  loggerName,
+		id:          newID(),
+		connectedAt: time.Now(),
+		logLevel:    DefaultLogLevel,
+	}
+}
+
+// ID returns the identifier of this connection
+func (s *Session) ID() string {
+	return s.id
+}
+
+// ConnectedAt returns when the connection was established
+func (s *Session) ConnectedAt() time.Time {
+	return s.connectedAt
+}
+
+// RemoteAddr returns the client's IP, or "" when the transport has none
+func (s *Session) RemoteAddr() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.remoteAddr
+}
+
+// SetRemoteAddr records the client's IP
+func (s *Session) SetRemoteAddr(addr string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remoteAddr = addr
+}
+
+// Identity returns the authenticated caller, or "" for anonymous clients
+func (s *Session) Identity() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.identity
+}
+
+// SetIdentity records the authenticated caller
+func (s *Session) SetIdentity(identity string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.identity = identity
+}
+
+// Tenant returns the tenant the caller belongs to, or "" when it is not
+// one of a tenant's identities
+func (s *Session) Tenant() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tenant
+}
+
+// SetTenant records the tenant the caller belongs to
+func (s *Session) SetTenant(tenant string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tenant = tenant
+}
+
+// ClientInfo returns the name and version the client reported at initialize
+func (s *Session) ClientInfo() protocol.Implementation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.client
+}
+
+// SetClientInfo records the name and version the client reported at initialize
+func (s *Session) SetClientInfo(info protocol.Implementation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.client = info
+}
+
+// Conn returns the connection backing the session
+func (s *Session) Conn() *jsonrpc2.Conn {
+	return s.conn
+}
+
+// Key returns the identity used to resume this client's session
+func (s *Session) Key() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.key
+}
+
+// SetKey sets the identity used to resume this client's session
+func (s *Session) SetKey(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.key = key
+}
+
+// SetCoalescer sets the coalescer that batches this session's notifications
+func (s *Session) SetCoalescer(c *Coalescer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.coalescer = c
+}
+
+// Coalescer returns the session's notification coalescer, if any
+func (s *Session) Coalescer() *Coalescer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.coalescer
+}
+
+// SetLogLevel sets the minimum level of log notifications sent to this client
+func (s *Session) SetLogLevel(level protocol.LoggingLevel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logLevel = level
+	s.logLevelSet = true
+}
+
+// LogLevel returns the minimum level of log notifications sent to this client
+func (s *Session) LogLevel() protocol.LoggingLevel {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.logLevel
+}
+
+// Log sends a notifications/message to the client if it supports logging
+// and level passes the session's filter
+func (s *Session) Log(ctx context.Context, level protocol.LoggingLevel, logger string, data interface{}) {
+	if s.conn == nil || !s.Supports(FeatureLogging) || !level.AtLeast(s.LogLevel()) {
+		return
+	}
+
+	if logger == "" {
+		logger = s.loggerName
+	}
+
+	params := protocol.LoggingMessageParams{
+		Level:  level,
+		Logger: logger,
+		Data:   data,
+	}
+
+	if err := s.conn.Notify(ctx, protocol.NotificationLoggingMessage, params); err != nil {
+		slog.Error("Failed to send log message notification", "error", err)
+	}
+}
+
+type contextKey struct{}
+
+// WithSession returns a copy of ctx carrying the session
+func WithSession(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, contextKey{}, s)
+}
+
+// FromContext returns the session stored in ctx, or nil
+func FromContext(ctx context.Context) *Session {
+	s, _ := ctx.Value(contextKey{}).(*Session)
+	return s
+}
+
+// newID returns a random session ID
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
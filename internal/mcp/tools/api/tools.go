@@ -4,9 +4,13 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 
+	"github.com/dkoosis/axe-handle/internal/codec"
 	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
 	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
 	"github.com/dkoosis/axe-handle/pkg/mcperrors"
 	"github.com/sourcegraph/jsonrpc2"
@@ -18,15 +22,20 @@ type ServerHandler interface {
 	GetToolsManager() *manager.ToolsManager
 }
 
+// ErrorSender replies to req with err
+type ErrorSender func(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request, err error)
+
 // ToolsHandler handles tools-related requests
 type ToolsHandler struct {
-	server ServerHandler
+	server    ServerHandler
+	sendError ErrorSender
 }
 
 // NewToolsHandler creates a new tools handler
-func NewToolsHandler(server ServerHandler) *ToolsHandler {
+func NewToolsHandler(server ServerHandler, sendError ErrorSender) *ToolsHandler {
 	return &ToolsHandler{
-		server: server,
+		server:    server,
+		sendError: sendError,
 	}
 }
 
@@ -41,6 +50,16 @@ type ToolsListResult struct {
 	NextCursor string          `json:"nextCursor,omitempty"`
 }
 
+// Downgrade implements protocol.Downgrader
+func (r ToolsListResult) Downgrade(version string) interface{} {
+	tools := make([]protocol.Tool, len(r.Tools))
+	for i, tool := range r.Tools {
+		tools[i] = protocol.DowngradeTool(version, tool)
+	}
+	r.Tools = tools
+	return r
+}
+
 // ToolsCallRequest represents a request to call a tool
 type ToolsCallRequest struct {
 	Name      string          `json:"name"`
@@ -48,23 +67,32 @@ type ToolsCallRequest struct {
 }
 
 // HandleToolsList handles the tools/list request
-func (h *ToolsHandler) HandleToolsList(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+func (h *ToolsHandler) HandleToolsList(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request) {
 	var params ToolsListRequest
 	if req.Params != nil {
 		if err := json.Unmarshal(*req.Params, &params); err != nil {
-			sendError(ctx, conn, req.ID, mcperrors.NewInvalidParamsError(err))
+			h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(err))
 			return
 		}
 	}
 
 	// Check if server is initialized
 	if err := h.server.CheckInitialized(); err != nil {
-		sendError(ctx, conn, req.ID, err)
+		h.sendError(ctx, conn, req, err)
 		return
 	}
 
-	// Get tools from manager
+	// Get tools from manager, leaving out those outside the client's profile
 	tools := h.server.GetToolsManager().ListTools()
+	if profile := sessionProfile(ctx); profile != nil {
+		allowed := make([]protocol.Tool, 0, len(tools))
+		for _, tool := range tools {
+			if profile.AllowsTool(tool.Name) {
+				allowed = append(allowed, tool)
+			}
+		}
+		tools = allowed
+	}
 
 	// Create response
 	result := ToolsListResult{
@@ -79,16 +107,28 @@ func (h *ToolsHandler) HandleToolsList(ctx context.Context, conn *jsonrpc2.Conn,
 }
 
 // HandleToolsCall handles the tools/call request
-func (h *ToolsHandler) HandleToolsCall(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+func (h *ToolsHandler) HandleToolsCall(ctx context.Context, conn protocol.ResponderConn, req *jsonrpc2.Request) {
 	var params ToolsCallRequest
 	if err := json.Unmarshal(*req.Params, &params); err != nil {
-		sendError(ctx, conn, req.ID, mcperrors.NewInvalidParamsError(err))
+		h.sendError(ctx, conn, req, mcperrors.NewInvalidParamsError(err))
 		return
 	}
 
 	// Check if server is initialized
 	if err := h.server.CheckInitialized(); err != nil {
-		sendError(ctx, conn, req.ID, err)
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
+	// Tools outside the client's profile do not exist for it
+	if !sessionProfile(ctx).AllowsTool(params.Name) {
+		result := protocol.ToolsCallResult{
+			Content: []protocol.Content{protocol.TextContent(fmt.Sprintf("Tool '%s' not found", params.Name))},
+			IsError: true,
+		}
+		if err := conn.Reply(ctx, req.ID, result); err != nil {
+			slog.Error("Failed to send tool call error response", "error", err)
+		}
 		return
 	}
 
@@ -107,6 +147,14 @@ func (h *ToolsHandler) HandleToolsCall(ctx context.Context, conn *jsonrpc2.Conn,
 
 	// Call the tool
 	result, err := h.server.GetToolsManager().CallTool(ctx, params.Name, params.Arguments, progressToken)
+
+	// Invalid arguments are reported as a JSON-RPC error so clients get the structured details
+	var rpcErr *mcperrors.RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == mcperrors.InvalidParams {
+		h.sendError(ctx, conn, req, err)
+		return
+	}
+
 	if err != nil {
 		// Log the error
 		slog.Error("Error calling tool",
@@ -130,47 +178,26 @@ func (h *ToolsHandler) HandleToolsCall(ctx context.Context, conn *jsonrpc2.Conn,
 		return
 	}
 
-	// Send successful result
-	if err := conn.Reply(ctx, req.ID, result); err != nil {
+	// Send successful result; tool output can be large, so use the fast
+	// codec, downgrading first as encoded results pass the conn unchanged
+	if err := conn.Reply(ctx, req.ID, codec.Raw(protocol.Downgrade(sessionVersion(ctx), result))); err != nil {
 		slog.Error("Failed to send tool call response", "error", err)
 	}
 }
 
-// isValidID checks if the ID is valid for responding.
-func isValidID(id jsonrpc2.ID) bool {
-	// For string IDs, check if it's not empty
-	if id.IsString {
-		return id.Str != ""
+// sessionProfile returns the profile of the client in ctx, or nil
+func sessionProfile(ctx context.Context) *session.Profile {
+	if sess := session.FromContext(ctx); sess != nil {
+		return sess.Profile()
 	}
-	// For numeric IDs, check if it's not zero
-	return id.Num != 0
+	return nil
 }
 
-// sendError sends an error response
-func sendError(ctx context.Context, conn *jsonrpc2.Conn, id jsonrpc2.ID, err error) {
-	rpcErr := mcperrors.FromError(err)
-
-	// Create the jsonrpc2.Error object
-	jsonErr := &jsonrpc2.Error{
-		Code:    int64(rpcErr.Code),
-		Message: rpcErr.Message,
-	}
-
-	// Handle the Data field with proper type conversion
-	if rpcErr.Data != nil {
-		// Convert the data to JSON
-		dataBytes, err := json.Marshal(rpcErr.Data)
-		if err == nil {
-			// Set the data using the SetError method
-			rawMsg := json.RawMessage(dataBytes)
-			jsonErr.Data = &rawMsg
-		}
-	}
-
-	// Only send error if ID is valid
-	if isValidID(id) {
-		if err := conn.ReplyWithError(ctx, id, jsonErr); err != nil {
-			slog.Error("Failed to send error response", "error", err)
-		}
+// sessionVersion returns the protocol version the client in ctx
+// negotiated, or ""
+func sessionVersion(ctx context.Context) string {
+	if sess := session.FromContext(ctx); sess != nil {
+		return sess.ProtocolVersion()
 	}
+	return ""
 }
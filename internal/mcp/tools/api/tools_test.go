@@ -0,0 +1,37 @@
+// internal/mcp/tools/api/tools_test.go
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/pkg/mcperrors"
+	"github.com/dkoosis/axe-handle/pkg/mcptest"
+)
+
+func TestToolErrorsAreCounted(t *testing.T) {
+	c := mcptest.New(t)
+	c.Initialize()
+
+	c.ExpectError(protocol.MethodToolsCall, json.RawMessage(`{"name":42}`), int64(mcperrors.InvalidParams))
+
+	want := `axe_handle_errors_total{method="tools/call",code="-32602"} 1`
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var buf bytes.Buffer
+		if err := c.Server().ErrorMetrics().WritePrometheus(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(buf.String(), want) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("error metrics miss the failed call:\n%s", buf.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -1,7 +1,11 @@
 // internal/mcp/tools/errors.go (updated)
 package tools
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/dkoosis/axe-handle/pkg/mcperrors"
+)
 
 var (
 	// ErrToolNotFound is returned when a requested tool cannot be found
@@ -10,3 +14,9 @@ var (
 	// ErrInvalidToolArguments is returned when tool arguments are invalid
 	ErrInvalidToolArguments = errors.New("invalid tool arguments")
 )
+
+// init maps this package's sentinel errors to their JSON-RPC error codes
+func init() {
+	mcperrors.RegisterMapping(ErrToolNotFound, mcperrors.ErrToolNotFound)
+	mcperrors.RegisterMapping(ErrInvalidToolArguments, mcperrors.ErrInvalidParams)
+}
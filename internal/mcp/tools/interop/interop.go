@@ -0,0 +1,154 @@
+// internal/mcp/tools/interop/interop.go
+
+// Package interop converts MCP tool definitions to and from the tool formats
+// of the major LLM APIs, so one registry can serve MCP clients and direct
+// API integrations alike.
+package interop
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+)
+
+// Format names a tool definition format
+type Format string
+
+// Supported formats
+const (
+	FormatOpenAI    Format = "openai"    // chat completions "tools" with type "function"
+	FormatAnthropic Format = "anthropic" // messages API "tools"
+)
+
+// ParseFormat validates a format name
+func ParseFormat(name string) (Format, error) {
+	switch Format(name) {
+	case FormatOpenAI, FormatAnthropic:
+		return Format(name), nil
+	default:
+		return "", fmt.Errorf("unknown tool format %q (want %s or %s)", name, FormatOpenAI, FormatAnthropic)
+	}
+}
+
+// OpenAITool is a tool in the OpenAI chat completions request
+type OpenAITool struct {
+	Type     string         `json:"type"` // always "function"
+	Function OpenAIFunction `json:"function"`
+}
+
+// OpenAIFunction is the function a tool exposes to OpenAI models
+type OpenAIFunction struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Parameters  interface{} `json:"parameters,omitempty"`
+}
+
+// AnthropicTool is a tool in the Anthropic messages request
+type AnthropicTool struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	InputSchema interface{} `json:"input_schema"`
+}
+
+// Export converts tools to the given format. The result marshals to the
+// JSON array each API expects in its "tools" request field.
+func Export(tools []protocol.Tool, format Format) (interface{}, error) {
+	switch format {
+	case FormatOpenAI:
+		return ToOpenAI(tools), nil
+	case FormatAnthropic:
+		return ToAnthropic(tools), nil
+	default:
+		return nil, fmt.Errorf("unknown tool format %q", format)
+	}
+}
+
+// ToOpenAI converts tools to OpenAI function tools
+func ToOpenAI(tools []protocol.Tool) []OpenAITool {
+	out := make([]OpenAITool, 0, len(tools))
+	for _, tool := range tools {
+		out = append(out, OpenAITool{
+			Type: "function",
+			Function: OpenAIFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.InputSchema,
+			},
+		})
+	}
+	return out
+}
+
+// ToAnthropic converts tools to Anthropic tools
+func ToAnthropic(tools []protocol.Tool) []AnthropicTool {
+	out := make([]AnthropicTool, 0, len(tools))
+	for _, tool := range tools {
+		out = append(out, AnthropicTool{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: tool.InputSchema,
+		})
+	}
+	return out
+}
+
+// emptyParameters is used for functions that declare no parameters
+var emptyParameters = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+
+// ParseOpenAI reads OpenAI function definitions. data may be an array of
+// tools ({"type":"function","function":{...}}), an array of bare functions
+// ({"name","description","parameters"}), or an object holding either under
+// "tools" or "functions", as in a saved request body.
+func ParseOpenAI(data []byte) ([]protocol.Tool, error) {
+	var wrapper struct {
+		Tools     []json.RawMessage `json:"tools"`
+		Functions []json.RawMessage `json:"functions"`
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, fmt.Errorf("expected an array of functions or an object with tools or functions: %w", err)
+		}
+		items = append(wrapper.Tools, wrapper.Functions...)
+	}
+
+	tools := make([]protocol.Tool, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		var entry struct {
+			Type     string          `json:"type"`
+			Function *OpenAIFunction `json:"function"`
+			OpenAIFunction
+		}
+		if err := json.Unmarshal(item, &entry); err != nil {
+			return nil, fmt.Errorf("function %d: %w", i, err)
+		}
+
+		fn := entry.OpenAIFunction
+		if entry.Function != nil {
+			if entry.Type != "" && entry.Type != "function" {
+				return nil, fmt.Errorf("function %d: unsupported tool type %q", i, entry.Type)
+			}
+			fn = *entry.Function
+		}
+		if fn.Name == "" {
+			return nil, fmt.Errorf("function %d: missing name", i)
+		}
+		if seen[fn.Name] {
+			return nil, fmt.Errorf("function %d: duplicate name %q", i, fn.Name)
+		}
+		seen[fn.Name] = true
+
+		params := fn.Parameters
+		if params == nil {
+			params = emptyParameters
+		}
+		tools = append(tools, protocol.Tool{
+			Name:        fn.Name,
+			Description: fn.Description,
+			InputSchema: params,
+		})
+	}
+	return tools, nil
+}
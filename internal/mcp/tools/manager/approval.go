@@ -0,0 +1,39 @@
+// internal/mcp/tools/manager/approval.go
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+)
+
+// ErrNotApproved is returned by approvers that refuse a call
+var ErrNotApproved = errors.New("call was not approved")
+
+// Approver decides whether a call to a tool that changes things may run.
+// A nil error lets the call through; any other error refuses it and is
+// reported to the caller.
+type Approver func(ctx context.Context, tool protocol.Tool, args json.RawMessage) error
+
+// DenyAll is an Approver that refuses every call it is asked about
+func DenyAll(context.Context, protocol.Tool, json.RawMessage) error {
+	return ErrNotApproved
+}
+
+// NeedsApproval reports whether calls to tool go through the approver:
+// those annotated as not read-only. Tools without annotations make no
+// claim either way and are not gated.
+func NeedsApproval(tool protocol.Tool) bool {
+	a := tool.Annotations
+	return a != nil && a.ReadOnlyHint != nil && !*a.ReadOnlyHint
+}
+
+// SetApprover sets the hook asked before tools that change things run; nil
+// runs them unasked
+func (m *ToolsManager) SetApprover(a Approver) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.approver = a
+}
@@ -0,0 +1,206 @@
+// internal/mcp/tools/manager/cache.go
+package manager
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/clock"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+)
+
+// Result cache defaults
+const (
+	DefaultResultCacheMaxBytes      = 16 << 20
+	DefaultResultCacheMaxEntryBytes = 1 << 20
+	DefaultResultCacheTTL           = 5 * time.Minute
+)
+
+// cachedResult is a tool result held in the cache
+type cachedResult struct {
+	key     string
+	tool    string
+	result  protocol.ToolsCallResult
+	size    int64
+	expires time.Time
+}
+
+// ResultCache is a size-bounded LRU of tool results keyed by tool name and
+// a hash of the arguments. Only tools enabled with Enable are cached, and
+// only their successful results. Results are shared by all clients, so it
+// suits read-only tools whose answer does not depend on who asks.
+type ResultCache struct {
+	maxBytes      int64
+	maxEntryBytes int64
+	clock         clock.Clock
+
+	ttls    map[string]time.Duration // cached tools and how long their results live
+	entries map[string]*list.Element
+	order   *list.List // front is most recently used
+	bytes   int64
+	mu      sync.Mutex
+}
+
+// NewResultCache creates a cache holding at most maxBytes of results,
+// skipping results over maxEntryBytes. A nil clock uses the system clock.
+func NewResultCache(maxBytes, maxEntryBytes int64, clk clock.Clock) *ResultCache {
+	if maxBytes <= 0 {
+		maxBytes = DefaultResultCacheMaxBytes
+	}
+	if maxEntryBytes <= 0 {
+		maxEntryBytes = DefaultResultCacheMaxEntryBytes
+	}
+	if clk == nil {
+		clk = clock.Real
+	}
+	return &ResultCache{
+		maxBytes:      maxBytes,
+		maxEntryBytes: maxEntryBytes,
+		clock:         clk,
+		ttls:          make(map[string]time.Duration),
+		entries:       make(map[string]*list.Element),
+		order:         list.New(),
+	}
+}
+
+// Enable caches results of tool for ttl; a ttl of 0 or less disables
+// caching for it again
+func (c *ResultCache) Enable(tool string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ttl <= 0 {
+		delete(c.ttls, tool)
+		c.invalidateTool(tool)
+		return
+	}
+	c.ttls[tool] = ttl
+}
+
+// Enabled reports whether results of tool are cached
+func (c *ResultCache) Enabled(tool string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.ttls[tool]
+	return ok
+}
+
+// Get returns the cached result of calling tool with args
+func (c *ResultCache) Get(tool string, args json.RawMessage) (protocol.ToolsCallResult, bool) {
+	key, ok := cacheKey(tool, args)
+	if !ok {
+		return protocol.ToolsCallResult{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return protocol.ToolsCallResult{}, false
+	}
+	entry := el.Value.(*cachedResult)
+	if !c.clock.Now().Before(entry.expires) {
+		c.removeElement(el)
+		return protocol.ToolsCallResult{}, false
+	}
+	c.order.MoveToFront(el)
+	return entry.result, true
+}
+
+// Put stores the result of calling tool with args if the tool is cached,
+// the result is not an error and it is small enough, evicting the least
+// recently used entries as needed
+func (c *ResultCache) Put(tool string, args json.RawMessage, result protocol.ToolsCallResult) bool {
+	if result.IsError {
+		return false
+	}
+	key, ok := cacheKey(tool, args)
+	if !ok {
+		return false
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return false
+	}
+	size := int64(len(data))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ttl, ok := c.ttls[tool]
+	if !ok || size > c.maxEntryBytes || size > c.maxBytes {
+		return false
+	}
+	if el, ok := c.entries[key]; ok {
+		c.removeElement(el)
+	}
+	c.entries[key] = c.order.PushFront(&cachedResult{
+		key:     key,
+		tool:    tool,
+		result:  result,
+		size:    size,
+		expires: c.clock.Now().Add(ttl),
+	})
+	c.bytes += size
+
+	for c.bytes > c.maxBytes {
+		c.removeElement(c.order.Back())
+	}
+	return true
+}
+
+// InvalidateTool drops every cached result of tool
+func (c *ResultCache) InvalidateTool(tool string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidateTool(tool)
+}
+
+// invalidateTool drops tool's entries; c.mu must be held
+func (c *ResultCache) invalidateTool(tool string) {
+	for el := c.order.Front(); el != nil; {
+		next := el.Next()
+		if el.Value.(*cachedResult).tool == tool {
+			c.removeElement(el)
+		}
+		el = next
+	}
+}
+
+// Len returns the number of cached results
+func (c *ResultCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// removeElement unlinks an entry; c.mu must be held
+func (c *ResultCache) removeElement(el *list.Element) {
+	entry := el.Value.(*cachedResult)
+	c.order.Remove(el)
+	delete(c.entries, entry.key)
+	c.bytes -= entry.size
+}
+
+// cacheKey identifies a call by tool name and a hash of its arguments in
+// canonical form, so key order and whitespace do not cause misses
+func cacheKey(tool string, args json.RawMessage) (string, bool) {
+	if len(bytes.TrimSpace(args)) == 0 {
+		args = json.RawMessage("{}")
+	}
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return "", false
+	}
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(canonical)
+	return tool + "\x00" + hex.EncodeToString(sum[:]), true
+}
@@ -0,0 +1,123 @@
+// internal/mcp/tools/manager/deadline.go
+package manager
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/clock"
+)
+
+// deadlineKey finds a call's toolDeadline in its context
+type deadlineKey struct{}
+
+// toolDeadline is a tool call timeout that can be paused while the call
+// waits on the client's user, so time spent filling in a form does not
+// count against the tool.
+type toolDeadline struct {
+	context.Context
+	cancel  context.CancelFunc
+	clock   clock.Clock
+	expired atomic.Bool
+
+	mu        sync.Mutex
+	remaining time.Duration
+	started   time.Time
+	timer     clock.Timer
+	paused    int
+}
+
+// withToolDeadline returns a context that expires with
+// context.DeadlineExceeded after d of unpaused time
+func withToolDeadline(parent context.Context, clk clock.Clock, d time.Duration) (*toolDeadline, context.CancelFunc) {
+	inner, cancel := context.WithCancel(parent)
+	td := &toolDeadline{Context: inner, cancel: cancel, clock: clk, remaining: d}
+	td.mu.Lock()
+	td.start()
+	td.mu.Unlock()
+	return td, func() {
+		td.mu.Lock()
+		if td.timer != nil {
+			td.timer.Stop()
+		}
+		td.mu.Unlock()
+		cancel()
+	}
+}
+
+// start runs the timer for the remaining time; mu must be held
+func (td *toolDeadline) start() {
+	td.started = td.clock.Now()
+	td.timer = td.clock.AfterFunc(td.remaining, func() {
+		td.expired.Store(true)
+		td.cancel()
+	})
+}
+
+// Deadline reports when the call expires if it is not paused again
+func (td *toolDeadline) Deadline() (time.Time, bool) {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	if td.paused > 0 {
+		return td.clock.Now().Add(td.remaining), true
+	}
+	return td.started.Add(td.remaining), true
+}
+
+// Err reports DeadlineExceeded once the timeout has fired
+func (td *toolDeadline) Err() error {
+	err := td.Context.Err()
+	if err != nil && td.expired.Load() {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+// Value makes the deadline findable from derived contexts
+func (td *toolDeadline) Value(key any) any {
+	if key == (deadlineKey{}) {
+		return td
+	}
+	return td.Context.Value(key)
+}
+
+// pause stops the clock until the returned function is called. Pauses
+// nest; the clock restarts when the last one ends.
+func (td *toolDeadline) pause() func() {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	if td.paused == 0 && td.timer != nil {
+		if !td.timer.Stop() {
+			// Already fired; nothing to pause
+			return func() {}
+		}
+		td.remaining -= td.clock.Since(td.started)
+	}
+	td.paused++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			td.mu.Lock()
+			defer td.mu.Unlock()
+			td.paused--
+			if td.paused == 0 && td.Context.Err() == nil {
+				td.start()
+			}
+		})
+	}
+}
+
+// PauseTimeout stops the timeout of the tool call running in ctx until
+// resume is called. Handlers use it around waits on a person, such as
+// elicitation. Outside a tool call, or when the caller set its own
+// deadline, it does nothing.
+func PauseTimeout(ctx context.Context) (resume func()) {
+	td, ok := ctx.Value(deadlineKey{}).(*toolDeadline)
+	if !ok {
+		return func() {}
+	}
+	return td.pause()
+}
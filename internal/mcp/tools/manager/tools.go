@@ -6,28 +6,53 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"runtime/debug"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/dkoosis/axe-handle/internal/clock"
+	"github.com/dkoosis/axe-handle/internal/events"
 	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+	"github.com/dkoosis/axe-handle/pkg/mcperrors"
 	jsonschema "github.com/xeipuuv/gojsonschema"
 )
 
 // ToolHandler is a function that handles a tool call with progress reporting
 type ToolHandler func(ctx context.Context, args json.RawMessage, progressCh chan<- float64) (protocol.ToolsCallResult, error)
 
-// ProgressReporter is a function that reports tool execution progress
-type ProgressReporter func(toolName string, token string, progress float64, total float64)
+// ProgressReporter is a function that reports tool execution progress; ctx
+// is the tool call's context
+type ProgressReporter func(ctx context.Context, toolName string, token string, progress float64, total float64)
+
+// progressTokenKey carries a call's progress token in its context
+type progressTokenKey struct{}
+
+// ProgressToken returns the progress token of the tool call running in ctx,
+// or "" when the client did not ask for progress
+func ProgressToken(ctx context.Context) string {
+	token, _ := ctx.Value(progressTokenKey{}).(string)
+	return token
+}
 
 // ToolsManager manages tool registration and execution
 type ToolsManager struct {
 	tools            map[string]protocol.Tool
 	handlers         map[string]ToolHandler
+	sources          map[string]string // tool name to the source that owns it, see ReplaceSource
 	progressReporter ProgressReporter
+	events           *events.Bus // nil until SetEventBus
+	validator        Validator
+	cache            *ResultCache            // nil when no tool is cached
+	tenantCaches     map[string]*ResultCache // used instead of cache by the tenants that have one
+	approver         Approver                // nil runs every tool unasked
+	strict           bool                    // see SetStrict
 	mu               sync.RWMutex
 
 	// Configuration
 	defaultTimeout time.Duration
+	clock          clock.Clock
 }
 
 // NewToolsManager creates a new tools manager
@@ -35,7 +60,10 @@ func NewToolsManager() *ToolsManager {
 	return &ToolsManager{
 		tools:          make(map[string]protocol.Tool),
 		handlers:       make(map[string]ToolHandler),
+		sources:        make(map[string]string),
+		validator:      Draft7Validator{},
 		defaultTimeout: 30 * time.Second,
+		clock:          clock.Real,
 	}
 }
 
@@ -46,37 +74,141 @@ func (m *ToolsManager) SetProgressReporter(reporter ProgressReporter) {
 	m.progressReporter = reporter
 }
 
+// SetEventBus sets the bus that tool registrations are announced on
+func (m *ToolsManager) SetEventBus(bus *events.Bus) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = bus
+}
+
+// publish announces e if a bus is set; m.mu must not be held
+func (m *ToolsManager) publish(e events.Event) {
+	m.mu.RLock()
+	bus := m.events
+	m.mu.RUnlock()
+	if bus != nil {
+		bus.Publish(e)
+	}
+}
+
 // RegisterTool registers a tool with the manager
 func (m *ToolsManager) RegisterTool(tool protocol.Tool, handler ToolHandler) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	strict := m.strict
+	m.mu.Unlock()
 
-	// Validate tool definition
+	// Validate tool definition; a broken one is a bug in its provider
 	if tool.Name == "" {
+		if strict {
+			panic(fmt.Sprintf("tool registered with empty name: %+v", tool))
+		}
 		slog.Error("Attempted to register tool with empty name", "tool", tool)
 		return
 	}
 
 	if tool.InputSchema == nil {
+		if strict {
+			panic(fmt.Sprintf("tool %s registered with nil input schema", tool.Name))
+		}
 		slog.Error("Attempted to register tool with nil input schema", "tool_name", tool.Name)
 		return
 	}
 
+	m.mu.Lock()
+
 	m.tools[tool.Name] = tool
 	m.handlers[tool.Name] = handler
+	delete(m.sources, tool.Name)
+	caches := m.caches()
+	m.mu.Unlock()
+
+	// A replaced handler may answer differently
+	for _, cache := range caches {
+		cache.InvalidateTool(tool.Name)
+	}
 
 	slog.Info("Registered tool", "name", tool.Name, "description", tool.Description)
+	m.publish(events.Event{Kind: events.ToolRegistered, Subject: tool.Name})
 }
 
 // UnregisterTool removes a tool from the manager
 func (m *ToolsManager) UnregisterTool(name string) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	delete(m.tools, name)
 	delete(m.handlers, name)
+	delete(m.sources, name)
+	caches := m.caches()
+	m.mu.Unlock()
+
+	for _, cache := range caches {
+		cache.InvalidateTool(name)
+	}
 
 	slog.Info("Unregistered tool", "name", name)
+	m.publish(events.Event{Kind: events.ToolsChanged, Subject: name})
+}
+
+// ReplaceSource swaps the tools owned by source for tools, each served by
+// the handler of the same name, under one lock. Tools of other sources and
+// those registered with RegisterTool are kept; clients never list a
+// half-replaced set and get a single tools/list_changed. Nothing changes
+// when a tool is invalid, lacks a handler or belongs to someone else.
+func (m *ToolsManager) ReplaceSource(source string, tools []protocol.Tool, handlers map[string]ToolHandler) error {
+	if source == "" {
+		return fmt.Errorf("tool source must not be empty")
+	}
+	for i, tool := range tools {
+		if tool.Name == "" {
+			return fmt.Errorf("tool with empty name")
+		}
+		if tool.InputSchema == nil {
+			return fmt.Errorf("tool %s has no input schema", tool.Name)
+		}
+		for _, other := range tools[:i] {
+			if other.Name == tool.Name {
+				return fmt.Errorf("tool %s is listed more than once", tool.Name)
+			}
+		}
+		if handler, ok := handlers[tool.Name]; !ok || handler == nil {
+			return fmt.Errorf("tool %s has no handler", tool.Name)
+		}
+	}
+
+	m.mu.Lock()
+	for _, tool := range tools {
+		if _, exists := m.tools[tool.Name]; exists && m.sources[tool.Name] != source {
+			m.mu.Unlock()
+			return fmt.Errorf("tool %s is not owned by %s", tool.Name, source)
+		}
+	}
+	var changed []string
+	for name, owner := range m.sources {
+		if owner == source {
+			delete(m.tools, name)
+			delete(m.handlers, name)
+			delete(m.sources, name)
+			changed = append(changed, name)
+		}
+	}
+	for _, tool := range tools {
+		m.tools[tool.Name] = tool
+		m.handlers[tool.Name] = handlers[tool.Name]
+		m.sources[tool.Name] = source
+		changed = append(changed, tool.Name)
+	}
+	caches := m.caches()
+	m.mu.Unlock()
+
+	// Replaced handlers may answer differently and removed ones not at all
+	for _, cache := range caches {
+		for _, name := range changed {
+			cache.InvalidateTool(name)
+		}
+	}
+
+	slog.Info("Replaced tools", "source", source, "tools", len(tools))
+	m.publish(events.Event{Kind: events.ToolsChanged, Subject: source})
+	return nil
 }
 
 // ListTools returns a list of all registered tools
@@ -99,6 +231,11 @@ func (m *ToolsManager) CallTool(ctx context.Context, name string, args json.RawM
 	tool, toolExists := m.tools[name]
 	handler, handlerExists := m.handlers[name]
 	progressReporter := m.progressReporter
+	timeout, clk := m.defaultTimeout, m.clock
+	validator := m.validator
+	cache := m.cacheFor(session.FromContext(ctx))
+	approver := m.approver
+	strict := m.strict
 	m.mu.RUnlock()
 
 	if !toolExists || !handlerExists {
@@ -113,34 +250,54 @@ func (m *ToolsManager) CallTool(ctx context.Context, name string, args json.RawM
 		}, nil
 	}
 
+	m.publish(events.Event{Kind: events.ToolCalled, Subject: name, Data: session.FromContext(ctx)})
+
 	// Log tool call
 	slog.Info("Calling tool",
 		"name", name,
 		"progress_token", progressToken,
 		"args_size", len(args))
 
-	// Validate arguments against schema
-	if err := validateToolArguments(tool.InputSchema, args); err != nil {
+	// Validate arguments against schema; failures are protocol errors with structured details
+	schema := tool.InputSchema
+	if strict {
+		schema = closedSchema(schema)
+	}
+	if err := validator.Validate(schema, args); err != nil {
 		slog.Error("Tool argument validation failed",
 			"name", name,
 			"error", err)
+		return protocol.ToolsCallResult{}, err
+	}
 
-		return protocol.ToolsCallResult{
-			Content: []protocol.Content{
-				{
-					Type: "text",
-					Text: fmt.Sprintf("Invalid arguments: %s", err),
-				},
-			},
-			IsError: true,
-		}, nil
+	// Tools that change things run only once approved
+	if approver != nil && NeedsApproval(tool) {
+		if err := approver(ctx, tool, args); err != nil {
+			slog.Warn("Tool call not approved", "name", name, "error", err)
+			return protocol.ToolsCallResult{
+				Content: []protocol.Content{protocol.TextContent(fmt.Sprintf("Tool '%s' was not run: %s", name, err))},
+				IsError: true,
+			}, nil
+		}
 	}
 
-	// Add timeout if not already present
-	var cancel context.CancelFunc
+	// Identical calls to cached tools are answered without running them
+	if cache != nil && cache.Enabled(name) {
+		if result, ok := cache.Get(name, args); ok {
+			slog.Info("Tool result served from cache", "name", name)
+			return result, nil
+		}
+	}
+
+	// Add timeout if not already present; handlers may pause it while they wait on the user
 	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
-		ctx, cancel = context.WithTimeout(ctx, m.defaultTimeout)
+		td, cancel := withToolDeadline(ctx, clk, timeout)
 		defer cancel()
+		ctx = td
+	}
+
+	if progressToken != "" {
+		ctx = context.WithValue(ctx, progressTokenKey{}, progressToken)
 	}
 
 	// Create progress channel
@@ -157,16 +314,16 @@ func (m *ToolsManager) CallTool(ctx context.Context, name string, args json.RawM
 					if !ok {
 						return // Channel closed
 					}
-					progressReporter(name, progressToken, progress, 100.0)
+					progressReporter(ctx, name, progressToken, progress, 100.0)
 				}
 			}
 		}()
 	}
 
 	// Execute tool
-	startTime := time.Now()
-	result, err := handler(ctx, args, progressCh)
-	duration := time.Since(startTime)
+	startTime := clk.Now()
+	result, err := invokeHandler(ctx, name, handler, args, progressCh)
+	duration := clk.Since(startTime)
 
 	// Close progress channel
 	close(progressCh)
@@ -176,6 +333,9 @@ func (m *ToolsManager) CallTool(ctx context.Context, name string, args json.RawM
 		slog.Info("Tool executed successfully",
 			"name", name,
 			"duration_ms", duration.Milliseconds())
+		if cache != nil && cache.Enabled(name) {
+			cache.Put(name, args, result)
+		}
 		return result, nil
 	}
 
@@ -197,8 +357,65 @@ func (m *ToolsManager) CallTool(ctx context.Context, name string, args json.RawM
 	}, nil
 }
 
-// validateToolArguments validates the provided arguments against the tool's input schema
-func validateToolArguments(schemaObj interface{}, args json.RawMessage) error {
+// invokeHandler runs a tool handler, converting a panic into an error so a
+// misbehaving tool cannot kill the process.
+func invokeHandler(ctx context.Context, name string, handler ToolHandler, args json.RawMessage, progressCh chan<- float64) (result protocol.ToolsCallResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Tool handler panicked",
+				"name", name,
+				"panic", r,
+				"stack", string(debug.Stack()))
+			err = fmt.Errorf("tool %s encountered an internal error", name)
+		}
+	}()
+
+	return handler(ctx, args, progressCh)
+}
+
+// schemaKeywords maps gojsonschema error types to the JSON Schema keyword they enforce
+var schemaKeywords = map[string]string{
+	"required":                        "required",
+	"invalid_type":                    "type",
+	"number_any_of":                   "anyOf",
+	"number_one_of":                   "oneOf",
+	"number_all_of":                   "allOf",
+	"number_not":                      "not",
+	"missing_dependency":              "dependencies",
+	"const":                           "const",
+	"enum":                            "enum",
+	"array_no_additional_items":       "additionalItems",
+	"array_min_items":                 "minItems",
+	"array_max_items":                 "maxItems",
+	"unique":                          "uniqueItems",
+	"contains":                        "contains",
+	"array_min_properties":            "minProperties",
+	"array_max_properties":            "maxProperties",
+	"additional_property_not_allowed": "additionalProperties",
+	"invalid_property_pattern":        "patternProperties",
+	"invalid_property_name":           "propertyNames",
+	"string_gte":                      "minLength",
+	"string_lte":                      "maxLength",
+	"pattern":                         "pattern",
+	"format":                          "format",
+	"multiple_of":                     "multipleOf",
+	"number_gte":                      "minimum",
+	"number_gt":                       "exclusiveMinimum",
+	"number_lte":                      "maximum",
+	"number_lt":                       "exclusiveMaximum",
+}
+
+// Draft7Validator validates with gojsonschema, which supports drafts 4, 6
+// and 7. It is the default backend.
+type Draft7Validator struct{}
+
+// Validate implements Validator
+func (Draft7Validator) Validate(schemaObj interface{}, args json.RawMessage) error {
+	// Treat missing arguments as an empty object
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+
 	// Convert schema to proper format
 	schemaLoader := jsonschema.NewGoLoader(schemaObj)
 
@@ -208,28 +425,159 @@ func validateToolArguments(schemaObj interface{}, args json.RawMessage) error {
 	// Perform validation
 	result, err := jsonschema.Validate(schemaLoader, documentLoader)
 	if err != nil {
-		return fmt.Errorf("schema validation error: %w", err)
+		return mcperrors.NewInvalidParamsError(fmt.Errorf("schema validation error: %w", err))
 	}
 
 	// Check validation result
 	if !result.Valid() {
-		// Collect validation errors
-		var errMsg string
-		for i, err := range result.Errors() {
-			if i > 0 {
-				errMsg += "; "
-			}
-			errMsg += err.String()
+		details := make([]mcperrors.ValidationDetail, 0, len(result.Errors()))
+		for _, resultErr := range result.Errors() {
+			details = append(details, validationDetail(resultErr))
 		}
-		return fmt.Errorf("invalid arguments: %s", errMsg)
+		return mcperrors.NewValidationError(details)
 	}
 
 	return nil
 }
 
+// validationDetail converts a gojsonschema error into a ValidationDetail
+func validationDetail(resultErr jsonschema.ResultError) mcperrors.ValidationDetail {
+	// Context is "(root)" or "(root).a.b"; drop the root marker
+	path := strings.Split(resultErr.Context().String(), ".")[1:]
+
+	// Build a pointer to the rule, e.g. /properties/a/properties/b/minLength
+	schemaPath := ""
+	for _, segment := range path {
+		schemaPath += "/properties/" + segment
+	}
+	if keyword, ok := schemaKeywords[resultErr.Type()]; ok {
+		schemaPath += "/" + keyword
+	}
+
+	// For missing properties the offending field is named in the details
+	field := path
+	if property, ok := resultErr.Details()["property"].(string); ok && resultErr.Type() == "required" {
+		field = append(path[:len(path):len(path)], property)
+	}
+
+	return mcperrors.ValidationDetail{
+		Field:      strings.Join(field, "."),
+		Message:    resultErr.Description(),
+		SchemaPath: "#" + schemaPath,
+	}
+}
+
 // SetDefaultTimeout sets the default timeout for tool execution
 func (m *ToolsManager) SetDefaultTimeout(timeout time.Duration) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.defaultTimeout = timeout
 }
+
+// SetStrict turns on development checks. Registering a tool without a name
+// or schema panics instead of being logged, and arguments a tool's schema
+// does not declare are rejected even when the schema leaves
+// additionalProperties open, so mismatches between clients and tools show
+// up before production.
+func (m *ToolsManager) SetStrict(strict bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.strict = strict
+}
+
+// closedSchema returns schema with additionalProperties false when it
+// declares properties and says nothing about others
+func closedSchema(schema interface{}) interface{} {
+	obj, ok := schema.(map[string]interface{})
+	if !ok {
+		return schema
+	}
+	if _, declared := obj["properties"]; !declared {
+		return schema
+	}
+	if _, set := obj["additionalProperties"]; set {
+		return schema
+	}
+
+	closed := make(map[string]interface{}, len(obj)+1)
+	for k, v := range obj {
+		closed[k] = v
+	}
+	closed["additionalProperties"] = false
+	return closed
+}
+
+// SetValidator replaces the JSON Schema backend used to check tool arguments
+func (m *ToolsManager) SetValidator(v Validator) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.validator = v
+}
+
+// SetResultCache sets the cache consulted for tools enabled in it; nil
+// disables result caching
+func (m *ToolsManager) SetResultCache(c *ResultCache) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cache = c
+}
+
+// ResultCache returns the result cache, or nil when none is set
+func (m *ToolsManager) ResultCache() *ResultCache {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cache
+}
+
+// SetTenantResultCache gives a tenant's sessions a cache of their own, so
+// tenants neither see each other's results nor evict them; nil returns the
+// tenant to the shared cache
+func (m *ToolsManager) SetTenantResultCache(tenant string, c *ResultCache) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c == nil {
+		delete(m.tenantCaches, tenant)
+		return
+	}
+	if m.tenantCaches == nil {
+		m.tenantCaches = make(map[string]*ResultCache)
+	}
+	m.tenantCaches[tenant] = c
+}
+
+// cacheFor returns the result cache sess uses, or nil; m.mu must be held
+func (m *ToolsManager) cacheFor(sess *session.Session) *ResultCache {
+	if sess != nil && sess.Tenant() != "" {
+		if c, ok := m.tenantCaches[sess.Tenant()]; ok {
+			return c
+		}
+	}
+	return m.cache
+}
+
+// caches returns every result cache set; m.mu must be held
+func (m *ToolsManager) caches() []*ResultCache {
+	caches := make([]*ResultCache, 0, 1+len(m.tenantCaches))
+	if m.cache != nil {
+		caches = append(caches, m.cache)
+	}
+	for _, c := range m.tenantCaches {
+		caches = append(caches, c)
+	}
+	return caches
+}
+
+// Handler returns the handler registered for the named tool
+func (m *ToolsManager) Handler(name string) (ToolHandler, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	handler, ok := m.handlers[name]
+	return handler, ok
+}
+
+// SetClock replaces the clock used for tool timeouts and durations
+func (m *ToolsManager) SetClock(c clock.Clock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clock = c
+}
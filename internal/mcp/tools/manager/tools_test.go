@@ -0,0 +1,89 @@
+// internal/mcp/tools/manager/tools_test.go
+package manager_test
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/dkoosis/axe-handle/internal/events"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+)
+
+func echo(context.Context, json.RawMessage, chan<- float64) (protocol.ToolsCallResult, error) {
+	return protocol.ToolsCallResult{}, nil
+}
+
+// toolset builds tools of the given names, each served by echo
+func toolset(names ...string) ([]protocol.Tool, map[string]manager.ToolHandler) {
+	tools := make([]protocol.Tool, 0, len(names))
+	handlers := make(map[string]manager.ToolHandler, len(names))
+	for _, name := range names {
+		tools = append(tools, protocol.Tool{Name: name, InputSchema: map[string]interface{}{"type": "object"}})
+		handlers[name] = echo
+	}
+	return tools, handlers
+}
+
+// replace swaps source's tools for tools of the given names
+func replace(m *manager.ToolsManager, source string, names ...string) error {
+	tools, handlers := toolset(names...)
+	return m.ReplaceSource(source, tools, handlers)
+}
+
+func listed(m *manager.ToolsManager) []string {
+	var names []string
+	for _, tool := range m.ListTools() {
+		names = append(names, tool.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestReplaceSourceKeepsOtherSources(t *testing.T) {
+	m := manager.NewToolsManager()
+	builtin, _ := toolset("builtin")
+	m.RegisterTool(builtin[0], echo)
+	if err := replace(m, "a", "a.one", "a.two"); err != nil {
+		t.Fatal(err)
+	}
+	if err := replace(m, "b", "b.one"); err != nil {
+		t.Fatal(err)
+	}
+
+	bus := events.NewBus()
+	changes := 0
+	bus.Subscribe(events.ToolsChanged, func(events.Event) { changes++ })
+	m.SetEventBus(bus)
+
+	if err := replace(m, "a", "a.two", "a.three"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a.three", "a.two", "b.one", "builtin"}
+	if got := listed(m); !slices.Equal(got, want) {
+		t.Fatalf("tools after swapping a = %v, want %v", got, want)
+	}
+	if changes != 1 {
+		t.Errorf("swap announced %d changes, want 1", changes)
+	}
+
+	// Names owned by another source or registered directly are refused whole
+	for _, name := range []string{"b.one", "builtin"} {
+		if err := replace(m, "a", "a.four", name); err == nil {
+			t.Errorf("a took over %s", name)
+		}
+	}
+	if got := listed(m); !slices.Equal(got, want) {
+		t.Fatalf("refused swaps changed the tools to %v, want %v", got, want)
+	}
+
+	if err := m.ReplaceSource("a", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"b.one", "builtin"}
+	if got := listed(m); !slices.Equal(got, want) {
+		t.Fatalf("tools after emptying a = %v, want %v", got, want)
+	}
+}
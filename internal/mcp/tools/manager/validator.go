@@ -0,0 +1,173 @@
+// internal/mcp/tools/manager/validator.go
+package manager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dkoosis/axe-handle/pkg/mcperrors"
+	jsonschema "github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Validator checks tool arguments against a tool's input schema. Invalid
+// arguments are reported with mcperrors.NewValidationError so clients get
+// the same structured details whichever backend is in use.
+type Validator interface {
+	Validate(schema interface{}, args json.RawMessage) error
+}
+
+// Schema validation backends selectable by name
+const (
+	ValidatorDraft7    = "draft-07" // gojsonschema; drafts 4, 6 and 7
+	ValidatorDraft2020 = "2020-12"  // santhosh-tekuri/jsonschema; drafts 4 through 2020-12
+)
+
+// NewValidator returns the backend for a configured name. The empty string
+// selects ValidatorDraft7.
+func NewValidator(name string) (Validator, error) {
+	switch name {
+	case "", ValidatorDraft7:
+		return Draft7Validator{}, nil
+	case ValidatorDraft2020:
+		return NewDraft2020Validator(), nil
+	default:
+		return nil, fmt.Errorf("unknown schema validator %q (want %s or %s)", name, ValidatorDraft7, ValidatorDraft2020)
+	}
+}
+
+// schemaURL is the location compiled input schemas are registered under
+const schemaURL = "urn:axe-handle:tool-input-schema"
+
+// maxCompiledSchemas bounds the compiled schema cache; it is cleared when full
+const maxCompiledSchemas = 1024
+
+// Draft2020Validator validates with santhosh-tekuri/jsonschema, which supports
+// the keywords newer drafts added ($defs, prefixItems, unevaluatedProperties
+// and friends). Schemas without $schema are read as draft 2020-12, the
+// dialect of OpenAPI 3.1. Compiled schemas are cached by their JSON text.
+type Draft2020Validator struct {
+	mu       sync.Mutex
+	compiled map[string]*jsonschema.Schema
+	printer  *message.Printer
+}
+
+// NewDraft2020Validator creates a draft 2020-12 validator
+func NewDraft2020Validator() *Draft2020Validator {
+	return &Draft2020Validator{
+		compiled: make(map[string]*jsonschema.Schema),
+		printer:  message.NewPrinter(language.English),
+	}
+}
+
+// Validate implements Validator
+func (v *Draft2020Validator) Validate(schemaObj interface{}, args json.RawMessage) error {
+	// Treat missing arguments as an empty object
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+
+	schema, err := v.compile(schemaObj)
+	if err != nil {
+		return mcperrors.NewInvalidParamsError(fmt.Errorf("schema validation error: %w", err))
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(args))
+	if err != nil {
+		return mcperrors.NewInvalidParamsError(fmt.Errorf("schema validation error: %w", err))
+	}
+
+	err = schema.Validate(doc)
+	if err == nil {
+		return nil
+	}
+	verr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return mcperrors.NewInvalidParamsError(fmt.Errorf("schema validation error: %w", err))
+	}
+	return mcperrors.NewValidationError(v.details(verr, nil))
+}
+
+// compile returns the compiled form of schemaObj, compiling it on first use
+func (v *Draft2020Validator) compile(schemaObj interface{}) (*jsonschema.Schema, error) {
+	data, err := json.Marshal(schemaObj)
+	if err != nil {
+		return nil, err
+	}
+	key := string(data)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if schema, ok := v.compiled[key]; ok {
+		return schema, nil
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	c := jsonschema.NewCompiler()
+	c.DefaultDraft(jsonschema.Draft2020)
+	if err := c.AddResource(schemaURL, doc); err != nil {
+		return nil, err
+	}
+	schema, err := c.Compile(schemaURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(v.compiled) >= maxCompiledSchemas {
+		clear(v.compiled)
+	}
+	v.compiled[key] = schema
+	return schema, nil
+}
+
+// details flattens an error tree into one ValidationDetail per failed
+// keyword. Only leaves are reported; inner nodes merely group their causes.
+func (v *Draft2020Validator) details(verr *jsonschema.ValidationError, out []mcperrors.ValidationDetail) []mcperrors.ValidationDetail {
+	if len(verr.Causes) > 0 {
+		for _, cause := range verr.Causes {
+			out = v.details(cause, out)
+		}
+		return out
+	}
+
+	// SchemaURL is "<schemaURL>#/properties/a"; keep the fragment
+	_, fragment, _ := strings.Cut(verr.SchemaURL, "#")
+	schemaPath := "#" + fragment
+	for _, token := range verr.ErrorKind.KeywordPath() {
+		schemaPath += "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+	}
+
+	path := verr.InstanceLocation
+
+	// Name each missing property as its own field, as the draft-07 backend does
+	if required, ok := verr.ErrorKind.(*kind.Required); ok {
+		for _, property := range required.Missing {
+			out = append(out, mcperrors.ValidationDetail{
+				Field:      strings.Join(append(path[:len(path):len(path)], property), "."),
+				Message:    fmt.Sprintf("%s is required", property),
+				SchemaPath: schemaPath,
+			})
+		}
+		return out
+	}
+
+	// A false subschema (additionalProperties: false and the like) just says "false schema"
+	message := verr.ErrorKind.LocalizedString(v.printer)
+	if _, ok := verr.ErrorKind.(*kind.FalseSchema); ok {
+		message = "value is not allowed here"
+	}
+
+	return append(out, mcperrors.ValidationDetail{
+		Field:      strings.Join(path, "."),
+		Message:    message,
+		SchemaPath: schemaPath,
+	})
+}
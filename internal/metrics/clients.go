@@ -0,0 +1,229 @@
+// internal/metrics/clients.go
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Bounds on what ClientStats keeps, so clients cannot grow it without limit
+const (
+	maxClients       = 100 // distinct implementations; later ones count as "other"
+	maxClientMethods = 64  // distinct methods per implementation
+	maxLabelLength   = 64  // longer names and versions are cut
+)
+
+// otherLabel stands in for whatever exceeded a bound
+const otherLabel = "other"
+
+// clientKey identifies a client implementation as it names itself in
+// initialize
+type clientKey struct {
+	name, version string
+}
+
+// clientCounters are the usage counts of one implementation
+type clientCounters struct {
+	sessions  int64
+	protocols map[string]int64 // protocol versions requested
+	methods   map[string]int64
+	tools     map[string]int64
+	firstSeen time.Time
+	lastSeen  time.Time
+}
+
+// ClientUsage is what one client implementation has done
+type ClientUsage struct {
+	Name      string           `json:"name"`
+	Version   string           `json:"version"`
+	Sessions  int64            `json:"sessions"`
+	Protocols map[string]int64 `json:"protocols"`
+	Methods   map[string]int64 `json:"methods"`
+	Tools     map[string]int64 `json:"tools,omitempty"`
+	FirstSeen time.Time        `json:"firstSeen"`
+	LastSeen  time.Time        `json:"lastSeen"`
+}
+
+// ClientStats counts sessions, requests and tool calls per client
+// implementation. Only the name and version clients report for their
+// software are kept, never who connected or from where, or what they sent.
+type ClientStats struct {
+	clients map[clientKey]*clientCounters
+	now     func() time.Time
+	mu      sync.Mutex
+}
+
+// NewClientStats creates empty client statistics
+func NewClientStats() *ClientStats {
+	return &ClientStats{
+		clients: make(map[clientKey]*clientCounters),
+		now:     time.Now,
+	}
+}
+
+// RecordSession counts a session of the named implementation that asked
+// for protocolVersion
+func (s *ClientStats) RecordSession(name, version, protocolVersion string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.counters(name, version)
+	c.sessions++
+	c.protocols[label(protocolVersion)]++
+}
+
+// RecordMethod counts a request or notification from the implementation
+func (s *ClientStats) RecordMethod(name, version, method string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.counters(name, version)
+	method = label(method)
+	if _, ok := c.methods[method]; !ok && len(c.methods) >= maxClientMethods {
+		method = otherLabel
+	}
+	c.methods[method]++
+}
+
+// RecordTool counts a call of a registered tool by the implementation
+func (s *ClientStats) RecordTool(name, version, tool string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counters(name, version).tools[tool]++
+}
+
+// counters returns the counters of an implementation, creating them if
+// there is room. Callers hold s.mu.
+func (s *ClientStats) counters(name, version string) *clientCounters {
+	key := clientKey{name: label(name), version: label(version)}
+	c, ok := s.clients[key]
+	if !ok && len(s.clients) >= maxClients {
+		key = clientKey{name: otherLabel, version: otherLabel}
+		c, ok = s.clients[key]
+	}
+	now := s.now()
+	if !ok {
+		c = &clientCounters{
+			protocols: make(map[string]int64),
+			methods:   make(map[string]int64),
+			tools:     make(map[string]int64),
+			firstSeen: now,
+		}
+		s.clients[key] = c
+	}
+	c.lastSeen = now
+	return c
+}
+
+// label bounds a client-supplied string used as a key
+func label(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	if len(s) > maxLabelLength {
+		return s[:maxLabelLength]
+	}
+	return s
+}
+
+// Snapshot returns the usage of every implementation seen, most sessions
+// first
+func (s *ClientStats) Snapshot() []ClientUsage {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	usage := make([]ClientUsage, 0, len(s.clients))
+	for key, c := range s.clients {
+		usage = append(usage, ClientUsage{
+			Name:      key.name,
+			Version:   key.version,
+			Sessions:  c.sessions,
+			Protocols: copyCounts(c.protocols),
+			Methods:   copyCounts(c.methods),
+			Tools:     copyCounts(c.tools),
+			FirstSeen: c.firstSeen,
+			LastSeen:  c.lastSeen,
+		})
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Sessions != usage[j].Sessions {
+			return usage[i].Sessions > usage[j].Sessions
+		}
+		if usage[i].Name != usage[j].Name {
+			return usage[i].Name < usage[j].Name
+		}
+		return usage[i].Version < usage[j].Version
+	})
+	return usage
+}
+
+// copyCounts copies a counter map
+func copyCounts(m map[string]int64) map[string]int64 {
+	out := make(map[string]int64, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// WritePrometheus writes the counters in Prometheus text exposition format
+func (s *ClientStats) WritePrometheus(w io.Writer) error {
+	usage := s.Snapshot()
+
+	if _, err := fmt.Fprintln(w, "# HELP axe_handle_client_sessions_total Sessions initialized, by client implementation and requested protocol version.\n# TYPE axe_handle_client_sessions_total counter"); err != nil {
+		return err
+	}
+	for _, u := range usage {
+		for _, protocol := range sortedKeys(u.Protocols) {
+			if _, err := fmt.Fprintf(w, "axe_handle_client_sessions_total{client=%q,version=%q,protocol=%q} %d\n", u.Name, u.Version, protocol, u.Protocols[protocol]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := fmt.Fprintln(w, "# HELP axe_handle_client_requests_total Requests received, by client implementation and method.\n# TYPE axe_handle_client_requests_total counter"); err != nil {
+		return err
+	}
+	for _, u := range usage {
+		for _, method := range sortedKeys(u.Methods) {
+			if _, err := fmt.Fprintf(w, "axe_handle_client_requests_total{client=%q,version=%q,method=%q} %d\n", u.Name, u.Version, method, u.Methods[method]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := fmt.Fprintln(w, "# HELP axe_handle_client_tool_calls_total Tool calls, by client implementation and tool.\n# TYPE axe_handle_client_tool_calls_total counter"); err != nil {
+		return err
+	}
+	for _, u := range usage {
+		for _, tool := range sortedKeys(u.Tools) {
+			if _, err := fmt.Fprintf(w, "axe_handle_client_tool_calls_total{client=%q,version=%q,tool=%q} %d\n", u.Name, u.Version, tool, u.Tools[tool]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string]int64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,227 @@
+// internal/metrics/errors.go
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Default alerting settings
+const (
+	DefaultAlertWindow      = 5 * time.Minute
+	DefaultAlertThreshold   = 0.5
+	DefaultAlertMinRequests = 20
+)
+
+// Alert describes an error rate that crossed the configured threshold
+type Alert struct {
+	ErrorRate float64       `json:"errorRate"`
+	Threshold float64       `json:"threshold"`
+	Errors    int           `json:"errors"`
+	Requests  int           `json:"requests"`
+	Window    time.Duration `json:"window"`
+	At        time.Time     `json:"at"`
+}
+
+// AlertHook is called when the error rate over the window exceeds the threshold
+type AlertHook func(ctx context.Context, alert Alert)
+
+// AlertConfig controls when the alert hook fires
+type AlertConfig struct {
+	Threshold   float64       // error rate (0-1) that triggers an alert
+	Window      time.Duration // sliding window the rate is computed over
+	MinRequests int           // ignore the rate until the window holds this many requests
+}
+
+// errorKey identifies an error counter
+type errorKey struct {
+	method string
+	code   int
+}
+
+// event is a single request outcome kept for the sliding window
+type event struct {
+	at     time.Time
+	failed bool
+}
+
+// ErrorMetrics counts requests and errors per method and code, and raises
+// alerts when the recent failure rate gets too high.
+type ErrorMetrics struct {
+	requests map[string]int64
+	errors   map[errorKey]int64
+
+	window     []event
+	collectors []Collector // written after the error counters
+	alertCfg   AlertConfig
+	alertHook  AlertHook
+	lastAlert  time.Time
+
+	mu sync.Mutex
+}
+
+// NewErrorMetrics creates an empty set of error metrics
+func NewErrorMetrics() *ErrorMetrics {
+	return &ErrorMetrics{
+		requests: make(map[string]int64),
+		errors:   make(map[errorKey]int64),
+		alertCfg: AlertConfig{
+			Threshold:   DefaultAlertThreshold,
+			Window:      DefaultAlertWindow,
+			MinRequests: DefaultAlertMinRequests,
+		},
+	}
+}
+
+// Collector is a further set of metrics served alongside the error counters
+type Collector interface {
+	WritePrometheus(w io.Writer) error
+}
+
+// AddCollector serves c's metrics from the same endpoint
+func (m *ErrorMetrics) AddCollector(c Collector) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.collectors = append(m.collectors, c)
+}
+
+// SetAlertHook installs the hook called when the error rate crosses the threshold
+func (m *ErrorMetrics) SetAlertHook(cfg AlertConfig, hook AlertHook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.alertCfg = cfg
+	m.alertHook = hook
+}
+
+// RecordRequest counts a request for method
+func (m *ErrorMetrics) RecordRequest(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[method]++
+	m.window = append(m.window, event{at: time.Now()})
+	m.prune(time.Now())
+}
+
+// RecordError counts an error response with the given code for method
+func (m *ErrorMetrics) RecordError(method string, code int) {
+	m.mu.Lock()
+	m.errors[errorKey{method: method, code: code}]++
+	now := time.Now()
+	m.window = append(m.window, event{at: now, failed: true})
+	m.prune(now)
+	alert, fire := m.checkAlert(now)
+	hook := m.alertHook
+	m.mu.Unlock()
+
+	if fire && hook != nil {
+		slog.Warn("Error rate threshold exceeded",
+			"error_rate", alert.ErrorRate,
+			"threshold", alert.Threshold,
+			"window", alert.Window)
+		go hook(context.Background(), alert)
+	}
+}
+
+// prune drops window events older than the alert window
+func (m *ErrorMetrics) prune(now time.Time) {
+	cutoff := now.Add(-m.alertCfg.Window)
+	i := 0
+	for i < len(m.window) && m.window[i].at.Before(cutoff) {
+		i++
+	}
+	m.window = m.window[i:]
+}
+
+// checkAlert evaluates the window; alerts fire at most once per window
+func (m *ErrorMetrics) checkAlert(now time.Time) (Alert, bool) {
+	var requests, errors int
+	for _, e := range m.window {
+		if e.failed {
+			errors++
+		} else {
+			requests++
+		}
+	}
+
+	if requests == 0 || requests < m.alertCfg.MinRequests {
+		return Alert{}, false
+	}
+
+	rate := float64(errors) / float64(requests)
+	if rate < m.alertCfg.Threshold || now.Sub(m.lastAlert) < m.alertCfg.Window {
+		return Alert{}, false
+	}
+
+	m.lastAlert = now
+	return Alert{
+		ErrorRate: rate,
+		Threshold: m.alertCfg.Threshold,
+		Errors:    errors,
+		Requests:  requests,
+		Window:    m.alertCfg.Window,
+		At:        now,
+	}, true
+}
+
+// WritePrometheus writes the counters in Prometheus text exposition format
+func (m *ErrorMetrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	requests := make(map[string]int64, len(m.requests))
+	for k, v := range m.requests {
+		requests[k] = v
+	}
+	errors := make(map[errorKey]int64, len(m.errors))
+	for k, v := range m.errors {
+		errors[k] = v
+	}
+	collectors := append([]Collector(nil), m.collectors...)
+	m.mu.Unlock()
+
+	methods := make([]string, 0, len(requests))
+	for method := range requests {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	if _, err := fmt.Fprintln(w, "# HELP axe_handle_requests_total Requests received, by method.\n# TYPE axe_handle_requests_total counter"); err != nil {
+		return err
+	}
+	for _, method := range methods {
+		if _, err := fmt.Fprintf(w, "axe_handle_requests_total{method=%q} %d\n", method, requests[method]); err != nil {
+			return err
+		}
+	}
+
+	keys := make([]errorKey, 0, len(errors))
+	for k := range errors {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].method != keys[j].method {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].code < keys[j].code
+	})
+
+	if _, err := fmt.Fprintln(w, "# HELP axe_handle_errors_total Error responses sent, by method and JSON-RPC code.\n# TYPE axe_handle_errors_total counter"); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if _, err := fmt.Fprintf(w, "axe_handle_errors_total{method=%q,code=\"%d\"} %d\n", k.method, k.code, errors[k]); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range collectors {
+		if err := c.WritePrometheus(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// internal/metrics/http.go
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// webhookTimeout bounds a single alert webhook delivery
+const webhookTimeout = 10 * time.Second
+
+// Handler serves the metrics in Prometheus text format
+func (m *ErrorMetrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := m.WritePrometheus(w); err != nil {
+			slog.Error("Failed to write metrics", "error", err)
+		}
+	})
+}
+
+// Serve exposes the metrics on addr at /metrics until ctx is done
+func (m *ErrorMetrics) Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		_ = srv.Shutdown(context.Background())
+	}()
+
+	slog.Info("Serving metrics", "address", addr)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("metrics server failed: %w", err)
+	}
+	return nil
+}
+
+// NewWebhookAlertHook returns an AlertHook that POSTs the alert as JSON to url
+func NewWebhookAlertHook(url string) AlertHook {
+	client := &http.Client{Timeout: webhookTimeout}
+
+	return func(ctx context.Context, alert Alert) {
+		body, err := json.Marshal(alert)
+		if err != nil {
+			slog.Error("Failed to encode alert", "error", err)
+			return
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			slog.Error("Failed to create alert webhook request", "error", err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			slog.Error("Alert webhook failed", "error", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			slog.Error("Alert webhook returned error status", "status", resp.StatusCode)
+		}
+	}
+}
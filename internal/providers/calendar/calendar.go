@@ -0,0 +1,425 @@
+// internal/providers/calendar/calendar.go
+
+// Package calendar serves events from iCalendar files, ICS feeds and
+// CalDAV collections. Upcoming events are listed as resources, and the
+// calendar.find_slot and calendar.create_event tools let assistants
+// schedule around them.
+//
+// Each upcoming event is listed as calendar://<calendar>/<uid>, with
+// ?at=<start> added for occurrences of recurring events. Events are read
+// from their source on every request, so edits made elsewhere show up at
+// once. calendar.create_event is annotated as not read-only, so it runs
+// only once the server's approval hook lets it.
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+)
+
+// Provider defaults
+const (
+	DefaultUpcoming  = 7 * 24 * time.Hour
+	DefaultWorkHours = "09:00-17:00"
+	defaultTimeout   = 30 * time.Second
+	maxResponseSize  = 16 << 20
+	uriScheme        = "calendar://"
+)
+
+// Source is one calendar. Exactly one of ICS and CalDAV is set.
+type Source struct {
+	Name     string // names the calendar in tools and resource URIs
+	ICS      string // .ics file path, or http(s) URL of a read-only feed
+	CalDAV   string // URL of a CalDAV calendar collection
+	Username string // for ICS URLs and CalDAV; may reference environment variables as $VAR
+	Password string // may reference environment variables as $VAR
+	ReadOnly bool   // refuse calendar.create_event for this calendar
+}
+
+// Config configures a Provider
+type Config struct {
+	Calendars []Source
+	Upcoming  time.Duration  // how far ahead events are listed as resources
+	WorkHours string         // HH:MM-HH:MM on weekdays searched by calendar.find_slot
+	Location  *time.Location // for times without a zone and for work hours
+	Timeout   time.Duration  // per request to a remote calendar
+}
+
+// Provider serves a set of calendars
+type Provider struct {
+	cfg       Config
+	client    *http.Client
+	workStart time.Duration
+	workEnd   time.Duration
+	now       func() time.Time
+
+	mu sync.Mutex // serializes writes to ICS files
+}
+
+// Ensure Provider implements resources.Provider
+var _ resources.Provider = (*Provider)(nil)
+
+// New creates a provider for cfg's calendars
+func New(cfg Config) (*Provider, error) {
+	if cfg.Upcoming <= 0 {
+		cfg.Upcoming = DefaultUpcoming
+	}
+	if cfg.WorkHours == "" {
+		cfg.WorkHours = DefaultWorkHours
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.Local
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	start, end, err := parseWorkHours(cfg.WorkHours)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, src := range cfg.Calendars {
+		switch {
+		case src.Name == "" || strings.ContainsAny(src.Name, "/?#"):
+			return nil, fmt.Errorf("invalid calendar name %q", src.Name)
+		case seen[src.Name]:
+			return nil, fmt.Errorf("calendar %q is configured twice", src.Name)
+		case (src.ICS == "") == (src.CalDAV == ""):
+			return nil, fmt.Errorf("calendar %s needs exactly one of ics and caldav", src.Name)
+		}
+		seen[src.Name] = true
+	}
+	return &Provider{
+		cfg:       cfg,
+		client:    &http.Client{Timeout: cfg.Timeout},
+		workStart: start,
+		workEnd:   end,
+		now:       time.Now,
+	}, nil
+}
+
+// Names returns the configured calendars' names
+func (p *Provider) Names() []string {
+	names := make([]string, len(p.cfg.Calendars))
+	for i, src := range p.cfg.Calendars {
+		names[i] = src.Name
+	}
+	return names
+}
+
+// source returns the named calendar
+func (p *Provider) source(name string) (Source, bool) {
+	for _, src := range p.cfg.Calendars {
+		if src.Name == name {
+			return src, true
+		}
+	}
+	return Source{}, false
+}
+
+// Events returns the occurrences in [from, to) of the named calendars, or
+// of all of them when names is empty, sorted by start. A calendar that
+// cannot be read fails the whole call, since a missing calendar would make
+// busy time look free.
+func (p *Provider) Events(ctx context.Context, names []string, from, to time.Time) ([]Event, error) {
+	if len(names) == 0 {
+		names = p.Names()
+	}
+	var all []Event
+	for _, name := range names {
+		src, ok := p.source(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown calendar %q", name)
+		}
+		events, err := p.read(ctx, src, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("calendar %s: %w", name, err)
+		}
+		all = append(all, expand(name, events, from, to)...)
+	}
+	sortEvents(all)
+	return all, nil
+}
+
+// read fetches and parses a calendar's events
+func (p *Provider) read(ctx context.Context, src Source, from, to time.Time) ([]*vevent, error) {
+	if src.CalDAV != "" {
+		return p.caldavQuery(ctx, src, from, to)
+	}
+	if !isURL(src.ICS) {
+		f, err := os.Open(src.ICS)
+		if os.IsNotExist(err) {
+			return nil, nil // created by the first calendar.create_event
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return parseICS(f, p.cfg.Location)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src.ICS, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.do(req, src)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return parseICS(io.LimitReader(resp.Body, maxResponseSize), p.cfg.Location)
+}
+
+// Create adds an event to the named calendar and returns it with its UID
+func (p *Provider) Create(ctx context.Context, name string, ev Event) (Event, error) {
+	src, ok := p.source(name)
+	if !ok {
+		return Event{}, fmt.Errorf("unknown calendar %q", name)
+	}
+	if src.ReadOnly || isURL(src.ICS) {
+		return Event{}, fmt.Errorf("calendar %s is read-only", name)
+	}
+	ev.Calendar, ev.UID = name, newUID()
+	vevent := formatVEvent(ev, p.now())
+	if src.CalDAV != "" {
+		return ev, p.caldavPut(ctx, src, ev.UID, formatCalendar(vevent))
+	}
+	return ev, p.appendICS(src.ICS, vevent)
+}
+
+// appendICS adds a VEVENT to an ICS file, creating the file if needed. The
+// file is replaced atomically so readers never see half an edit.
+func (p *Provider) appendICS(path, vevent string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	var content string
+	if i := bytes.LastIndex(bytes.ToUpper(data), []byte("END:VCALENDAR")); i >= 0 {
+		content = string(data[:i]) + vevent + string(data[i:])
+	} else {
+		content = formatCalendar(vevent)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".calendar-*.ics")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// ListResources returns one resource per upcoming event. Calendars that
+// cannot be read are logged and skipped.
+func (p *Provider) ListResources() ([]resources.Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+	defer cancel()
+	from := p.now()
+	var list []resources.Resource
+	for _, name := range p.Names() {
+		events, err := p.Events(ctx, []string{name}, from, from.Add(p.cfg.Upcoming))
+		if err != nil {
+			slog.Warn("Failed to read calendar", "calendar", name, "error", err)
+			continue
+		}
+		for _, ev := range events {
+			list = append(list, resources.Resource{
+				URI:         eventURI(ev),
+				Name:        ev.Summary,
+				Description: p.describe(ev),
+				MimeType:    "application/json",
+			})
+		}
+	}
+	return list, nil
+}
+
+// GetResource returns an upcoming event
+func (p *Provider) GetResource(uri string) (interface{}, error) {
+	rest, ok := strings.CutPrefix(uri, uriScheme)
+	if !ok {
+		return nil, resources.ErrResourceNotFound
+	}
+	rest, rawQuery, _ := strings.Cut(rest, "?")
+	name, escapedUID, ok := strings.Cut(rest, "/")
+	uid, err := url.PathUnescape(escapedUID)
+	if !ok || err != nil {
+		return nil, resources.ErrResourceNotFound
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, resources.ErrResourceNotFound
+	}
+	from, to := p.now(), p.now().Add(p.cfg.Upcoming)
+	var at time.Time
+	if v := query.Get("at"); v != "" {
+		if at, err = time.Parse("20060102T150405Z", v); err != nil {
+			return nil, resources.ErrResourceNotFound
+		}
+		from, to = at, at.Add(time.Second)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+	defer cancel()
+	events, err := p.Events(ctx, []string{name}, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, ev := range events {
+		if ev.UID == uid && (at.IsZero() || ev.Start.Equal(at)) {
+			return marshalIndent(ev)
+		}
+	}
+	return nil, resources.ErrResourceNotFound
+}
+
+// eventURI addresses an occurrence of an event
+func eventURI(ev Event) string {
+	uri := uriScheme + ev.Calendar + "/" + url.PathEscape(ev.UID)
+	if ev.Recurring {
+		uri += "?at=" + ev.Start.UTC().Format("20060102T150405Z")
+	}
+	return uri
+}
+
+// describe summarizes when and where an event is
+func (p *Provider) describe(ev Event) string {
+	start := ev.Start.In(p.cfg.Location)
+	var when string
+	if ev.AllDay {
+		when = start.Format("Mon 2 Jan 2006") + ", all day"
+	} else {
+		when = start.Format("Mon 2 Jan 2006 15:04") + "-" + ev.End.In(p.cfg.Location).Format("15:04")
+	}
+	if ev.Location != "" {
+		when += " at " + ev.Location
+	}
+	return when
+}
+
+// do sends a request with the calendar's credentials and fails on error
+// statuses
+func (p *Provider) do(req *http.Request, src Source) (*http.Response, error) {
+	if src.Username != "" {
+		creds := os.ExpandEnv(src.Username) + ":" + os.ExpandEnv(src.Password)
+		req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(creds)))
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s returned %s", req.Method, req.URL.Redacted(), resp.Status)
+	}
+	return resp, nil
+}
+
+// calendarQuery asks a CalDAV server for the events overlapping a range;
+// recurring events are returned whole and expanded locally
+const calendarQuery = `<?xml version="1.0" encoding="utf-8"?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><C:calendar-data/></D:prop>
+  <C:filter>
+    <C:comp-filter name="VCALENDAR">
+      <C:comp-filter name="VEVENT">
+        <C:time-range start="%s" end="%s"/>
+      </C:comp-filter>
+    </C:comp-filter>
+  </C:filter>
+</C:calendar-query>`
+
+// multistatus is the part of a WebDAV multistatus response that carries
+// calendar data
+type multistatus struct {
+	Responses []struct {
+		Propstats []struct {
+			CalendarData string `xml:"prop>calendar-data"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// caldavQuery fetches the events of a CalDAV collection that overlap
+// [from, to)
+func (p *Provider) caldavQuery(ctx context.Context, src Source, from, to time.Time) ([]*vevent, error) {
+	const utc = "20060102T150405Z"
+	body := fmt.Sprintf(calendarQuery, from.UTC().Format(utc), to.UTC().Format(utc))
+	req, err := http.NewRequestWithContext(ctx, "REPORT", src.CalDAV, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "1")
+	resp, err := p.do(req, src)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var ms multistatus
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("invalid CalDAV response: %w", err)
+	}
+	var events []*vevent
+	for _, r := range ms.Responses {
+		for _, ps := range r.Propstats {
+			if ps.CalendarData == "" {
+				continue
+			}
+			parsed, err := parseICS(strings.NewReader(ps.CalendarData), p.cfg.Location)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, parsed...)
+		}
+	}
+	return events, nil
+}
+
+// caldavPut stores a new calendar object in a CalDAV collection
+func (p *Provider) caldavPut(ctx context.Context, src Source, uid, object string) error {
+	target, err := url.JoinPath(src.CalDAV, url.PathEscape(uid)+".ics")
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, strings.NewReader(object))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+	req.Header.Set("If-None-Match", "*")
+	resp, err := p.do(req, src)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// isURL reports whether an ICS source is fetched over HTTP
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
@@ -0,0 +1,478 @@
+// internal/providers/calendar/ics.go
+package calendar
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxOccurrences bounds the expansion of one recurring event, so a rule
+// without an end cannot run away
+const maxOccurrences = 5000
+
+// Event is one occurrence of a calendar event
+type Event struct {
+	Calendar    string    `json:"calendar"`
+	UID         string    `json:"uid"`
+	Summary     string    `json:"summary"`
+	Description string    `json:"description,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	AllDay      bool      `json:"allDay,omitempty"`
+	Recurring   bool      `json:"recurring,omitempty"`
+	Free        bool      `json:"free,omitempty"` // TRANSP:TRANSPARENT; does not block time
+}
+
+// vevent is a VEVENT as parsed, before recurrences are expanded
+type vevent struct {
+	Event
+	rule         *rrule
+	exdates      map[int64]bool
+	recurrenceID time.Time // set on overrides of one occurrence
+	cancelled    bool
+}
+
+// rrule is the subset of RFC 5545 recurrence rules that is expanded:
+// FREQ with INTERVAL, COUNT, UNTIL and, for weekly rules, BYDAY
+type rrule struct {
+	freq     string
+	interval int
+	count    int
+	until    time.Time
+	byDay    []time.Weekday
+}
+
+// property is one content line: NAME;PARAM=VALUE:value
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseICS reads the events of an iCalendar stream. Times without a zone
+// are read in loc.
+func parseICS(r io.Reader, loc *time.Location) ([]*vevent, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		events []*vevent
+		cur    *vevent
+		depth  int // components nested inside the current VEVENT, such as VALARM
+	)
+	for _, line := range lines {
+		p, ok := parseProperty(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case p.name == "BEGIN" && strings.EqualFold(p.value, "VEVENT") && cur == nil:
+			cur = &vevent{exdates: map[int64]bool{}}
+		case cur == nil:
+		case p.name == "BEGIN":
+			depth++
+		case p.name == "END" && depth > 0:
+			depth--
+		case p.name == "END" && strings.EqualFold(p.value, "VEVENT"):
+			if !cur.Start.IsZero() {
+				if cur.End.IsZero() {
+					cur.End = cur.Start
+					if cur.AllDay {
+						cur.End = cur.Start.AddDate(0, 0, 1)
+					}
+				}
+				events = append(events, cur)
+			}
+			cur = nil
+		case depth > 0:
+		default:
+			if err := cur.set(p, loc); err != nil {
+				return nil, fmt.Errorf("event %s: %w", cur.UID, err)
+			}
+		}
+	}
+	return events, nil
+}
+
+// set applies one property to the event
+func (e *vevent) set(p property, loc *time.Location) error {
+	switch p.name {
+	case "UID":
+		e.UID = p.value
+	case "SUMMARY":
+		e.Summary = unescape(p.value)
+	case "DESCRIPTION":
+		e.Description = unescape(p.value)
+	case "LOCATION":
+		e.Location = unescape(p.value)
+	case "STATUS":
+		e.cancelled = strings.EqualFold(p.value, "CANCELLED")
+	case "TRANSP":
+		e.Free = strings.EqualFold(p.value, "TRANSPARENT")
+	case "DTSTART":
+		t, allDay, err := parseTime(p, loc)
+		if err != nil {
+			return err
+		}
+		e.Start, e.AllDay = t, allDay
+	case "DTEND":
+		t, _, err := parseTime(p, loc)
+		if err != nil {
+			return err
+		}
+		e.End = t
+	case "DURATION":
+		if e.Start.IsZero() {
+			return fmt.Errorf("DURATION before DTSTART")
+		}
+		d, err := parseDuration(p.value)
+		if err != nil {
+			return err
+		}
+		e.End = e.Start.Add(d)
+	case "RRULE":
+		rule, err := parseRRule(p.value, loc)
+		if err != nil {
+			return err
+		}
+		e.rule = rule
+	case "EXDATE":
+		for _, v := range strings.Split(p.value, ",") {
+			t, _, err := parseTime(property{name: p.name, params: p.params, value: v}, loc)
+			if err != nil {
+				return err
+			}
+			e.exdates[t.Unix()] = true
+		}
+	case "RECURRENCE-ID":
+		t, _, err := parseTime(p, loc)
+		if err != nil {
+			return err
+		}
+		e.recurrenceID = t
+	}
+	return nil
+}
+
+// expand returns the occurrences of events that overlap [from, to), sorted
+// by start. Overrides replace the occurrence they name; cancelled events
+// and occurrences are dropped.
+func expand(calendar string, events []*vevent, from, to time.Time) []Event {
+	overridden := make(map[string]map[int64]bool)
+	for _, e := range events {
+		if !e.recurrenceID.IsZero() {
+			if overridden[e.UID] == nil {
+				overridden[e.UID] = map[int64]bool{}
+			}
+			overridden[e.UID][e.recurrenceID.Unix()] = true
+		}
+	}
+
+	var out []Event
+	add := func(ev Event) {
+		if ev.Start.Before(to) && (ev.End.After(from) || ev.End.Equal(ev.Start) && !ev.Start.Before(from)) {
+			ev.Calendar = calendar
+			out = append(out, ev)
+		}
+	}
+	for _, e := range events {
+		if e.cancelled {
+			continue
+		}
+		if e.rule == nil || !e.recurrenceID.IsZero() {
+			add(e.Event)
+			continue
+		}
+		length := e.End.Sub(e.Start)
+		e.rule.each(e.Start, to, func(start time.Time) {
+			if e.exdates[start.Unix()] || overridden[e.UID][start.Unix()] {
+				return
+			}
+			ev := e.Event
+			ev.Start, ev.End, ev.Recurring = start, start.Add(length), true
+			add(ev)
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	return out
+}
+
+// each calls fn with every occurrence of the rule starting at start and
+// before to
+func (r *rrule) each(start, to time.Time, fn func(time.Time)) {
+	n := 0
+	emit := func(t time.Time) bool {
+		if !t.Before(to) || (!r.until.IsZero() && t.After(r.until)) || (r.count > 0 && n >= r.count) || n >= maxOccurrences {
+			return false
+		}
+		n++
+		fn(t)
+		return true
+	}
+
+	if r.freq == "WEEKLY" && len(r.byDay) > 0 {
+		// Weeks start on Monday, as RFC 5545 defaults WKST to MO
+		weekStart := start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+		for week := 0; ; week += r.interval {
+			base := weekStart.AddDate(0, 0, 7*week)
+			if !base.Before(to) || (!r.until.IsZero() && base.After(r.until)) || n >= maxOccurrences {
+				return
+			}
+			for _, day := range r.byDay {
+				t := base.AddDate(0, 0, (int(day)+6)%7)
+				if t.Before(start) {
+					continue
+				}
+				if !emit(t) {
+					return
+				}
+			}
+		}
+	}
+
+	for i := 0; ; i++ {
+		var t time.Time
+		switch r.freq {
+		case "DAILY":
+			t = start.AddDate(0, 0, i*r.interval)
+		case "WEEKLY":
+			t = start.AddDate(0, 0, 7*i*r.interval)
+		case "MONTHLY":
+			t = start.AddDate(0, i*r.interval, 0)
+		case "YEARLY":
+			t = start.AddDate(i*r.interval, 0, 0)
+		default:
+			emit(start)
+			return
+		}
+		if t.Day() != start.Day() {
+			// The 31st of a shorter month, or 29 February outside leap years
+			if t.Before(to) && i < maxOccurrences {
+				continue
+			}
+			return
+		}
+		if !emit(t) {
+			return
+		}
+	}
+}
+
+// parseRRule reads a recurrence rule; parts that are not supported are
+// ignored, so such rules expand to a superset of their occurrences
+func parseRRule(value string, loc *time.Location) (*rrule, error) {
+	r := &rrule{interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, val, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.freq = strings.ToUpper(val)
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid RRULE INTERVAL %q", val)
+			}
+			r.interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid RRULE COUNT %q", val)
+			}
+			r.count = n
+		case "UNTIL":
+			t, _, err := parseTime(property{name: "UNTIL", value: val, params: map[string]string{}}, loc)
+			if err != nil {
+				return nil, err
+			}
+			r.until = t
+		case "BYDAY":
+			for _, d := range strings.Split(val, ",") {
+				if day, ok := weekdays[strings.ToUpper(d)]; ok {
+					r.byDay = append(r.byDay, day)
+				}
+			}
+		}
+	}
+	sort.Slice(r.byDay, func(i, j int) bool { return (r.byDay[i]+6)%7 < (r.byDay[j]+6)%7 })
+	return r, nil
+}
+
+// weekdays maps RFC 5545 day codes to weekdays
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// parseTime reads a DATE or DATE-TIME value: UTC when it ends in Z, in its
+// TZID when it names one, and in loc otherwise
+func parseTime(p property, loc *time.Location) (t time.Time, allDay bool, err error) {
+	if tzid := p.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(strings.Trim(tzid, "/")); err == nil {
+			loc = l
+		}
+	}
+	v := strings.TrimSpace(p.value)
+	switch {
+	case p.params["VALUE"] == "DATE" || len(v) == 8:
+		t, err = time.ParseInLocation("20060102", v, loc)
+		allDay = true
+	case strings.HasSuffix(v, "Z"):
+		t, err = time.Parse("20060102T150405Z", v)
+	default:
+		t, err = time.ParseInLocation("20060102T150405", v, loc)
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid %s %q", p.name, p.value)
+	}
+	return t, allDay, nil
+}
+
+// parseDuration reads an RFC 5545 duration such as PT1H30M or P1D
+func parseDuration(v string) (time.Duration, error) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(v, "+"), "P")
+	sign := time.Duration(1)
+	if strings.HasPrefix(v, "-") {
+		sign, rest = -1, strings.TrimPrefix(v, "-P")
+	}
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, c := range rest {
+		switch {
+		case c >= '0' && c <= '9':
+			num += string(c)
+		case c == 'T':
+			inTime = true
+		default:
+			n, err := strconv.Atoi(num)
+			if err != nil {
+				return 0, fmt.Errorf("invalid DURATION %q", v)
+			}
+			unit := map[rune]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour}[c]
+			if inTime {
+				unit = map[rune]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}[c]
+			}
+			if unit == 0 {
+				return 0, fmt.Errorf("invalid DURATION %q", v)
+			}
+			d += time.Duration(n) * unit
+			num = ""
+		}
+	}
+	return sign * d, nil
+}
+
+// unfold reads content lines, joining folded continuation lines
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4<<20)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseProperty splits a content line into its name, parameters and value
+func parseProperty(line string) (property, bool) {
+	quoted := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, false
+	}
+	head := strings.Split(line[:colon], ";")
+	p := property{name: strings.ToUpper(head[0]), params: map[string]string{}, value: line[colon+1:]}
+	for _, param := range head[1:] {
+		k, v, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return p, true
+}
+
+// unescape decodes an iCalendar TEXT value
+func unescape(v string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(v)
+}
+
+// escape encodes an iCalendar TEXT value
+func escape(v string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, ",", `\,`, ";", `\;`).Replace(v)
+}
+
+// newUID returns a globally unique event UID
+func newUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b) + "@axe-handle"
+}
+
+// formatVEvent writes ev as a folded VEVENT component
+func formatVEvent(ev Event, now time.Time) string {
+	const utc = "20060102T150405Z"
+	lines := []string{
+		"BEGIN:VEVENT",
+		"UID:" + ev.UID,
+		"DTSTAMP:" + now.UTC().Format(utc),
+		"DTSTART:" + ev.Start.UTC().Format(utc),
+		"DTEND:" + ev.End.UTC().Format(utc),
+		"SUMMARY:" + escape(ev.Summary),
+	}
+	if ev.Location != "" {
+		lines = append(lines, "LOCATION:"+escape(ev.Location))
+	}
+	if ev.Description != "" {
+		lines = append(lines, "DESCRIPTION:"+escape(ev.Description))
+	}
+	lines = append(lines, "END:VEVENT")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(fold(line))
+	}
+	return b.String()
+}
+
+// formatCalendar wraps VEVENTs in a VCALENDAR
+func formatCalendar(vevents string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//axe-handle//calendar//EN\r\n" + vevents + "END:VCALENDAR\r\n"
+}
+
+// fold splits a content line into lines of at most 75 octets, as RFC 5545
+// requires, without splitting UTF-8 sequences
+func fold(line string) string {
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,324 @@
+// internal/providers/calendar/tools.go
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+)
+
+// Tool names
+const (
+	FindSlotToolName    = "calendar.find_slot"
+	CreateEventToolName = "calendar.create_event"
+)
+
+// Search limits of calendar.find_slot
+const (
+	defaultSlots      = 3
+	maxSlots          = 20
+	defaultSearchDays = 7
+	maxSearchDays     = 90
+	slotAlignment     = 15 * time.Minute
+)
+
+// UsageHint implements provider.Hinter
+func (p *Provider) UsageHint() string {
+	return "Upcoming events are calendar:// resources. Call " + FindSlotToolName +
+		" for a free slot before " + CreateEventToolName + " rather than guessing from the events."
+}
+
+// Register adds the calendar tools to m
+func (p *Provider) Register(m *manager.ToolsManager) {
+	calendars := map[string]interface{}{"type": "string", "enum": p.Names()}
+
+	m.RegisterTool(protocol.Tool{
+		Name: FindSlotToolName,
+		Description: fmt.Sprintf("Find free time of the given length during work hours (%s, Monday to Friday) "+
+			"when none of the calendars has an event. Times are RFC 3339.", p.cfg.WorkHours),
+		InputSchema: map[string]interface{}{
+			"type":     "object",
+			"required": []string{"minutes"},
+			"properties": map[string]interface{}{
+				"minutes":   map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 24 * 60, "description": "Length of the slot"},
+				"after":     map[string]interface{}{"type": "string", "format": "date-time", "description": "Earliest start; now by default"},
+				"before":    map[string]interface{}{"type": "string", "format": "date-time", "description": fmt.Sprintf("Latest end; %d days after the earliest start by default", defaultSearchDays)},
+				"calendars": map[string]interface{}{"type": "array", "items": calendars, "description": "Calendars that must be free; all by default"},
+				"count":     map[string]interface{}{"type": "integer", "minimum": 1, "maximum": maxSlots, "description": fmt.Sprintf("Slots to return; %d by default", defaultSlots)},
+			},
+		},
+		Annotations: protocol.ReadOnly(),
+	}, func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		var params struct {
+			Minutes   int      `json:"minutes"`
+			After     string   `json:"after"`
+			Before    string   `json:"before"`
+			Calendars []string `json:"calendars"`
+			Count     int      `json:"count"`
+		}
+		if err := json.Unmarshal(args, &params); err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		after, before, err := p.searchRange(params.After, params.Before)
+		if err != nil {
+			return errorResult(err), nil
+		}
+		if params.Count <= 0 {
+			params.Count = defaultSlots
+		}
+		events, err := p.Events(ctx, params.Calendars, after, before)
+		if err != nil {
+			return errorResult(err), nil
+		}
+		slots := p.freeSlots(events, after, before, time.Duration(params.Minutes)*time.Minute, min(params.Count, maxSlots))
+		return jsonResult(map[string]interface{}{"slots": slots})
+	})
+
+	f := false
+	m.RegisterTool(protocol.Tool{
+		Name:        CreateEventToolName,
+		Description: "Add an event to a calendar. Times are RFC 3339; without an end the event lasts an hour.",
+		InputSchema: map[string]interface{}{
+			"type":     "object",
+			"required": []string{"summary", "start"},
+			"properties": map[string]interface{}{
+				"calendar":    map[string]interface{}{"type": "string", "enum": p.Names(), "description": "The first writable calendar by default"},
+				"summary":     map[string]interface{}{"type": "string", "minLength": 1},
+				"start":       map[string]interface{}{"type": "string", "format": "date-time"},
+				"end":         map[string]interface{}{"type": "string", "format": "date-time"},
+				"location":    map[string]interface{}{"type": "string"},
+				"description": map[string]interface{}{"type": "string"},
+			},
+		},
+		Annotations: &protocol.ToolAnnotations{ReadOnlyHint: &f, DestructiveHint: &f, IdempotentHint: &f},
+	}, func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		var params struct {
+			Calendar    string `json:"calendar"`
+			Summary     string `json:"summary"`
+			Start       string `json:"start"`
+			End         string `json:"end"`
+			Location    string `json:"location"`
+			Description string `json:"description"`
+		}
+		if err := json.Unmarshal(args, &params); err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		ev := Event{Summary: params.Summary, Location: params.Location, Description: params.Description}
+		var err error
+		if ev.Start, err = time.Parse(time.RFC3339, params.Start); err != nil {
+			return errorResult(fmt.Errorf("start %q is not an RFC 3339 time", params.Start)), nil
+		}
+		ev.End = ev.Start.Add(time.Hour)
+		if params.End != "" {
+			if ev.End, err = time.Parse(time.RFC3339, params.End); err != nil || !ev.End.After(ev.Start) {
+				return errorResult(fmt.Errorf("end %q must be an RFC 3339 time after start", params.End)), nil
+			}
+		}
+		if params.Calendar == "" {
+			if params.Calendar = p.defaultWritable(); params.Calendar == "" {
+				return errorResult(fmt.Errorf("no calendar accepts new events")), nil
+			}
+		}
+		created, err := p.Create(ctx, params.Calendar, ev)
+		if err != nil {
+			return errorResult(err), nil
+		}
+		return jsonResult(created)
+	})
+}
+
+// ToolNames returns the names of the tools Register adds
+func (p *Provider) ToolNames() []string {
+	return []string{FindSlotToolName, CreateEventToolName}
+}
+
+// defaultWritable returns the first calendar events can be created in
+func (p *Provider) defaultWritable() string {
+	for _, src := range p.cfg.Calendars {
+		if !src.ReadOnly && !isURL(src.ICS) {
+			return src.Name
+		}
+	}
+	return ""
+}
+
+// searchRange reads the after and before arguments of calendar.find_slot
+func (p *Provider) searchRange(afterArg, beforeArg string) (after, before time.Time, err error) {
+	after = p.now()
+	if afterArg != "" {
+		if after, err = time.Parse(time.RFC3339, afterArg); err != nil {
+			return after, before, fmt.Errorf("after %q is not an RFC 3339 time", afterArg)
+		}
+	}
+	before = after.AddDate(0, 0, defaultSearchDays)
+	if beforeArg != "" {
+		if before, err = time.Parse(time.RFC3339, beforeArg); err != nil {
+			return after, before, fmt.Errorf("before %q is not an RFC 3339 time", beforeArg)
+		}
+	}
+	if !before.After(after) {
+		return after, before, fmt.Errorf("before must be later than after")
+	}
+	if before.Sub(after) > maxSearchDays*24*time.Hour {
+		return after, before, fmt.Errorf("search at most %d days at once", maxSearchDays)
+	}
+	return after, before, nil
+}
+
+// Slot is a free period found by calendar.find_slot
+type Slot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// freeSlots returns up to count slots of length d within work hours in
+// [after, before) that no busy event overlaps, at most one per free
+// period. Starts are aligned to the quarter hour.
+func (p *Provider) freeSlots(events []Event, after, before time.Time, d time.Duration, count int) []Slot {
+	slots := []Slot{}
+	loc := p.cfg.Location
+	day := time.Date(after.In(loc).Year(), after.In(loc).Month(), after.In(loc).Day(), 0, 0, 0, 0, loc)
+	for ; day.Before(before) && len(slots) < count; day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		start := latest(clockTime(day, p.workStart), after)
+		end := earliest(clockTime(day, p.workEnd), before)
+		for start.Add(d).Compare(end) <= 0 && len(slots) < count {
+			start = alignUp(start, day)
+			if start.Add(d).After(end) {
+				break
+			}
+			if busy, ok := firstOverlap(events, start, start.Add(d)); ok {
+				start = busy.End
+				continue
+			}
+			slots = append(slots, Slot{Start: start.In(loc), End: start.Add(d).In(loc)})
+			// Skip the rest of this free period
+			next, ok := nextBusy(events, start)
+			if !ok {
+				start = end
+			} else {
+				start = next.End
+			}
+		}
+	}
+	return slots
+}
+
+// firstOverlap returns the busy event overlapping [start, end) that ends
+// last, so the search can jump past it
+func firstOverlap(events []Event, start, end time.Time) (Event, bool) {
+	var found Event
+	ok := false
+	for _, ev := range events {
+		if ev.Free || !ev.Start.Before(end) || !ev.End.After(start) {
+			continue
+		}
+		if !ok || ev.End.After(found.End) {
+			found, ok = ev, true
+		}
+	}
+	return found, ok
+}
+
+// nextBusy returns the first busy event starting after t
+func nextBusy(events []Event, t time.Time) (Event, bool) {
+	for _, ev := range events {
+		if !ev.Free && ev.Start.After(t) {
+			return ev, true
+		}
+	}
+	return Event{}, false
+}
+
+// clockTime returns the wall clock time offset into day, which differs
+// from day.Add(offset) on days the clocks change
+func clockTime(day time.Time, offset time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, int(offset/time.Minute), 0, 0, day.Location())
+}
+
+// alignUp rounds t up to the next slot boundary of its day
+func alignUp(t, day time.Time) time.Time {
+	offset := t.Sub(day)
+	if rem := offset % slotAlignment; rem != 0 {
+		return t.Add(slotAlignment - rem)
+	}
+	return t
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// parseWorkHours reads HH:MM-HH:MM as offsets from midnight
+func parseWorkHours(s string) (start, end time.Duration, err error) {
+	from, to, ok := strings.Cut(s, "-")
+	if ok {
+		start, err = clockOffset(from)
+	}
+	if ok && err == nil {
+		end, err = clockOffset(to)
+	}
+	if !ok || err != nil || end <= start {
+		return 0, 0, fmt.Errorf("invalid work hours %q; use HH:MM-HH:MM", s)
+	}
+	return start, end, nil
+}
+
+// clockOffset reads HH:MM as an offset from midnight
+func clockOffset(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// sortEvents orders events by start
+func sortEvents(events []Event) {
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+}
+
+// marshalIndent renders v as indented JSON text
+func marshalIndent(v interface{}) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// jsonResult returns v as a tool's JSON text result
+func jsonResult(v interface{}) (protocol.ToolsCallResult, error) {
+	text, err := marshalIndent(v)
+	if err != nil {
+		return protocol.ToolsCallResult{}, err
+	}
+	return protocol.ToolsCallResult{Content: []protocol.Content{protocol.TextContent(text)}}, nil
+}
+
+// errorResult reports a mistake or unavailable calendar to the model so it
+// can adjust its request
+func errorResult(err error) protocol.ToolsCallResult {
+	return protocol.ToolsCallResult{
+		Content: []protocol.Content{protocol.TextContent(err.Error())},
+		IsError: true,
+	}
+}
@@ -0,0 +1,244 @@
+// internal/providers/declarative/declarative.go
+
+// Package declarative serves tools that are defined in data rather than code.
+// Each tool definition is bound to an Executor, a webhook or a local command
+// that receives the call's arguments, so existing function-calling setups
+// can be moved behind MCP without writing a provider.
+package declarative
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/interop"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+)
+
+// maxOutputBytes caps how much executor output becomes the tool result
+const maxOutputBytes = 4 << 20
+
+// Executor runs a declarative tool call
+type Executor interface {
+	Execute(ctx context.Context, tool string, args json.RawMessage) (protocol.ToolsCallResult, error)
+}
+
+// Call is the JSON body a webhook receives
+type Call struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// Webhook retry settings
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 10 * time.Second
+)
+
+// WebhookExecutor POSTs each call to URL as a Call. A response that is a
+// tool result ({"content":[...]}) is returned as is; any other body becomes
+// a single text item.
+//
+// With a Secret, each request carries X-Axe-Timestamp and X-Axe-Signature,
+// "sha256=" and the hex HMAC-SHA256 of "<timestamp>.<body>", so endpoints
+// can verify the caller. Network errors, 429 and 5xx responses are retried
+// up to Retries times with exponential backoff; every attempt carries the
+// same X-Axe-Delivery ID so endpoints can drop duplicates.
+type WebhookExecutor struct {
+	URL     string
+	Headers map[string]string
+	Secret  string
+	Retries int
+	Client  *http.Client
+}
+
+// NewWebhookExecutor creates a webhook executor whose requests time out after timeout
+func NewWebhookExecutor(url string, headers map[string]string, timeout time.Duration) *WebhookExecutor {
+	return &WebhookExecutor{
+		URL:     url,
+		Headers: headers,
+		Client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Execute implements Executor
+func (e *WebhookExecutor) Execute(ctx context.Context, tool string, args json.RawMessage) (protocol.ToolsCallResult, error) {
+	body, err := json.Marshal(Call{Name: tool, Arguments: args})
+	if err != nil {
+		return protocol.ToolsCallResult{}, err
+	}
+	delivery, err := newDeliveryID()
+	if err != nil {
+		return protocol.ToolsCallResult{}, err
+	}
+
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		data, retry, err := e.post(ctx, body, delivery)
+		if err == nil {
+			return toResult(data), nil
+		}
+		if !retry || attempt >= e.Retries {
+			return protocol.ToolsCallResult{}, err
+		}
+
+		slog.Warn("Retrying tool webhook", "tool", tool, "attempt", attempt+1, "error", err)
+		select {
+		case <-ctx.Done():
+			return protocol.ToolsCallResult{}, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, retryMaxDelay)
+	}
+}
+
+// post makes one delivery attempt and reports whether a failure is worth retrying
+func (e *WebhookExecutor) post(ctx context.Context, body []byte, delivery string) ([]byte, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Axe-Delivery", delivery)
+	for k, v := range e.Headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+	if e.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("X-Axe-Timestamp", timestamp)
+		req.Header.Set("X-Axe-Signature", Sign(e.Secret, timestamp, body))
+	}
+
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return nil, ctx.Err() == nil, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxOutputBytes))
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to read webhook response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return nil, retry, fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, false, nil
+}
+
+// Sign returns the X-Axe-Signature value for body sent at timestamp
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newDeliveryID returns a random ID shared by all attempts of one call
+func newDeliveryID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// CommandExecutor runs Command for each call with the arguments as JSON on
+// stdin and the tool name in AXE_TOOL_NAME. Standard output becomes the
+// result, decoded like a webhook response; a non-zero exit is an error that
+// carries standard error.
+type CommandExecutor struct {
+	Command []string
+	Dir     string
+}
+
+// Execute implements Executor
+func (e *CommandExecutor) Execute(ctx context.Context, tool string, args json.RawMessage) (protocol.ToolsCallResult, error) {
+	if len(e.Command) == 0 {
+		return protocol.ToolsCallResult{}, fmt.Errorf("no command configured")
+	}
+
+	cmd := exec.CommandContext(ctx, e.Command[0], e.Command[1:]...)
+	cmd.Dir = e.Dir
+	cmd.Env = append(os.Environ(), "AXE_TOOL_NAME="+tool)
+	cmd.Stdin = bytes.NewReader(args)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &limitedBuffer{buf: &stdout, remaining: maxOutputBytes}
+	cmd.Stderr = &limitedBuffer{buf: &stderr, remaining: 64 << 10}
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return protocol.ToolsCallResult{}, fmt.Errorf("%w: %s", err, msg)
+		}
+		return protocol.ToolsCallResult{}, err
+	}
+	return toResult(stdout.Bytes()), nil
+}
+
+// limitedBuffer keeps the first remaining bytes written and discards the rest
+type limitedBuffer struct {
+	buf       *bytes.Buffer
+	remaining int
+}
+
+// Write implements io.Writer; it never fails so the command is not killed by a full pipe
+func (w *limitedBuffer) Write(p []byte) (int, error) {
+	if w.remaining > 0 {
+		n := min(len(p), w.remaining)
+		w.buf.Write(p[:n])
+		w.remaining -= n
+	}
+	return len(p), nil
+}
+
+// toResult interprets executor output as a tool result or plain text
+func toResult(data []byte) protocol.ToolsCallResult {
+	var result protocol.ToolsCallResult
+	if err := json.Unmarshal(data, &result); err == nil && result.Content != nil {
+		return result
+	}
+	return protocol.ToolsCallResult{
+		Content: []protocol.Content{protocol.TextContent(string(data))},
+	}
+}
+
+// Register adds tools to m, each executed by exec
+func Register(m *manager.ToolsManager, tools []protocol.Tool, exec Executor) {
+	for _, tool := range tools {
+		name := tool.Name
+		m.RegisterTool(tool, func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+			if len(args) == 0 {
+				args = json.RawMessage("{}")
+			}
+			return exec.Execute(ctx, name, args)
+		})
+	}
+}
+
+// LoadOpenAIFile reads OpenAI function definitions from path
+func LoadOpenAIFile(path string) ([]protocol.Tool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tools, err := interop.ParseOpenAI(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return tools, nil
+}
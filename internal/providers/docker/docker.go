@@ -0,0 +1,320 @@
+// internal/providers/docker/docker.go
+
+// Package docker lets assistants look at the containers of a local Docker
+// or Podman engine. It talks to the engine's HTTP API over its unix socket,
+// which Podman serves in Docker-compatible form, and offers the docker.ps,
+// docker.logs and docker.inspect tools. Each running container is listed as
+// a docker://<name> resource whose content is its inspect document.
+//
+// Tools that start, stop or restart containers are only served when the
+// configuration asks for them, and are annotated as destructive so clients
+// confirm them with their user.
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+)
+
+// Provider defaults
+const (
+	DefaultSocket   = "/var/run/docker.sock"
+	DefaultLogLines = 200
+	defaultTimeout  = 30 * time.Second
+	maxResponseSize = 8 << 20
+	uriScheme       = "docker://"
+)
+
+// Config configures a Provider
+type Config struct {
+	Socket    string        // unix socket of the engine API; may reference environment variables as $VAR
+	Lifecycle bool          // also serve docker.start, docker.stop and docker.restart
+	LogLines  int           // lines docker.logs returns when the caller does not say
+	Timeout   time.Duration // per API request
+}
+
+// Provider serves the containers of one engine
+type Provider struct {
+	cfg    Config
+	client *http.Client
+}
+
+// Ensure Provider implements resources.Provider
+var _ resources.Provider = (*Provider)(nil)
+
+// New creates a provider for the engine listening on cfg.Socket
+func New(cfg Config) *Provider {
+	if cfg.Socket == "" {
+		cfg.Socket = DefaultSocket
+	}
+	cfg.Socket = strings.TrimPrefix(os.ExpandEnv(cfg.Socket), "unix://")
+	if cfg.LogLines <= 0 {
+		cfg.LogLines = DefaultLogLines
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	socket := cfg.Socket
+	dialer := net.Dialer{}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socket)
+		},
+	}
+	return &Provider{cfg: cfg, client: &http.Client{Transport: transport, Timeout: cfg.Timeout}}
+}
+
+// Ping reports whether the engine answers on the socket
+func (p *Provider) Ping(ctx context.Context) error {
+	_, err := p.do(ctx, http.MethodGet, "/_ping", nil)
+	return err
+}
+
+// Container is the summary of a container returned by docker.ps
+type Container struct {
+	ID      string   `json:"id"`
+	Names   []string `json:"names"`
+	Image   string   `json:"image"`
+	State   string   `json:"state"`
+	Status  string   `json:"status"`
+	Created string   `json:"created"`
+}
+
+// Name returns the container's primary name, or its ID if it has none
+func (c Container) Name() string {
+	if len(c.Names) == 0 {
+		return c.ID
+	}
+	return c.Names[0]
+}
+
+// Containers lists running containers, or all of them when all is set
+func (p *Provider) Containers(ctx context.Context, all bool) ([]Container, error) {
+	query := url.Values{}
+	if all {
+		query.Set("all", "true")
+	}
+	data, err := p.do(ctx, http.MethodGet, "/containers/json", query)
+	if err != nil {
+		return nil, err
+	}
+	var raw []struct {
+		ID      string   `json:"Id"`
+		Names   []string `json:"Names"`
+		Image   string   `json:"Image"`
+		State   string   `json:"State"`
+		Status  string   `json:"Status"`
+		Created int64    `json:"Created"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid container list: %w", err)
+	}
+	containers := make([]Container, 0, len(raw))
+	for _, c := range raw {
+		names := make([]string, len(c.Names))
+		for i, n := range c.Names {
+			names[i] = strings.TrimPrefix(n, "/")
+		}
+		containers = append(containers, Container{
+			ID:      shortID(c.ID),
+			Names:   names,
+			Image:   c.Image,
+			State:   c.State,
+			Status:  c.Status,
+			Created: time.Unix(c.Created, 0).UTC().Format(time.RFC3339),
+		})
+	}
+	return containers, nil
+}
+
+// Inspect returns the engine's inspect document for a container, by name
+// or ID
+func (p *Provider) Inspect(ctx context.Context, container string) (json.RawMessage, error) {
+	if err := checkContainer(container); err != nil {
+		return nil, err
+	}
+	return p.do(ctx, http.MethodGet, "/containers/"+url.PathEscape(container)+"/json", nil)
+}
+
+// Logs returns the last lines of a container's output, interleaving
+// stdout and stderr. since, if set, is a duration back from now or an
+// RFC 3339 time.
+func (p *Provider) Logs(ctx context.Context, container string, lines int, since string, timestamps bool) (string, error) {
+	if err := checkContainer(container); err != nil {
+		return "", err
+	}
+	if lines <= 0 {
+		lines = p.cfg.LogLines
+	}
+	query := url.Values{
+		"stdout": {"true"},
+		"stderr": {"true"},
+		"tail":   {fmt.Sprint(lines)},
+	}
+	if since != "" {
+		from, err := parseSince(since)
+		if err != nil {
+			return "", err
+		}
+		query.Set("since", fmt.Sprint(from.Unix()))
+	}
+	if timestamps {
+		query.Set("timestamps", "true")
+	}
+	data, err := p.do(ctx, http.MethodGet, "/containers/"+url.PathEscape(container)+"/logs", query)
+	if err != nil {
+		return "", err
+	}
+	return demux(data), nil
+}
+
+// ListResources returns one resource per running container
+func (p *Provider) ListResources() ([]resources.Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+	defer cancel()
+	containers, err := p.Containers(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]resources.Resource, 0, len(containers))
+	for _, c := range containers {
+		list = append(list, resources.Resource{
+			URI:         uriScheme + c.Name(),
+			Name:        c.Name(),
+			Description: fmt.Sprintf("Container %s running %s (%s)", c.Name(), c.Image, c.Status),
+			MimeType:    "application/json",
+		})
+	}
+	return list, nil
+}
+
+// GetResource returns a container's inspect document
+func (p *Provider) GetResource(uri string) (interface{}, error) {
+	name, ok := strings.CutPrefix(uri, uriScheme)
+	if !ok || checkContainer(name) != nil {
+		return nil, resources.ErrResourceNotFound
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+	defer cancel()
+	data, err := p.Inspect(ctx, name)
+	if err != nil {
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.status == http.StatusNotFound {
+			return nil, resources.ErrResourceNotFound
+		}
+		return nil, err
+	}
+	return indent(data), nil
+}
+
+// apiError is an error response from the engine, or a request it would
+// reject
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return "docker: " + e.message
+}
+
+// do sends one API request and returns the response body
+func (p *Provider) do(ctx context.Context, method, path string, query url.Values) ([]byte, error) {
+	target := "http://docker" + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot reach the container engine at %s: %w", p.cfg.Socket, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusNotModified {
+		var body struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &body) != nil || body.Message == "" {
+			body.Message = resp.Status
+		}
+		return nil, &apiError{status: resp.StatusCode, message: body.Message}
+	}
+	return data, nil
+}
+
+// demux strips the frame headers the engine adds when a container's
+// stdout and stderr share one stream. Output of containers with a TTY is
+// not framed and is returned as is.
+func demux(data []byte) string {
+	var out bytes.Buffer
+	rest := data
+	for len(rest) > 0 {
+		if len(rest) < 8 || rest[0] > 2 || rest[1] != 0 || rest[2] != 0 || rest[3] != 0 {
+			return string(data)
+		}
+		size := int(rest[4])<<24 | int(rest[5])<<16 | int(rest[6])<<8 | int(rest[7])
+		if len(rest) < 8+size {
+			return string(data)
+		}
+		out.Write(rest[8 : 8+size])
+		rest = rest[8+size:]
+	}
+	return out.String()
+}
+
+// parseSince reads a duration back from now or an RFC 3339 time
+func parseSince(since string) (time.Time, error) {
+	if d, err := time.ParseDuration(since); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, &apiError{status: http.StatusBadRequest, message: fmt.Sprintf("since %q is neither a duration like 10m nor an RFC 3339 time", since)}
+	}
+	return t, nil
+}
+
+// checkContainer rejects names that could not be a container name or ID
+func checkContainer(container string) error {
+	if container == "" || strings.ContainsAny(container, "/?#") {
+		return &apiError{status: http.StatusBadRequest, message: fmt.Sprintf("invalid container name %q", container)}
+	}
+	return nil
+}
+
+// shortID abbreviates a container ID the way the docker CLI does
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// indent pretty-prints a JSON document, or returns it as is if it is not
+// valid JSON
+func indent(data []byte) string {
+	var pretty bytes.Buffer
+	if json.Indent(&pretty, data, "", "  ") != nil {
+		return string(data)
+	}
+	return pretty.String()
+}
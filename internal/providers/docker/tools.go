@@ -0,0 +1,220 @@
+// internal/providers/docker/tools.go
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+)
+
+// Tool names
+const (
+	PsToolName      = "docker.ps"
+	LogsToolName    = "docker.logs"
+	InspectToolName = "docker.inspect"
+	StartToolName   = "docker.start"
+	StopToolName    = "docker.stop"
+	RestartToolName = "docker.restart"
+)
+
+// containerProperty is the schema of the container argument shared by
+// every tool that acts on one container
+var containerProperty = map[string]interface{}{
+	"type":        "string",
+	"description": "Container name or ID, as listed by docker.ps",
+}
+
+// containerSchema takes just a container
+var containerSchema = map[string]interface{}{
+	"type":       "object",
+	"required":   []string{"container"},
+	"properties": map[string]interface{}{"container": containerProperty},
+}
+
+// UsageHint implements provider.Hinter
+func (p *Provider) UsageHint() string {
+	return "Running containers are docker:// resources. Start with " + PsToolName +
+		", then " + LogsToolName + " or " + InspectToolName + " for the container in question."
+}
+
+// Register adds the provider's tools to m: the read-only ones always, the
+// lifecycle ones when the configuration enables them
+func (p *Provider) Register(m *manager.ToolsManager) {
+	m.RegisterTool(protocol.Tool{
+		Name:        PsToolName,
+		Description: "List containers with their image, state and status. Only running containers unless all is set.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"all": map[string]interface{}{"type": "boolean", "description": "Include stopped containers"},
+			},
+		},
+		Annotations: protocol.ReadOnly(),
+	}, func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		var params struct {
+			All bool `json:"all"`
+		}
+		if err := unmarshalArgs(args, &params); err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		containers, err := p.Containers(ctx, params.All)
+		if err != nil {
+			return toolResult(nil, err)
+		}
+		data, err := json.MarshalIndent(containers, "", "  ")
+		return toolResult(data, err)
+	})
+
+	m.RegisterTool(protocol.Tool{
+		Name:        LogsToolName,
+		Description: "Read the most recent output of a container, stdout and stderr interleaved.",
+		InputSchema: map[string]interface{}{
+			"type":     "object",
+			"required": []string{"container"},
+			"properties": map[string]interface{}{
+				"container":  containerProperty,
+				"lines":      map[string]interface{}{"type": "integer", "minimum": 1, "description": fmt.Sprintf("Lines from the end; %d by default", p.cfg.LogLines)},
+				"since":      map[string]interface{}{"type": "string", "description": "Only output after this: a duration back from now such as 10m, or an RFC 3339 time"},
+				"timestamps": map[string]interface{}{"type": "boolean", "description": "Prefix each line with its time"},
+			},
+		},
+		Annotations: protocol.ReadOnly(),
+	}, func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		var params struct {
+			Container  string `json:"container"`
+			Lines      int    `json:"lines"`
+			Since      string `json:"since"`
+			Timestamps bool   `json:"timestamps"`
+		}
+		if err := unmarshalArgs(args, &params); err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		logs, err := p.Logs(ctx, params.Container, params.Lines, params.Since, params.Timestamps)
+		return toolResult([]byte(logs), err)
+	})
+
+	m.RegisterTool(protocol.Tool{
+		Name:        InspectToolName,
+		Description: "Show a container's full configuration and state: command, environment, mounts, networks and health.",
+		InputSchema: containerSchema,
+		Annotations: protocol.ReadOnly(),
+	}, func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		var params struct {
+			Container string `json:"container"`
+		}
+		if err := unmarshalArgs(args, &params); err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		data, err := p.Inspect(ctx, params.Container)
+		if err != nil {
+			return toolResult(nil, err)
+		}
+		return toolResult([]byte(indent(data)), nil)
+	})
+
+	if p.cfg.Lifecycle {
+		p.registerLifecycle(m)
+	}
+}
+
+// ToolNames returns the names of the tools Register adds
+func (p *Provider) ToolNames() []string {
+	names := []string{PsToolName, LogsToolName, InspectToolName}
+	if p.cfg.Lifecycle {
+		names = append(names, StartToolName, StopToolName, RestartToolName)
+	}
+	return names
+}
+
+// registerLifecycle adds the tools that change a container's state.
+// Starting or stopping an already started or stopped container does
+// nothing, so those two are idempotent; restart is not.
+func (p *Provider) registerLifecycle(m *manager.ToolsManager) {
+	for _, action := range []struct {
+		name        string
+		verb        string
+		description string
+		idempotent  bool
+	}{
+		{StartToolName, "start", "Start a stopped container.", true},
+		{StopToolName, "stop", "Stop a running container, killing it if it has not exited after timeout seconds.", true},
+		{RestartToolName, "restart", "Stop and start a container again.", false},
+	} {
+		verb := action.verb
+		schema := containerSchema
+		if verb != "start" {
+			schema = map[string]interface{}{
+				"type":     "object",
+				"required": []string{"container"},
+				"properties": map[string]interface{}{
+					"container": containerProperty,
+					"timeout":   map[string]interface{}{"type": "integer", "minimum": 0, "description": "Seconds to wait for the container to exit before killing it; the engine's default if unset"},
+				},
+			}
+		}
+		m.RegisterTool(protocol.Tool{
+			Name:        action.name,
+			Description: action.description,
+			InputSchema: schema,
+			Annotations: protocol.Destructive(action.idempotent),
+		}, func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+			var params struct {
+				Container string `json:"container"`
+				Timeout   *int   `json:"timeout"`
+			}
+			if err := unmarshalArgs(args, &params); err != nil {
+				return protocol.ToolsCallResult{}, err
+			}
+			if err := p.lifecycle(ctx, verb, params.Container, params.Timeout); err != nil {
+				return toolResult(nil, err)
+			}
+			return toolResult([]byte(fmt.Sprintf("%s: %s done", params.Container, verb)), nil)
+		})
+	}
+}
+
+// lifecycle sends a start, stop or restart request for a container
+func (p *Provider) lifecycle(ctx context.Context, verb, container string, timeout *int) error {
+	if err := checkContainer(container); err != nil {
+		return err
+	}
+	query := url.Values{}
+	if timeout != nil {
+		query.Set("t", fmt.Sprint(*timeout))
+	}
+	_, err := p.do(ctx, http.MethodPost, "/containers/"+url.PathEscape(container)+"/"+verb, query)
+	return err
+}
+
+// unmarshalArgs decodes tool arguments, which clients may omit entirely
+func unmarshalArgs(args json.RawMessage, v interface{}) error {
+	if len(args) == 0 {
+		return nil
+	}
+	return json.Unmarshal(args, v)
+}
+
+// toolResult turns a tool's output into a result. Engine errors such as
+// an unknown container are the model's to fix and are reported to it;
+// failing to reach the engine is an error of the call.
+func toolResult(data []byte, err error) (protocol.ToolsCallResult, error) {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return protocol.ToolsCallResult{
+			Content: []protocol.Content{protocol.TextContent(apiErr.Error())},
+			IsError: true,
+		}, nil
+	}
+	if err != nil {
+		return protocol.ToolsCallResult{}, err
+	}
+	return protocol.ToolsCallResult{
+		Content: []protocol.Content{protocol.TextContent(string(data))},
+	}, nil
+}
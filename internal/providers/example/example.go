@@ -3,6 +3,7 @@ package example
 
 import (
 	"github.com/dkoosis/axe-handle/internal/mcp/prompts"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
 	"github.com/dkoosis/axe-handle/internal/mcp/resources"
 	"github.com/dkoosis/axe-handle/internal/mcp/tools"
 )
@@ -96,6 +97,10 @@ func (p *Provider) ListPrompts() ([]prompts.Prompt, error) {
 				},
 			},
 		},
+		{
+			Name:        "summarize-hello",
+			Description: "Asks for a summary of the hello resource",
+		},
 	}, nil
 }
 
@@ -106,15 +111,21 @@ func (p *Provider) GetPrompt(name string, args map[string]string) (interface{},
 		if !ok {
 			recipientName = "World"
 		}
-		return map[string]interface{}{
-			"messages": []map[string]interface{}{
-				{
-					"role": "user",
-					"content": map[string]interface{}{
-						"type": "text",
-						"text": "Hello, " + recipientName + "! How are you today?",
-					},
-				},
+		return protocol.PromptsGetResult{
+			Messages: []protocol.PromptMessage{
+				protocol.UserMessage(protocol.TextContent("Hello, " + recipientName + "! How are you today?")),
+				protocol.AssistantMessage(protocol.TextContent("I'm doing well, thanks for asking!")),
+				protocol.UserMessage(protocol.TextContent("Glad to hear it. Can you help me with something?")),
+			},
+		}, nil
+	}
+	if name == "summarize-hello" {
+		// The resource is embedded by URI; the server fills in its content
+		return protocol.PromptsGetResult{
+			Description: "Summarize the hello resource",
+			Messages: []protocol.PromptMessage{
+				protocol.UserMessage(protocol.TextContent("Please summarize this resource.")),
+				protocol.UserMessage(protocol.ResourceRef("example://hello")),
 			},
 		}, nil
 	}
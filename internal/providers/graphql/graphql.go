@@ -0,0 +1,487 @@
+// internal/providers/graphql/graphql.go
+
+// Package graphql exposes selected queries and mutations of a GraphQL API as
+// tools. The endpoint is introspected once at startup: each whitelisted root
+// field becomes a tool whose input schema is derived from the field's
+// arguments, and the introspected schema is served as a resource.
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+)
+
+// Provider defaults
+const (
+	DefaultDepth    = 2
+	defaultTimeout  = 30 * time.Second
+	maxResponseSize = 8 << 20
+	maxInputDepth   = 5 // nesting of input objects expanded in tool schemas
+)
+
+// Config configures one GraphQL endpoint
+type Config struct {
+	Name      string            // prefixes tool names and names the schema resource
+	Endpoint  string            // GraphQL HTTP endpoint
+	Headers   map[string]string // sent with every request; values may reference environment variables as $VAR
+	Queries   []string          // query fields exposed as tools
+	Mutations []string          // mutation fields exposed as tools
+	Depth     int               // levels of object fields selected from results
+	Timeout   time.Duration     // per request
+}
+
+// Provider serves the tools and schema of one GraphQL endpoint
+type Provider struct {
+	cfg    Config
+	client *http.Client
+	schema json.RawMessage
+	types  map[string]*fullType
+	tools  []tool
+}
+
+// tool is a root field exposed as a tool
+type tool struct {
+	def      protocol.Tool
+	document string // the operation sent for each call
+}
+
+// Ensure Provider implements resources.Provider
+var _ resources.Provider = (*Provider)(nil)
+
+// New creates a provider for cfg. Call Load before registering it.
+func New(cfg Config) *Provider {
+	if cfg.Depth <= 0 {
+		cfg.Depth = DefaultDepth
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	return &Provider{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}
+}
+
+// SchemaURI is the URI of the endpoint's introspected schema
+func (p *Provider) SchemaURI() string {
+	return "graphql://" + p.cfg.Name + "/schema"
+}
+
+// Load introspects the endpoint and builds a tool for each whitelisted
+// field. A field the schema does not have is an error.
+func (p *Provider) Load(ctx context.Context) error {
+	data, err := p.do(ctx, introspectionQuery, nil)
+	if err != nil {
+		return fmt.Errorf("introspection failed: %w", err)
+	}
+	var result struct {
+		Schema introspectionSchema `json:"__schema"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("invalid introspection result: %w", err)
+	}
+
+	p.schema = data
+	p.types = make(map[string]*fullType, len(result.Schema.Types))
+	for i := range result.Schema.Types {
+		t := &result.Schema.Types[i]
+		p.types[t.Name] = t
+	}
+
+	p.tools = nil
+	if err := p.addRoot("query", result.Schema.QueryType, p.cfg.Queries); err != nil {
+		return err
+	}
+	return p.addRoot("mutation", result.Schema.MutationType, p.cfg.Mutations)
+}
+
+// addRoot builds tools for the named fields of a root operation type
+func (p *Provider) addRoot(operation string, root *namedType, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	if root == nil || p.types[root.Name] == nil {
+		return fmt.Errorf("schema has no %s type", operation)
+	}
+	fields := make(map[string]*field)
+	for i := range p.types[root.Name].Fields {
+		f := &p.types[root.Name].Fields[i]
+		fields[f.Name] = f
+	}
+
+	for _, name := range names {
+		f, ok := fields[name]
+		if !ok {
+			return fmt.Errorf("schema has no %s field %q", operation, name)
+		}
+		description := f.Description
+		if description == "" {
+			description = fmt.Sprintf("GraphQL %s %s", operation, name)
+		}
+		p.tools = append(p.tools, tool{
+			def: protocol.Tool{
+				Name:        p.cfg.Name + "_" + name,
+				Description: description,
+				InputSchema: p.argsSchema(f.Args),
+			},
+			document: p.document(operation, f),
+		})
+	}
+	return nil
+}
+
+// Register adds the provider's tools to m
+func (p *Provider) Register(m *manager.ToolsManager) {
+	for _, t := range p.tools {
+		document := t.document
+		m.RegisterTool(t.def, func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+			var variables map[string]interface{}
+			if len(args) > 0 {
+				if err := json.Unmarshal(args, &variables); err != nil {
+					return protocol.ToolsCallResult{}, err
+				}
+			}
+			data, err := p.do(ctx, document, variables)
+			if err != nil {
+				var gqlErr *responseError
+				if errors.As(err, &gqlErr) {
+					// Field errors are the model's to fix, not transport failures
+					return protocol.ToolsCallResult{
+						Content: []protocol.Content{protocol.TextContent(gqlErr.Error())},
+						IsError: true,
+					}, nil
+				}
+				return protocol.ToolsCallResult{}, err
+			}
+			var pretty bytes.Buffer
+			if json.Indent(&pretty, data, "", "  ") != nil {
+				pretty.Reset()
+				pretty.Write(data)
+			}
+			return protocol.ToolsCallResult{
+				Content: []protocol.Content{protocol.TextContent(pretty.String())},
+			}, nil
+		})
+	}
+}
+
+// ToolNames returns the names of the tools built by Load
+func (p *Provider) ToolNames() []string {
+	names := make([]string, 0, len(p.tools))
+	for _, t := range p.tools {
+		names = append(names, t.def.Name)
+	}
+	return names
+}
+
+// ListResources returns the schema resource
+func (p *Provider) ListResources() ([]resources.Resource, error) {
+	return []resources.Resource{
+		{
+			URI:         p.SchemaURI(),
+			Name:        p.cfg.Name + " GraphQL schema",
+			Description: "Introspection result for " + p.cfg.Endpoint,
+			MimeType:    "application/json",
+		},
+	}, nil
+}
+
+// GetResource returns the introspected schema
+func (p *Provider) GetResource(uri string) (interface{}, error) {
+	if uri != p.SchemaURI() || p.schema == nil {
+		return nil, resources.ErrResourceNotFound
+	}
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, p.schema, "", "  "); err != nil {
+		return nil, err
+	}
+	return pretty.String(), nil
+}
+
+// responseError carries the errors a GraphQL response reported
+type responseError struct {
+	messages []string
+}
+
+func (e *responseError) Error() string {
+	return "graphql: " + strings.Join(e.messages, "; ")
+}
+
+// do sends one operation and returns its data
+func (p *Provider) do(ctx context.Context, document string, variables map[string]interface{}) (json.RawMessage, error) {
+	body, err := json.Marshal(map[string]interface{}{"query": document, "variables": variables})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for k, v := range p.cfg.Headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		if resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("graphql endpoint returned %s", resp.Status)
+		}
+		return nil, fmt.Errorf("invalid graphql response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		messages := make([]string, 0, len(result.Errors))
+		for _, e := range result.Errors {
+			messages = append(messages, e.Message)
+		}
+		return nil, &responseError{messages: messages}
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("graphql endpoint returned %s", resp.Status)
+	}
+	return result.Data, nil
+}
+
+// document builds the operation for a root field: every argument becomes a
+// variable and the selection covers scalar fields to the configured depth
+func (p *Provider) document(operation string, f *field) string {
+	var b strings.Builder
+	b.WriteString(operation)
+	if len(f.Args) > 0 {
+		vars := make([]string, 0, len(f.Args))
+		for _, a := range f.Args {
+			vars = append(vars, "$"+a.Name+": "+a.Type.String())
+		}
+		b.WriteString("(" + strings.Join(vars, ", ") + ")")
+	}
+	b.WriteString(" { " + f.Name)
+	if len(f.Args) > 0 {
+		args := make([]string, 0, len(f.Args))
+		for _, a := range f.Args {
+			args = append(args, a.Name+": $"+a.Name)
+		}
+		b.WriteString("(" + strings.Join(args, ", ") + ")")
+	}
+	b.WriteString(p.selection(f.Type.named(), p.cfg.Depth))
+	b.WriteString(" }")
+	return b.String()
+}
+
+// selection returns the selection set for a type, or "" for leaf types
+func (p *Provider) selection(name string, depth int) string {
+	t := p.types[name]
+	if t == nil {
+		return ""
+	}
+	switch t.Kind {
+	case "OBJECT", "INTERFACE":
+	case "UNION":
+		return " { __typename }"
+	default:
+		return ""
+	}
+
+	parts := []string{"__typename"}
+	for _, f := range t.Fields {
+		if hasRequiredArgs(f.Args) {
+			continue
+		}
+		named := p.types[f.Type.named()]
+		switch {
+		case named == nil || named.Kind == "SCALAR" || named.Kind == "ENUM":
+			parts = append(parts, f.Name)
+		case depth > 1:
+			parts = append(parts, f.Name+p.selection(named.Name, depth-1))
+		}
+	}
+	return " { " + strings.Join(parts, " ") + " }"
+}
+
+// hasRequiredArgs reports whether a field cannot be selected without arguments
+func hasRequiredArgs(args []inputValue) bool {
+	for _, a := range args {
+		if a.Type.Kind == "NON_NULL" && a.DefaultValue == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// argsSchema derives the JSON Schema for a field's arguments
+func (p *Provider) argsSchema(args []inputValue) map[string]interface{} {
+	return p.objectSchema(args, maxInputDepth)
+}
+
+// objectSchema derives an object schema from input values
+func (p *Provider) objectSchema(values []inputValue, depth int) map[string]interface{} {
+	properties := make(map[string]interface{}, len(values))
+	var required []string
+	for _, v := range values {
+		s := p.typeSchema(v.Type, depth)
+		if v.Description != "" {
+			s["description"] = v.Description
+		}
+		properties[v.Name] = s
+		if v.Type.Kind == "NON_NULL" && v.DefaultValue == nil {
+			required = append(required, v.Name)
+		}
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+// typeSchema derives the JSON Schema for an input type reference
+func (p *Provider) typeSchema(ref typeRef, depth int) map[string]interface{} {
+	switch ref.Kind {
+	case "NON_NULL":
+		if ref.OfType != nil {
+			return p.typeSchema(*ref.OfType, depth)
+		}
+	case "LIST":
+		items := map[string]interface{}{}
+		if ref.OfType != nil {
+			items = p.typeSchema(*ref.OfType, depth)
+		}
+		return map[string]interface{}{"type": "array", "items": items}
+	case "SCALAR":
+		switch ref.Name {
+		case "Int":
+			return map[string]interface{}{"type": "integer"}
+		case "Float":
+			return map[string]interface{}{"type": "number"}
+		case "Boolean":
+			return map[string]interface{}{"type": "boolean"}
+		case "String", "ID":
+			return map[string]interface{}{"type": "string"}
+		}
+		// Custom scalars accept whatever the server does
+		return map[string]interface{}{"description": "GraphQL scalar " + ref.Name}
+	case "ENUM":
+		if t := p.types[ref.Name]; t != nil {
+			values := make([]string, 0, len(t.EnumValues))
+			for _, v := range t.EnumValues {
+				values = append(values, v.Name)
+			}
+			return map[string]interface{}{"type": "string", "enum": values}
+		}
+	case "INPUT_OBJECT":
+		if t := p.types[ref.Name]; t != nil && depth > 0 {
+			return p.objectSchema(t.InputFields, depth-1)
+		}
+		return map[string]interface{}{"type": "object"}
+	}
+	return map[string]interface{}{}
+}
+
+// introspectionSchema is the part of __schema the provider reads
+type introspectionSchema struct {
+	QueryType    *namedType `json:"queryType"`
+	MutationType *namedType `json:"mutationType"`
+	Types        []fullType `json:"types"`
+}
+
+type namedType struct {
+	Name string `json:"name"`
+}
+
+type fullType struct {
+	Kind        string       `json:"kind"`
+	Name        string       `json:"name"`
+	Fields      []field      `json:"fields"`
+	InputFields []inputValue `json:"inputFields"`
+	EnumValues  []struct {
+		Name string `json:"name"`
+	} `json:"enumValues"`
+}
+
+type field struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Args        []inputValue `json:"args"`
+	Type        typeRef      `json:"type"`
+}
+
+type inputValue struct {
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	Type         typeRef `json:"type"`
+	DefaultValue *string `json:"defaultValue"`
+}
+
+type typeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *typeRef `json:"ofType"`
+}
+
+// named returns the underlying named type of a reference
+func (r typeRef) named() string {
+	if r.OfType != nil && (r.Kind == "NON_NULL" || r.Kind == "LIST") {
+		return r.OfType.named()
+	}
+	return r.Name
+}
+
+// String prints the reference in GraphQL syntax, as variable definitions need
+func (r typeRef) String() string {
+	switch r.Kind {
+	case "NON_NULL":
+		if r.OfType != nil {
+			return r.OfType.String() + "!"
+		}
+	case "LIST":
+		if r.OfType != nil {
+			return "[" + r.OfType.String() + "]"
+		}
+	}
+	return r.Name
+}
+
+// introspectionQuery fetches what the provider needs to build tools
+const introspectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    types {
+      kind name description
+      fields(includeDeprecated: false) { name description args { ...InputValue } type { ...TypeRef } }
+      inputFields { ...InputValue }
+      enumValues(includeDeprecated: false) { name description }
+    }
+  }
+}
+fragment InputValue on __InputValue { name description type { ...TypeRef } defaultValue }
+fragment TypeRef on __Type {
+  kind name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } }
+}`
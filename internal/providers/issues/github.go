@@ -0,0 +1,182 @@
+// internal/providers/issues/github.go
+package issues
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const githubAPI = "https://api.github.com"
+
+// github adapts the GitHub REST API for one repository
+type github struct {
+	api
+	repo string // owner/repo
+}
+
+func newGitHub(t Tracker, client *http.Client) *github {
+	base := strings.TrimSuffix(t.URL, "/")
+	if base == "" {
+		base = githubAPI
+	}
+	token := t.Token
+	return &github{
+		api: api{name: "GitHub", base: base, client: client, auth: func(req *http.Request) {
+			req.Header.Set("Accept", "application/vnd.github+json")
+			req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+		}},
+		repo: t.Project,
+	}
+}
+
+// githubIssue is an issue or pull request in GitHub's API
+type githubIssue struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	State       string    `json:"state"`
+	Body        string    `json:"body"`
+	HTMLURL     string    `json:"html_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	User        struct{ Login string }
+	Labels      []struct{ Name string }
+	PullRequest *struct {
+		MergedAt *time.Time `json:"merged_at"`
+	} `json:"pull_request"`
+}
+
+func (g githubIssue) issue() Issue {
+	issue := Issue{
+		ID:      strconv.Itoa(g.Number),
+		Kind:    "issue",
+		Title:   g.Title,
+		State:   g.State,
+		Author:  g.User.Login,
+		Created: g.CreatedAt,
+		Updated: g.UpdatedAt,
+		URL:     g.HTMLURL,
+		Body:    g.Body,
+	}
+	for _, l := range g.Labels {
+		issue.Labels = append(issue.Labels, l.Name)
+	}
+	if g.PullRequest != nil {
+		issue.Kind = "pull request"
+		if g.PullRequest.MergedAt != nil {
+			issue.State = StateMerged
+		}
+	}
+	return issue
+}
+
+// Search lists the repository's issues, or uses the search API for a query
+func (g *github) Search(ctx context.Context, query, state string, limit int) ([]Issue, error) {
+	var found []githubIssue
+	if query == "" {
+		listState := state
+		if state == StateMerged {
+			listState = StateClosed
+		}
+		params := url.Values{"state": {listState}, "sort": {"updated"}, "per_page": {strconv.Itoa(min(limit, 100))}}
+		if err := g.do(ctx, http.MethodGet, "/repos/"+g.repo+"/issues", params, nil, &found); err != nil {
+			return nil, err
+		}
+	} else {
+		q := "repo:" + g.repo + " " + query
+		switch state {
+		case StateOpen, StateClosed:
+			q += " state:" + state
+		case StateMerged:
+			q += " is:merged"
+		}
+		params := url.Values{"q": {q}, "sort": {"updated"}, "per_page": {strconv.Itoa(min(limit, 100))}}
+		var resp struct {
+			Items []githubIssue `json:"items"`
+		}
+		if err := g.do(ctx, http.MethodGet, "/search/issues", params, nil, &resp); err != nil {
+			return nil, err
+		}
+		found = resp.Items
+	}
+	list := make([]Issue, 0, len(found))
+	for _, gi := range found {
+		if issue := gi.issue(); state != StateMerged || issue.State == StateMerged {
+			list = append(list, issue)
+		}
+	}
+	return list, nil
+}
+
+// githubNumber matches the IDs of GitHub issues and pull requests
+var githubNumber = regexp.MustCompile(`^#?[0-9]+$`)
+
+// Get returns an issue or pull request with its comments
+func (g *github) Get(ctx context.Context, id string) (*Issue, error) {
+	if !githubNumber.MatchString(id) {
+		return nil, invalidID(id, "the issue number")
+	}
+	path := "/repos/" + g.repo + "/issues/" + strings.TrimPrefix(id, "#")
+	var gi githubIssue
+	if err := g.do(ctx, http.MethodGet, path, nil, nil, &gi); err != nil {
+		return nil, err
+	}
+	var comments []githubComment
+	params := url.Values{"per_page": {strconv.Itoa(maxComments)}}
+	if err := g.do(ctx, http.MethodGet, path+"/comments", params, nil, &comments); err != nil {
+		return nil, err
+	}
+	issue := gi.issue()
+	for _, c := range comments {
+		issue.Comments = append(issue.Comments, c.comment())
+	}
+	return &issue, nil
+}
+
+// githubComment is an issue comment in GitHub's API
+type githubComment struct {
+	Body      string    `json:"body"`
+	HTMLURL   string    `json:"html_url"`
+	CreatedAt time.Time `json:"created_at"`
+	User      struct{ Login string }
+}
+
+func (c githubComment) comment() Comment {
+	return Comment{Author: c.User.Login, Body: c.Body, Created: c.CreatedAt, URL: c.HTMLURL}
+}
+
+// Comment comments on an issue or pull request
+func (g *github) Comment(ctx context.Context, id, body string) (*Comment, error) {
+	if !githubNumber.MatchString(id) {
+		return nil, invalidID(id, "the issue number")
+	}
+	var c githubComment
+	path := fmt.Sprintf("/repos/%s/issues/%s/comments", g.repo, strings.TrimPrefix(id, "#"))
+	if err := g.do(ctx, http.MethodPost, path, nil, map[string]string{"body": body}, &c); err != nil {
+		return nil, err
+	}
+	comment := c.comment()
+	return &comment, nil
+}
+
+// Create opens an issue
+func (g *github) Create(ctx context.Context, n NewIssue) (*Issue, error) {
+	body := map[string]interface{}{"title": n.Title, "body": n.Body}
+	if len(n.Labels) > 0 {
+		body["labels"] = n.Labels
+	}
+	var gi githubIssue
+	if err := g.do(ctx, http.MethodPost, "/repos/"+g.repo+"/issues", nil, body, &gi); err != nil {
+		return nil, err
+	}
+	issue := gi.issue()
+	return &issue, nil
+}
@@ -0,0 +1,184 @@
+// internal/providers/issues/gitlab.go
+package issues
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const gitlabAPI = "https://gitlab.com/api/v4"
+
+// gitlab adapts the GitLab REST API for one project. Merge requests have
+// IDs starting with !, as GitLab writes them.
+type gitlab struct {
+	api
+	project string // path-escaped group/project
+}
+
+func newGitLab(t Tracker, client *http.Client) *gitlab {
+	base := strings.TrimSuffix(t.URL, "/")
+	if base == "" {
+		base = gitlabAPI
+	}
+	token := t.Token
+	return &gitlab{
+		api: api{name: "GitLab", base: base, client: client, auth: func(req *http.Request) {
+			if token != "" {
+				req.Header.Set("PRIVATE-TOKEN", token)
+			}
+		}},
+		project: url.PathEscape(t.Project),
+	}
+}
+
+// gitlabIssue is an issue or merge request in GitLab's API
+type gitlabIssue struct {
+	IID         int       `json:"iid"`
+	Title       string    `json:"title"`
+	State       string    `json:"state"`
+	Description string    `json:"description"`
+	WebURL      string    `json:"web_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Author      struct{ Username string }
+	Labels      []string `json:"labels"`
+}
+
+func (g gitlabIssue) issue(mergeRequest bool) Issue {
+	issue := Issue{
+		ID:      strconv.Itoa(g.IID),
+		Kind:    "issue",
+		Title:   g.Title,
+		State:   g.State,
+		Author:  g.Author.Username,
+		Labels:  g.Labels,
+		Created: g.CreatedAt,
+		Updated: g.UpdatedAt,
+		URL:     g.WebURL,
+		Body:    g.Description,
+	}
+	if issue.State == "opened" {
+		issue.State = StateOpen
+	}
+	if mergeRequest {
+		issue.ID = "!" + issue.ID
+		issue.Kind = "merge request"
+	}
+	return issue
+}
+
+// Search returns matching issues and merge requests
+func (g *gitlab) Search(ctx context.Context, query, state string, limit int) ([]Issue, error) {
+	params := url.Values{"order_by": {"updated_at"}, "per_page": {strconv.Itoa(min(limit, 100))}}
+	switch state {
+	case StateOpen:
+		params.Set("state", "opened")
+	case StateClosed, StateMerged:
+		params.Set("state", state)
+	}
+	if query != "" {
+		params.Set("search", query)
+	}
+	var list []Issue
+	for _, kind := range []string{"issues", "merge_requests"} {
+		if kind == "issues" && state == StateMerged {
+			continue
+		}
+		var found []gitlabIssue
+		if err := g.do(ctx, http.MethodGet, "/projects/"+g.project+"/"+kind, params, nil, &found); err != nil {
+			return nil, err
+		}
+		for _, gi := range found {
+			list = append(list, gi.issue(kind == "merge_requests"))
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Updated.After(list[j].Updated) })
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+// gitlabID matches the IDs of GitLab issues and merge requests
+var gitlabID = regexp.MustCompile(`^[#!]?[0-9]+$`)
+
+// path returns the API path of an issue or merge request
+func (g *gitlab) path(id string) (string, bool, error) {
+	if !gitlabID.MatchString(id) {
+		return "", false, invalidID(id, "the issue number, or ! and the merge request number")
+	}
+	if iid, ok := strings.CutPrefix(id, "!"); ok {
+		return "/projects/" + g.project + "/merge_requests/" + iid, true, nil
+	}
+	return "/projects/" + g.project + "/issues/" + strings.TrimPrefix(id, "#"), false, nil
+}
+
+// gitlabNote is a comment in GitLab's API
+type gitlabNote struct {
+	Body      string    `json:"body"`
+	System    bool      `json:"system"` // generated for events such as label changes
+	CreatedAt time.Time `json:"created_at"`
+	Author    struct{ Username string }
+}
+
+func (n gitlabNote) comment() Comment {
+	return Comment{Author: n.Author.Username, Body: n.Body, Created: n.CreatedAt}
+}
+
+// Get returns an issue or merge request with its comments
+func (g *gitlab) Get(ctx context.Context, id string) (*Issue, error) {
+	path, mergeRequest, err := g.path(id)
+	if err != nil {
+		return nil, err
+	}
+	var gi gitlabIssue
+	if err := g.do(ctx, http.MethodGet, path, nil, nil, &gi); err != nil {
+		return nil, err
+	}
+	var notes []gitlabNote
+	params := url.Values{"sort": {"asc"}, "per_page": {strconv.Itoa(maxComments)}}
+	if err := g.do(ctx, http.MethodGet, path+"/notes", params, nil, &notes); err != nil {
+		return nil, err
+	}
+	issue := gi.issue(mergeRequest)
+	for _, n := range notes {
+		if !n.System {
+			issue.Comments = append(issue.Comments, n.comment())
+		}
+	}
+	return &issue, nil
+}
+
+// Comment comments on an issue or merge request
+func (g *gitlab) Comment(ctx context.Context, id, body string) (*Comment, error) {
+	path, _, err := g.path(id)
+	if err != nil {
+		return nil, err
+	}
+	var n gitlabNote
+	if err := g.do(ctx, http.MethodPost, path+"/notes", nil, map[string]string{"body": body}, &n); err != nil {
+		return nil, err
+	}
+	comment := n.comment()
+	return &comment, nil
+}
+
+// Create opens an issue
+func (g *gitlab) Create(ctx context.Context, n NewIssue) (*Issue, error) {
+	body := map[string]string{"title": n.Title, "description": n.Body}
+	if len(n.Labels) > 0 {
+		body["labels"] = strings.Join(n.Labels, ",")
+	}
+	var gi gitlabIssue
+	if err := g.do(ctx, http.MethodPost, "/projects/"+g.project+"/issues", nil, body, &gi); err != nil {
+		return nil, err
+	}
+	issue := gi.issue(false)
+	return &issue, nil
+}
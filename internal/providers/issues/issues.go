@@ -0,0 +1,385 @@
+// internal/providers/issues/issues.go
+
+// Package issues connects assistants to issue trackers. Each configured
+// tracker is a GitHub repository, a GitLab project or a Jira project,
+// reached through an adapter for its API. Recently updated open issues, and
+// on GitHub and GitLab pull and merge requests, are listed as
+// issues://<tracker>/<id> resources whose content is the issue and its
+// comments as Markdown.
+//
+// issues.search finds issues, issues.comment comments on one and
+// issues.create opens a new one. Creating issues is annotated as
+// destructive so clients confirm it with their user.
+package issues
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+)
+
+// Provider defaults
+const (
+	DefaultRecent   = 20
+	defaultTimeout  = 30 * time.Second
+	maxResponseSize = 8 << 20
+	maxComments     = 100
+	uriScheme       = "issues://"
+)
+
+// Tracker types
+const (
+	TypeGitHub = "github"
+	TypeGitLab = "gitlab"
+	TypeJira   = "jira"
+)
+
+// Issue states, normalized across trackers
+const (
+	StateOpen   = "open"
+	StateClosed = "closed"
+	StateMerged = "merged"
+	StateAll    = "all" // a search filter only
+)
+
+// Issue is an issue, pull request or merge request
+type Issue struct {
+	ID       string    `json:"id"`   // number, !number for GitLab merge requests, or Jira key
+	Kind     string    `json:"kind"` // issue, pull request or merge request
+	Title    string    `json:"title"`
+	State    string    `json:"state"`
+	Status   string    `json:"status,omitempty"` // workflow status where the tracker has one
+	Author   string    `json:"author,omitempty"`
+	Labels   []string  `json:"labels,omitempty"`
+	Created  time.Time `json:"created"`
+	Updated  time.Time `json:"updated"`
+	URL      string    `json:"url,omitempty"`
+	Body     string    `json:"body,omitempty"`
+	Comments []Comment `json:"comments,omitempty"`
+}
+
+// Comment is a comment on an issue
+type Comment struct {
+	Author  string    `json:"author,omitempty"`
+	Body    string    `json:"body"`
+	Created time.Time `json:"created"`
+	URL     string    `json:"url,omitempty"`
+}
+
+// NewIssue is an issue to create
+type NewIssue struct {
+	Title  string
+	Body   string
+	Labels []string
+}
+
+// Backend adapts one tracker's API
+type Backend interface {
+	// Search returns up to limit issues matching query in state, most
+	// recently updated first. An empty query matches every issue.
+	Search(ctx context.Context, query, state string, limit int) ([]Issue, error)
+
+	// Get returns an issue with its body and comments
+	Get(ctx context.Context, id string) (*Issue, error)
+
+	// Comment adds a comment to an issue
+	Comment(ctx context.Context, id, body string) (*Comment, error)
+
+	// Create opens an issue
+	Create(ctx context.Context, issue NewIssue) (*Issue, error)
+}
+
+// Tracker is one configured tracker
+type Tracker struct {
+	Name      string
+	Type      string // github, gitlab or jira
+	URL       string // API base URL; empty uses the public service for GitHub and GitLab
+	Project   string // owner/repo, group/project or a Jira project key
+	Token     string
+	User      string // Jira Cloud account email; empty sends the token as a bearer token
+	IssueType string // Jira issue type of created issues; empty uses Task
+}
+
+// Config configures a Provider
+type Config struct {
+	Trackers []Tracker
+	Recent   int // open issues listed per tracker
+	Timeout  time.Duration
+}
+
+// Provider serves the issues of its trackers
+type Provider struct {
+	cfg      Config
+	backends map[string]Backend
+}
+
+// Ensure Provider implements resources.Provider
+var _ resources.Provider = (*Provider)(nil)
+
+// New creates a provider with an adapter for each tracker
+func New(cfg Config) (*Provider, error) {
+	if cfg.Recent <= 0 {
+		cfg.Recent = DefaultRecent
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if len(cfg.Trackers) == 0 {
+		return nil, errors.New("no trackers are configured")
+	}
+	client := &http.Client{Timeout: cfg.Timeout}
+	backends := make(map[string]Backend, len(cfg.Trackers))
+	for _, t := range cfg.Trackers {
+		if t.Name == "" || strings.ContainsAny(t.Name, "/?#") {
+			return nil, fmt.Errorf("invalid tracker name %q", t.Name)
+		}
+		if _, ok := backends[t.Name]; ok {
+			return nil, fmt.Errorf("tracker %s is configured twice", t.Name)
+		}
+		if t.Project == "" {
+			return nil, fmt.Errorf("tracker %s has no project", t.Name)
+		}
+		var b Backend
+		switch t.Type {
+		case TypeGitHub:
+			b = newGitHub(t, client)
+		case TypeGitLab:
+			b = newGitLab(t, client)
+		case TypeJira:
+			if t.URL == "" {
+				return nil, fmt.Errorf("tracker %s: jira needs the site URL", t.Name)
+			}
+			b = newJira(t, client)
+		default:
+			return nil, fmt.Errorf("tracker %s: unknown type %q; use github, gitlab or jira", t.Name, t.Type)
+		}
+		backends[t.Name] = b
+	}
+	return &Provider{cfg: cfg, backends: backends}, nil
+}
+
+// Names returns the configured trackers in order
+func (p *Provider) Names() []string {
+	names := make([]string, len(p.cfg.Trackers))
+	for i, t := range p.cfg.Trackers {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// Backend returns the adapter of the named tracker
+func (p *Provider) Backend(tracker string) (Backend, error) {
+	b, ok := p.backends[tracker]
+	if !ok {
+		return nil, &apiError{status: http.StatusNotFound, message: fmt.Sprintf("unknown tracker %q", tracker)}
+	}
+	return b, nil
+}
+
+// ListResources returns the recently updated open issues of each tracker.
+// Trackers that cannot be reached are logged and skipped.
+func (p *Provider) ListResources() ([]resources.Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+	defer cancel()
+	var list []resources.Resource
+	for _, t := range p.cfg.Trackers {
+		found, err := p.backends[t.Name].Search(ctx, "", StateOpen, p.cfg.Recent)
+		if err != nil {
+			slog.Warn("Failed to list issues", "tracker", t.Name, "error", err)
+			continue
+		}
+		for _, issue := range found {
+			description := fmt.Sprintf("%s %s in %s, %s", issue.Kind, issue.ID, t.Name, issue.State)
+			if !issue.Updated.IsZero() {
+				description += ", updated " + issue.Updated.Format("2 Jan 2006")
+			}
+			list = append(list, resources.Resource{
+				URI:         issueURI(t.Name, issue.ID),
+				Name:        issue.Title,
+				Description: description,
+				MimeType:    "text/markdown",
+			})
+		}
+	}
+	return list, nil
+}
+
+// GetResource returns an issue and its comments as Markdown
+func (p *Provider) GetResource(uri string) (interface{}, error) {
+	rest, ok := strings.CutPrefix(uri, uriScheme)
+	if !ok {
+		return nil, resources.ErrResourceNotFound
+	}
+	tracker, id, ok := strings.Cut(rest, "/")
+	b, exists := p.backends[tracker]
+	if !ok || !exists {
+		return nil, resources.ErrResourceNotFound
+	}
+	if id, err := url.PathUnescape(id); err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+		defer cancel()
+		issue, err := b.Get(ctx, id)
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.status == http.StatusNotFound {
+			return nil, resources.ErrResourceNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		return render(issue), nil
+	}
+	return nil, resources.ErrResourceNotFound
+}
+
+// issueURI addresses an issue
+func issueURI(tracker, id string) string {
+	return uriScheme + tracker + "/" + url.PathEscape(id)
+}
+
+// render formats an issue and its comments as Markdown
+func render(issue *Issue) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", issue.Title)
+	state := issue.State
+	if issue.Status != "" {
+		state += " (" + issue.Status + ")"
+	}
+	fmt.Fprintf(&b, "%s %s, %s", capitalize(issue.Kind), issue.ID, state)
+	if issue.Author != "" {
+		fmt.Fprintf(&b, ", opened by %s", issue.Author)
+	}
+	if !issue.Created.IsZero() {
+		fmt.Fprintf(&b, " on %s", issue.Created.Format("2 Jan 2006"))
+	}
+	b.WriteString("\n")
+	if issue.URL != "" {
+		fmt.Fprintf(&b, "%s\n", issue.URL)
+	}
+	if len(issue.Labels) > 0 {
+		fmt.Fprintf(&b, "Labels: %s\n", strings.Join(issue.Labels, ", "))
+	}
+	if body := strings.TrimSpace(issue.Body); body != "" {
+		fmt.Fprintf(&b, "\n%s\n", body)
+	}
+	if len(issue.Comments) > 0 {
+		b.WriteString("\n## Comments\n")
+		for _, c := range issue.Comments {
+			fmt.Fprintf(&b, "\n**%s** on %s:\n\n%s\n", c.Author, c.Created.Format("2 Jan 2006 15:04"), strings.TrimSpace(c.Body))
+		}
+	}
+	return b.String()
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// apiError is an error response from a tracker, or a request it would
+// reject
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+// invalidID reports an issue ID the tracker cannot have
+func invalidID(id, want string) error {
+	return &apiError{status: http.StatusNotFound, message: fmt.Sprintf("invalid issue ID %q; use %s", id, want)}
+}
+
+// api sends JSON requests to one tracker's REST API
+type api struct {
+	name   string
+	base   string
+	client *http.Client
+	auth   func(*http.Request)
+}
+
+// do sends a request with an optional JSON body and decodes the JSON
+// response into out
+func (a *api) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	target := a.base + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	a.auth(req)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot reach %s: %w", a.name, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return &apiError{status: resp.StatusCode, message: fmt.Sprintf("%s returned %s: %s", a.name, resp.Status, errorMessage(data))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", a.name, err)
+	}
+	return nil
+}
+
+// errorMessage extracts the message of an error response from any of the
+// supported trackers
+func errorMessage(data []byte) string {
+	var body struct {
+		Message       string            `json:"message"` // GitHub, GitLab
+		Error         string            `json:"error"`   // GitLab
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"` // Jira
+	}
+	if json.Unmarshal(data, &body) != nil {
+		return strings.TrimSpace(string(data))
+	}
+	parts := append([]string{}, body.ErrorMessages...)
+	for field, msg := range body.Errors {
+		parts = append(parts, field+": "+msg)
+	}
+	for _, s := range []string{body.Message, body.Error} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if len(parts) == 0 {
+		return strings.TrimSpace(string(data))
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,233 @@
+// internal/providers/issues/jira.go
+package issues
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jiraFields are the issue fields read from Jira
+const jiraFields = "summary,description,status,reporter,labels,created,updated,issuetype,comment"
+
+// jiraTime is how Jira formats timestamps
+const jiraTime = "2006-01-02T15:04:05.000-0700"
+
+// jira adapts the Jira REST API, version 2, for one project. Version 2
+// takes and returns plain text rather than Atlassian Document Format.
+type jira struct {
+	api
+	project   string // project key
+	issueType string
+	search    string // search endpoint, which differs between Cloud and Data Center
+}
+
+func newJira(t Tracker, client *http.Client) *jira {
+	base := strings.TrimSuffix(t.URL, "/")
+	user, token := t.User, t.Token
+	j := &jira{
+		api: api{name: "Jira", base: base, client: client, auth: func(req *http.Request) {
+			switch {
+			case user != "":
+				req.SetBasicAuth(user, token) // Cloud API token
+			case token != "":
+				req.Header.Set("Authorization", "Bearer "+token) // Data Center personal access token
+			}
+		}},
+		project:   strings.ToUpper(t.Project),
+		issueType: t.IssueType,
+		search:    "/rest/api/2/search",
+	}
+	if j.issueType == "" {
+		j.issueType = "Task"
+	}
+	if u, err := url.Parse(base); err == nil && strings.HasSuffix(u.Hostname(), ".atlassian.net") {
+		j.search = "/rest/api/2/search/jql"
+	}
+	return j
+}
+
+// jiraTimestamp parses Jira's timestamp format
+type jiraTimestamp time.Time
+
+func (t *jiraTimestamp) UnmarshalJSON(data []byte) error {
+	s, err := strconv.Unquote(string(data))
+	if err != nil || s == "" {
+		return nil
+	}
+	parsed, err := time.Parse(jiraTime, s)
+	if err != nil {
+		return err
+	}
+	*t = jiraTimestamp(parsed)
+	return nil
+}
+
+// jiraUser is a user in Jira's API
+type jiraUser struct {
+	DisplayName string `json:"displayName"`
+}
+
+// jiraIssue is an issue in Jira's API
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string        `json:"summary"`
+		Description string        `json:"description"`
+		Labels      []string      `json:"labels"`
+		Created     jiraTimestamp `json:"created"`
+		Updated     jiraTimestamp `json:"updated"`
+		Reporter    *jiraUser     `json:"reporter"`
+		IssueType   struct {
+			Name string `json:"name"`
+		} `json:"issuetype"`
+		Status struct {
+			Name           string `json:"name"`
+			StatusCategory struct {
+				Key string `json:"key"`
+			} `json:"statusCategory"`
+		} `json:"status"`
+		Comment struct {
+			Comments []jiraComment `json:"comments"`
+		} `json:"comment"`
+	} `json:"fields"`
+}
+
+func (j *jira) issue(ji jiraIssue) Issue {
+	f := ji.Fields
+	issue := Issue{
+		ID:      ji.Key,
+		Kind:    strings.ToLower(f.IssueType.Name),
+		Title:   f.Summary,
+		State:   StateOpen,
+		Status:  f.Status.Name,
+		Labels:  f.Labels,
+		Created: time.Time(f.Created),
+		Updated: time.Time(f.Updated),
+		URL:     j.base + "/browse/" + ji.Key,
+		Body:    f.Description,
+	}
+	if issue.Kind == "" {
+		issue.Kind = "issue"
+	}
+	if f.Status.StatusCategory.Key == "done" {
+		issue.State = StateClosed
+	}
+	if f.Reporter != nil {
+		issue.Author = f.Reporter.DisplayName
+	}
+	for _, c := range f.Comment.Comments {
+		issue.Comments = append(issue.Comments, c.comment())
+	}
+	return issue
+}
+
+// jiraComment is a comment in Jira's API
+type jiraComment struct {
+	Body    string        `json:"body"`
+	Created jiraTimestamp `json:"created"`
+	Author  *jiraUser     `json:"author"`
+}
+
+func (c jiraComment) comment() Comment {
+	comment := Comment{Body: c.Body, Created: time.Time(c.Created)}
+	if c.Author != nil {
+		comment.Author = c.Author.DisplayName
+	}
+	return comment
+}
+
+// Search runs a JQL text search within the project
+func (j *jira) Search(ctx context.Context, query, state string, limit int) ([]Issue, error) {
+	jql := "project = " + strconv.Quote(j.project)
+	if query != "" {
+		jql += " AND text ~ " + strconv.Quote(query)
+	}
+	switch state {
+	case StateOpen:
+		jql += " AND statusCategory != Done"
+	case StateClosed, StateMerged:
+		jql += " AND statusCategory = Done"
+	}
+	jql += " ORDER BY updated DESC"
+	params := url.Values{
+		"jql":        {jql},
+		"maxResults": {strconv.Itoa(min(limit, 100))},
+		"fields":     {"summary,status,reporter,labels,created,updated,issuetype"},
+	}
+	var resp struct {
+		Issues []jiraIssue `json:"issues"`
+	}
+	if err := j.do(ctx, http.MethodGet, j.search, params, nil, &resp); err != nil {
+		return nil, err
+	}
+	list := make([]Issue, 0, len(resp.Issues))
+	for _, ji := range resp.Issues {
+		list = append(list, j.issue(ji))
+	}
+	return list, nil
+}
+
+// jiraKey matches the keys of Jira issues
+var jiraKey = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*-[0-9]+$`)
+
+// checkKey accepts keys of this project's issues only
+func (j *jira) checkKey(id string) (string, error) {
+	key := strings.ToUpper(id)
+	if !jiraKey.MatchString(key) || !strings.HasPrefix(key, j.project+"-") {
+		return "", invalidID(id, "a key such as "+j.project+"-123")
+	}
+	return key, nil
+}
+
+// Get returns an issue with its comments
+func (j *jira) Get(ctx context.Context, id string) (*Issue, error) {
+	key, err := j.checkKey(id)
+	if err != nil {
+		return nil, err
+	}
+	var ji jiraIssue
+	if err := j.do(ctx, http.MethodGet, "/rest/api/2/issue/"+key, url.Values{"fields": {jiraFields}}, nil, &ji); err != nil {
+		return nil, err
+	}
+	issue := j.issue(ji)
+	return &issue, nil
+}
+
+// Comment comments on an issue
+func (j *jira) Comment(ctx context.Context, id, body string) (*Comment, error) {
+	key, err := j.checkKey(id)
+	if err != nil {
+		return nil, err
+	}
+	var c jiraComment
+	if err := j.do(ctx, http.MethodPost, "/rest/api/2/issue/"+key+"/comment", nil, map[string]string{"body": body}, &c); err != nil {
+		return nil, err
+	}
+	comment := c.comment()
+	return &comment, nil
+}
+
+// Create opens an issue of the configured type
+func (j *jira) Create(ctx context.Context, n NewIssue) (*Issue, error) {
+	fields := map[string]interface{}{
+		"project":     map[string]string{"key": j.project},
+		"summary":     n.Title,
+		"description": n.Body,
+		"issuetype":   map[string]string{"name": j.issueType},
+	}
+	if len(n.Labels) > 0 {
+		fields["labels"] = n.Labels
+	}
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := j.do(ctx, http.MethodPost, "/rest/api/2/issue", nil, map[string]interface{}{"fields": fields}, &created); err != nil {
+		return nil, err
+	}
+	return j.Get(ctx, created.Key)
+}
@@ -0,0 +1,188 @@
+// internal/providers/issues/tools.go
+package issues
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+)
+
+// Tool names
+const (
+	SearchToolName  = "issues.search"
+	CommentToolName = "issues.comment"
+	CreateToolName  = "issues.create"
+)
+
+// Search limits of issues.search
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// UsageHint implements provider.Hinter
+func (p *Provider) UsageHint() string {
+	return "Recently updated open issues are issues:// resources. Call " + SearchToolName +
+		" for older or closed ones, and before " + CreateToolName + " to avoid filing duplicates."
+}
+
+// Register adds the issue tools to m
+func (p *Provider) Register(m *manager.ToolsManager) {
+	trackers := map[string]interface{}{"type": "string", "enum": p.Names(), "description": p.Names()[0] + " by default"}
+	id := map[string]interface{}{"type": "string", "description": "Issue number, ! and a GitLab merge request number, or Jira issue key"}
+
+	m.RegisterTool(protocol.Tool{
+		Name: SearchToolName,
+		Description: "Search a tracker's issues, and on GitHub and GitLab its pull or merge requests, " +
+			"most recently updated first. Read an issue with its comments as the resource in its uri.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"tracker": trackers,
+				"query":   map[string]interface{}{"type": "string", "description": "Text to search for; all issues by default"},
+				"state":   map[string]interface{}{"type": "string", "enum": []string{StateOpen, StateClosed, StateMerged, StateAll}, "description": "open by default"},
+				"limit":   map[string]interface{}{"type": "integer", "minimum": 1, "maximum": maxSearchLimit, "description": fmt.Sprintf("%d by default", defaultSearchLimit)},
+			},
+		},
+		Annotations: protocol.ReadOnly(),
+	}, func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		var params struct {
+			Tracker string `json:"tracker"`
+			Query   string `json:"query"`
+			State   string `json:"state"`
+			Limit   int    `json:"limit"`
+		}
+		if err := json.Unmarshal(args, &params); err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		b, tracker, err := p.trackerArg(params.Tracker)
+		if err != nil {
+			return toolResult(nil, err)
+		}
+		if params.State == "" {
+			params.State = StateOpen
+		}
+		if params.Limit <= 0 {
+			params.Limit = defaultSearchLimit
+		}
+		found, err := b.Search(ctx, params.Query, params.State, min(params.Limit, maxSearchLimit))
+		if err != nil {
+			return toolResult(nil, err)
+		}
+		type result struct {
+			URI string `json:"uri"`
+			Issue
+		}
+		list := make([]result, len(found))
+		for i, issue := range found {
+			issue.Body = ""
+			list[i] = result{URI: issueURI(tracker, issue.ID), Issue: issue}
+		}
+		return toolResult(map[string]interface{}{"issues": list}, nil)
+	})
+
+	f := false
+	m.RegisterTool(protocol.Tool{
+		Name:        CommentToolName,
+		Description: "Add a comment to an issue, pull request or merge request. Comments are public to everyone who can see the issue.",
+		InputSchema: map[string]interface{}{
+			"type":     "object",
+			"required": []string{"id", "body"},
+			"properties": map[string]interface{}{
+				"tracker": trackers,
+				"id":      id,
+				"body":    map[string]interface{}{"type": "string", "minLength": 1, "description": "Markdown on GitHub and GitLab, plain text on Jira"},
+			},
+		},
+		Annotations: &protocol.ToolAnnotations{ReadOnlyHint: &f, DestructiveHint: &f, IdempotentHint: &f},
+	}, func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		var params struct {
+			Tracker string `json:"tracker"`
+			ID      string `json:"id"`
+			Body    string `json:"body"`
+		}
+		if err := json.Unmarshal(args, &params); err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		b, _, err := p.trackerArg(params.Tracker)
+		if err != nil {
+			return toolResult(nil, err)
+		}
+		comment, err := b.Comment(ctx, params.ID, params.Body)
+		return toolResult(comment, err)
+	})
+
+	m.RegisterTool(protocol.Tool{
+		Name:        CreateToolName,
+		Description: "Open a new issue. Search first to avoid duplicates; issues cannot be deleted once created.",
+		InputSchema: map[string]interface{}{
+			"type":     "object",
+			"required": []string{"title"},
+			"properties": map[string]interface{}{
+				"tracker": trackers,
+				"title":   map[string]interface{}{"type": "string", "minLength": 1},
+				"body":    map[string]interface{}{"type": "string", "description": "Markdown on GitHub and GitLab, plain text on Jira"},
+				"labels":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			},
+		},
+		Annotations: protocol.Destructive(false),
+	}, func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		var params struct {
+			Tracker string   `json:"tracker"`
+			Title   string   `json:"title"`
+			Body    string   `json:"body"`
+			Labels  []string `json:"labels"`
+		}
+		if err := json.Unmarshal(args, &params); err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		b, tracker, err := p.trackerArg(params.Tracker)
+		if err != nil {
+			return toolResult(nil, err)
+		}
+		issue, err := b.Create(ctx, NewIssue{Title: params.Title, Body: params.Body, Labels: params.Labels})
+		if err != nil {
+			return toolResult(nil, err)
+		}
+		return toolResult(map[string]interface{}{"uri": issueURI(tracker, issue.ID), "id": issue.ID, "url": issue.URL}, nil)
+	})
+}
+
+// ToolNames returns the names of the tools Register adds
+func (p *Provider) ToolNames() []string {
+	return []string{SearchToolName, CommentToolName, CreateToolName}
+}
+
+// trackerArg returns the backend a tracker argument names, the first
+// tracker when it is empty
+func (p *Provider) trackerArg(tracker string) (Backend, string, error) {
+	if tracker == "" {
+		tracker = p.cfg.Trackers[0].Name
+	}
+	b, err := p.Backend(tracker)
+	return b, tracker, err
+}
+
+// toolResult returns v as JSON text, or reports a tracker's refusal or a
+// mistake in the arguments to the model
+func toolResult(v interface{}, err error) (protocol.ToolsCallResult, error) {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return protocol.ToolsCallResult{
+			Content: []protocol.Content{protocol.TextContent(apiErr.Error())},
+			IsError: true,
+		}, nil
+	}
+	if err != nil {
+		return protocol.ToolsCallResult{}, err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return protocol.ToolsCallResult{}, err
+	}
+	return protocol.ToolsCallResult{Content: []protocol.Content{protocol.TextContent(string(data))}}, nil
+}
@@ -0,0 +1,310 @@
+// internal/providers/mail/imap.go
+package mail
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxLiteral bounds one literal in an IMAP response, such as a message body
+const maxLiteral = 32 << 20
+
+// imapConn is a minimal IMAP4rev1 client: enough to log in, open a folder
+// read-only and fetch messages
+type imapConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	tag  int
+}
+
+// dialIMAP connects and logs in to server. The whole conversation must end
+// by deadline.
+func dialIMAP(ctx context.Context, server Server, username, password string, deadline time.Time) (*imapConn, error) {
+	addr := net.JoinHostPort(server.Host, strconv.Itoa(server.Port))
+	tlsConfig := &tls.Config{ServerName: server.Host}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if server.Security == SecurityTLS {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(deadline)
+	c := &imapConn{conn: conn, r: bufio.NewReader(conn)}
+
+	tag, fields, err := c.readResponse()
+	if err != nil || tag != "*" || len(fields) == 0 || fields[0] != "OK" {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected IMAP greeting from %s", addr)
+	}
+	if server.Security == SecurityStartTLS {
+		if _, err := c.command("STARTTLS"); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		c.conn, c.r = tlsConn, bufio.NewReader(tlsConn)
+	}
+	if server.Security == SecurityNone && !isLocalhost(server.Host) {
+		c.conn.Close()
+		return nil, fmt.Errorf("refusing to log in to %s without TLS", server.Host)
+	}
+	if _, err := c.command("LOGIN " + quote(username) + " " + quote(password)); err != nil {
+		c.conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close logs out and closes the connection
+func (c *imapConn) Close() error {
+	_, _ = c.command("LOGOUT")
+	return c.conn.Close()
+}
+
+// examine opens folder read-only and returns its message count
+func (c *imapConn) examine(folder string) (int, error) {
+	untagged, err := c.command("EXAMINE " + quote(folder))
+	if err != nil {
+		return 0, err
+	}
+	for _, fields := range untagged {
+		if len(fields) == 2 && fields[1] == "EXISTS" {
+			if s, ok := fields[0].(string); ok {
+				return strconv.Atoi(s)
+			}
+		}
+	}
+	return 0, fmt.Errorf("folder %s: server did not report a message count", folder)
+}
+
+// fetch runs a FETCH or UID FETCH command and returns each message's items
+// keyed by name, e.g. UID or BODY[]
+func (c *imapConn) fetch(command string) ([]map[string]interface{}, error) {
+	untagged, err := c.command(command)
+	if err != nil {
+		return nil, err
+	}
+	var messages []map[string]interface{}
+	for _, fields := range untagged {
+		if len(fields) != 3 || fields[1] != "FETCH" {
+			continue
+		}
+		list, ok := fields[2].([]interface{})
+		if !ok {
+			continue
+		}
+		items := make(map[string]interface{}, len(list)/2)
+		for i := 0; i+1 < len(list); i += 2 {
+			if key, ok := list[i].(string); ok {
+				items[strings.ToUpper(key)] = list[i+1]
+			}
+		}
+		messages = append(messages, items)
+	}
+	return messages, nil
+}
+
+// command sends one command and returns its untagged responses, or an
+// error when the server does not answer OK
+func (c *imapConn) command(command string) ([][]interface{}, error) {
+	c.tag++
+	tag := "a" + strconv.Itoa(c.tag)
+	if _, err := io.WriteString(c.conn, tag+" "+command+"\r\n"); err != nil {
+		return nil, err
+	}
+	var untagged [][]interface{}
+	for {
+		got, fields, err := c.readResponse()
+		if err != nil {
+			return nil, err
+		}
+		switch got {
+		case "*":
+			untagged = append(untagged, fields)
+		case tag:
+			if len(fields) == 0 || fields[0] != "OK" {
+				verb, _, _ := strings.Cut(command, " ")
+				return nil, fmt.Errorf("IMAP %s failed: %s", verb, responseText(fields))
+			}
+			return untagged, nil
+		}
+	}
+}
+
+// statusWords begin responses whose remainder is free text
+var statusWords = map[string]bool{"OK": true, "NO": true, "BAD": true, "BYE": true, "PREAUTH": true}
+
+// readResponse reads one response line. Status responses yield their
+// status word and the rest of the line; others yield their parsed fields,
+// with NIL as nil, atoms, quoted strings and literals as strings and
+// parenthesized lists as []interface{}.
+func (c *imapConn) readResponse() (tag string, fields []interface{}, err error) {
+	line, err := c.r.ReadString(' ')
+	if err != nil {
+		return "", nil, err
+	}
+	tag = strings.TrimSpace(line)
+	if tag == "+" {
+		rest, err := c.r.ReadString('\n')
+		return tag, []interface{}{strings.TrimSpace(rest)}, err
+	}
+	first, err := c.readValue()
+	if err != nil {
+		return "", nil, err
+	}
+	if word, ok := first.(string); ok && statusWords[strings.ToUpper(word)] {
+		rest, err := c.r.ReadString('\n')
+		return tag, []interface{}{strings.ToUpper(word), strings.TrimSpace(rest)}, err
+	}
+	fields = []interface{}{first}
+	for {
+		b, err := c.r.ReadByte()
+		if err != nil {
+			return "", nil, err
+		}
+		switch b {
+		case ' ', '\r':
+			continue
+		case '\n':
+			return tag, fields, nil
+		}
+		_ = c.r.UnreadByte()
+		v, err := c.readValue()
+		if err != nil {
+			return "", nil, err
+		}
+		if s, ok := v.(string); ok && len(fields) == 1 {
+			v = strings.ToUpper(s) // the response name, e.g. FETCH or EXISTS
+		}
+		fields = append(fields, v)
+	}
+}
+
+// readValue reads one atom, quoted string, literal or list
+func (c *imapConn) readValue() (interface{}, error) {
+	b, err := c.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch b {
+	case '(':
+		var list []interface{}
+		for {
+			b, err := c.r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			switch b {
+			case ' ':
+				continue
+			case ')':
+				return list, nil
+			case '\r', '\n':
+				return nil, fmt.Errorf("IMAP response ended inside a list")
+			}
+			_ = c.r.UnreadByte()
+			v, err := c.readValue()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+	case '"':
+		var sb strings.Builder
+		for {
+			b, err := c.r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			switch b {
+			case '"':
+				return sb.String(), nil
+			case '\\':
+				if b, err = c.r.ReadByte(); err != nil {
+					return nil, err
+				}
+			}
+			sb.WriteByte(b)
+		}
+	case '{':
+		size, err := c.r.ReadString('}')
+		if err != nil {
+			return nil, err
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSuffix(size, "}"), "+"))
+		if err != nil || n < 0 || n > maxLiteral {
+			return nil, fmt.Errorf("invalid IMAP literal size {%s", size)
+		}
+		if _, err := c.r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		data := make([]byte, n)
+		if _, err := io.ReadFull(c.r, data); err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	}
+
+	// An atom, which may carry a bracketed section such as
+	// BODY[HEADER.FIELDS (FROM SUBJECT)]
+	var sb strings.Builder
+	depth := 0
+	for {
+		switch {
+		case b == '[':
+			depth++
+		case b == ']' && depth > 0:
+			depth--
+		case depth == 0 && (b == ' ' || b == '(' || b == ')' || b == '\r' || b == '\n'):
+			_ = c.r.UnreadByte()
+			if atom := sb.String(); atom != "NIL" {
+				return atom, nil
+			}
+			return nil, nil
+		}
+		sb.WriteByte(b)
+		if b, err = c.r.ReadByte(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isLocalhost reports whether host is this machine, where unencrypted
+// connections do not leave it
+func isLocalhost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// quote encodes s as an IMAP quoted string
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// responseText returns the text of a status response
+func responseText(fields []interface{}) string {
+	if len(fields) < 2 {
+		return "no reason given"
+	}
+	return fmt.Sprint(fields[1])
+}
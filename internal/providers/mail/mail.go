@@ -0,0 +1,375 @@
+// internal/providers/mail/mail.go
+
+// Package mail reads recent messages from an IMAP mailbox and sends mail
+// over SMTP. The newest messages of each configured folder are listed as
+// mail://<folder>/<uid> resources whose content is the message as text, and
+// the mail.send tool sends plain text mail from the configured address.
+//
+// Folders are opened read-only, so reading never marks messages as seen.
+// mail.send is annotated as destructive: sent mail cannot be recalled, so
+// it runs only once the server's approval hook lets it.
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+)
+
+// Provider defaults
+const (
+	DefaultFolder  = "INBOX"
+	DefaultRecent  = 20
+	SendToolName   = "mail.send"
+	defaultTimeout = 30 * time.Second
+	uriScheme      = "mail://"
+)
+
+// Connection security
+const (
+	SecurityTLS      = "tls"      // TLS from the first byte
+	SecurityStartTLS = "starttls" // plain connection upgraded with STARTTLS
+	SecurityNone     = "none"     // unencrypted; for servers on localhost
+)
+
+// Server is an IMAP or SMTP server
+type Server struct {
+	Host     string
+	Port     int
+	Security string
+}
+
+// Config configures a Provider
+type Config struct {
+	Address  string // sender of mail.send
+	Username string // for both servers
+	Password string
+	IMAP     Server // empty Host disables reading
+	SMTP     Server // empty Host disables mail.send
+	Folders  []string
+	Recent   int // messages listed per folder
+	Timeout  time.Duration
+}
+
+// Provider serves one mailbox
+type Provider struct {
+	cfg Config
+	now func() time.Time
+}
+
+// Ensure Provider implements resources.Provider
+var _ resources.Provider = (*Provider)(nil)
+
+// New creates a provider for cfg's mailbox
+func New(cfg Config) (*Provider, error) {
+	if cfg.Username == "" {
+		cfg.Username = bareAddress(cfg.Address)
+	}
+	if len(cfg.Folders) == 0 {
+		cfg.Folders = []string{DefaultFolder}
+	}
+	if cfg.Recent <= 0 {
+		cfg.Recent = DefaultRecent
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	var err error
+	if cfg.IMAP, err = withDefaults(cfg.IMAP, SecurityTLS, 993, 143); err != nil {
+		return nil, fmt.Errorf("imap: %w", err)
+	}
+	if cfg.SMTP, err = withDefaults(cfg.SMTP, SecurityStartTLS, 465, 587); err != nil {
+		return nil, fmt.Errorf("smtp: %w", err)
+	}
+	if cfg.SMTP.Host != "" && cfg.Address == "" {
+		return nil, fmt.Errorf("sending mail needs an address")
+	}
+	return &Provider{cfg: cfg, now: time.Now}, nil
+}
+
+// withDefaults fills in a server's security and port
+func withDefaults(s Server, security string, tlsPort, plainPort int) (Server, error) {
+	if s.Security == "" {
+		s.Security = security
+	}
+	switch s.Security {
+	case SecurityTLS, SecurityStartTLS, SecurityNone:
+	default:
+		return s, fmt.Errorf("unknown security %q; use tls, starttls or none", s.Security)
+	}
+	if s.Port == 0 {
+		s.Port = plainPort
+		if s.Security == SecurityTLS {
+			s.Port = tlsPort
+		}
+	}
+	return s, nil
+}
+
+// Summary describes a message listed as a resource
+type Summary struct {
+	Folder  string    `json:"folder"`
+	UID     string    `json:"uid"`
+	From    string    `json:"from"`
+	Subject string    `json:"subject"`
+	Date    time.Time `json:"date"`
+}
+
+// Recent returns the newest messages of a folder, newest first
+func (p *Provider) Recent(ctx context.Context, folder string) ([]Summary, error) {
+	c, err := p.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	count, err := c.examine(folder)
+	if err != nil || count == 0 {
+		return nil, err
+	}
+	first := max(count-p.cfg.Recent+1, 1)
+	messages, err := c.fetch(fmt.Sprintf("FETCH %d:%d (UID INTERNALDATE BODY.PEEK[HEADER.FIELDS (FROM SUBJECT DATE)])", first, count))
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Summary, 0, len(messages))
+	for i := len(messages) - 1; i >= 0; i-- {
+		m := messages[i]
+		uid, _ := m["UID"].(string)
+		if uid == "" {
+			continue
+		}
+		var header string
+		for key, v := range m {
+			if strings.HasPrefix(key, "BODY[") {
+				header, _ = v.(string)
+			}
+		}
+		h := parseHeader(header)
+		s := Summary{Folder: folder, UID: uid, From: decodeHeader(h, "From"), Subject: decodeHeader(h, "Subject")}
+		if date, ok := m["INTERNALDATE"].(string); ok {
+			s.Date, _ = time.Parse("2-Jan-2006 15:04:05 -0700", strings.TrimSpace(date))
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+// Message returns one message of a folder as text
+func (p *Provider) Message(ctx context.Context, folder, uid string) (string, error) {
+	if _, err := strconv.ParseUint(uid, 10, 32); err != nil {
+		return "", fmt.Errorf("invalid message UID %q", uid)
+	}
+	c, err := p.connect(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer c.Close()
+
+	if _, err := c.examine(folder); err != nil {
+		return "", err
+	}
+	messages, err := c.fetch("UID FETCH " + uid + " (UID BODY.PEEK[])")
+	if err != nil {
+		return "", err
+	}
+	for _, m := range messages {
+		if raw, ok := m["BODY[]"].(string); ok && m["UID"] == uid {
+			return render([]byte(raw))
+		}
+	}
+	return "", resources.ErrResourceNotFound
+}
+
+// connect logs in to the IMAP server
+func (p *Provider) connect(ctx context.Context) (*imapConn, error) {
+	if p.cfg.IMAP.Host == "" {
+		return nil, fmt.Errorf("no IMAP server is configured")
+	}
+	return dialIMAP(ctx, p.cfg.IMAP, p.cfg.Username, p.cfg.Password, p.now().Add(p.cfg.Timeout))
+}
+
+// ListResources returns the newest messages of each folder. Folders that
+// cannot be read are logged and skipped.
+func (p *Provider) ListResources() ([]resources.Resource, error) {
+	if p.cfg.IMAP.Host == "" {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+	defer cancel()
+	var list []resources.Resource
+	for _, folder := range p.cfg.Folders {
+		messages, err := p.Recent(ctx, folder)
+		if err != nil {
+			slog.Warn("Failed to list mail folder", "folder", folder, "error", err)
+			continue
+		}
+		for _, m := range messages {
+			list = append(list, resources.Resource{
+				URI:         messageURI(m.Folder, m.UID),
+				Name:        m.Subject,
+				Description: fmt.Sprintf("From %s, %s", m.From, m.Date.Format("Mon 2 Jan 2006 15:04")),
+				MimeType:    "text/plain",
+			})
+		}
+	}
+	return list, nil
+}
+
+// GetResource returns a message as text
+func (p *Provider) GetResource(uri string) (interface{}, error) {
+	rest, ok := strings.CutPrefix(uri, uriScheme)
+	if !ok || p.cfg.IMAP.Host == "" {
+		return nil, resources.ErrResourceNotFound
+	}
+	i := strings.LastIndex(rest, "/")
+	if i < 0 {
+		return nil, resources.ErrResourceNotFound
+	}
+	folder, err := url.PathUnescape(rest[:i])
+	if err != nil || !p.configured(folder) {
+		return nil, resources.ErrResourceNotFound
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+	defer cancel()
+	return p.Message(ctx, folder, rest[i+1:])
+}
+
+// configured reports whether folder is one of the listed folders; others
+// are not served
+func (p *Provider) configured(folder string) bool {
+	for _, f := range p.cfg.Folders {
+		if f == folder {
+			return true
+		}
+	}
+	return false
+}
+
+// messageURI addresses a message
+func messageURI(folder, uid string) string {
+	return uriScheme + url.PathEscape(folder) + "/" + uid
+}
+
+// Send sends a plain text message from the configured address
+func (p *Provider) Send(ctx context.Context, out Outgoing) error {
+	msg, rcpts, err := compose(p.cfg.Address, out, p.now())
+	if err != nil {
+		return err
+	}
+	s := p.cfg.SMTP
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	tlsConfig := &tls.Config{ServerName: s.Host}
+
+	var conn net.Conn
+	if s.Security == SecurityTLS {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(p.now().Add(p.cfg.Timeout))
+	c, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if s.Security == SecurityStartTLS {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+	if p.cfg.Password != "" {
+		// PlainAuth refuses to send credentials unencrypted except to localhost
+		if err := c.Auth(smtp.PlainAuth("", p.cfg.Username, p.cfg.Password, s.Host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(bareAddress(p.cfg.Address)); err != nil {
+		return err
+	}
+	for _, rcpt := range rcpts {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("recipient %s refused: %w", rcpt, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// UsageHint implements provider.Hinter
+func (p *Provider) UsageHint() string {
+	hint := "Recent messages are mail:// resources; read one before replying to it."
+	if p.cfg.SMTP.Host != "" {
+		hint += " " + SendToolName + " sends mail at once, so confirm recipients and text with the user first."
+	}
+	return hint
+}
+
+// Register adds mail.send to m when an SMTP server is configured
+func (p *Provider) Register(m *manager.ToolsManager) {
+	if p.cfg.SMTP.Host == "" {
+		return
+	}
+	addresses := map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}
+	m.RegisterTool(protocol.Tool{
+		Name:        SendToolName,
+		Description: fmt.Sprintf("Send a plain text email from %s. Sent mail cannot be recalled.", p.cfg.Address),
+		InputSchema: map[string]interface{}{
+			"type":     "object",
+			"required": []string{"to", "subject", "body"},
+			"properties": map[string]interface{}{
+				"to":        addresses,
+				"cc":        addresses,
+				"subject":   map[string]interface{}{"type": "string"},
+				"body":      map[string]interface{}{"type": "string"},
+				"inReplyTo": map[string]interface{}{"type": "string", "description": "Message-ID of the message this answers"},
+			},
+		},
+		Annotations: protocol.Destructive(false),
+	}, func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		var params struct {
+			To        []string `json:"to"`
+			Cc        []string `json:"cc"`
+			Subject   string   `json:"subject"`
+			Body      string   `json:"body"`
+			InReplyTo string   `json:"inReplyTo"`
+		}
+		if err := json.Unmarshal(args, &params); err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		out := Outgoing{To: params.To, Cc: params.Cc, Subject: params.Subject, Body: params.Body, InReplyTo: params.InReplyTo}
+		if err := p.Send(ctx, out); err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		return protocol.ToolsCallResult{
+			Content: []protocol.Content{protocol.TextContent(fmt.Sprintf("Sent to %s", strings.Join(append(params.To, params.Cc...), ", ")))},
+		}, nil
+	})
+}
@@ -0,0 +1,305 @@
+// internal/providers/mail/message.go
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxBodyText bounds the body text rendered for a message resource
+const maxBodyText = 256 << 10
+
+// headerDecoder decodes RFC 2047 encoded words in headers
+var headerDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+// parseHeader reads a block of header fields
+func parseHeader(raw string) mail.Header {
+	msg, err := mail.ReadMessage(strings.NewReader(strings.TrimRight(raw, "\r\n") + "\r\n\r\n"))
+	if err != nil {
+		return mail.Header{}
+	}
+	return msg.Header
+}
+
+// decodeHeader returns a header's value with encoded words decoded
+func decodeHeader(h mail.Header, name string) string {
+	v := h.Get(name)
+	if decoded, err := headerDecoder.DecodeHeader(v); err == nil {
+		return decoded
+	}
+	return v
+}
+
+// render turns a raw RFC 5322 message into readable text: its main headers
+// followed by its plain text body, or its HTML body with tags removed.
+// Attachments are listed by name.
+func render(raw []byte) (string, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return "", fmt.Errorf("invalid message: %w", err)
+	}
+	var b strings.Builder
+	for _, name := range []string{"From", "To", "Cc", "Date", "Subject", "Message-ID"} {
+		if v := decodeHeader(msg.Header, name); v != "" {
+			fmt.Fprintf(&b, "%s: %s\n", name, v)
+		}
+	}
+	b.WriteString("\n")
+
+	var parts bodyParts
+	if err := parts.walk(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), "", msg.Body); err != nil {
+		return "", err
+	}
+	switch {
+	case parts.text != "":
+		b.WriteString(parts.text)
+	case parts.html != "":
+		b.WriteString(stripHTML(parts.html))
+	default:
+		b.WriteString("(no text body)")
+	}
+	if len(parts.attachments) > 0 {
+		b.WriteString("\n\nAttachments: " + strings.Join(parts.attachments, ", "))
+	}
+	return b.String(), nil
+}
+
+// bodyParts collects the readable parts of a message
+type bodyParts struct {
+	text        string
+	html        string
+	attachments []string
+}
+
+// walk visits a body part, descending into multiparts
+func (bp *bodyParts) walk(contentType, encoding, disposition string, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("invalid multipart body: %w", err)
+			}
+			if err := bp.walk(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"),
+				part.Header.Get("Content-Disposition"), part); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, dparams, err := mime.ParseMediaType(disposition); err == nil && strings.HasPrefix(strings.ToLower(disposition), "attachment") {
+		name := dparams["filename"]
+		if name == "" {
+			name = params["name"]
+		}
+		bp.attachments = append(bp.attachments, fmt.Sprintf("%s (%s)", name, mediaType))
+		return nil
+	}
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		if name := params["name"]; name != "" {
+			bp.attachments = append(bp.attachments, fmt.Sprintf("%s (%s)", name, mediaType))
+		}
+		return nil
+	}
+	if (mediaType == "text/plain" && bp.text != "") || (mediaType == "text/html" && bp.html != "") {
+		return nil
+	}
+
+	switch strings.ToLower(encoding) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, newlineStripper{body})
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+	if reader, err := charsetReader(params["charset"], body); err == nil {
+		body = reader
+	}
+	data, err := io.ReadAll(io.LimitReader(body, maxBodyText))
+	if err != nil {
+		return fmt.Errorf("invalid %s body: %w", mediaType, err)
+	}
+	if mediaType == "text/plain" {
+		bp.text = string(data)
+	} else {
+		bp.html = string(data)
+	}
+	return nil
+}
+
+// newlineStripper drops line breaks, which base64 bodies are wrapped with
+type newlineStripper struct {
+	r io.Reader
+}
+
+func (n newlineStripper) Read(p []byte) (int, error) {
+	read, err := n.r.Read(p)
+	kept := 0
+	for _, b := range p[:read] {
+		if b != '\r' && b != '\n' {
+			p[kept] = b
+			kept++
+		}
+	}
+	return kept, err
+}
+
+// charsetReader decodes the charsets Go has no table for but mail commonly
+// uses. UTF-8 and US-ASCII pass through; Latin-1 and Windows-1252 are
+// mapped byte by byte. Others are an error, leaving the bytes as they are.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "", "utf-8", "us-ascii":
+		return input, nil
+	case "iso-8859-1", "latin1", "windows-1252":
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return strings.NewReader(string(runes)), nil
+	}
+	return nil, fmt.Errorf("unsupported charset %q", charset)
+}
+
+var (
+	blockTags = regexp.MustCompile(`(?i)<(br|/p|/div|/tr|/h[1-6]|/li)[^>]*>`)
+	anyTag    = regexp.MustCompile(`(?s)<[^>]*>`)
+	hidden    = regexp.MustCompile(`(?is)<(script|style)[^>]*>.*?</(script|style)>`)
+	blankRuns = regexp.MustCompile(`\n{3,}`)
+)
+
+// stripHTML reduces an HTML body to its text
+func stripHTML(html string) string {
+	text := hidden.ReplaceAllString(html, "")
+	text = blockTags.ReplaceAllString(text, "\n")
+	text = anyTag.ReplaceAllString(text, "")
+	text = strings.NewReplacer("&nbsp;", " ", "&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&#39;", "'").Replace(text)
+	return strings.TrimSpace(blankRuns.ReplaceAllString(text, "\n\n"))
+}
+
+// Outgoing is a message to send
+type Outgoing struct {
+	To        []string
+	Cc        []string
+	Subject   string
+	Body      string
+	InReplyTo string // Message-ID of the message being answered
+}
+
+// compose builds an RFC 5322 message from from, returning it with the
+// envelope recipients
+func compose(from string, out Outgoing, now time.Time) ([]byte, []string, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid sender %q: %w", from, err)
+	}
+	to, err := parseAddresses(out.To)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(to) == 0 {
+		return nil, nil, fmt.Errorf("at least one recipient is required")
+	}
+	cc, err := parseAddresses(out.Cc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var b bytes.Buffer
+	header := func(name, value string) {
+		fmt.Fprintf(&b, "%s: %s\r\n", name, value)
+	}
+	header("From", sender.String())
+	header("To", joinAddresses(to))
+	if len(cc) > 0 {
+		header("Cc", joinAddresses(cc))
+	}
+	// Line breaks in a subject would start new header fields
+	subject := strings.Join(strings.Fields(out.Subject), " ")
+	header("Subject", mime.QEncoding.Encode("utf-8", subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", messageID(sender.Address))
+	if id := strings.Join(strings.Fields(out.InReplyTo), ""); id != "" {
+		header("In-Reply-To", id)
+		header("References", id)
+	}
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	b.WriteString("\r\n")
+	qp := quotedprintable.NewWriter(&b)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(strings.ReplaceAll(out.Body, "\r\n", "\n"), "\n", "\r\n"))); err != nil {
+		return nil, nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	rcpts := make([]string, 0, len(to)+len(cc))
+	for _, a := range append(to, cc...) {
+		rcpts = append(rcpts, a.Address)
+	}
+	return b.Bytes(), rcpts, nil
+}
+
+// parseAddresses parses each recipient
+func parseAddresses(list []string) ([]*mail.Address, error) {
+	addrs := make([]*mail.Address, 0, len(list))
+	for _, s := range list {
+		a, err := mail.ParseAddress(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", s, err)
+		}
+		addrs = append(addrs, a)
+	}
+	return addrs, nil
+}
+
+func joinAddresses(addrs []*mail.Address) string {
+	s := make([]string, len(addrs))
+	for i, a := range addrs {
+		s[i] = a.String()
+	}
+	return strings.Join(s, ", ")
+}
+
+// bareAddress returns the address part of "Name <address>", or s if it
+// does not parse
+func bareAddress(s string) string {
+	if a, err := mail.ParseAddress(s); err == nil {
+		return a.Address
+	}
+	return s
+}
+
+// messageID returns a new Message-ID in the sender's domain
+func messageID(address string) string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	_, domain, _ := strings.Cut(address, "@")
+	if domain == "" {
+		domain = "axe-handle"
+	}
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
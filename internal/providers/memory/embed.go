@@ -0,0 +1,208 @@
+// internal/providers/memory/embed.go
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Embedder turns texts into vectors whose cosine similarity reflects how
+// alike the texts are
+type Embedder interface {
+	// Name identifies the embedder and model. Vectors from embedders with
+	// different names are not compared.
+	Name() string
+
+	// Embed returns one vector per text
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// DefaultHashDimensions is the vector size of the hash embedder
+const DefaultHashDimensions = 512
+
+// maxEmbedResponse bounds an embedding API response
+const maxEmbedResponse = 64 << 20
+
+// HashEmbedder embeds text without a model by hashing its words and word
+// pairs into a fixed number of dimensions. It matches shared vocabulary
+// rather than meaning, which is enough when no model is available.
+type HashEmbedder struct {
+	Dimensions int
+}
+
+// Name identifies the embedder and its size
+func (h HashEmbedder) Name() string {
+	return "hash:" + strconv.Itoa(h.dimensions())
+}
+
+// Embed hashes each text
+func (h HashEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		v := make([]float32, h.dimensions())
+		words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		})
+		for j, w := range words {
+			h.add(v, w, 1)
+			if j > 0 {
+				h.add(v, words[j-1]+" "+w, 0.5)
+			}
+		}
+		vectors[i] = normalize(v)
+	}
+	return vectors, nil
+}
+
+// add adds weight to the dimension feature hashes to, with a sign taken
+// from the hash so collisions tend to cancel out
+func (h HashEmbedder) add(v []float32, feature string, weight float32) {
+	f := fnv.New64a()
+	f.Write([]byte(feature))
+	sum := f.Sum64()
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	v[sum%uint64(len(v))] += weight
+}
+
+func (h HashEmbedder) dimensions() int {
+	if h.Dimensions <= 0 {
+		return DefaultHashDimensions
+	}
+	return h.Dimensions
+}
+
+// OllamaEmbedder embeds text with a model served by a local Ollama server
+type OllamaEmbedder struct {
+	URL    string // e.g. http://localhost:11434
+	Model  string
+	Client *http.Client
+}
+
+// Name identifies the model
+func (o OllamaEmbedder) Name() string {
+	return "ollama:" + o.Model
+}
+
+// Embed calls /api/embed
+func (o OllamaEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	var resp struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	err := postJSON(ctx, o.Client, strings.TrimSuffix(o.URL, "/")+"/api/embed", "",
+		map[string]interface{}{"model": o.Model, "input": texts}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return checkCount(resp.Embeddings, len(texts))
+}
+
+// OpenAIEmbedder embeds text with an OpenAI-compatible embeddings API
+type OpenAIEmbedder struct {
+	URL    string // API base URL, e.g. https://api.openai.com/v1
+	Model  string
+	APIKey string
+	Client *http.Client
+}
+
+// Name identifies the model
+func (o OpenAIEmbedder) Name() string {
+	return "openai:" + o.Model
+}
+
+// Embed calls /embeddings
+func (o OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	var resp struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	err := postJSON(ctx, o.Client, strings.TrimSuffix(o.URL, "/")+"/embeddings", o.APIKey,
+		map[string]interface{}{"model": o.Model, "input": texts}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	vectors := make([][]float32, len(texts))
+	for _, d := range resp.Data {
+		if d.Index >= 0 && d.Index < len(vectors) {
+			vectors[d.Index] = d.Embedding
+		}
+	}
+	return checkCount(vectors, len(texts))
+}
+
+// postJSON sends body to url and decodes the JSON response into out
+func postJSON(ctx context.Context, client *http.Client, url, apiKey string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("embedding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err = io.ReadAll(io.LimitReader(resp.Body, maxEmbedResponse))
+	if err != nil {
+		return fmt.Errorf("failed to read embedding response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("embedding API returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid embedding response: %w", err)
+	}
+	return nil
+}
+
+// checkCount normalizes vectors, failing unless there is one per text
+func checkCount(vectors [][]float32, texts int) ([][]float32, error) {
+	if len(vectors) != texts {
+		return nil, fmt.Errorf("embedding API returned %d vectors for %d texts", len(vectors), texts)
+	}
+	for i, v := range vectors {
+		if len(v) == 0 {
+			return nil, fmt.Errorf("embedding API returned no vector for text %d", i)
+		}
+		vectors[i] = normalize(v)
+	}
+	return vectors, nil
+}
+
+// normalize scales v to unit length, so cosine similarity is a dot product
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+	scale := float32(1 / math.Sqrt(sum))
+	for i := range v {
+		v[i] *= scale
+	}
+	return v
+}
@@ -0,0 +1,266 @@
+// internal/providers/memory/memory.go
+
+// Package memory gives assistants long-term memory that is searched by
+// meaning. memory.remember splits text into chunks, embeds each and keeps
+// them in the shared state store; memory.recall embeds a query and returns
+// the most similar chunks.
+//
+// Embedders are pluggable: a model served by a local Ollama server, an
+// OpenAI-compatible embeddings API, or a built-in hashing embedder that
+// needs no model and matches shared words. Chunks are compared only with
+// queries embedded by the same embedder and model, so changing the model
+// leaves older memories unrecalled rather than wrongly ranked.
+package memory
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dkoosis/axe-handle/pkg/state"
+)
+
+// Provider defaults
+const (
+	Namespace        = "memory"
+	DefaultChunkSize = 1000
+	DefaultRecall    = 5
+	maxRecall        = 50
+	chunkPrefix      = "chunk/"
+)
+
+// Config configures a Provider
+type Config struct {
+	Embedder  Embedder // nil uses a HashEmbedder
+	ChunkSize int      // characters per chunk
+	Recall    int      // results memory.recall returns when not asked
+}
+
+// Provider stores and searches memories
+type Provider struct {
+	cfg Config
+	ns  *state.Namespace
+	now func() time.Time
+}
+
+// New creates a provider keeping its memories in store
+func New(store *state.Store, cfg Config) (*Provider, error) {
+	if store == nil {
+		return nil, errors.New("memory needs the state store; set storage.path")
+	}
+	if cfg.Embedder == nil {
+		cfg.Embedder = HashEmbedder{}
+	}
+	if cfg.ChunkSize <= 0 {
+		cfg.ChunkSize = DefaultChunkSize
+	}
+	if cfg.Recall <= 0 {
+		cfg.Recall = DefaultRecall
+	}
+	ns, err := store.Namespace(Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return &Provider{cfg: cfg, ns: ns, now: time.Now}, nil
+}
+
+// Memory is a recalled chunk
+type Memory struct {
+	ID      uint64    `json:"id"`
+	Part    int       `json:"part,omitempty"` // position of the chunk within a memory split into several
+	Text    string    `json:"text"`
+	Tags    []string  `json:"tags,omitempty"`
+	Created time.Time `json:"created"`
+	Score   float32   `json:"score"` // cosine similarity to the query, at most 1
+}
+
+// record is a stored chunk
+type record struct {
+	ID       uint64    `json:"id"`
+	Part     int       `json:"part"`
+	Text     string    `json:"text"`
+	Tags     []string  `json:"tags,omitempty"`
+	Created  time.Time `json:"created"`
+	Embedder string    `json:"embedder"`
+	Vector   []byte    `json:"vector"` // little-endian float32s
+}
+
+// Remember stores text as a new memory and returns its ID and how many
+// chunks it was split into
+func (p *Provider) Remember(ctx context.Context, text string, tags []string) (uint64, int, error) {
+	chunks := split(text, p.cfg.ChunkSize)
+	if len(chunks) == 0 {
+		return 0, 0, errors.New("nothing to remember")
+	}
+	vectors, err := p.cfg.Embedder.Embed(ctx, chunks)
+	if err != nil {
+		return 0, 0, err
+	}
+	id, err := p.ns.NextSequence()
+	if err != nil {
+		return 0, 0, err
+	}
+	created := p.now().UTC()
+	err = p.ns.Update(func(tx *state.Tx) error {
+		for i, chunk := range chunks {
+			data, err := json.Marshal(record{
+				ID:       id,
+				Part:     i,
+				Text:     chunk,
+				Tags:     tags,
+				Created:  created,
+				Embedder: p.cfg.Embedder.Name(),
+				Vector:   encodeVector(vectors[i]),
+			})
+			if err != nil {
+				return err
+			}
+			if err := tx.Put(fmt.Sprintf("%s%020d/%04d", chunkPrefix, id, i), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return id, len(chunks), nil
+}
+
+// Recall returns up to limit chunks related to query, most similar first,
+// from memories carrying all of tags. skipped counts chunks that were
+// embedded by another embedder and could not be compared.
+func (p *Provider) Recall(ctx context.Context, query string, limit int, tags []string) (found []Memory, skipped int, err error) {
+	vectors, err := p.cfg.Embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, 0, err
+	}
+	q := vectors[0]
+	name := p.cfg.Embedder.Name()
+
+	found = []Memory{}
+	err = p.ns.Scan(chunkPrefix, func(_ string, value []byte) error {
+		var r record
+		if err := json.Unmarshal(value, &r); err != nil {
+			return err
+		}
+		if !hasAll(r.Tags, tags) {
+			return nil
+		}
+		if r.Embedder != name {
+			skipped++
+			return nil
+		}
+		score := dot(q, r.Vector)
+		if score <= 0 {
+			return nil // unrelated
+		}
+		found = append(found, Memory{
+			ID:      r.ID,
+			Part:    r.Part,
+			Text:    r.Text,
+			Tags:    r.Tags,
+			Created: r.Created,
+			Score:   score,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Score > found[j].Score })
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	return found, skipped, nil
+}
+
+// split breaks text into chunks of at most size characters, keeping
+// paragraphs together where they fit and breaking long ones between words
+func split(text string, size int) []string {
+	var chunks []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+	}
+	for _, para := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		para = strings.TrimSpace(para)
+		for len(para) > size {
+			flush()
+			cut := strings.LastIndexAny(para[:size], " \n\t")
+			if cut <= 0 {
+				cut = size
+				for cut > 0 && !isRuneStart(para[cut]) {
+					cut--
+				}
+			}
+			chunks = append(chunks, strings.TrimSpace(para[:cut]))
+			para = strings.TrimSpace(para[cut:])
+		}
+		if para == "" {
+			continue
+		}
+		if current.Len() > 0 && current.Len()+2+len(para) > size {
+			flush()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n\n")
+		}
+		current.WriteString(para)
+	}
+	flush()
+	return chunks
+}
+
+// isRuneStart reports whether b begins a UTF-8 sequence
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// hasAll reports whether have contains every tag of want
+func hasAll(have, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if strings.EqualFold(h, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeVector packs v as little-endian float32s
+func encodeVector(v []float32) []byte {
+	buf := make([]byte, 4*len(v))
+	for i, x := range v {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(x))
+	}
+	return buf
+}
+
+// dot returns the dot product of q and an encoded vector of the same
+// length; vectors of another length score zero
+func dot(q []float32, encoded []byte) float32 {
+	if len(encoded) != 4*len(q) {
+		return 0
+	}
+	var sum float32
+	for i, x := range q {
+		sum += x * math.Float32frombits(binary.LittleEndian.Uint32(encoded[4*i:]))
+	}
+	return sum
+}
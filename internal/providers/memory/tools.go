@@ -0,0 +1,106 @@
+// internal/providers/memory/tools.go
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+)
+
+// Tool names
+const (
+	RememberToolName = "memory.remember"
+	RecallToolName   = "memory.recall"
+)
+
+// UsageHint returns advice on using memory for the server's instructions
+func (p *Provider) UsageHint() string {
+	return "Call " + RecallToolName + " before answering questions about the user or past work, and " +
+		RememberToolName + " for facts worth keeping across conversations."
+}
+
+// Register adds memory.remember and memory.recall to m
+func (p *Provider) Register(m *manager.ToolsManager) {
+	tags := map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}
+
+	f := false
+	m.RegisterTool(protocol.Tool{
+		Name: RememberToolName,
+		Description: "Store text in long-term memory so it can be recalled in later conversations, " +
+			"e.g. decisions, preferences or facts learned. Write it to make sense without the current conversation.",
+		InputSchema: map[string]interface{}{
+			"type":     "object",
+			"required": []string{"text"},
+			"properties": map[string]interface{}{
+				"text": map[string]interface{}{"type": "string", "minLength": 1},
+				"tags": tags,
+			},
+		},
+		Annotations: &protocol.ToolAnnotations{ReadOnlyHint: &f, DestructiveHint: &f, IdempotentHint: &f},
+	}, func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		var params struct {
+			Text string   `json:"text"`
+			Tags []string `json:"tags"`
+		}
+		if err := json.Unmarshal(args, &params); err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		id, chunks, err := p.Remember(ctx, params.Text, params.Tags)
+		if err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		text := fmt.Sprintf("Remembered as memory %d", id)
+		if chunks > 1 {
+			text += fmt.Sprintf(" in %d chunks", chunks)
+		}
+		return protocol.ToolsCallResult{Content: []protocol.Content{protocol.TextContent(text)}}, nil
+	})
+
+	m.RegisterTool(protocol.Tool{
+		Name:        RecallToolName,
+		Description: "Search long-term memory for stored text similar in meaning to the query, best matches first.",
+		InputSchema: map[string]interface{}{
+			"type":     "object",
+			"required": []string{"query"},
+			"properties": map[string]interface{}{
+				"query": map[string]interface{}{"type": "string", "minLength": 1},
+				"limit": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": maxRecall, "description": fmt.Sprintf("Results to return; %d by default", p.cfg.Recall)},
+				"tags":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Only memories carrying all of these tags"},
+			},
+		},
+		Annotations: protocol.ReadOnly(),
+	}, func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		var params struct {
+			Query string   `json:"query"`
+			Limit int      `json:"limit"`
+			Tags  []string `json:"tags"`
+		}
+		if err := json.Unmarshal(args, &params); err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		if params.Limit <= 0 {
+			params.Limit = p.cfg.Recall
+		}
+		found, skipped, err := p.Recall(ctx, params.Query, min(params.Limit, maxRecall), params.Tags)
+		if err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		result := map[string]interface{}{"memories": found}
+		if skipped > 0 {
+			result["note"] = fmt.Sprintf("%d stored chunks were embedded with another model and were not searched", skipped)
+		}
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		return protocol.ToolsCallResult{Content: []protocol.Content{protocol.TextContent(string(data))}}, nil
+	})
+}
+
+// ToolNames returns the names of the tools Register adds
+func (p *Provider) ToolNames() []string {
+	return []string{RememberToolName, RecallToolName}
+}
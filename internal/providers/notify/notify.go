@@ -0,0 +1,338 @@
+// internal/providers/notify/notify.go
+
+// Package notify posts messages to configured endpoints so an assistant can
+// report the outcome of long jobs. notify.slack posts to Slack incoming
+// webhooks and notify.webhook to any other HTTP endpoint that accepts JSON.
+//
+// Each endpoint may give a JSON payload template whose {{message}},
+// {{title}}, {{level}}, {{endpoint}} and {{time}} placeholders are filled
+// with JSON-escaped values. Each endpoint also accepts only a limited number
+// of messages per window, so a looping assistant cannot flood a channel;
+// further calls fail with the time until the next message is allowed.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/clock"
+	"github.com/dkoosis/axe-handle/internal/providers/declarative"
+)
+
+// Provider defaults
+const (
+	DefaultLimit    = 10
+	DefaultWindow   = time.Hour
+	defaultTimeout  = 10 * time.Second
+	maxResponseBody = 4 << 10
+)
+
+// Message levels
+const (
+	LevelInfo    = "info"
+	LevelSuccess = "success"
+	LevelWarning = "warning"
+	LevelError   = "error"
+)
+
+// Levels lists the accepted message levels
+var Levels = []string{LevelInfo, LevelSuccess, LevelWarning, LevelError}
+
+// Endpoint is a place messages are posted to
+type Endpoint struct {
+	Name     string
+	URL      string
+	Headers  map[string]string // values may reference environment variables as $VAR
+	Secret   string            // signs requests with X-Axe-Signature like tool webhooks
+	Template string            // JSON payload template; empty uses the endpoint kind's default
+	Limit    int               // messages per window; 0 uses the provider's limit
+}
+
+// Config configures a Provider
+type Config struct {
+	Slack    []Endpoint
+	Webhooks []Endpoint
+	Limit    int           // messages per endpoint per window
+	Window   time.Duration // period Limit applies to
+	Timeout  time.Duration // per request
+}
+
+// Message is one notification
+type Message struct {
+	Title   string `json:"title,omitempty"`
+	Message string `json:"message"`
+	Level   string `json:"level"`
+}
+
+// Provider posts messages to its endpoints
+type Provider struct {
+	cfg    Config
+	client *http.Client
+	clock  clock.Clock
+
+	mu   sync.Mutex
+	sent map[string][]time.Time // recent sends by endpoint key
+}
+
+// New checks cfg's endpoints and templates. A nil clock uses the system
+// clock.
+func New(cfg Config, clk clock.Clock) (*Provider, error) {
+	if clk == nil {
+		clk = clock.Real
+	}
+	if cfg.Limit <= 0 {
+		cfg.Limit = DefaultLimit
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultWindow
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	for kind, endpoints := range map[string][]Endpoint{"slack": cfg.Slack, "webhook": cfg.Webhooks} {
+		seen := make(map[string]bool, len(endpoints))
+		for i, e := range endpoints {
+			if e.Name == "" {
+				return nil, fmt.Errorf("%s endpoint %d has no name", kind, i)
+			}
+			if seen[e.Name] {
+				return nil, fmt.Errorf("%s endpoint %s is configured twice", kind, e.Name)
+			}
+			seen[e.Name] = true
+			if !strings.HasPrefix(e.URL, "https://") && !strings.HasPrefix(e.URL, "http://") {
+				return nil, fmt.Errorf("%s endpoint %s: url must be http or https", kind, e.Name)
+			}
+			if err := checkTemplate(e.Template); err != nil {
+				return nil, fmt.Errorf("%s endpoint %s: %w", kind, e.Name, err)
+			}
+		}
+	}
+	return &Provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		clock:  clk,
+		sent:   make(map[string][]time.Time),
+	}, nil
+}
+
+// RateLimitError reports an endpoint that has taken its share of messages
+type RateLimitError struct {
+	Endpoint   string
+	Limit      int
+	Window     time.Duration
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s accepts %d messages per %s; the next can be sent in %s",
+		e.Endpoint, e.Limit, e.Window, e.RetryAfter.Round(time.Second))
+}
+
+// StatusError reports an endpoint that refused a message
+type StatusError struct {
+	Endpoint string
+	Status   string
+	Body     string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("%s returned %s", e.Endpoint, e.Status)
+	}
+	return fmt.Sprintf("%s returned %s: %s", e.Endpoint, e.Status, e.Body)
+}
+
+// Slack posts msg to the named Slack webhook
+func (p *Provider) Slack(ctx context.Context, name string, msg Message) error {
+	e, ok := find(p.cfg.Slack, name)
+	if !ok {
+		return fmt.Errorf("unknown Slack channel %q", name)
+	}
+	return p.post(ctx, "slack:"+name, e, msg, slackPayload)
+}
+
+// Webhook posts msg to the named webhook
+func (p *Provider) Webhook(ctx context.Context, name string, msg Message) error {
+	e, ok := find(p.cfg.Webhooks, name)
+	if !ok {
+		return fmt.Errorf("unknown webhook %q", name)
+	}
+	return p.post(ctx, "webhook:"+name, e, msg, webhookPayload)
+}
+
+// post renders msg for e and sends it if e's rate limit allows
+func (p *Provider) post(ctx context.Context, key string, e Endpoint, msg Message, defaultPayload func(Message, time.Time) ([]byte, error)) error {
+	if msg.Level == "" {
+		msg.Level = LevelInfo
+	}
+	now := p.clock.Now()
+	var (
+		body []byte
+		err  error
+	)
+	if e.Template != "" {
+		body = render(e.Template, values(e.Name, msg, now))
+	} else if body, err = defaultPayload(msg, now); err != nil {
+		return err
+	}
+	if err := p.take(key, e); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.Headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+	if e.Secret != "" {
+		timestamp := strconv.FormatInt(now.Unix(), 10)
+		req.Header.Set("X-Axe-Timestamp", timestamp)
+		req.Header.Set("X-Axe-Signature", declarative.Sign(e.Secret, timestamp, body))
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting to %s failed: %w", e.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+		return &StatusError{Endpoint: e.Name, Status: resp.Status, Body: strings.TrimSpace(string(data))}
+	}
+	return nil
+}
+
+// take records a send to an endpoint, or returns a RateLimitError when its
+// limit for the current window is used up
+func (p *Provider) take(key string, e Endpoint) error {
+	limit := e.Limit
+	if limit <= 0 {
+		limit = p.cfg.Limit
+	}
+	now := p.clock.Now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	recent := p.sent[key]
+	for len(recent) > 0 && now.Sub(recent[0]) >= p.cfg.Window {
+		recent = recent[1:]
+	}
+	if len(recent) >= limit {
+		p.sent[key] = recent
+		return &RateLimitError{Endpoint: e.Name, Limit: limit, Window: p.cfg.Window, RetryAfter: p.cfg.Window - now.Sub(recent[0])}
+	}
+	p.sent[key] = append(recent, now)
+	return nil
+}
+
+// SlackNames returns the configured Slack channels
+func (p *Provider) SlackNames() []string {
+	return names(p.cfg.Slack)
+}
+
+// WebhookNames returns the configured webhooks
+func (p *Provider) WebhookNames() []string {
+	return names(p.cfg.Webhooks)
+}
+
+func names(endpoints []Endpoint) []string {
+	list := make([]string, len(endpoints))
+	for i, e := range endpoints {
+		list[i] = e.Name
+	}
+	return list
+}
+
+func find(endpoints []Endpoint, name string) (Endpoint, bool) {
+	for _, e := range endpoints {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return Endpoint{}, false
+}
+
+// slackPayload formats msg as Slack mrkdwn text
+func slackPayload(msg Message, _ time.Time) ([]byte, error) {
+	var text strings.Builder
+	switch msg.Level {
+	case LevelSuccess:
+		text.WriteString(":white_check_mark: ")
+	case LevelWarning:
+		text.WriteString(":warning: ")
+	case LevelError:
+		text.WriteString(":x: ")
+	}
+	if msg.Title != "" {
+		text.WriteString("*" + msg.Title + "*\n")
+	}
+	text.WriteString(msg.Message)
+	return json.Marshal(map[string]string{"text": text.String()})
+}
+
+// webhookPayload sends msg's fields with the time it was sent
+func webhookPayload(msg Message, now time.Time) ([]byte, error) {
+	return json.Marshal(struct {
+		Message
+		Time time.Time `json:"time"`
+	}{msg, now.UTC()})
+}
+
+// placeholderPattern matches {{name}} in payload templates
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}`)
+
+// templateFields are the placeholders a payload template may use
+var templateFields = []string{"message", "title", "level", "endpoint", "time"}
+
+// values returns the placeholder values for msg
+func values(endpoint string, msg Message, now time.Time) map[string]string {
+	return map[string]string{
+		"message":  msg.Message,
+		"title":    msg.Title,
+		"level":    msg.Level,
+		"endpoint": endpoint,
+		"time":     now.UTC().Format(time.RFC3339),
+	}
+}
+
+// render fills tmpl's placeholders with vals escaped for use inside JSON
+// strings
+func render(tmpl string, vals map[string]string) []byte {
+	return []byte(placeholderPattern.ReplaceAllStringFunc(tmpl, func(m string) string {
+		quoted, _ := json.Marshal(vals[placeholderPattern.FindStringSubmatch(m)[1]])
+		return string(quoted[1 : len(quoted)-1])
+	}))
+}
+
+// checkTemplate rejects templates with unknown placeholders or that do not
+// render to JSON
+func checkTemplate(tmpl string) error {
+	if tmpl == "" {
+		return nil
+	}
+	sample := make(map[string]string, len(templateFields))
+	for _, name := range templateFields {
+		sample[name] = name
+	}
+	for _, m := range placeholderPattern.FindAllStringSubmatch(tmpl, -1) {
+		if _, ok := sample[m[1]]; !ok {
+			return fmt.Errorf("template has unknown placeholder {{%s}}; use %s", m[1], strings.Join(templateFields, ", "))
+		}
+	}
+	if !json.Valid(render(tmpl, sample)) {
+		return fmt.Errorf("template does not render to JSON")
+	}
+	return nil
+}
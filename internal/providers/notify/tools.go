@@ -0,0 +1,103 @@
+// internal/providers/notify/tools.go
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+)
+
+// Tool names
+const (
+	SlackToolName   = "notify.slack"
+	WebhookToolName = "notify.webhook"
+)
+
+// Register adds notify.slack and notify.webhook to m, each only when it has
+// endpoints to post to
+func (p *Provider) Register(m *manager.ToolsManager) {
+	if len(p.cfg.Slack) > 0 {
+		m.RegisterTool(p.tool(SlackToolName, "channel", p.SlackNames(),
+			"Post a message to a Slack channel, e.g. to report the result of a long job."), p.handler("channel", p.Slack))
+	}
+	if len(p.cfg.Webhooks) > 0 {
+		m.RegisterTool(p.tool(WebhookToolName, "endpoint", p.WebhookNames(),
+			"Post a notification to a configured webhook, e.g. to report the result of a long job."), p.handler("endpoint", p.Webhook))
+	}
+}
+
+// ToolNames returns the names of the tools Register adds
+func (p *Provider) ToolNames() []string {
+	var list []string
+	if len(p.cfg.Slack) > 0 {
+		list = append(list, SlackToolName)
+	}
+	if len(p.cfg.Webhooks) > 0 {
+		list = append(list, WebhookToolName)
+	}
+	return list
+}
+
+// tool describes a notify tool whose target argument picks one of names
+func (p *Provider) tool(name, target string, names []string, description string) protocol.Tool {
+	f, t := false, true
+	return protocol.Tool{
+		Name: name,
+		Description: fmt.Sprintf("%s Each %s accepts at most %d messages per %s; send one summary rather than a message per step.",
+			description, target, p.cfg.Limit, p.cfg.Window),
+		InputSchema: map[string]interface{}{
+			"type":     "object",
+			"required": []string{"message"},
+			"properties": map[string]interface{}{
+				target:    map[string]interface{}{"type": "string", "enum": names, "description": fmt.Sprintf("%s by default", names[0])},
+				"message": map[string]interface{}{"type": "string", "minLength": 1},
+				"title":   map[string]interface{}{"type": "string"},
+				"level":   map[string]interface{}{"type": "string", "enum": Levels, "description": "info by default"},
+			},
+		},
+		Annotations: &protocol.ToolAnnotations{ReadOnlyHint: &f, DestructiveHint: &f, IdempotentHint: &f, OpenWorldHint: &t},
+	}
+}
+
+// handler reads a notify tool's arguments and posts the message with send
+func (p *Provider) handler(target string, send func(context.Context, string, Message) error) manager.ToolHandler {
+	return func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		var params map[string]string
+		if err := json.Unmarshal(args, &params); err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		name := params[target]
+		if name == "" {
+			name = p.defaultName(target)
+		}
+		msg := Message{Title: params["title"], Message: params["message"], Level: params["level"]}
+		err := send(ctx, name, msg)
+		var rateErr *RateLimitError
+		var statusErr *StatusError
+		switch {
+		case errors.As(err, &rateErr), errors.As(err, &statusErr):
+			return protocol.ToolsCallResult{
+				Content: []protocol.Content{protocol.TextContent(err.Error())},
+				IsError: true,
+			}, nil
+		case err != nil:
+			return protocol.ToolsCallResult{}, err
+		}
+		return protocol.ToolsCallResult{
+			Content: []protocol.Content{protocol.TextContent("Posted to " + name)},
+		}, nil
+	}
+}
+
+// defaultName returns the first endpoint of the kind target selects
+func (p *Provider) defaultName(target string) string {
+	endpoints := p.cfg.Webhooks
+	if target == "channel" {
+		endpoints = p.cfg.Slack
+	}
+	return endpoints[0].Name
+}
@@ -0,0 +1,301 @@
+// internal/providers/plugin/plugin.go
+
+// Package plugin serves the tools of external MCP servers. A plugin is a
+// command that speaks MCP over stdio with newline-delimited JSON; it runs
+// under the server's supervisor, which restarts it with backoff when it
+// exits, and its tools are registered as <plugin>.<tool> while it is up.
+// New capabilities can then be added by configuration, in any language,
+// without rebuilding the server.
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/buildinfo"
+	"github.com/dkoosis/axe-handle/internal/codec"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/server/supervisor"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// defaultTimeout bounds starting a plugin and listing its tools
+const defaultTimeout = 30 * time.Second
+
+// Config configures a Plugin
+type Config struct {
+	Name    string   // prefixes the plugin's tools
+	Command []string // program and arguments
+	Dir     string   // working directory; empty uses the server's
+	Env     []string // KEY=value pairs added to the server's environment
+	Timeout time.Duration
+}
+
+// Plugin runs one external MCP server and serves its tools
+type Plugin struct {
+	cfg   Config
+	tools *manager.ToolsManager
+
+	mu    sync.Mutex
+	pipes *pipes         // set by command, taken by start
+	conn  *jsonrpc2.Conn // nil while the plugin is down
+	names []string       // tools registered for the plugin
+}
+
+// pipes connect the server to a starting plugin. They are plain OS pipes
+// rather than exec's, so the supervisor waiting for the process does not
+// close them under the connection.
+type pipes struct {
+	stdin, stdout     *os.File // the server's ends
+	childIn, childOut *os.File // the plugin's ends, closed here once it has started
+	err               error
+}
+
+// Read implements io.Reader on the plugin's stdout
+func (p *pipes) Read(b []byte) (int, error) { return p.stdout.Read(b) }
+
+// Write implements io.Writer on the plugin's stdin
+func (p *pipes) Write(b []byte) (int, error) { return p.stdin.Write(b) }
+
+// Close closes the server's ends, which ends a well-behaved plugin
+func (p *pipes) Close() error {
+	return errors.Join(p.stdin.Close(), p.stdout.Close())
+}
+
+// New creates a plugin whose tools are registered with m while it runs
+func New(cfg Config, m *manager.ToolsManager) (*Plugin, error) {
+	if cfg.Name == "" || len(cfg.Command) == 0 {
+		return nil, fmt.Errorf("a plugin needs a name and a command")
+	}
+	if strings.ContainsAny(cfg.Name, ". ") {
+		return nil, fmt.Errorf("plugin name %q must not contain dots or spaces", cfg.Name)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	return &Plugin{cfg: cfg, tools: m}, nil
+}
+
+// Child describes the plugin to the supervisor
+func (p *Plugin) Child() supervisor.Child {
+	return supervisor.Child{
+		Name:    p.source(),
+		Command: p.command,
+		OnStart: p.start,
+		OnStop:  p.stop,
+	}
+}
+
+// Names returns the names the plugin's tools are currently served under
+func (p *Plugin) Names() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.names...)
+}
+
+// command builds the plugin's process with fresh pipes
+func (p *Plugin) command() *exec.Cmd {
+	cmd := exec.Command(p.cfg.Command[0], p.cfg.Command[1:]...)
+	cmd.Dir = p.cfg.Dir
+	cmd.Env = append(os.Environ(), p.cfg.Env...)
+	cmd.Stderr = os.Stderr
+
+	pp := &pipes{}
+	var inR, outW *os.File
+	if inR, pp.stdin, pp.err = os.Pipe(); pp.err == nil {
+		if pp.stdout, outW, pp.err = os.Pipe(); pp.err != nil {
+			inR.Close()
+			pp.stdin.Close()
+		}
+	}
+	if pp.err == nil {
+		cmd.Stdin, cmd.Stdout = inR, outW
+		pp.childIn, pp.childOut = inR, outW
+	}
+
+	p.mu.Lock()
+	p.pipes = pp
+	p.mu.Unlock()
+	return cmd
+}
+
+// start initializes the running plugin and registers its tools
+func (p *Plugin) start(*exec.Cmd) error {
+	p.mu.Lock()
+	pp := p.pipes
+	p.pipes = nil
+	p.mu.Unlock()
+	if pp == nil {
+		return fmt.Errorf("plugin %s started without pipes", p.cfg.Name)
+	}
+	if pp.err != nil {
+		return pp.err
+	}
+	// The plugin holds its own copies now
+	pp.childIn.Close()
+	pp.childOut.Close()
+
+	conn := jsonrpc2.NewConn(context.Background(), codec.NewFramedStream(pp, codec.FramingNDJSON),
+		jsonrpc2.HandlerWithError(p.handle))
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+	defer cancel()
+
+	var init protocol.InitializeResult
+	err := conn.Call(ctx, protocol.MethodInitialize, protocol.InitializeParams{
+		ProtocolVersion: protocol.LatestProtocolVersion,
+		ClientInfo:      protocol.Implementation{Name: "axe-handle", Version: buildinfo.Get().Version},
+	}, &init)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("initialize: %w", err)
+	}
+	if err := conn.Notify(ctx, protocol.NotificationInitialized, nil); err != nil {
+		conn.Close()
+		return err
+	}
+
+	p.mu.Lock()
+	p.conn = conn
+	p.mu.Unlock()
+	if err := p.refresh(ctx); err != nil {
+		p.stop()
+		return fmt.Errorf("listing tools: %w", err)
+	}
+	slog.Info("Plugin ready", "plugin", p.cfg.Name, "server", init.ServerInfo.Name, "tools", p.Names())
+	return nil
+}
+
+// stop drops the connection and unregisters the plugin's tools
+func (p *Plugin) stop() {
+	p.mu.Lock()
+	conn, names := p.conn, p.names
+	p.conn, p.names = nil, nil
+	p.mu.Unlock()
+
+	if conn != nil {
+		conn.Close()
+	}
+	if len(names) > 0 {
+		if err := p.tools.ReplaceSource(p.source(), nil, nil); err != nil {
+			slog.Warn("Failed to unregister plugin tools", "plugin", p.cfg.Name, "error", err)
+		}
+	}
+}
+
+// source is the name the plugin's tools are owned by in the tools manager
+func (p *Plugin) source() string {
+	return "plugin." + p.cfg.Name
+}
+
+// handle answers the plugin's own messages. Pings are answered and tool
+// list changes re-read; the server does not offer clients' features, such
+// as sampling, to plugins.
+func (p *Plugin) handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (interface{}, error) {
+	switch req.Method {
+	case protocol.MethodPing:
+		return struct{}{}, nil
+	case protocol.NotificationToolsListChanged:
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+			defer cancel()
+			if err := p.refresh(ctx); err != nil {
+				slog.Warn("Failed to refresh plugin tools", "plugin", p.cfg.Name, "error", err)
+			}
+		}()
+		return nil, nil
+	}
+	return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeMethodNotFound, Message: "method not supported: " + req.Method}
+}
+
+// refresh lists the plugin's tools and registers them, dropping any it no
+// longer offers
+func (p *Plugin) refresh(ctx context.Context) error {
+	p.mu.Lock()
+	conn := p.conn
+	p.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+
+	var tools []protocol.Tool
+	cursor := ""
+	for {
+		var page struct {
+			Tools      []protocol.Tool `json:"tools"`
+			NextCursor string          `json:"nextCursor"`
+		}
+		params := map[string]interface{}{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		if err := conn.Call(ctx, protocol.MethodToolsList, params, &page); err != nil {
+			return err
+		}
+		tools = append(tools, page.Tools...)
+		if page.NextCursor == "" || page.NextCursor == cursor {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	names := make([]string, 0, len(tools))
+	handlers := make(map[string]manager.ToolHandler, len(tools))
+	for i, tool := range tools {
+		remote := tool.Name
+		tool.Name = p.cfg.Name + "." + remote
+		if tool.InputSchema == nil {
+			tool.InputSchema = map[string]interface{}{"type": "object"}
+		}
+		handlers[tool.Name] = func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+			return p.call(ctx, remote, args)
+		}
+		tools[i] = tool
+		names = append(names, tool.Name)
+	}
+
+	// One swap, so clients see the old list or the new one and are told once
+	if err := p.tools.ReplaceSource(p.source(), tools, handlers); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.names = names
+	p.mu.Unlock()
+	return nil
+}
+
+// call forwards a tool call to the plugin. Errors the plugin reports are
+// tool errors the model can act on; a plugin that is down is a Go error.
+func (p *Plugin) call(ctx context.Context, name string, args json.RawMessage) (protocol.ToolsCallResult, error) {
+	p.mu.Lock()
+	conn := p.conn
+	p.mu.Unlock()
+	if conn == nil {
+		return protocol.ToolsCallResult{}, fmt.Errorf("plugin %s is not running", p.cfg.Name)
+	}
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+
+	var result protocol.ToolsCallResult
+	err := conn.Call(ctx, protocol.MethodToolsCall, map[string]interface{}{"name": name, "arguments": args}, &result)
+	var rpcErr *jsonrpc2.Error
+	if errors.As(err, &rpcErr) {
+		return protocol.ToolsCallResult{
+			Content: []protocol.Content{protocol.TextContent(rpcErr.Message)},
+			IsError: true,
+		}, nil
+	}
+	if err != nil {
+		return protocol.ToolsCallResult{}, err
+	}
+	return result, nil
+}
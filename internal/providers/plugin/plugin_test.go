@@ -0,0 +1,110 @@
+// internal/providers/plugin/plugin_test.go
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/dkoosis/axe-handle/internal/events"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// fakePlugin answers tools/list with whatever tools it currently offers
+type fakePlugin struct {
+	mu    sync.Mutex
+	tools []string
+}
+
+func (f *fakePlugin) offer(names ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tools = names
+}
+
+func (f *fakePlugin) handle(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var page struct {
+		Tools []protocol.Tool `json:"tools"`
+	}
+	for _, name := range f.tools {
+		page.Tools = append(page.Tools, protocol.Tool{Name: name})
+	}
+	return page, nil
+}
+
+// connect serves f to p over an in-memory pipe
+func connect(t *testing.T, p *Plugin, f *fakePlugin) {
+	server, client := net.Pipe()
+	codec := jsonrpc2.PlainObjectCodec{}
+	remote := jsonrpc2.NewConn(context.Background(), jsonrpc2.NewBufferedStream(server, codec), jsonrpc2.HandlerWithError(f.handle))
+	conn := jsonrpc2.NewConn(context.Background(), jsonrpc2.NewBufferedStream(client, codec), jsonrpc2.HandlerWithError(p.handle))
+	t.Cleanup(func() {
+		conn.Close()
+		remote.Close()
+	})
+	p.mu.Lock()
+	p.conn = conn
+	p.mu.Unlock()
+}
+
+func listed(m *manager.ToolsManager) []string {
+	var names []string
+	for _, tool := range m.ListTools() {
+		names = append(names, tool.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestRefreshSwapsToolsOnce(t *testing.T) {
+	m := manager.NewToolsManager()
+	m.RegisterTool(protocol.Tool{Name: "builtin", InputSchema: map[string]interface{}{"type": "object"}},
+		func(context.Context, json.RawMessage, chan<- float64) (protocol.ToolsCallResult, error) {
+			return protocol.ToolsCallResult{}, nil
+		})
+	bus := events.NewBus()
+	var changes int
+	bus.Subscribe(events.ToolsChanged, func(events.Event) { changes++ })
+	bus.Subscribe(events.ToolRegistered, func(events.Event) { changes++ })
+	m.SetEventBus(bus)
+
+	p, err := New(Config{Name: "fake", Command: []string{"fake"}}, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakePlugin{}
+	connect(t, p, f)
+	ctx := context.Background()
+
+	f.offer("one", "two", "three")
+	if err := p.refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	f.offer("two", "four")
+	if err := p.refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"builtin", "fake.four", "fake.two"}
+	if got := listed(m); !slices.Equal(got, want) {
+		t.Fatalf("tools = %v, want %v", got, want)
+	}
+	if changes != 2 {
+		t.Errorf("two refreshes announced %d changes, want 2", changes)
+	}
+
+	p.stop()
+	if got := listed(m); !slices.Equal(got, []string{"builtin"}) {
+		t.Fatalf("tools after stop = %v, want only builtin", got)
+	}
+	if changes != 3 {
+		t.Errorf("stop announced %d changes, want 1", changes-2)
+	}
+}
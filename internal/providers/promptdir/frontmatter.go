@@ -0,0 +1,103 @@
+// internal/providers/promptdir/frontmatter.go
+package promptdir
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/prompts"
+	"gopkg.in/yaml.v3"
+)
+
+// frontMatterDelimiter opens and closes the YAML block at the top of a file
+const frontMatterDelimiter = "---"
+
+// namePattern is what prompt, partial and argument names may look like
+var namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// frontMatter is the YAML header of a prompt file:
+//
+//	---
+//	name: review
+//	description: Review a file
+//	arguments:
+//	  - name: file
+//	    description: Path of the file to review
+//	---
+type frontMatter struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Arguments   []struct {
+		Name        string `yaml:"name"`
+		Description string `yaml:"description"`
+		Required    *bool  `yaml:"required"` // defaults to true
+	} `yaml:"arguments"`
+}
+
+// splitFrontMatter separates a leading front matter block from the body.
+// Files without one return a nil header and the text unchanged.
+func splitFrontMatter(text string) (*frontMatter, string, error) {
+	text = strings.TrimPrefix(text, "\ufeff") // editors may add a byte order mark
+	first, rest, _ := strings.Cut(text, "\n")
+	if strings.TrimSpace(first) != frontMatterDelimiter {
+		return nil, text, nil
+	}
+
+	var header strings.Builder
+	for rest != "" {
+		var line string
+		line, rest, _ = strings.Cut(rest, "\n")
+		if strings.TrimSpace(line) == frontMatterDelimiter {
+			meta, err := decodeFrontMatter(header.String())
+			return meta, rest, err
+		}
+		header.WriteString(line)
+		header.WriteByte('\n')
+	}
+	return nil, "", fmt.Errorf("front matter is not closed with %s", frontMatterDelimiter)
+}
+
+// decodeFrontMatter parses the YAML header, rejecting unknown keys so typos
+// surface in review rather than being silently ignored
+func decodeFrontMatter(header string) (*frontMatter, error) {
+	meta := &frontMatter{}
+	dec := yaml.NewDecoder(bytes.NewBufferString(header))
+	dec.KnownFields(true)
+	if err := dec.Decode(meta); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid front matter: %w", err)
+	}
+	return meta, nil
+}
+
+// apply copies the header's fields onto t
+func (m *frontMatter) apply(t *template) error {
+	if m.Name != "" {
+		if !namePattern.MatchString(m.Name) {
+			return fmt.Errorf("invalid name %q", m.Name)
+		}
+		t.prompt.Name = m.Name
+	}
+	t.prompt.Description = m.Description
+
+	for _, a := range m.Arguments {
+		if !namePattern.MatchString(a.Name) {
+			return fmt.Errorf("invalid argument name %q", a.Name)
+		}
+		for _, d := range t.declared {
+			if d.Name == a.Name {
+				return fmt.Errorf("argument %q declared twice", a.Name)
+			}
+		}
+		required := a.Required == nil || *a.Required
+		t.declared = append(t.declared, prompts.PromptArgument{
+			Name:        a.Name,
+			Description: a.Description,
+			Required:    required,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// internal/providers/promptdir/include.go
+package promptdir
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/prompts"
+)
+
+// PartialsDir is the subdirectory holding shared fragments
+const PartialsDir = "partials"
+
+// maxIncludeDepth bounds how deeply includes may nest
+const maxIncludeDepth = 8
+
+// includePattern matches {{> name}} includes in a prompt body
+var includePattern = regexp.MustCompile(`\{\{>\s*([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}`)
+
+// library is a consistent set of prompts and the partials they include
+type library struct {
+	prompts  map[string]*template
+	partials map[string]*template
+}
+
+// lookup finds an include target; partials shadow prompts of the same name
+func (l *library) lookup(name string) (*template, bool) {
+	if t, ok := l.partials[name]; ok {
+		return t, true
+	}
+	t, ok := l.prompts[name]
+	return t, ok
+}
+
+// link checks every include resolves without cycles and gives each prompt
+// the arguments of everything it includes, so clients see the full set
+func (l *library) link() error {
+	for _, t := range l.prompts {
+		args, err := l.arguments(t, nil)
+		if err != nil {
+			return fmt.Errorf("prompt %s: %w", t.prompt.Name, err)
+		}
+		t.prompt.Arguments = args
+	}
+	return nil
+}
+
+// arguments returns t's own arguments followed by those of its includes.
+// path holds the templates being expanded, to detect cycles.
+func (l *library) arguments(t *template, path []string) ([]prompts.PromptArgument, error) {
+	for _, name := range path {
+		if name == t.prompt.Name {
+			return nil, fmt.Errorf("include cycle through %s", name)
+		}
+	}
+	if len(path) > maxIncludeDepth {
+		return nil, fmt.Errorf("includes nested deeper than %d", maxIncludeDepth)
+	}
+	path = append(path, t.prompt.Name)
+
+	args := own(t)
+	for _, name := range t.includes {
+		inc, ok := l.lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown include %q", name)
+		}
+		incArgs, err := l.arguments(inc, path)
+		if err != nil {
+			return nil, err
+		}
+		args = mergeArguments(args, incArgs)
+	}
+	return args, nil
+}
+
+// own returns t's declared arguments followed by any other placeholders
+func own(t *template) []prompts.PromptArgument {
+	args := append([]prompts.PromptArgument(nil), t.declared...)
+	for _, m := range placeholderPattern.FindAllStringSubmatch(t.body, -1) {
+		args = mergeArguments(args, []prompts.PromptArgument{{Name: m[1], Required: true}})
+	}
+	return args
+}
+
+// mergeArguments appends the arguments of more not already in args
+func mergeArguments(args, more []prompts.PromptArgument) []prompts.PromptArgument {
+	for _, a := range more {
+		found := false
+		for _, b := range args {
+			if a.Name == b.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			args = append(args, a)
+		}
+	}
+	return args
+}
+
+// expand returns t's body with its includes substituted. link has already
+// rejected cycles, so depth only guards against a library changed under us.
+func (l *library) expand(t *template, depth int) (string, error) {
+	if depth > maxIncludeDepth {
+		return "", fmt.Errorf("includes nested deeper than %d", maxIncludeDepth)
+	}
+
+	var expandErr error
+	body := includePattern.ReplaceAllStringFunc(t.body, func(m string) string {
+		name := includePattern.FindStringSubmatch(m)[1]
+		inc, ok := l.lookup(name)
+		if !ok {
+			expandErr = fmt.Errorf("unknown include %q", name)
+			return ""
+		}
+		text, err := l.expand(inc, depth+1)
+		if err != nil {
+			expandErr = err
+		}
+		return text
+	})
+	return body, expandErr
+}
+
+// loadOptional loads dir like load, treating a missing directory as empty
+func loadOptional(dir string) (map[string]*template, error) {
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return map[string]*template{}, nil
+	}
+	return load(dir)
+}
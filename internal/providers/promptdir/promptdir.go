@@ -0,0 +1,186 @@
+// internal/providers/promptdir/promptdir.go
+
+// Package promptdir serves prompt templates from a directory, one file per
+// prompt. Files are reloaded when they change so prompts can be edited
+// without restarting the server.
+package promptdir
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/prompts"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+)
+
+// extensions are the file types loaded as prompts
+var extensions = map[string]bool{".md": true, ".txt": true}
+
+// placeholderPattern matches {{name}} placeholders in a prompt body
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}`)
+
+// template is one loaded prompt or partial file
+type template struct {
+	prompt   prompts.Prompt
+	body     string
+	declared []prompts.PromptArgument // from front matter
+	includes []string                 // names from {{> name}}, in order of appearance
+}
+
+// Provider serves the prompt files in a directory. Files are Markdown with
+// optional YAML front matter giving the name, description and arguments.
+// The file name is the default prompt name, and each undeclared {{name}}
+// placeholder in the body is a required argument. Files under partials/
+// are fragments that prompts pull in with {{> name}}; they are not listed.
+type Provider struct {
+	dir string
+	lib *library
+	mu  sync.RWMutex
+}
+
+// Ensure Provider implements prompts.Provider
+var _ prompts.Provider = (*Provider)(nil)
+
+// New loads the prompts in dir
+func New(dir string) (*Provider, error) {
+	p := &Provider{dir: dir}
+	if _, err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Reload re-reads the directory and reports whether the prompts changed.
+// If any file is invalid the current prompts are kept.
+func (p *Provider) Reload() (bool, error) {
+	prompts, err := load(p.dir)
+	if err != nil {
+		return false, err
+	}
+	partials, err := loadOptional(filepath.Join(p.dir, PartialsDir))
+	if err != nil {
+		return false, err
+	}
+	lib := &library{prompts: prompts, partials: partials}
+	if err := lib.link(); err != nil {
+		return false, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if reflect.DeepEqual(p.lib, lib) {
+		return false, nil
+	}
+	p.lib = lib
+	return true, nil
+}
+
+// ListPrompts returns the loaded prompts sorted by name
+func (p *Provider) ListPrompts() ([]prompts.Prompt, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	list := make([]prompts.Prompt, 0, len(p.lib.prompts))
+	for _, t := range p.lib.prompts {
+		list = append(list, t.prompt)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// GetPrompt renders the named prompt as a single user message
+func (p *Provider) GetPrompt(name string, args map[string]string) (interface{}, error) {
+	p.mu.RLock()
+	lib := p.lib
+	p.mu.RUnlock()
+
+	t, ok := lib.prompts[name]
+	if !ok {
+		return nil, prompts.ErrPromptNotFound
+	}
+	body, err := lib.expand(t, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	text := placeholderPattern.ReplaceAllStringFunc(body, func(m string) string {
+		return args[placeholderPattern.FindStringSubmatch(m)[1]]
+	})
+	return protocol.PromptsGetResult{
+		Description: t.prompt.Description,
+		Messages:    []protocol.PromptMessage{protocol.UserMessage(protocol.TextContent(text))},
+	}, nil
+}
+
+// load reads and validates every prompt file in dir
+func load(dir string) (map[string]*template, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt directory %s: %w", dir, err)
+	}
+
+	templates := make(map[string]*template)
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || !extensions[ext] {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompt %s: %w", path, err)
+		}
+
+		t, err := parse(strings.TrimSuffix(entry.Name(), ext), data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prompt %s: %w", path, err)
+		}
+		if _, dup := templates[t.prompt.Name]; dup {
+			return nil, fmt.Errorf("prompt %s: defined by more than one file", t.prompt.Name)
+		}
+		templates[t.prompt.Name] = t
+	}
+	return templates, nil
+}
+
+// parse builds a template from a prompt file's contents. name is used
+// unless the front matter sets one.
+func parse(name string, data []byte) (*template, error) {
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("not valid UTF-8")
+	}
+	meta, body, err := splitFrontMatter(string(data))
+	if err != nil {
+		return nil, err
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, fmt.Errorf("empty prompt")
+	}
+
+	matches := placeholderPattern.FindAllStringSubmatch(body, -1)
+	includes := includePattern.FindAllStringSubmatch(body, -1)
+	if strings.Count(body, "{{") != len(matches)+len(includes) {
+		return nil, fmt.Errorf("malformed placeholder")
+	}
+
+	t := &template{prompt: prompts.Prompt{Name: name}, body: body}
+	if meta != nil {
+		if err := meta.apply(t); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range includes {
+		t.includes = append(t.includes, m[1])
+	}
+	t.prompt.Arguments = own(t)
+	return t, nil
+}
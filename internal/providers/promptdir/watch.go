@@ -0,0 +1,68 @@
+// internal/providers/promptdir/watch.go
+package promptdir
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/resources/watch"
+)
+
+// Notifier receives prompt list changes; *server.Server implements it
+type Notifier interface {
+	NotifyPromptsListChanged()
+}
+
+// reloader adapts filesystem events to prompt reloads
+type reloader struct {
+	provider *Provider
+	notifier Notifier
+}
+
+// Ensure reloader can receive watcher events
+var _ watch.Notifier = reloader{}
+
+// NotifyResourceUpdated reloads after a prompt file is written
+func (r reloader) NotifyResourceUpdated(string) {
+	r.reload()
+}
+
+// NotifyResourcesListChanged reloads after prompt files are added or removed
+func (r reloader) NotifyResourcesListChanged() {
+	r.reload()
+}
+
+// reload re-reads the prompts and tells clients if they changed. Invalid
+// edits are logged and the last good prompts stay in service.
+func (r reloader) reload() {
+	changed, err := r.provider.Reload()
+	if err != nil {
+		slog.Error("Failed to reload prompts, keeping previous set", "dir", r.provider.dir, "error", err)
+		return
+	}
+	if changed {
+		slog.Info("Prompts reloaded", "dir", r.provider.dir)
+		r.notifier.NotifyPromptsListChanged()
+	}
+}
+
+// Watch reloads the prompts whenever files in the directory or its
+// partials change and reports changes to notifier. It blocks until ctx is
+// done.
+func (p *Provider) Watch(ctx context.Context, notifier Notifier) error {
+	w, err := watch.NewWatcher(reloader{provider: p, notifier: notifier},
+		watch.WithRecursive(true),
+		watch.WithFilter(func(path string) bool {
+			return extensions[filepath.Ext(path)] || filepath.Base(path) == PartialsDir
+		}))
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	if err := w.Add(p.dir); err != nil {
+		return err
+	}
+	return w.Run(ctx)
+}
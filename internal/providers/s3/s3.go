@@ -0,0 +1,488 @@
+// internal/providers/s3/s3.go
+
+// Package s3 serves objects in Amazon S3 or S3-compatible storage such as
+// MinIO. Each configured bucket or bucket prefix is browsable: a
+// s3://<bucket>/<prefix>/ resource lists the objects and sub-prefixes under
+// it as JSON, and each object is a s3://<bucket>/<key> resource read in
+// chunks with ranged requests, so large objects are never held in memory.
+//
+// The s3.presign tool hands out time-limited download URLs, and when the
+// configuration allows writes, upload URLs and the s3.put tool. Requests are
+// signed with AWS Signature Version 4; without credentials they are sent
+// unsigned, which public buckets accept.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+)
+
+// Provider defaults
+const (
+	DefaultRegion    = "us-east-1"
+	DefaultMaxList   = 1000
+	defaultTimeout   = 30 * time.Second
+	maxResponseSize  = 8 << 20
+	listPageSize     = 1000
+	uriScheme        = "s3://"
+	listingMimeType  = "application/json"
+	defaultMimeType  = "application/octet-stream"
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// Config configures a Provider
+type Config struct {
+	Endpoint        string // scheme and host of the service; empty uses AWS in Region
+	Region          string
+	PathStyle       bool // address buckets as endpoint/bucket rather than bucket.endpoint
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string   // for temporary credentials
+	Buckets         []string // bucket or bucket/prefix served
+	Writable        bool     // serve s3.put and presigned uploads
+	MaxList         int      // entries listed per prefix
+	Timeout         time.Duration
+}
+
+// location is a bucket, or a prefix within one, that is served
+type location struct {
+	bucket string
+	prefix string // empty or ending in a slash
+}
+
+// Provider serves the objects under its locations
+type Provider struct {
+	cfg       Config
+	endpoint  *url.URL
+	creds     credentials
+	locations []location
+	client    *http.Client
+	now       func() time.Time
+}
+
+// Ensure Provider streams its objects
+var _ resources.StreamProvider = (*Provider)(nil)
+
+// New creates a provider for cfg's buckets
+func New(cfg Config) (*Provider, error) {
+	if cfg.Region == "" {
+		cfg.Region = DefaultRegion
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	if cfg.MaxList <= 0 {
+		cfg.MaxList = DefaultMaxList
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q; use http(s)://host[:port]", cfg.Endpoint)
+	}
+	if cfg.AccessKeyID != "" && cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("an access key ID needs a secret access key")
+	}
+	if len(cfg.Buckets) == 0 {
+		return nil, fmt.Errorf("no buckets are configured")
+	}
+	locations := make([]location, 0, len(cfg.Buckets))
+	for _, b := range cfg.Buckets {
+		bucket, prefix, _ := strings.Cut(strings.Trim(b, "/"), "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("invalid bucket %q", b)
+		}
+		if prefix != "" {
+			prefix += "/"
+		}
+		locations = append(locations, location{bucket: bucket, prefix: prefix})
+	}
+	return &Provider{
+		cfg:      cfg,
+		endpoint: endpoint,
+		creds: credentials{
+			AccessKeyID:     cfg.AccessKeyID,
+			SecretAccessKey: cfg.SecretAccessKey,
+			SessionToken:    cfg.SessionToken,
+			Region:          cfg.Region,
+		},
+		locations: locations,
+		client:    &http.Client{Timeout: cfg.Timeout},
+		now:       time.Now,
+	}, nil
+}
+
+// Object describes one object of a listing
+type Object struct {
+	URI          string    `json:"uri"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// Listing is the content of a prefix resource
+type Listing struct {
+	URI       string   `json:"uri"`
+	Prefixes  []string `json:"prefixes"` // URIs of the prefixes one level down
+	Objects   []Object `json:"objects"`
+	Truncated bool     `json:"truncated,omitempty"` // more entries exist than are listed
+}
+
+// List returns the objects and prefixes directly under prefix, up to the
+// configured maximum
+func (p *Provider) List(ctx context.Context, bucket, prefix string) (*Listing, error) {
+	listing := &Listing{URI: objectURI(bucket, prefix), Prefixes: []string{}, Objects: []Object{}}
+	token := ""
+	for {
+		query := url.Values{
+			"list-type": {"2"},
+			"delimiter": {"/"},
+			"prefix":    {prefix},
+			"max-keys":  {strconv.Itoa(min(listPageSize, p.cfg.MaxList))},
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		data, err := p.do(ctx, http.MethodGet, bucket, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			IsTruncated           bool
+			NextContinuationToken string
+			Contents              []struct {
+				Key          string
+				Size         int64
+				LastModified time.Time
+			}
+			CommonPrefixes []struct {
+				Prefix string
+			}
+		}
+		if err := xml.Unmarshal(data, &page); err != nil {
+			return nil, fmt.Errorf("invalid listing of %s: %w", listing.URI, err)
+		}
+		for _, c := range page.CommonPrefixes {
+			listing.Prefixes = append(listing.Prefixes, objectURI(bucket, c.Prefix))
+		}
+		for _, c := range page.Contents {
+			if c.Key == prefix {
+				continue // a folder placeholder object
+			}
+			listing.Objects = append(listing.Objects, Object{URI: objectURI(bucket, c.Key), Size: c.Size, LastModified: c.LastModified})
+		}
+		if len(listing.Prefixes)+len(listing.Objects) >= p.cfg.MaxList {
+			listing.Truncated = page.IsTruncated || len(listing.Prefixes)+len(listing.Objects) > p.cfg.MaxList
+			return listing, nil
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return listing, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+// Put stores data as an object
+func (p *Provider) Put(ctx context.Context, bucket, key, contentType string, data []byte) error {
+	if contentType == "" {
+		contentType = mimeType(key, "")
+	}
+	_, err := p.do(ctx, http.MethodPut, bucket, key, nil, data, "Content-Type", contentType)
+	return err
+}
+
+// Presign returns a URL that allows method on an object until expires has
+// passed
+func (p *Provider) Presign(method, bucket, key string, expires time.Duration) string {
+	return p.creds.presign(method, p.objectURL(bucket, key, nil), p.now(), expires)
+}
+
+// ListResources returns the listing of each location and the entries
+// directly under it. Locations that cannot be listed are skipped.
+func (p *Provider) ListResources() ([]resources.Resource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+	defer cancel()
+	var list []resources.Resource
+	var lastErr error
+	for _, loc := range p.locations {
+		listing, err := p.List(ctx, loc.bucket, loc.prefix)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		list = append(list, prefixResource(listing.URI))
+		for _, uri := range listing.Prefixes {
+			list = append(list, prefixResource(uri))
+		}
+		for _, o := range listing.Objects {
+			_, key, _ := parseURI(o.URI)
+			list = append(list, resources.Resource{
+				URI:         o.URI,
+				Name:        path.Base(key),
+				Description: fmt.Sprintf("%d bytes, modified %s", o.Size, o.LastModified.Format(time.RFC3339)),
+				MimeType:    mimeType(key, ""),
+			})
+		}
+	}
+	if len(list) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return list, nil
+}
+
+// prefixResource describes the listing of a prefix
+func prefixResource(uri string) resources.Resource {
+	_, prefix, _ := parseURI(uri)
+	name := path.Base(strings.TrimSuffix(prefix, "/")) + "/"
+	if prefix == "" {
+		name = strings.TrimSuffix(strings.TrimPrefix(uri, uriScheme), "/") + "/"
+	}
+	return resources.Resource{
+		URI:         uri,
+		Name:        name,
+		Description: "Objects and prefixes under " + uri,
+		MimeType:    listingMimeType,
+	}
+}
+
+// GetResource returns the listing of a prefix. Objects are read with
+// OpenResource.
+func (p *Provider) GetResource(uri string) (interface{}, error) {
+	bucket, key, ok := parseURI(uri)
+	if !ok || (key != "" && !strings.HasSuffix(key, "/")) || !p.served(bucket, key) {
+		return nil, resources.ErrResourceNotFound
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+	defer cancel()
+	return p.List(ctx, bucket, key)
+}
+
+// OpenResource opens an object for ranged reads
+func (p *Provider) OpenResource(uri string) (*resources.Stream, error) {
+	bucket, key, ok := parseURI(uri)
+	if !ok || key == "" || strings.HasSuffix(key, "/") || !p.served(bucket, key) {
+		return nil, resources.ErrResourceNotFound
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+	defer cancel()
+	resp, err := p.send(ctx, http.MethodHead, bucket, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if err := responseError(resp, nil); err != nil {
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.status == http.StatusNotFound {
+			return nil, resources.ErrResourceNotFound
+		}
+		return nil, err
+	}
+	return &resources.Stream{
+		ReadCloser: &objectReader{p: p, bucket: bucket, key: key, etag: resp.Header.Get("ETag")},
+		Size:       resp.ContentLength,
+		MimeType:   mimeType(key, resp.Header.Get("Content-Type")),
+		ETag:       strings.Trim(resp.Header.Get("ETag"), `"`),
+	}, nil
+}
+
+// served reports whether key of bucket lies within a configured location
+func (p *Provider) served(bucket, key string) bool {
+	for _, loc := range p.locations {
+		if loc.bucket == bucket && strings.HasPrefix(key, loc.prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// objectReader reads an object from its current offset with a ranged GET,
+// opened on the first Read. Seeking before reading costs nothing, which
+// lets chunked reads start anywhere in a large object.
+type objectReader struct {
+	p      *Provider
+	bucket string
+	key    string
+	etag   string // the version being read; a changed object fails the read
+	offset int64
+	body   io.ReadCloser
+}
+
+func (r *objectReader) Read(b []byte) (int, error) {
+	if r.body == nil {
+		header := []string{"Range", "bytes=" + strconv.FormatInt(r.offset, 10) + "-"}
+		if r.etag != "" {
+			header = append(header, "If-Match", r.etag)
+		}
+		resp, err := r.p.send(context.Background(), http.MethodGet, r.bucket, r.key, nil, nil, header...)
+		if err != nil {
+			return 0, err
+		}
+		if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			resp.Body.Close()
+			return 0, io.EOF
+		}
+		if err := responseError(resp, nil); err != nil {
+			return 0, err
+		}
+		r.body = resp.Body
+	}
+	n, err := r.body.Read(b)
+	r.offset += int64(n)
+	return n, err
+}
+
+func (r *objectReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	default:
+		return r.offset, fmt.Errorf("objects can only be seeked from the start or current offset")
+	}
+	if offset < 0 {
+		return r.offset, fmt.Errorf("negative offset %d", offset)
+	}
+	if offset != r.offset && r.body != nil {
+		r.body.Close()
+		r.body = nil
+	}
+	r.offset = offset
+	return offset, nil
+}
+
+func (r *objectReader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+	return r.body.Close()
+}
+
+// apiError is an error response from the service, or a request it would
+// reject
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return "s3: " + e.message
+}
+
+// do sends one request and returns the response body. header holds extra
+// header names and values in pairs.
+func (p *Provider) do(ctx context.Context, method, bucket, key string, query url.Values, body []byte, header ...string) ([]byte, error) {
+	resp, err := p.send(ctx, method, bucket, key, query, body, header...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if err := responseError(resp, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// send signs and sends one request, leaving the response to the caller
+func (p *Provider) send(ctx context.Context, method, bucket, key string, query url.Values, body []byte, header ...string) (*http.Response, error) {
+	u := p.objectURL(bucket, key, query)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	payloadHash := emptyPayloadHash
+	if len(body) > 0 {
+		payloadHash = hashHex(body)
+	}
+	p.creds.sign(req, payloadHash, p.now())
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot reach %s: %w", p.endpoint.Host, err)
+	}
+	return resp, nil
+}
+
+// responseError returns the error a failed response carries. data is its
+// body when already read.
+func responseError(resp *http.Response, data []byte) error {
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	if data == nil && resp.Request.Method != http.MethodHead {
+		data, _ = io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+		resp.Body.Close()
+	}
+	var body struct {
+		Code    string
+		Message string
+	}
+	message := resp.Status
+	if xml.Unmarshal(data, &body) == nil && body.Code != "" {
+		message = body.Code + ": " + body.Message
+	}
+	return &apiError{status: resp.StatusCode, message: message}
+}
+
+// objectURL addresses an object, or a bucket when key is empty
+func (p *Provider) objectURL(bucket, key string, query url.Values) *url.URL {
+	u := *p.endpoint
+	u.Path = "/" + key
+	if p.cfg.PathStyle {
+		u.Path = "/" + bucket + "/" + key
+	} else {
+		u.Host = bucket + "." + u.Host
+	}
+	u.RawPath = uriEscape(u.Path, true)
+	if len(query) > 0 {
+		u.RawQuery = canonicalQuery(query)
+	}
+	return &u
+}
+
+// objectURI returns the resource URI of a key, or of a prefix when it ends
+// in a slash
+func objectURI(bucket, key string) string {
+	return uriScheme + bucket + "/" + key
+}
+
+// parseURI splits s3://<bucket>/<key>
+func parseURI(uri string) (bucket, key string, ok bool) {
+	rest, ok := strings.CutPrefix(uri, uriScheme)
+	if !ok {
+		return "", "", false
+	}
+	bucket, key, _ = strings.Cut(rest, "/")
+	return bucket, key, bucket != ""
+}
+
+// mimeType returns an object's type, preferring the stored type unless it
+// is generic
+func mimeType(key, stored string) string {
+	if stored != "" && stored != defaultMimeType && stored != "binary/octet-stream" {
+		return stored
+	}
+	if t := mime.TypeByExtension(path.Ext(key)); t != "" {
+		return t
+	}
+	return defaultMimeType
+}
@@ -0,0 +1,171 @@
+// internal/providers/s3/sign.go
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signature Version 4 constants
+const (
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	signingService   = "s3"
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+	amzDateFormat    = "20060102T150405Z"
+)
+
+// credentials sign requests with AWS Signature Version 4. Without an access
+// key requests are sent unsigned, which public buckets accept.
+type credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Region          string
+}
+
+// anonymous reports whether requests go unsigned
+func (c credentials) anonymous() bool {
+	return c.AccessKeyID == ""
+}
+
+// sign adds the Authorization header for req, whose body hashes to
+// payloadHash
+func (c credentials) sign(req *http.Request, payloadHash string, now time.Time) {
+	if c.anonymous() {
+		return
+	}
+	amzDate := now.UTC().Format(amzDateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if c.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", c.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-amz-") || lower == "content-type" {
+			headers[lower] = strings.TrimSpace(req.Header.Get(name))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := c.scope(now)
+	req.Header.Set("Authorization", signingAlgorithm+
+		" Credential="+c.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+
+		", Signature="+c.signature(now, scope, canonical))
+}
+
+// presign returns u with query parameters that authorize method on it
+// until now+expires
+func (c credentials) presign(method string, u *url.URL, now time.Time, expires time.Duration) string {
+	if c.anonymous() {
+		return u.String()
+	}
+	scope := c.scope(now)
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", signingAlgorithm)
+	query.Set("X-Amz-Credential", c.AccessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", now.UTC().Format(amzDateFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires/time.Second)))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if c.SessionToken != "" {
+		query.Set("X-Amz-Security-Token", c.SessionToken)
+	}
+
+	canonical := strings.Join([]string{
+		method,
+		u.EscapedPath(),
+		canonicalQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+	signed := *u
+	signed.RawQuery = canonicalQuery(query) + "&X-Amz-Signature=" + c.signature(now, scope, canonical)
+	return signed.String()
+}
+
+// scope is the credential scope of requests signed at now
+func (c credentials) scope(now time.Time) string {
+	return now.UTC().Format("20060102") + "/" + c.Region + "/" + signingService + "/aws4_request"
+}
+
+// signature signs a canonical request with the key derived for scope
+func (c credentials) signature(now time.Time, scope, canonical string) string {
+	stringToSign := strings.Join([]string{
+		signingAlgorithm,
+		now.UTC().Format(amzDateFormat),
+		scope,
+		hashHex([]byte(canonical)),
+	}, "\n")
+	key := hmacSHA256([]byte("AWS4"+c.SecretAccessKey), now.UTC().Format("20060102"))
+	key = hmacSHA256(key, c.Region)
+	key = hmacSHA256(key, signingService)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalQuery encodes query sorted by key and value as SigV4 requires
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for key, values := range query {
+		for _, v := range values {
+			pairs = append(pairs, uriEscape(key, false)+"="+uriEscape(v, false))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEscape percent-encodes every byte but the unreserved characters, and
+// slashes when keepSlash is set
+func uriEscape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			b.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+		}
+	}
+	return b.String()
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
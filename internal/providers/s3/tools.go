@@ -0,0 +1,168 @@
+// internal/providers/s3/tools.go
+package s3
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+)
+
+// Tool names
+const (
+	PresignToolName = "s3.presign"
+	PutToolName     = "s3.put"
+)
+
+// Expiry of presigned URLs
+const (
+	defaultPresignExpiry = 15 * time.Minute
+	maxPresignExpiry     = 7 * 24 * time.Hour
+)
+
+// UsageHint implements provider.Hinter
+func (p *Provider) UsageHint() string {
+	return "Buckets are browsed as s3:// resources; a URI ending in / lists the objects under it. " +
+		"Use " + PresignToolName + " to hand out a link rather than reading large objects."
+}
+
+// Register adds s3.presign to m, and s3.put when writes are allowed
+func (p *Provider) Register(m *manager.ToolsManager) {
+	methods := []string{http.MethodGet}
+	annotations := protocol.ReadOnly()
+	purpose := "download an object"
+	if p.cfg.Writable {
+		// An upload URL lets whoever holds it overwrite the object
+		methods = append(methods, http.MethodPut)
+		annotations = protocol.Destructive(true)
+		purpose = "download or upload an object"
+	}
+	m.RegisterTool(protocol.Tool{
+		Name: PresignToolName,
+		Description: "Create a time-limited URL to " + purpose + " without credentials, e.g. to share a large file. " +
+			"Objects are addressed as s3://<bucket>/<key>.",
+		InputSchema: map[string]interface{}{
+			"type":     "object",
+			"required": []string{"uri"},
+			"properties": map[string]interface{}{
+				"uri":       map[string]interface{}{"type": "string", "pattern": "^s3://"},
+				"method":    map[string]interface{}{"type": "string", "enum": methods, "description": "GET to download, PUT to upload; GET by default"},
+				"expiresIn": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": int(maxPresignExpiry / time.Second), "description": fmt.Sprintf("Seconds the URL stays valid; %d by default", int(defaultPresignExpiry/time.Second))},
+			},
+		},
+		Annotations: annotations,
+	}, func(_ context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		var params struct {
+			URI       string `json:"uri"`
+			Method    string `json:"method"`
+			ExpiresIn int    `json:"expiresIn"`
+		}
+		if err := json.Unmarshal(args, &params); err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		if params.Method == "" {
+			params.Method = http.MethodGet
+		}
+		if params.Method != http.MethodGet && !(params.Method == http.MethodPut && p.cfg.Writable) {
+			return toolResult("", &apiError{message: fmt.Sprintf("method %s is not allowed", params.Method)})
+		}
+		bucket, key, err := p.objectArg(params.URI)
+		if err != nil {
+			return toolResult("", err)
+		}
+		expires := defaultPresignExpiry
+		if params.ExpiresIn > 0 {
+			expires = min(time.Duration(params.ExpiresIn)*time.Second, maxPresignExpiry)
+		}
+		return toolResult(p.Presign(params.Method, bucket, key, expires), nil)
+	})
+
+	if !p.cfg.Writable {
+		return
+	}
+	m.RegisterTool(protocol.Tool{
+		Name:        PutToolName,
+		Description: "Store content as an object at s3://<bucket>/<key>, replacing any object already there.",
+		InputSchema: map[string]interface{}{
+			"type":     "object",
+			"required": []string{"uri", "content"},
+			"properties": map[string]interface{}{
+				"uri":         map[string]interface{}{"type": "string", "pattern": "^s3://"},
+				"content":     map[string]interface{}{"type": "string"},
+				"base64":      map[string]interface{}{"type": "boolean", "description": "content is base64-encoded binary data"},
+				"contentType": map[string]interface{}{"type": "string", "description": "guessed from the key's extension by default"},
+			},
+		},
+		Annotations: protocol.Destructive(true),
+	}, func(ctx context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		var params struct {
+			URI         string `json:"uri"`
+			Content     string `json:"content"`
+			Base64      bool   `json:"base64"`
+			ContentType string `json:"contentType"`
+		}
+		if err := json.Unmarshal(args, &params); err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		bucket, key, err := p.objectArg(params.URI)
+		if err != nil {
+			return toolResult("", err)
+		}
+		data := []byte(params.Content)
+		if params.Base64 {
+			if data, err = base64.StdEncoding.DecodeString(params.Content); err != nil {
+				return toolResult("", &apiError{message: "content is not valid base64"})
+			}
+		}
+		if err := p.Put(ctx, bucket, key, params.ContentType, data); err != nil {
+			return toolResult("", err)
+		}
+		return toolResult(fmt.Sprintf("Stored %d bytes at %s", len(data), params.URI), nil)
+	})
+}
+
+// ToolNames returns the names of the tools Register adds
+func (p *Provider) ToolNames() []string {
+	if p.cfg.Writable {
+		return []string{PresignToolName, PutToolName}
+	}
+	return []string{PresignToolName}
+}
+
+// objectArg reads an object URI argument, which must lie within a
+// configured location
+func (p *Provider) objectArg(uri string) (bucket, key string, err error) {
+	bucket, key, ok := parseURI(uri)
+	if !ok || key == "" || strings.HasSuffix(key, "/") {
+		return "", "", &apiError{message: fmt.Sprintf("%q is not an object URI; use s3://<bucket>/<key>", uri)}
+	}
+	if !p.served(bucket, key) {
+		return "", "", &apiError{message: fmt.Sprintf("%s is outside the configured buckets", uri)}
+	}
+	return bucket, key, nil
+}
+
+// toolResult returns text, or reports a service error or a mistake in the
+// arguments to the model
+func toolResult(text string, err error) (protocol.ToolsCallResult, error) {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return protocol.ToolsCallResult{
+			Content: []protocol.Content{protocol.TextContent(apiErr.Error())},
+			IsError: true,
+		}, nil
+	}
+	if err != nil {
+		return protocol.ToolsCallResult{}, err
+	}
+	return protocol.ToolsCallResult{
+		Content: []protocol.Content{protocol.TextContent(text)},
+	}, nil
+}
@@ -0,0 +1,294 @@
+// internal/providers/schedule/schedule.go
+
+// Package schedule calls registered tools on cron schedules, turning the
+// server into a small automation hub for recurring checks. Each schedule's
+// latest outcome is served as the resource schedule://<name>; when a run
+// finishes, subscribers get notifications/resources/updated and every
+// client gets a log message summarizing it.
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/clock"
+	"github.com/dkoosis/axe-handle/internal/mcp/notify"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+)
+
+// uriScheme prefixes the resources of schedules
+const uriScheme = "schedule://"
+
+// loggerName identifies the scheduler's log notifications
+const loggerName = "schedule"
+
+// Job is a tool call made on a schedule
+type Job struct {
+	Name      string          // unique; names the schedule's resource
+	Cron      string          // when to run, see ParseCron
+	Tool      string          // registered tool to call
+	Arguments json.RawMessage // the tool's arguments; empty sends {}
+}
+
+// Run is the outcome of one scheduled call
+type Run struct {
+	Started  time.Time                 `json:"started"`
+	Duration string                    `json:"duration"`
+	Status   string                    `json:"status"` // succeeded or failed
+	Error    string                    `json:"error,omitempty"`
+	Result   *protocol.ToolsCallResult `json:"result,omitempty"`
+}
+
+// entry is a job and its schedule state
+type entry struct {
+	job     Job
+	cron    *Cron
+	next    time.Time
+	running bool
+	last    *Run
+}
+
+// Provider runs the jobs and serves their outcomes as resources
+type Provider struct {
+	tools *manager.ToolsManager
+	clock clock.Clock
+
+	mu        sync.Mutex
+	entries   []*entry // sorted by name
+	publisher notify.NotificationPublisher
+	wake      chan struct{}
+}
+
+// Ensure Provider implements the interfaces the server looks for
+var (
+	_ resources.Provider    = (*Provider)(nil)
+	_ notify.PublisherAware = (*Provider)(nil)
+)
+
+// New validates jobs and creates a provider that calls their tools on m.
+// A nil clock uses the system clock.
+func New(m *manager.ToolsManager, clk clock.Clock, jobs []Job) (*Provider, error) {
+	if clk == nil {
+		clk = clock.Real
+	}
+	p := &Provider{tools: m, clock: clk, wake: make(chan struct{}, 1)}
+	seen := make(map[string]bool)
+	for _, job := range jobs {
+		if job.Name == "" || job.Tool == "" {
+			return nil, fmt.Errorf("schedule %q: name and tool are required", job.Name)
+		}
+		if strings.Contains(job.Name, "/") || seen[job.Name] {
+			return nil, fmt.Errorf("schedule %q: names must be unique and must not contain '/'", job.Name)
+		}
+		seen[job.Name] = true
+		c, err := ParseCron(job.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %w", job.Name, err)
+		}
+		if len(job.Arguments) == 0 {
+			job.Arguments = json.RawMessage(`{}`)
+		}
+		p.entries = append(p.entries, &entry{job: job, cron: c})
+	}
+	sort.Slice(p.entries, func(i, j int) bool { return p.entries[i].job.Name < p.entries[j].job.Name })
+	return p, nil
+}
+
+// SetNotificationPublisher receives the server's publisher on registration
+func (p *Provider) SetNotificationPublisher(pub notify.NotificationPublisher) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.publisher = pub
+}
+
+// Run calls the tools as their schedules come due until ctx is cancelled.
+// A run that is still going when its job comes due again is skipped rather
+// than overlapped.
+func (p *Provider) Run(ctx context.Context) {
+	known := make(map[string]bool)
+	for _, t := range p.tools.ListTools() {
+		known[t.Name] = true
+	}
+
+	p.mu.Lock()
+	now := p.clock.Now()
+	for _, e := range p.entries {
+		e.next = e.cron.Next(now)
+		if !known[e.job.Tool] {
+			slog.Warn("Scheduled tool is not registered", "schedule", e.job.Name, "tool", e.job.Tool)
+		}
+	}
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		p.mu.Lock()
+		var due time.Time
+		for _, e := range p.entries {
+			if !e.next.IsZero() && (due.IsZero() || e.next.Before(due)) {
+				due = e.next
+			}
+		}
+		p.mu.Unlock()
+		if due.IsZero() {
+			<-ctx.Done()
+			return
+		}
+
+		timer := p.clock.AfterFunc(due.Sub(p.clock.Now()), func() {
+			select {
+			case p.wake <- struct{}{}:
+			default:
+			}
+		})
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-p.wake:
+		}
+
+		for _, e := range p.dueEntries() {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p.execute(ctx, e)
+			}()
+		}
+	}
+}
+
+// dueEntries advances the schedules that have come due and returns those
+// whose previous run has finished
+func (p *Provider) dueEntries() []*entry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.clock.Now()
+	var due []*entry
+	for _, e := range p.entries {
+		if e.next.IsZero() || e.next.After(now) {
+			continue
+		}
+		e.next = e.cron.Next(now)
+		if e.running {
+			slog.Warn("Skipping scheduled run; the previous one is still going", "schedule", e.job.Name)
+			continue
+		}
+		e.running = true
+		due = append(due, e)
+	}
+	return due
+}
+
+// execute calls a job's tool and publishes the outcome
+func (p *Provider) execute(ctx context.Context, e *entry) {
+	started := p.clock.Now()
+	slog.Info("Running scheduled tool", "schedule", e.job.Name, "tool", e.job.Tool)
+	result, err := p.tools.CallTool(ctx, e.job.Tool, e.job.Arguments, "")
+
+	run := &Run{Started: started, Duration: p.clock.Since(started).String(), Status: "succeeded"}
+	switch {
+	case err != nil:
+		run.Status, run.Error = "failed", err.Error()
+	case result.IsError:
+		run.Status, run.Result = "failed", &result
+	default:
+		run.Result = &result
+	}
+
+	p.mu.Lock()
+	e.running = false
+	e.last = run
+	pub := p.publisher
+	p.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return
+	}
+	slog.Info("Scheduled tool finished", "schedule", e.job.Name, "status", run.Status, "duration", run.Duration)
+	if pub == nil {
+		return
+	}
+	pub.ResourceUpdated(uriScheme + e.job.Name)
+	level := protocol.LoggingLevelInfo
+	if run.Status != "succeeded" {
+		level = protocol.LoggingLevelWarning
+	}
+	pub.Log(context.Background(), level, loggerName, map[string]interface{}{
+		"schedule": e.job.Name,
+		"tool":     e.job.Tool,
+		"status":   run.Status,
+		"resource": uriScheme + e.job.Name,
+	})
+}
+
+// ListResources returns one resource per schedule
+func (p *Provider) ListResources() ([]resources.Resource, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	list := make([]resources.Resource, 0, len(p.entries))
+	for _, e := range p.entries {
+		list = append(list, resources.Resource{
+			URI:         uriScheme + e.job.Name,
+			Name:        e.job.Name,
+			Description: fmt.Sprintf("Latest result of %s, run on the schedule %q", e.job.Tool, e.job.Cron),
+			MimeType:    "application/json",
+		})
+	}
+	return list, nil
+}
+
+// scheduleDocument is the content of a schedule's resource
+type scheduleDocument struct {
+	Name    string     `json:"name"`
+	Cron    string     `json:"cron"`
+	Tool    string     `json:"tool"`
+	Running bool       `json:"running"`
+	NextRun *time.Time `json:"nextRun,omitempty"`
+	LastRun *Run       `json:"lastRun,omitempty"`
+}
+
+// GetResource returns a schedule's next run time and latest outcome
+func (p *Provider) GetResource(uri string) (interface{}, error) {
+	name, ok := strings.CutPrefix(uri, uriScheme)
+	if !ok {
+		return nil, resources.ErrResourceNotFound
+	}
+	p.mu.Lock()
+	var doc *scheduleDocument
+	for _, e := range p.entries {
+		if e.job.Name == name {
+			doc = &scheduleDocument{
+				Name:    name,
+				Cron:    e.job.Cron,
+				Tool:    e.job.Tool,
+				Running: e.running,
+				LastRun: e.last,
+			}
+			if !e.next.IsZero() {
+				next := e.next
+				doc.NextRun = &next
+			}
+			break
+		}
+	}
+	p.mu.Unlock()
+	if doc == nil {
+		return nil, resources.ErrResourceNotFound
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,302 @@
+// internal/providers/tabular/load.go
+package tabular
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Column types
+const (
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+)
+
+// Column describes one column of a table
+type Column struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Table is a tabular file loaded into memory. Values are nil, int64,
+// float64, bool or string.
+type Table struct {
+	Name      string   `json:"name"`
+	Columns   []Column `json:"columns"`
+	Rows      [][]any  `json:"-"`
+	Truncated bool     `json:"truncated,omitempty"` // the file has more than the configured maximum rows
+	index     map[string]int
+}
+
+// Column returns the index of the named column
+func (t *Table) Column(name string) (int, bool) {
+	i, ok := t.index[name]
+	return i, ok
+}
+
+// supported reports whether path is a tabular file the provider can load
+func supported(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv", ".tsv", ".parquet":
+		return true
+	}
+	return false
+}
+
+// load reads up to maxRows rows of the file at path
+func load(name, path string, maxRows int) (*Table, error) {
+	var (
+		t   *Table
+		err error
+	)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		t, err = loadCSV(path, ',', maxRows)
+	case ".tsv":
+		t, err = loadCSV(path, '\t', maxRows)
+	case ".parquet":
+		t, err = loadParquet(path, maxRows)
+	default:
+		return nil, fmt.Errorf("unsupported table format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	t.Name = name
+	t.index = make(map[string]int, len(t.Columns))
+	for i, c := range t.Columns {
+		t.index[c.Name] = i
+	}
+	return t, nil
+}
+
+// loadCSV reads a delimited file whose first record is the header. Column
+// types are inferred from the values: a column is an integer, number or
+// boolean column when every non-empty value parses as one.
+func loadCSV(path string, comma rune, maxRows int) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("missing header row")
+		}
+		return nil, err
+	}
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	t := &Table{Columns: make([]Column, len(header))}
+	var records [][]string
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == maxRows {
+			t.Truncated = true
+			break
+		}
+		records = append(records, record)
+	}
+
+	for i, name := range header {
+		t.Columns[i] = Column{Name: strings.TrimSpace(name), Type: inferType(records, i)}
+	}
+	t.Rows = make([][]any, len(records))
+	for n, record := range records {
+		row := make([]any, len(header))
+		for i, c := range t.Columns {
+			if i < len(record) {
+				row[i] = convert(record[i], c.Type)
+			}
+		}
+		t.Rows[n] = row
+	}
+	return t, nil
+}
+
+// inferType picks the narrowest type every non-empty value of column i fits
+func inferType(records [][]string, i int) string {
+	isInt, isNum, isBool, seen := true, true, true, false
+	for _, record := range records {
+		if i >= len(record) || record[i] == "" {
+			continue
+		}
+		seen = true
+		v := record[i]
+		if isInt {
+			if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+				isInt = false
+			}
+		}
+		if isNum {
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				isNum = false
+			}
+		}
+		if isBool {
+			if _, err := strconv.ParseBool(v); err != nil || len(v) == 1 {
+				isBool = false
+			}
+		}
+	}
+	switch {
+	case !seen:
+		return TypeString
+	case isInt:
+		return TypeInteger
+	case isNum:
+		return TypeNumber
+	case isBool:
+		return TypeBoolean
+	}
+	return TypeString
+}
+
+// convert parses a CSV value as typ; empty values are null
+func convert(v, typ string) any {
+	if v == "" {
+		return nil
+	}
+	switch typ {
+	case TypeInteger:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	case TypeNumber:
+		n, _ := strconv.ParseFloat(v, 64)
+		return n
+	case TypeBoolean:
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+	return v
+}
+
+// loadParquet reads a Parquet file. Nested columns are named by their
+// dotted path and repeated columns hold a list of values.
+func loadParquet(path string, maxRows int) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	file, err := parquet.OpenFile(f, info.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	paths := file.Schema().Columns()
+	t := &Table{Columns: make([]Column, len(paths))}
+	for i, p := range paths {
+		t.Columns[i] = Column{Name: strings.Join(p, "."), Type: TypeString}
+		if leaf, ok := file.Schema().Lookup(p...); ok {
+			t.Columns[i].Type = parquetType(leaf.Node.Type().Kind())
+		}
+	}
+	t.Truncated = file.NumRows() > int64(maxRows)
+
+	reader := parquet.NewReader(file)
+	defer reader.Close()
+
+	buf := make([]parquet.Row, 256)
+	for len(t.Rows) < maxRows {
+		n, err := reader.ReadRows(buf[:min(len(buf), maxRows-len(t.Rows))])
+		for _, r := range buf[:n] {
+			t.Rows = append(t.Rows, parquetRow(r, len(paths)))
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// parquetType maps a physical Parquet type to a column type
+func parquetType(kind parquet.Kind) string {
+	switch kind {
+	case parquet.Boolean:
+		return TypeBoolean
+	case parquet.Int32, parquet.Int64:
+		return TypeInteger
+	case parquet.Float, parquet.Double:
+		return TypeNumber
+	}
+	return TypeString
+}
+
+// parquetRow converts a row's leaf values to table values
+func parquetRow(r parquet.Row, columns int) []any {
+	row := make([]any, columns)
+	counts := make([]int, columns)
+	for _, v := range r {
+		c := v.Column()
+		if c < 0 || c >= columns || v.IsNull() {
+			continue
+		}
+		value := parquetValue(v)
+		switch counts[c] {
+		case 0:
+			row[c] = value
+		case 1:
+			row[c] = []any{row[c], value}
+		default:
+			row[c] = append(row[c].([]any), value)
+		}
+		counts[c]++
+	}
+	return row
+}
+
+// parquetValue converts one non-null Parquet value
+func parquetValue(v parquet.Value) any {
+	switch v.Kind() {
+	case parquet.Boolean:
+		return v.Boolean()
+	case parquet.Int32:
+		return int64(v.Int32())
+	case parquet.Int64:
+		return v.Int64()
+	case parquet.Float:
+		return float64(v.Float())
+	case parquet.Double:
+		return v.Double()
+	case parquet.ByteArray, parquet.FixedLenByteArray:
+		b := v.ByteArray()
+		if utf8.Valid(b) {
+			return string(b)
+		}
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	return v.String()
+}
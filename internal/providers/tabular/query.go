@@ -0,0 +1,426 @@
+// internal/providers/tabular/query.go
+package tabular
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Query limits
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Query filters, groups and aggregates one table
+type Query struct {
+	Table      string      `json:"table"`
+	Select     []string    `json:"select,omitempty"`
+	Where      []Condition `json:"where,omitempty"`
+	GroupBy    []string    `json:"groupBy,omitempty"`
+	Aggregates []Aggregate `json:"aggregates,omitempty"`
+	OrderBy    []Order     `json:"orderBy,omitempty"`
+	Limit      int         `json:"limit,omitempty"`
+}
+
+// Condition compares a column with a value; conditions are ANDed
+type Condition struct {
+	Column string `json:"column"`
+	Op     string `json:"op"` // eq, ne, lt, lte, gt, gte, contains or in
+	Value  any    `json:"value"`
+}
+
+// Aggregate computes one value per group
+type Aggregate struct {
+	Func   string `json:"func"`             // count, sum, avg, min or max
+	Column string `json:"column,omitempty"` // optional for count
+	As     string `json:"as,omitempty"`     // output column name; defaults to func(column)
+}
+
+// Order sorts the result by an output column
+type Order struct {
+	Column string `json:"column"`
+	Desc   bool   `json:"desc,omitempty"`
+}
+
+// Result is the output of a query
+type Result struct {
+	Columns   []string `json:"columns"`
+	Rows      [][]any  `json:"rows"`
+	Matched   int      `json:"matched"`             // rows (or groups) before the limit
+	Truncated bool     `json:"truncated,omitempty"` // the limit cut the result
+}
+
+// querySchema is the input schema of the query tool
+var querySchema = map[string]interface{}{
+	"type":     "object",
+	"required": []string{"table"},
+	"properties": map[string]interface{}{
+		"table": map[string]interface{}{"type": "string", "description": "Table name as listed in the table:// resources"},
+		"select": map[string]interface{}{
+			"type": "array", "items": map[string]interface{}{"type": "string"},
+			"description": "Columns to return when not aggregating; all by default",
+		},
+		"where": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":     "object",
+				"required": []string{"column", "op"},
+				"properties": map[string]interface{}{
+					"column": map[string]interface{}{"type": "string"},
+					"op":     map[string]interface{}{"enum": []string{"eq", "ne", "lt", "lte", "gt", "gte", "contains", "in"}},
+					"value":  map[string]interface{}{"description": "Comparison value; an array for in"},
+				},
+			},
+		},
+		"groupBy": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		"aggregates": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":     "object",
+				"required": []string{"func"},
+				"properties": map[string]interface{}{
+					"func":   map[string]interface{}{"enum": []string{"count", "sum", "avg", "min", "max"}},
+					"column": map[string]interface{}{"type": "string"},
+					"as":     map[string]interface{}{"type": "string"},
+				},
+			},
+		},
+		"orderBy": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":     "object",
+				"required": []string{"column"},
+				"properties": map[string]interface{}{
+					"column": map[string]interface{}{"type": "string"},
+					"desc":   map[string]interface{}{"type": "boolean"},
+				},
+			},
+		},
+		"limit": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": MaxLimit},
+	},
+}
+
+// Run executes q against t
+func Run(t *Table, q Query) (*Result, error) {
+	filters, err := compileWhere(t, q.Where)
+	if err != nil {
+		return nil, err
+	}
+	var rows [][]any
+	for _, row := range t.Rows {
+		if matches(row, filters) {
+			rows = append(rows, row)
+		}
+	}
+
+	var result *Result
+	if len(q.GroupBy) > 0 || len(q.Aggregates) > 0 {
+		result, err = aggregate(t, rows, q.GroupBy, q.Aggregates)
+	} else {
+		result, err = project(t, rows, q.Select)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := order(result, q.OrderBy); err != nil {
+		return nil, err
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	limit = min(limit, MaxLimit)
+	result.Matched = len(result.Rows)
+	if len(result.Rows) > limit {
+		result.Rows = result.Rows[:limit]
+		result.Truncated = true
+	}
+	return result, nil
+}
+
+// filter is a condition bound to its column index
+type filter struct {
+	column int
+	Condition
+}
+
+// compileWhere resolves the columns of the conditions
+func compileWhere(t *Table, where []Condition) ([]filter, error) {
+	filters := make([]filter, 0, len(where))
+	for _, c := range where {
+		i, ok := t.Column(c.Column)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", c.Column)
+		}
+		switch c.Op {
+		case "eq", "ne", "lt", "lte", "gt", "gte", "contains":
+		case "in":
+			if _, ok := c.Value.([]any); !ok {
+				return nil, fmt.Errorf("in on %q needs an array value", c.Column)
+			}
+		default:
+			return nil, fmt.Errorf("unknown operator %q", c.Op)
+		}
+		filters = append(filters, filter{column: i, Condition: c})
+	}
+	return filters, nil
+}
+
+// matches reports whether row satisfies every filter
+func matches(row []any, filters []filter) bool {
+	for _, f := range filters {
+		v := row[f.column]
+		var ok bool
+		switch f.Op {
+		case "eq":
+			ok = compare(v, f.Value) == 0
+		case "ne":
+			ok = compare(v, f.Value) != 0
+		case "lt":
+			ok = v != nil && compare(v, f.Value) < 0
+		case "lte":
+			ok = v != nil && compare(v, f.Value) <= 0
+		case "gt":
+			ok = v != nil && compare(v, f.Value) > 0
+		case "gte":
+			ok = v != nil && compare(v, f.Value) >= 0
+		case "contains":
+			ok = v != nil && strings.Contains(strings.ToLower(fmt.Sprint(v)), strings.ToLower(fmt.Sprint(f.Value)))
+		case "in":
+			for _, candidate := range f.Value.([]any) {
+				if compare(v, candidate) == 0 {
+					ok = true
+					break
+				}
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// compare orders two values: nulls first, numbers numerically and
+// everything else by its text
+func compare(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	if x, ok := number(a); ok {
+		if y, ok := number(b); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// number returns v as a float64 when it is numeric
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// project selects columns from rows
+func project(t *Table, rows [][]any, columns []string) (*Result, error) {
+	if len(columns) == 0 {
+		for _, c := range t.Columns {
+			columns = append(columns, c.Name)
+		}
+	}
+	indexes := make([]int, len(columns))
+	for i, name := range columns {
+		idx, ok := t.Column(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		indexes[i] = idx
+	}
+
+	result := &Result{Columns: columns, Rows: make([][]any, len(rows))}
+	for n, row := range rows {
+		out := make([]any, len(indexes))
+		for i, idx := range indexes {
+			out[i] = row[idx]
+		}
+		result.Rows[n] = out
+	}
+	return result, nil
+}
+
+// accumulator computes one aggregate for one group
+type accumulator struct {
+	count int
+	sum   float64
+	best  any
+}
+
+// aggregate groups rows and computes the aggregates of each group. Groups
+// are returned in the order they first appear.
+func aggregate(t *Table, rows [][]any, groupBy []string, aggs []Aggregate) (*Result, error) {
+	keys := make([]int, len(groupBy))
+	for i, name := range groupBy {
+		idx, ok := t.Column(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		keys[i] = idx
+	}
+	if len(aggs) == 0 {
+		aggs = []Aggregate{{Func: "count"}}
+	}
+	cols := make([]int, len(aggs))
+	result := &Result{Columns: append([]string(nil), groupBy...)}
+	for i, a := range aggs {
+		cols[i] = -1
+		switch a.Func {
+		case "count":
+		case "sum", "avg", "min", "max":
+			if a.Column == "" {
+				return nil, fmt.Errorf("%s needs a column", a.Func)
+			}
+		default:
+			return nil, fmt.Errorf("unknown aggregate %q", a.Func)
+		}
+		if a.Column != "" {
+			idx, ok := t.Column(a.Column)
+			if !ok {
+				return nil, fmt.Errorf("unknown column %q", a.Column)
+			}
+			cols[i] = idx
+		}
+		name := a.As
+		switch {
+		case name != "":
+		case a.Column == "":
+			name = a.Func
+		default:
+			name = a.Func + "(" + a.Column + ")"
+		}
+		result.Columns = append(result.Columns, name)
+	}
+
+	type group struct {
+		key  []any
+		accs []accumulator
+	}
+	groups := make(map[string]*group)
+	var ordered []*group
+	for _, row := range rows {
+		key := make([]any, len(keys))
+		for i, idx := range keys {
+			key[i] = row[idx]
+		}
+		id := fmt.Sprintf("%#v", key)
+		g, ok := groups[id]
+		if !ok {
+			g = &group{key: key, accs: make([]accumulator, len(aggs))}
+			groups[id] = g
+			ordered = append(ordered, g)
+		}
+		for i, a := range aggs {
+			acc := &g.accs[i]
+			if cols[i] < 0 {
+				acc.count++
+				continue
+			}
+			v := row[cols[i]]
+			if v == nil {
+				continue
+			}
+			acc.count++
+			switch a.Func {
+			case "sum", "avg":
+				n, _ := number(v)
+				acc.sum += n
+			case "min":
+				if acc.count == 1 || compare(v, acc.best) < 0 {
+					acc.best = v
+				}
+			case "max":
+				if acc.count == 1 || compare(v, acc.best) > 0 {
+					acc.best = v
+				}
+			}
+		}
+	}
+
+	for _, g := range ordered {
+		out := append([]any(nil), g.key...)
+		for i, a := range aggs {
+			acc := g.accs[i]
+			switch a.Func {
+			case "count":
+				out = append(out, int64(acc.count))
+			case "sum":
+				out = append(out, acc.sum)
+			case "avg":
+				if acc.count == 0 {
+					out = append(out, nil)
+				} else {
+					out = append(out, acc.sum/float64(acc.count))
+				}
+			default:
+				out = append(out, acc.best)
+			}
+		}
+		result.Rows = append(result.Rows, out)
+	}
+	return result, nil
+}
+
+// order sorts result rows by the given output columns
+func order(result *Result, orderBy []Order) error {
+	if len(orderBy) == 0 {
+		return nil
+	}
+	indexes := make([]int, len(orderBy))
+	for i, o := range orderBy {
+		indexes[i] = -1
+		for j, name := range result.Columns {
+			if name == o.Column {
+				indexes[i] = j
+				break
+			}
+		}
+		if indexes[i] < 0 {
+			return fmt.Errorf("cannot order by %q: not an output column", o.Column)
+		}
+	}
+	sort.SliceStable(result.Rows, func(a, b int) bool {
+		for i, o := range orderBy {
+			c := compare(result.Rows[a][indexes[i]], result.Rows[b][indexes[i]])
+			if c == 0 {
+				continue
+			}
+			if o.Desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+	return nil
+}
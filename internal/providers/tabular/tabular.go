@@ -0,0 +1,270 @@
+// internal/providers/tabular/tabular.go
+
+// Package tabular serves CSV, TSV and Parquet files from a directory as
+// structured resources and answers filter and aggregate queries over them
+// with the table.query tool, so assistants can explore datasets without a
+// database.
+//
+// Each table is listed as table://<name>, whose content is its schema; rows
+// are read in pages from table://<name>?page=N. Tables are loaded into
+// memory on first use and reloaded when their file changes.
+package tabular
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+)
+
+// Provider defaults
+const (
+	DefaultPageSize = 100
+	DefaultMaxRows  = 1_000_000
+	QueryToolName   = "table.query"
+	uriScheme       = "table://"
+)
+
+// Config configures a Provider
+type Config struct {
+	Dir      string // searched recursively for .csv, .tsv and .parquet files
+	PageSize int    // rows per resource page
+	MaxRows  int    // rows loaded per table; larger files are truncated
+}
+
+// Provider serves the tables in a directory
+type Provider struct {
+	cfg Config
+
+	mu     sync.Mutex
+	loaded map[string]*cached
+}
+
+// cached is a loaded table and the file version it came from
+type cached struct {
+	table   *Table
+	modTime time.Time
+	size    int64
+}
+
+// Ensure Provider implements the resource interfaces
+var (
+	_ resources.TemplateProvider   = (*Provider)(nil)
+	_ resources.CompletionProvider = (*Provider)(nil)
+)
+
+// pageTemplate addresses a page of a table's rows
+const pageTemplate = uriScheme + "{name}?page={page}"
+
+// New creates a provider for the tables under cfg.Dir
+func New(cfg Config) *Provider {
+	if cfg.PageSize <= 0 {
+		cfg.PageSize = DefaultPageSize
+	}
+	if cfg.MaxRows <= 0 {
+		cfg.MaxRows = DefaultMaxRows
+	}
+	return &Provider{cfg: cfg, loaded: make(map[string]*cached)}
+}
+
+// Tables returns the names of the tables in the directory: their paths
+// relative to it, with forward slashes
+func (p *Provider) Tables() ([]string, error) {
+	var names []string
+	err := filepath.WalkDir(p.cfg.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !supported(path) {
+			return nil
+		}
+		rel, err := filepath.Rel(p.cfg.Dir, path)
+		if err != nil {
+			return err
+		}
+		names = append(names, filepath.ToSlash(rel))
+		return nil
+	})
+	sort.Strings(names)
+	return names, err
+}
+
+// Table returns the named table, loading it if it is not loaded or its
+// file has changed
+func (p *Provider) Table(name string) (*Table, error) {
+	if name == "" || !supported(name) || !filepath.IsLocal(filepath.FromSlash(name)) {
+		return nil, fmt.Errorf("unknown table %q", name)
+	}
+	path := filepath.Join(p.cfg.Dir, filepath.FromSlash(name))
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("unknown table %q", name)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if c, ok := p.loaded[name]; ok && c.modTime.Equal(info.ModTime()) && c.size == info.Size() {
+		return c.table, nil
+	}
+	t, err := load(name, path, p.cfg.MaxRows)
+	if err != nil {
+		return nil, err
+	}
+	p.loaded[name] = &cached{table: t, modTime: info.ModTime(), size: info.Size()}
+	return t, nil
+}
+
+// ListResources returns one schema resource per table
+func (p *Provider) ListResources() ([]resources.Resource, error) {
+	names, err := p.Tables()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]resources.Resource, 0, len(names))
+	for _, name := range names {
+		list = append(list, resources.Resource{
+			URI:         uriScheme + name,
+			Name:        name,
+			Description: fmt.Sprintf("Schema of table %s; read %s%s?page=N for rows", name, uriScheme, name),
+			MimeType:    "application/json",
+		})
+	}
+	return list, nil
+}
+
+// ListResourceTemplates returns the template for pages of rows
+func (p *Provider) ListResourceTemplates() ([]resources.ResourceTemplate, error) {
+	return []resources.ResourceTemplate{{
+		URITemplate: pageTemplate,
+		Name:        "Table rows",
+		Description: "One page of a table's rows, counting from 0",
+		MimeType:    "application/json",
+	}}, nil
+}
+
+// CompleteTemplateVariable suggests table names for the page template
+func (p *Provider) CompleteTemplateVariable(_ context.Context, template, variable, _ string) ([]string, error) {
+	if template != pageTemplate || variable != "name" {
+		return nil, nil
+	}
+	return p.Tables()
+}
+
+// schemaDocument is the content of a table's schema resource
+type schemaDocument struct {
+	*Table
+	RowCount int `json:"rowCount"`
+	PageSize int `json:"pageSize"`
+	Pages    int `json:"pages"`
+}
+
+// pageDocument is the content of one page of rows
+type pageDocument struct {
+	Table    string   `json:"table"`
+	Columns  []string `json:"columns"`
+	Page     int      `json:"page"`
+	Pages    int      `json:"pages"`
+	RowCount int      `json:"rowCount"`
+	Rows     [][]any  `json:"rows"`
+}
+
+// GetResource returns a table's schema, or a page of its rows when the URI
+// carries a page parameter
+func (p *Provider) GetResource(uri string) (interface{}, error) {
+	rest, ok := strings.CutPrefix(uri, uriScheme)
+	if !ok {
+		return nil, resources.ErrResourceNotFound
+	}
+	name, rawQuery, _ := strings.Cut(rest, "?")
+	t, err := p.Table(name)
+	if err != nil {
+		return nil, resources.ErrResourceNotFound
+	}
+	pages := (len(t.Rows) + p.cfg.PageSize - 1) / p.cfg.PageSize
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid table URI %q: %w", uri, err)
+	}
+	var doc interface{}
+	if pageParam := query.Get("page"); pageParam == "" {
+		doc = schemaDocument{Table: t, RowCount: len(t.Rows), PageSize: p.cfg.PageSize, Pages: pages}
+	} else {
+		page, err := strconv.Atoi(pageParam)
+		if err != nil || page < 0 || (page >= pages && page != 0) {
+			return nil, fmt.Errorf("page %q out of range (0-%d)", pageParam, max(pages-1, 0))
+		}
+		start := page * p.cfg.PageSize
+		end := min(start+p.cfg.PageSize, len(t.Rows))
+		columns := make([]string, len(t.Columns))
+		for i, c := range t.Columns {
+			columns[i] = c.Name
+		}
+		doc = pageDocument{
+			Table:    name,
+			Columns:  columns,
+			Page:     page,
+			Pages:    pages,
+			RowCount: len(t.Rows),
+			Rows:     t.Rows[start:end],
+		}
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// UsageHint implements provider.Hinter
+func (p *Provider) UsageHint() string {
+	return "Each table's schema is a resource; read it for column names and types, then filter and " +
+		"aggregate with " + QueryToolName + " instead of reading whole tables."
+}
+
+// Register adds the table.query tool to m
+func (p *Provider) Register(m *manager.ToolsManager) {
+	m.RegisterTool(protocol.Tool{
+		Name: QueryToolName,
+		Description: "Filter, group and aggregate a CSV or Parquet table. " +
+			"Conditions are ANDed; with groupBy or aggregates one row is returned per group.",
+		InputSchema: querySchema,
+	}, func(_ context.Context, args json.RawMessage, _ chan<- float64) (protocol.ToolsCallResult, error) {
+		var q Query
+		if err := json.Unmarshal(args, &q); err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		t, err := p.Table(q.Table)
+		var result *Result
+		if err == nil {
+			result, err = Run(t, q)
+		}
+		if err != nil {
+			// Query mistakes are reported to the model so it can correct them
+			return protocol.ToolsCallResult{
+				Content: []protocol.Content{protocol.TextContent(err.Error())},
+				IsError: true,
+			}, nil
+		}
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return protocol.ToolsCallResult{}, err
+		}
+		return protocol.ToolsCallResult{
+			Content: []protocol.Content{protocol.TextContent(string(data))},
+		}, nil
+	})
+}
@@ -0,0 +1,78 @@
+// internal/transport/auth.go
+package transport
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrUnauthenticated is returned by authenticators that do not recognize
+// the caller
+var ErrUnauthenticated = errors.New("missing or invalid credentials")
+
+// Authenticator identifies the caller of an HTTP request. Returning an
+// error rejects the request with 401 Unauthorized.
+type Authenticator func(r *http.Request) (identity string, err error)
+
+// WithAuthenticator requires SSE clients to authenticate when they open a
+// stream and on every message they POST. The identity is available to the
+// session as Identity(ctx); messages from anyone else are refused.
+func WithAuthenticator(auth Authenticator) SSEOption {
+	return func(t *SSETransport) {
+		t.auth = auth
+	}
+}
+
+// BearerTokens authenticates callers by the bearer token in their
+// Authorization header, mapping each token to the identity it stands for.
+// With no tokens it returns nil, leaving the transport open.
+func BearerTokens(tokens map[string]string) Authenticator {
+	if len(tokens) == 0 {
+		return nil
+	}
+	return func(r *http.Request) (string, error) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || got == "" {
+			return "", ErrUnauthenticated
+		}
+		// Compare against every token so timing does not reveal a match
+		var identity string
+		for token, id := range tokens {
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1 {
+				identity = id
+			}
+		}
+		if identity == "" {
+			return "", ErrUnauthenticated
+		}
+		return identity, nil
+	}
+}
+
+// identityKey carries the authenticated caller in a session's context
+type identityKey struct{}
+
+// Identity returns the authenticated caller of the connection ctx belongs
+// to, or "" when the transport does not authenticate
+func Identity(ctx context.Context) string {
+	id, _ := ctx.Value(identityKey{}).(string)
+	return id
+}
+
+// authenticate identifies r's caller, replying 401 and returning false
+// when it is rejected. Without an authenticator everyone is anonymous.
+func (t *SSETransport) authenticate(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if t.auth == nil {
+		return "", true
+	}
+	identity, err := t.auth(r)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="mcp"`)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return "", false
+	}
+	return identity, true
+}
@@ -0,0 +1,63 @@
+// internal/transport/body.go
+package transport
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxBodyBytes caps the size of a single POSTed message
+const DefaultMaxBodyBytes = 16 << 20
+
+// errBodyTooLarge is returned when a message exceeds the configured limit
+var errBodyTooLarge = errors.New("message body too large")
+
+// readBody streams a request body into a pooled buffer, rejecting bodies
+// over limit before reading them when Content-Length is known and as soon
+// as the limit is crossed otherwise. The body is not checked to be JSON;
+// the caller answers malformed messages with a JSON-RPC parse error. It
+// returns the buffer with putBuffer once done with it.
+func readBody(w http.ResponseWriter, r *http.Request, limit int64) (*[]byte, error) {
+	if limit > 0 && r.ContentLength > limit {
+		return nil, fmt.Errorf("%w: %d bytes exceeds limit of %d", errBodyTooLarge, r.ContentLength, limit)
+	}
+
+	body := r.Body
+	if limit > 0 {
+		body = http.MaxBytesReader(w, r.Body, limit)
+	}
+
+	b := getBuffer(nil)
+	buf := bytes.NewBuffer(*b)
+	if r.ContentLength > 0 {
+		buf.Grow(int(r.ContentLength))
+	}
+	if _, err := buf.ReadFrom(body); err != nil {
+		*b = buf.Bytes()
+		putBuffer(b)
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, fmt.Errorf("%w: exceeds limit of %d bytes", errBodyTooLarge, maxErr.Limit)
+		}
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+	*b = buf.Bytes()
+	return b, nil
+}
+
+// bodyErrorStatus maps a readBody error to an HTTP status code
+func bodyErrorStatus(err error) int {
+	if errors.Is(err, errBodyTooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// discardBody drains what is left of a rejected body so the connection can
+// be reused, up to a small bound.
+func discardBody(r *http.Request) {
+	_, _ = io.CopyN(io.Discard, r.Body, 64<<10)
+}
@@ -0,0 +1,34 @@
+// internal/transport/bufpool.go
+package transport
+
+import "sync"
+
+// Buffer pool sizing
+const (
+	defaultBufferSize   = 4 * 1024
+	maxPooledBufferSize = 1024 * 1024 // larger buffers are left to the GC
+)
+
+// bufferPool recycles message buffers on the transport Read/Write paths
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, defaultBufferSize)
+		return &b
+	},
+}
+
+// getBuffer returns a pooled buffer holding a copy of p
+func getBuffer(p []byte) *[]byte {
+	b := bufferPool.Get().(*[]byte)
+	*b = append((*b)[:0], p...)
+	return b
+}
+
+// putBuffer returns b to the pool; oversized buffers are dropped
+func putBuffer(b *[]byte) {
+	if b == nil || cap(*b) > maxPooledBufferSize {
+		return
+	}
+	*b = (*b)[:0]
+	bufferPool.Put(b)
+}
@@ -0,0 +1,56 @@
+// internal/transport/capture.go
+package transport
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// WithCapture logs every message each SSE session sends and receives, in
+// full, at debug level. Messages may carry anything clients and tools
+// exchange, so this is for development only.
+func WithCapture(capture bool) SSEOption {
+	return func(t *SSETransport) {
+		t.capture = capture
+	}
+}
+
+// WithStdioCapture logs every message of the stdio session, like WithCapture
+func WithStdioCapture(capture bool) StdioOption {
+	return func(t *StdioTransport) {
+		t.capture = capture
+	}
+}
+
+// captureOpts returns the connection options that log session's messages,
+// or none when capture is off
+func captureOpts(capture bool, session string) []jsonrpc2.ConnOpt {
+	if !capture {
+		return nil
+	}
+	return []jsonrpc2.ConnOpt{
+		jsonrpc2.OnRecv(func(req *jsonrpc2.Request, resp *jsonrpc2.Response) {
+			logCaptured(session, "in", req, resp)
+		}),
+		jsonrpc2.OnSend(func(req *jsonrpc2.Request, resp *jsonrpc2.Response) {
+			logCaptured(session, "out", req, resp)
+		}),
+	}
+}
+
+// logCaptured logs one message. jsonrpc2 pairs a response with the request
+// it answers; only the message that actually crossed the wire is logged.
+func logCaptured(session, direction string, req *jsonrpc2.Request, resp *jsonrpc2.Response) {
+	var msg interface{} = req
+	if resp != nil {
+		msg = resp
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		slog.Debug("Captured message", "session", session, "direction", direction, "error", err)
+		return
+	}
+	slog.Debug("Captured message", "session", session, "direction", direction, "message", string(data))
+}
@@ -0,0 +1,209 @@
+// internal/transport/chaos.go
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// ChaosConfig sets which faults are injected and how often. Rates are
+// probabilities per write between 0 and 1.
+type ChaosConfig struct {
+	Latency        time.Duration // added before every read and write
+	Jitter         time.Duration // up to this much extra latency, chosen at random
+	DropRate       float64       // writes silently discarded
+	PartialRate    float64       // writes cut short, corrupting the stream
+	DisconnectRate float64       // writes that close the connection instead
+	Seed           int64         // 0 seeds from the current time
+}
+
+// ParseChaos parses a comma-separated spec such as
+// "latency=50ms,jitter=20ms,drop=0.01,partial=0.01,disconnect=0.001,seed=1"
+func ParseChaos(spec string) (ChaosConfig, error) {
+	var cfg ChaosConfig
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return ChaosConfig{}, fmt.Errorf("invalid chaos setting %q: want key=value", field)
+		}
+
+		var err error
+		switch key {
+		case "latency":
+			cfg.Latency, err = time.ParseDuration(value)
+		case "jitter":
+			cfg.Jitter, err = time.ParseDuration(value)
+		case "drop":
+			cfg.DropRate, err = parseRate(value)
+		case "partial":
+			cfg.PartialRate, err = parseRate(value)
+		case "disconnect":
+			cfg.DisconnectRate, err = parseRate(value)
+		case "seed":
+			cfg.Seed, err = strconv.ParseInt(value, 10, 64)
+		default:
+			return ChaosConfig{}, fmt.Errorf("unknown chaos setting %q", key)
+		}
+		if err != nil {
+			return ChaosConfig{}, fmt.Errorf("invalid chaos setting %q: %w", field, err)
+		}
+	}
+	return cfg, nil
+}
+
+// parseRate parses a probability
+func parseRate(s string) (float64, error) {
+	rate, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("rate must be between 0 and 1")
+	}
+	return rate, nil
+}
+
+// streamWrapper is implemented by transports that let a decorator wrap the
+// byte stream under each connection
+type streamWrapper interface {
+	wrapStreams(wrap func(io.ReadWriteCloser) io.ReadWriteCloser)
+}
+
+// Ensure the built-in transports accept stream wrappers
+var (
+	_ streamWrapper = (*StdioTransport)(nil)
+	_ streamWrapper = (*SSETransport)(nil)
+)
+
+// ChaosTransport decorates a transport so every connection it makes suffers
+// the configured faults. It is meant for development and tests.
+type ChaosTransport struct {
+	Transport
+	cfg ChaosConfig
+}
+
+// NewChaosTransport wraps inner with fault injection
+func NewChaosTransport(inner Transport, cfg ChaosConfig) *ChaosTransport {
+	return &ChaosTransport{Transport: inner, cfg: cfg}
+}
+
+// Connect installs the fault-injecting stream wrapper and connects inner
+func (c *ChaosTransport) Connect(ctx context.Context, handler jsonrpc2.Handler) (*jsonrpc2.Conn, error) {
+	w, ok := c.Transport.(streamWrapper)
+	if !ok {
+		return nil, fmt.Errorf("transport %T does not support fault injection", c.Transport)
+	}
+	w.wrapStreams(func(rwc io.ReadWriteCloser) io.ReadWriteCloser {
+		return NewChaosStream(rwc, c.cfg)
+	})
+	slog.Warn("Fault injection enabled",
+		"latency", c.cfg.Latency,
+		"jitter", c.cfg.Jitter,
+		"drop_rate", c.cfg.DropRate,
+		"partial_rate", c.cfg.PartialRate,
+		"disconnect_rate", c.cfg.DisconnectRate)
+	return c.Transport.Connect(ctx, handler)
+}
+
+// chaosStream injects faults into an underlying stream
+type chaosStream struct {
+	inner  io.ReadWriteCloser
+	cfg    ChaosConfig
+	closed atomic.Bool
+
+	mu  sync.Mutex // guards rng
+	rng *rand.Rand
+}
+
+// NewChaosStream wraps rwc so reads and writes suffer the configured faults
+func NewChaosStream(rwc io.ReadWriteCloser, cfg ChaosConfig) io.ReadWriteCloser {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &chaosStream{inner: rwc, cfg: cfg, rng: rand.New(rand.NewSource(seed))}
+}
+
+// Read delays, then reads; a disconnected stream reads EOF
+func (s *chaosStream) Read(p []byte) (int, error) {
+	s.delay()
+	if s.closed.Load() {
+		return 0, io.EOF
+	}
+	n, err := s.inner.Read(p)
+	if s.closed.Load() {
+		return 0, io.EOF
+	}
+	return n, err
+}
+
+// Write delays, then drops, truncates or disconnects according to the rates
+func (s *chaosStream) Write(p []byte) (int, error) {
+	s.delay()
+	if s.closed.Load() {
+		return 0, io.ErrClosedPipe
+	}
+
+	switch {
+	case s.chance(s.cfg.DisconnectRate):
+		slog.Warn("Chaos: disconnecting")
+		_ = s.Close()
+		return 0, io.ErrClosedPipe
+	case s.chance(s.cfg.DropRate):
+		slog.Warn("Chaos: dropping write", "bytes", len(p))
+		return len(p), nil
+	case s.chance(s.cfg.PartialRate) && len(p) > 1:
+		n, err := s.inner.Write(p[:len(p)/2])
+		slog.Warn("Chaos: partial write", "bytes", n, "of", len(p))
+		if err != nil {
+			return n, err
+		}
+		return n, io.ErrShortWrite
+	}
+	return s.inner.Write(p)
+}
+
+// Close closes the underlying stream once
+func (s *chaosStream) Close() error {
+	if s.closed.Swap(true) {
+		return nil
+	}
+	return s.inner.Close()
+}
+
+// chance reports true with probability rate
+func (s *chaosStream) chance(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Float64() < rate
+}
+
+// delay sleeps for the latency plus random jitter
+func (s *chaosStream) delay() {
+	d := s.cfg.Latency
+	if s.cfg.Jitter > 0 {
+		s.mu.Lock()
+		d += time.Duration(s.rng.Int63n(int64(s.cfg.Jitter)))
+		s.mu.Unlock()
+	}
+	if d > 0 {
+		time.Sleep(d)
+	}
+}
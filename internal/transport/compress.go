@@ -0,0 +1,104 @@
+// internal/transport/compress.go
+package transport
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Supported response encodings, in order of preference
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+// negotiateEncoding picks a response encoding from the Accept-Encoding header,
+// returning "" when the client accepts neither gzip nor deflate.
+func negotiateEncoding(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		name, q := parseEncoding(part)
+		if name != encodingGzip && name != encodingDeflate {
+			continue
+		}
+		// Ties favour gzip, which is listed first in our preference order
+		if q > bestQ || (q == bestQ && name == encodingGzip) {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+// parseEncoding splits one Accept-Encoding entry into a name and quality
+func parseEncoding(part string) (string, float64) {
+	name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+	q := 1.0
+	if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+			q = parsed
+		}
+	}
+	return strings.ToLower(strings.TrimSpace(name)), q
+}
+
+// compressWriter compresses a response while still supporting Flush, which
+// the SSE stream needs to push each event to the client immediately.
+type compressWriter struct {
+	http.ResponseWriter
+	w flushWriteCloser
+}
+
+// flushWriteCloser is satisfied by both gzip.Writer and flate.Writer
+type flushWriteCloser interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// Write compresses p into the response
+func (c *compressWriter) Write(p []byte) (int, error) {
+	return c.w.Write(p)
+}
+
+// Flush emits everything compressed so far and flushes the connection
+func (c *compressWriter) Flush() {
+	_ = c.w.Flush()
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (c *compressWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// compressHandler wraps next so responses are compressed when the client
+// advertises gzip or deflate support.
+func compressHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var cw flushWriteCloser
+		if encoding == encodingGzip {
+			cw = gzip.NewWriter(w)
+		} else {
+			// Only fails for an invalid level
+			cw, _ = flate.NewWriter(w, flate.DefaultCompression)
+		}
+		defer cw.Close()
+
+		w.Header().Set("Content-Encoding", encoding)
+		w.Header().Del("Content-Length")
+		next.ServeHTTP(&compressWriter{ResponseWriter: w, w: cw}, r)
+	})
+}
@@ -0,0 +1,92 @@
+// internal/transport/handoff.go
+package transport
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// ListenerFDEnv names the environment variable used to pass an inherited
+// listening socket from a parent process to its replacement.
+const ListenerFDEnv = "AXE_HANDLE_LISTENER_FD"
+
+// Restartable is implemented by transports that can hand their listening
+// socket to a replacement process and drain their existing sessions.
+type Restartable interface {
+	Transport
+
+	// Handoff starts a replacement process that inherits the listener
+	Handoff() (*os.Process, error)
+
+	// Drain stops accepting new connections and waits for active sessions
+	// to finish until ctx is done, then closes whatever remains
+	Drain(ctx context.Context) error
+}
+
+// listen returns the listener inherited from a parent process if one was
+// passed in, and otherwise binds a fresh listener on addr.
+func listen(addr string) (net.Listener, error) {
+	fdStr := os.Getenv(ListenerFDEnv)
+	if fdStr == "" {
+		return net.Listen("tcp", addr)
+	}
+
+	fd, err := strconv.Atoi(fdStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value %q: %w", ListenerFDEnv, fdStr, err)
+	}
+
+	// Clear the variable so processes we spawn later don't inherit it by accident
+	_ = os.Unsetenv(ListenerFDEnv)
+
+	f := os.NewFile(uintptr(fd), "inherited-listener")
+	defer f.Close()
+
+	l, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inherit listener from fd %d: %w", fd, err)
+	}
+
+	slog.Info("Inherited listener from parent process", "fd", fd, "address", l.Addr().String())
+	return l, nil
+}
+
+// spawnWithListener re-executes the current binary with the same arguments,
+// passing l to the child as an extra file descriptor.
+func spawnWithListener(l net.Listener) (*os.Process, error) {
+	tcpListener, ok := l.(*net.TCPListener)
+	if !ok {
+		return nil, fmt.Errorf("listener of type %T cannot be handed off", l)
+	}
+
+	f, err := tcpListener.File()
+	if err != nil {
+		return nil, fmt.Errorf("failed to duplicate listener: %w", err)
+	}
+	defer f.Close()
+
+	exePath, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get executable path: %w", err)
+	}
+
+	cmd := exec.Command(exePath, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = []*os.File{f}
+	// ExtraFiles[0] becomes fd 3 in the child
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", ListenerFDEnv, 3))
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start replacement process: %w", err)
+	}
+
+	slog.Info("Started replacement process", "pid", cmd.Process.Pid)
+	return cmd.Process, nil
+}
@@ -0,0 +1,31 @@
+// internal/transport/profile.go
+package transport
+
+import "context"
+
+// profileKey carries the server profile a client connected to in a
+// session's context
+type profileKey struct{}
+
+// Profile returns the name of the server profile the connection ctx belongs
+// to was opened for, or "" for the whole server
+func Profile(ctx context.Context) string {
+	name, _ := ctx.Value(profileKey{}).(string)
+	return name
+}
+
+// WithProfiles also mounts both endpoints under /<name> for each profile,
+// e.g. /work/sse and /work/messages, so one server can back several clients
+// that each see a different part of it
+func WithProfiles(names ...string) SSEOption {
+	return func(t *SSETransport) {
+		t.profiles = append(t.profiles, names...)
+	}
+}
+
+// WithStdioProfile serves the stdio session with the named profile
+func WithStdioProfile(name string) StdioOption {
+	return func(t *StdioTransport) {
+		t.profile = name
+	}
+}
@@ -0,0 +1,145 @@
+// internal/transport/proxy.go
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"path"
+	"strings"
+)
+
+// ParseTrustedProxies parses the addresses of reverse proxies whose
+// X-Forwarded-* headers are believed. Entries are IPs or CIDR ranges.
+func ParseTrustedProxies(list []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(list))
+	for _, s := range list {
+		s = strings.TrimSpace(s)
+		if p, err := netip.ParsePrefix(s); err == nil {
+			prefixes = append(prefixes, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: want an IP or CIDR", s)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return prefixes, nil
+}
+
+// WithTrustedProxies believes the X-Forwarded-For, -Proto, -Host and
+// -Prefix headers of requests arriving from these addresses. Requests from
+// anyone else are taken at face value, so clients cannot spoof their IP.
+func WithTrustedProxies(prefixes []netip.Prefix) SSEOption {
+	return func(t *SSETransport) {
+		t.trustedProxies = prefixes
+	}
+}
+
+// clientAddrKey carries the client's address in a session's context
+type clientAddrKey struct{}
+
+// ClientAddr returns the IP of the client whose connection ctx belongs to,
+// resolved through trusted proxies, or "" when the transport has none
+func ClientAddr(ctx context.Context) string {
+	addr, _ := ctx.Value(clientAddrKey{}).(string)
+	return addr
+}
+
+// origin describes where a request came from and the URL it was sent to,
+// as seen by the client rather than by the server behind its proxies
+type origin struct {
+	clientIP string
+	scheme   string
+	host     string
+	prefix   string // path the proxy strips before forwarding
+}
+
+// origin resolves r's client and external URL, honouring forwarding
+// headers only when the immediate peer is a trusted proxy
+func (t *SSETransport) origin(r *http.Request) origin {
+	o := origin{clientIP: remoteIP(r), scheme: "http", host: r.Host}
+	if r.TLS != nil {
+		o.scheme = "https"
+	}
+	if !t.trusted(o.clientIP) {
+		return o
+	}
+
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		o.clientIP = t.forwardedClient(xff, o.clientIP)
+	}
+	if proto := firstValue(r.Header.Get("X-Forwarded-Proto")); proto == "http" || proto == "https" {
+		o.scheme = proto
+	}
+	if host := firstValue(r.Header.Get("X-Forwarded-Host")); host != "" {
+		o.host = host
+	}
+	if prefix := firstValue(r.Header.Get("X-Forwarded-Prefix")); prefix != "" {
+		o.prefix = path.Clean("/" + prefix)
+	}
+	return o
+}
+
+// forwardedClient walks the X-Forwarded-For chain from the nearest hop and
+// returns the first address that is not a trusted proxy
+func (t *SSETransport) forwardedClient(headers []string, peer string) string {
+	var hops []string
+	for _, h := range headers {
+		for _, hop := range strings.Split(h, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	client := peer
+	for i := len(hops) - 1; i >= 0; i-- {
+		client = hops[i]
+		if !t.trusted(client) {
+			break
+		}
+	}
+	return client
+}
+
+// trusted reports whether ip belongs to a trusted proxy
+func (t *SSETransport) trusted(ip string) bool {
+	if len(t.trustedProxies) == 0 {
+		return false
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range t.trustedProxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// endpointURL is the absolute URL a client of profile posts its session's
+// messages to
+func (t *SSETransport) endpointURL(o origin, profile, sessionID string) string {
+	return fmt.Sprintf("%s://%s%s?sessionId=%s", o.scheme, o.host, path.Join(o.prefix, t.profileMessagePath(profile)), sessionID)
+}
+
+// remoteIP returns the IP of r's immediate peer
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// firstValue returns the first entry of a comma-separated header
+func firstValue(h string) string {
+	v, _, _ := strings.Cut(h, ",")
+	return strings.TrimSpace(v)
+}
@@ -2,67 +2,193 @@
 package transport
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
+	"net/netip"
+	"os"
+	"path"
 	"sync"
+	"time"
 
+	"github.com/dkoosis/axe-handle/internal/codec"
 	"github.com/sourcegraph/jsonrpc2"
 )
 
+// maxEventsPerFlush bounds how many queued events share one write and flush
+const maxEventsPerFlush = 32
+
 // SSETransport implements the Transport interface for SSE communication
 type SSETransport struct {
-	port        int
-	host        string
-	path        string
-	messagePath string
-	server      *http.Server
-	handler     jsonrpc2.Handler
-	clients     map[string]*sseClient
-	mu          sync.RWMutex
+	port           int
+	host           string
+	basePath       string
+	path           string
+	messagePath    string
+	profiles       []string // each also served under /<profile>
+	server         *http.Server
+	listener       net.Listener
+	handler        jsonrpc2.Handler
+	clients        map[string]*sseClient
+	compress       bool
+	maxBodyBytes   int64
+	trustedProxies []netip.Prefix // peers whose X-Forwarded-* headers are believed
+	timeouts       Timeouts
+	auth           Authenticator                               // nil lets anyone connect
+	capture        bool                                        // log every message of every session
+	wrap           func(io.ReadWriteCloser) io.ReadWriteCloser // optional stream decorator
+	mu             sync.RWMutex
 }
 
+// Ensure SSETransport supports listener handoff
+var _ Restartable = (*SSETransport)(nil)
+
 // sseClient represents a connected SSE client
 type sseClient struct {
 	id         string
+	identity   string // authenticated caller; "" when anonymous
+	profile    string // server profile the stream was opened on; "" for the whole server
 	conn       *jsonrpc2.Conn
-	messagesCh chan []byte
+	stream     *sseStreamAdapter
+	messagesCh chan *[]byte // pooled buffers; the SSE writer returns them. Never closed: writers stop on done
 	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// close signals the client's stream to stop; safe to call more than once
+func (c *sseClient) close() {
+	c.closeOnce.Do(func() { close(c.done) })
+}
+
+// SSEOption configures an SSETransport
+type SSEOption func(*SSETransport)
+
+// WithCompression enables gzip/deflate compression of HTTP responses for
+// clients that send a matching Accept-Encoding header
+func WithCompression(enabled bool) SSEOption {
+	return func(t *SSETransport) {
+		t.compress = enabled
+	}
+}
+
+// WithMaxBodyBytes limits the size of POSTed messages; 0 disables the limit
+func WithMaxBodyBytes(limit int64) SSEOption {
+	return func(t *SSETransport) {
+		t.maxBodyBytes = limit
+	}
+}
+
+// WithBasePath mounts both endpoints under prefix, such as /api/mcp, for
+// servers running behind a router or gateway that forwards a subtree
+func WithBasePath(prefix string) SSEOption {
+	return func(t *SSETransport) {
+		t.basePath = prefix
+	}
+}
+
+// WithEndpoints replaces the event stream path (/sse) and the path clients
+// POST messages to (/messages); empty arguments keep the defaults
+func WithEndpoints(streamPath, messagePath string) SSEOption {
+	return func(t *SSETransport) {
+		if streamPath != "" {
+			t.path = streamPath
+		}
+		if messagePath != "" {
+			t.messagePath = messagePath
+		}
+	}
 }
 
 // NewSSETransport creates a new SSE transport
-func NewSSETransport(host string, port int) *SSETransport {
-	return &SSETransport{
-		host:        host,
-		port:        port,
-		path:        "/sse",
-		messagePath: "/messages",
-		clients:     make(map[string]*sseClient),
+func NewSSETransport(host string, port int, opts ...SSEOption) *SSETransport {
+	t := &SSETransport{
+		host:         host,
+		port:         port,
+		path:         "/sse",
+		messagePath:  "/messages",
+		clients:      make(map[string]*sseClient),
+		maxBodyBytes: DefaultMaxBodyBytes,
+	}
+	for _, opt := range opts {
+		opt(t)
 	}
+	return t
 }
 
-// Connect establishes the HTTP server for SSE connections
-func (t *SSETransport) Connect(ctx context.Context, handler jsonrpc2.Handler) (*jsonrpc2.Conn, error) {
+// Handler returns the transport's HTTP routes, serving sessions through
+// handler. Connect uses it; tests can mount it on an httptest.Server.
+func (t *SSETransport) Handler(handler jsonrpc2.Handler) http.Handler {
 	t.handler = handler
 
-	// Set up HTTP routes
 	mux := http.NewServeMux()
-	mux.HandleFunc(t.path, t.handleSSE)
-	mux.HandleFunc(t.messagePath, t.handleMessages)
+	mux.HandleFunc(t.StreamPath(), t.streamHandler(""))
+	mux.HandleFunc(t.MessagePath(), t.handleMessages)
+	for _, profile := range t.profiles {
+		mux.HandleFunc(t.ProfileStreamPath(profile), t.streamHandler(profile))
+		mux.HandleFunc(t.profileMessagePath(profile), t.handleMessages)
+	}
+
+	if t.compress {
+		return compressHandler(mux)
+	}
+	return mux
+}
+
+// StreamPath returns the full path of the event stream endpoint
+func (t *SSETransport) StreamPath() string {
+	return t.ProfileStreamPath("")
+}
+
+// MessagePath returns the full path clients POST messages to
+func (t *SSETransport) MessagePath() string {
+	return t.profileMessagePath("")
+}
+
+// ProfileStreamPath returns the full path of a profile's event stream
+// endpoint; the empty profile is the whole server
+func (t *SSETransport) ProfileStreamPath(profile string) string {
+	return path.Join("/", t.basePath, profile, t.path)
+}
+
+// profileMessagePath returns the full path a profile's clients POST to
+func (t *SSETransport) profileMessagePath(profile string) string {
+	return path.Join("/", t.basePath, profile, t.messagePath)
+}
+
+// Sessions returns the number of connected SSE sessions
+func (t *SSETransport) Sessions() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.clients)
+}
+
+// Connect establishes the HTTP server for SSE connections
+func (t *SSETransport) Connect(ctx context.Context, handler jsonrpc2.Handler) (*jsonrpc2.Conn, error) {
+	h := t.Handler(handler)
 
 	// Create HTTP server
 	t.server = &http.Server{
 		Addr:    fmt.Sprintf("%s:%d", t.host, t.port),
-		Handler: mux,
+		Handler: h,
+	}
+	t.timeouts.applyServerTimeouts(t.server)
+
+	// Bind (or inherit) the listener up front so address errors surface here
+	listener, err := listen(t.server.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", t.server.Addr, err)
 	}
+	t.listener = listener
 
 	// Start server in a goroutine
 	go func() {
-		slog.Info("Starting SSE server", "address", t.server.Addr)
-		if err := t.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		slog.Info("Starting SSE server", "address", listener.Addr().String())
+		if err := t.server.Serve(listener); err != nil && err != http.ErrServerClosed {
 			slog.Error("SSE server error", "error", err)
 		}
 	}()
@@ -70,21 +196,45 @@ func (t *SSETransport) Connect(ctx context.Context, handler jsonrpc2.Handler) (*
 	return nil, nil // No single connection for SSE
 }
 
+// wrapStreams decorates the stream of every SSE session
+func (t *SSETransport) wrapStreams(wrap func(io.ReadWriteCloser) io.ReadWriteCloser) {
+	t.wrap = wrap
+}
+
+// streamHandler serves SSE connections opened on a profile's endpoint
+func (t *SSETransport) streamHandler(profile string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		t.handleSSE(w, r, profile)
+	}
+}
+
 // handleSSE handles SSE connections
-func (t *SSETransport) handleSSE(w http.ResponseWriter, r *http.Request) {
+func (t *SSETransport) handleSSE(w http.ResponseWriter, r *http.Request, profile string) {
+	identity, ok := t.authenticate(w, r)
+	if !ok {
+		return
+	}
+
 	// Set SSE headers
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 
+	// The server's read timeout covers the request, not the stream it opens
+	if t.timeouts.Read > 0 {
+		_ = http.NewResponseController(w).SetReadDeadline(time.Time{})
+	}
+
 	// Create unique client ID
 	clientID := fmt.Sprintf("%p", r)
 
 	// Set up client
 	client := &sseClient{
 		id:         clientID,
-		messagesCh: make(chan []byte, 100),
+		identity:   identity,
+		profile:    profile,
+		messagesCh: make(chan *[]byte, 100),
 		done:       make(chan struct{}),
 	}
 
@@ -98,21 +248,38 @@ func (t *SSETransport) handleSSE(w http.ResponseWriter, r *http.Request) {
 		t.mu.Lock()
 		delete(t.clients, clientID)
 		t.mu.Unlock()
-		close(client.done)
-		close(client.messagesCh)
+		// Writers select on done, so messagesCh is left open for them
+		client.close()
 	}()
 
-	// Set up client connection with a custom stream
-	// We need to create an adapter that converts our SSE stream to a proper io.ReadWriteCloser
-	adapter := &sseStreamAdapter{client: client, clientID: clientID}
+	// Resolve the client and the URL it reached us at through any proxies
+	o := t.origin(r)
+	ctx := context.WithValue(r.Context(), clientAddrKey{}, o.clientIP)
+	ctx = context.WithValue(ctx, identityKey{}, identity)
+	ctx = context.WithValue(ctx, profileKey{}, profile)
+	slog.Info("SSE client connected", "session", clientID, "client", o.clientIP, "identity", identity, "profile", profile)
+
+	// Messages travel one per line: POSTs feed the stream's reads and each
+	// write becomes one event, so requests reach the handler as requests
+	client.stream = newSSEStreamAdapter(client, clientID)
+	var stream io.ReadWriteCloser = client.stream
+	if t.wrap != nil {
+		stream = t.wrap(stream)
+	}
 	client.conn = jsonrpc2.NewConn(
-		r.Context(),
-		jsonrpc2.NewBufferedStream(adapter, jsonrpc2.VSCodeObjectCodec{}),
+		ctx,
+		codec.NewFramedStream(stream, codec.FramingNDJSON, codec.WithFrameCapture(t.capture)),
 		t.handler,
+		captureOpts(t.capture, clientID)...,
 	)
 
-	// Notify client of connection
-	fmt.Fprintf(w, "data: {\"sessionId\": \"%s\"}\n\n", clientID)
+	// Announce the session and the absolute URL to post its messages to
+	hello, _ := json.Marshal(map[string]string{
+		"sessionId": clientID,
+		"endpoint":  t.endpointURL(o, profile, clientID),
+	})
+	setWriteDeadline(w, t.timeouts.Write)
+	fmt.Fprintf(w, "data: %s\n\n", hello)
 	w.(http.Flusher).Flush()
 
 	// Keep connection open and send messages
@@ -122,16 +289,35 @@ func (t *SSETransport) handleSSE(w http.ResponseWriter, r *http.Request) {
 			return
 		case <-client.done:
 			return
-		case msg, ok := <-client.messagesCh:
-			if !ok {
+		case msg := <-client.messagesCh:
+			// A client that stops reading is dropped once a write times out
+			setWriteDeadline(w, t.timeouts.Write)
+			_, err := fmt.Fprintf(w, "data: %s\n\n", *msg)
+			putBuffer(msg)
+			if err != nil {
+				slog.Warn("Dropping SSE client after failed write", "session", clientID, "error", err)
 				return
 			}
-			fmt.Fprintf(w, "data: %s\n\n", msg)
+			// Coalesce whatever else is queued into the same flush
+			writeQueued(w, client.messagesCh, maxEventsPerFlush-1)
 			w.(http.Flusher).Flush()
 		}
 	}
 }
 
+// writeQueued writes up to limit already-queued messages without blocking
+func writeQueued(w io.Writer, ch <-chan *[]byte, limit int) {
+	for i := 0; i < limit; i++ {
+		select {
+		case msg := <-ch:
+			fmt.Fprintf(w, "data: %s\n\n", *msg)
+			putBuffer(msg)
+		default:
+			return
+		}
+	}
+}
+
 // handleMessages handles incoming messages from clients
 func (t *SSETransport) handleMessages(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -139,6 +325,11 @@ func (t *SSETransport) handleMessages(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	identity, ok := t.authenticate(w, r)
+	if !ok {
+		return
+	}
+
 	// Get client ID from query parameters
 	clientID := r.URL.Query().Get("sessionId")
 	if clientID == "" {
@@ -156,20 +347,54 @@ func (t *SSETransport) handleMessages(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse JSON-RPC message
-	var msg json.RawMessage
-	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	// Only the caller who opened the stream may post to it
+	if identity != client.identity {
+		http.Error(w, "Session belongs to another caller", http.StatusForbidden)
 		return
 	}
 
-	// Process message through client connection
-	if err := client.conn.Notify(r.Context(), "$/message", msg); err != nil {
-		http.Error(w, "Error processing message", http.StatusInternalServerError)
+	// Stream the body into a pooled buffer, rejecting oversized messages early
+	msg, err := readBody(w, r, t.maxBodyBytes)
+	if err != nil {
+		discardBody(r)
+		http.Error(w, err.Error(), bodyErrorStatus(err))
 		return
 	}
+	defer putBuffer(msg)
+
+	// Hand the message to the session's connection as one compact line;
+	// replies are sent on the event stream
+	line := getBuffer(nil)
+	if err := compactLine(line, *msg); err != nil {
+		putBuffer(line)
+		codec.LogMalformed(*msg, err, t.capture)
+		// The error goes where the client reads replies and in the response
+		reply := codec.ParseErrorReply()
+		_, _ = client.stream.Write(reply)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write(reply)
+		return
+	}
+	if err := client.stream.deliver(r.Context(), line); err != nil {
+		putBuffer(line)
+		http.Error(w, "Session closed", http.StatusGone)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
 
-	w.WriteHeader(http.StatusOK)
+// compactLine writes msg to dst without insignificant whitespace and ends it
+// with a newline, the framing the session's connection reads
+func compactLine(dst *[]byte, msg []byte) error {
+	buf := bytes.NewBuffer(*dst)
+	if err := json.Compact(buf, msg); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	*dst = buf.Bytes()
+	return nil
 }
 
 // Close shuts down the HTTP server
@@ -178,7 +403,7 @@ func (t *SSETransport) Close() error {
 		// Close all client connections
 		t.mu.Lock()
 		for _, client := range t.clients {
-			close(client.done)
+			client.close()
 		}
 		t.clients = make(map[string]*sseClient)
 		t.mu.Unlock()
@@ -189,56 +414,119 @@ func (t *SSETransport) Close() error {
 	return nil
 }
 
+// Handoff starts a replacement process that inherits the listening socket.
+// The caller should Drain this transport once the replacement is running.
+func (t *SSETransport) Handoff() (*os.Process, error) {
+	if t.listener == nil {
+		return nil, fmt.Errorf("SSE transport is not listening")
+	}
+	return spawnWithListener(t.listener)
+}
+
+// Drain stops accepting new connections and lets existing SSE sessions run
+// until they disconnect or ctx expires, at which point they are closed.
+func (t *SSETransport) Drain(ctx context.Context) error {
+	if t.server == nil {
+		return nil
+	}
+
+	t.mu.RLock()
+	active := len(t.clients)
+	t.mu.RUnlock()
+	slog.Info("Draining SSE transport", "active_sessions", active)
+
+	if err := t.server.Shutdown(ctx); err == nil {
+		return nil
+	}
+
+	t.mu.RLock()
+	remaining := len(t.clients)
+	t.mu.RUnlock()
+	slog.Warn("Drain deadline reached, closing remaining sessions", "remaining_sessions", remaining)
+
+	return t.Close()
+}
+
 // sseStreamAdapter adapts our SSE stream to the io.ReadWriteCloser interface
 // that jsonrpc2.NewBufferedStream expects
 type sseStreamAdapter struct {
 	client   *sseClient
 	clientID string
 	msgBuf   []byte
-	msgs     chan json.RawMessage
+	current  *[]byte // pooled buffer backing msgBuf
+	incoming chan *[]byte
+}
+
+// newSSEStreamAdapter creates a new SSE stream adapter
+func newSSEStreamAdapter(client *sseClient, clientID string) *sseStreamAdapter {
+	return &sseStreamAdapter{
+		client:   client,
+		clientID: clientID,
+		msgBuf:   nil,
+		incoming: make(chan *[]byte, 10),
+	}
+}
+
+// deliver queues a POSTed message for the connection to read, waiting while
+// earlier messages are processed
+func (s *sseStreamAdapter) deliver(ctx context.Context, msg *[]byte) error {
+	select {
+	case s.incoming <- msg:
+		return nil
+	case <-s.client.done:
+		return io.EOF
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // Read implements the io.Reader interface
 func (s *sseStreamAdapter) Read(p []byte) (int, error) {
 	// If we have data in the buffer, return it
 	if len(s.msgBuf) > 0 {
-		n := copy(p, s.msgBuf)
-		s.msgBuf = s.msgBuf[n:]
-		return n, nil
+		return s.drain(p), nil
 	}
 
 	// Otherwise, wait for a new message
-	ctx := context.Background()
 	select {
-	case <-ctx.Done():
-		return 0, ctx.Err()
 	case <-s.client.done:
 		return 0, io.EOF
-	case msg, ok := <-s.msgs:
-		if !ok {
-			return 0, io.EOF
-		}
-		// Copy the message into the buffer
-		s.msgBuf = msg
-		n := copy(p, s.msgBuf)
-		s.msgBuf = s.msgBuf[n:]
-		return n, nil
+	case msg := <-s.incoming:
+		s.current = msg
+		s.msgBuf = *msg
+		return s.drain(p), nil
+	}
+}
+
+// drain copies buffered data into p, recycling the buffer once it is consumed
+func (s *sseStreamAdapter) drain(p []byte) int {
+	n := copy(p, s.msgBuf)
+	s.msgBuf = s.msgBuf[n:]
+	if len(s.msgBuf) == 0 {
+		putBuffer(s.current)
+		s.current = nil
+		s.msgBuf = nil
 	}
+	return n
 }
 
-// Write implements the io.Writer interface
+// Write implements the io.Writer interface. It waits while the client's
+// queue is full and fails once the client is gone.
 func (s *sseStreamAdapter) Write(p []byte) (int, error) {
+	// Copy into a pooled buffer since we can't guarantee p won't be
+	// modified; the line's newline is replaced by the event's framing
+	msg := getBuffer(bytes.TrimRight(p, "\n"))
 	select {
 	case <-s.client.done:
+		putBuffer(msg)
 		return 0, io.EOF
-	default:
-		s.client.messagesCh <- p
+	case s.client.messagesCh <- msg:
 		return len(p), nil
 	}
 }
 
-// Close implements the io.Closer interface
+// Close implements the io.Closer interface, ending the session's stream
 func (s *sseStreamAdapter) Close() error {
-	close(s.msgs)
+	s.client.close()
 	return nil
 }
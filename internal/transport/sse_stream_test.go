@@ -0,0 +1,52 @@
+// internal/transport/sse_stream_test.go
+package transport
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWriteAfterDisconnect(t *testing.T) {
+	client := &sseClient{messagesCh: make(chan *[]byte, 1), done: make(chan struct{})}
+	stream := newSSEStreamAdapter(client, "test")
+
+	// Writers outrun the stream, so all but one wait on the full queue
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if _, err := stream.Write([]byte("{}\n")); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	// The client disconnects the way handleSSE cleans up
+	client.close()
+
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("writers still blocked after the client disconnected")
+	}
+	close(errs)
+	for err := range errs {
+		if !errors.Is(err, io.EOF) {
+			t.Errorf("Write() error = %v, want io.EOF", err)
+		}
+	}
+}
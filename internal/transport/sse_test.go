@@ -0,0 +1,42 @@
+// internal/transport/sse_test.go
+package transport_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dkoosis/axe-handle/pkg/mcptest"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+func TestMalformedPostGetsParseError(t *testing.T) {
+	srv := mcptest.NewSSEServer(t)
+	st := srv.Connect(t)
+
+	status, body := st.PostRaw(t, []byte(`{"jsonrpc":"2.0","id":1,"method":`))
+	if status != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", status, http.StatusBadRequest)
+	}
+	assertParseError(t, "response", body)
+
+	// The client also reads the error where it reads its replies
+	ev := st.Next(t)
+	assertParseError(t, "event", ev.Data)
+}
+
+// assertParseError fails unless data is a -32700 reply with a null ID
+func assertParseError(t *testing.T, what, data string) {
+	t.Helper()
+	var reply struct {
+		ID    *jsonrpc2.ID    `json:"id"`
+		Error *jsonrpc2.Error `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &reply); err != nil {
+		t.Fatalf("%s is not JSON: %q", what, data)
+	}
+	if reply.Error == nil || reply.Error.Code != jsonrpc2.CodeParseError || reply.ID != nil {
+		t.Errorf("%s = %s, want a -32700 error with a null id", what, data)
+	}
+}
@@ -9,17 +9,38 @@ import (
 
 	// "encoding/hex" // Uncomment if using hex logging in Read/Write
 
+	"github.com/dkoosis/axe-handle/internal/codec"
 	"github.com/sourcegraph/jsonrpc2"
 )
 
 // StdioTransport implements the Transport interface for stdio communication
 type StdioTransport struct {
-	conn *jsonrpc2.Conn
+	conn     *jsonrpc2.Conn
+	framing  codec.Framing
+	timeouts Timeouts
+	profile  string                                      // server profile of the session; "" for the whole server
+	capture  bool                                        // log every message
+	wrap     func(io.ReadWriteCloser) io.ReadWriteCloser // optional stream decorator
+}
+
+// StdioOption configures a StdioTransport
+type StdioOption func(*StdioTransport)
+
+// WithFraming selects how messages are delimited on stdin and stdout. Hosts
+// that emit newline-delimited JSON should use codec.FramingNDJSON.
+func WithFraming(f codec.Framing) StdioOption {
+	return func(t *StdioTransport) {
+		t.framing = f
+	}
 }
 
 // NewStdioTransport creates a new stdio transport
-func NewStdioTransport() *StdioTransport {
-	return &StdioTransport{}
+func NewStdioTransport(opts ...StdioOption) *StdioTransport {
+	t := &StdioTransport{framing: codec.FramingPlain}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
 }
 
 // In internal/transport/stdio.go -> Connect method
@@ -27,17 +48,36 @@ func NewStdioTransport() *StdioTransport {
 // In internal/transport/stdio.go -> Connect method
 
 func (t *StdioTransport) Connect(ctx context.Context, handler jsonrpc2.Handler) (*jsonrpc2.Conn, error) {
-	// Use NewPlainObjectStream for unframed JSON over stdio
-	stream := jsonrpc2.NewPlainObjectStream(stdioPipe{}) // <-- Use NewPlainObjectStream
+	// JSON over stdio, framed and encoded as configured
+	var conn *jsonrpc2.Conn
+	var pipe io.ReadWriteCloser = stdioPipe{}
+	pipe = newTimeoutStream(pipe, t.timeouts, func(string) {
+		// Closing ends the session; the blocked read or write is abandoned
+		if conn != nil {
+			_ = conn.Close()
+		}
+	})
+	if t.wrap != nil {
+		pipe = t.wrap(pipe)
+	}
+	stream := codec.NewFramedStream(pipe, t.framing, codec.WithFrameCapture(t.capture))
 
-	conn := jsonrpc2.NewConn(ctx, stream, handler)
+	if t.profile != "" {
+		ctx = context.WithValue(ctx, profileKey{}, t.profile)
+	}
+	conn = jsonrpc2.NewConn(ctx, stream, handler, captureOpts(t.capture, "stdio")...)
 	t.conn = conn
 
-	slog.Info("Connected stdio transport (using NewPlainObjectStream)") // <-- Log updated
+	slog.Info("Connected stdio transport", "codec", codec.Name(), "framing", t.framing)
 
 	return conn, nil
 }
 
+// wrapStreams decorates the stdio stream
+func (t *StdioTransport) wrapStreams(wrap func(io.ReadWriteCloser) io.ReadWriteCloser) {
+	t.wrap = wrap
+}
+
 // Close closes the transport
 func (t *StdioTransport) Close() error {
 	if t.conn != nil {
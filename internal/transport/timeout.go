@@ -0,0 +1,141 @@
+// internal/transport/timeout.go
+package transport
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Timeouts bound how long a transport waits on its peer, so slow-loris
+// clients and hung pipes cannot hold a connection forever. Zero disables a
+// timeout.
+type Timeouts struct {
+	ReadHeader time.Duration // HTTP request headers
+	Read       time.Duration // an HTTP request body; for stdio, the longest gap between inputs
+	Write      time.Duration // one write to the peer: an SSE event or a stdout message
+	Idle       time.Duration // a kept-alive HTTP connection between requests
+}
+
+// ErrWriteTimeout is returned by writes that did not complete in time
+var ErrWriteTimeout = errors.New("transport: write timed out")
+
+// WithTimeouts applies timeouts to the SSE transport's HTTP server and to
+// every event written to a stream
+func WithTimeouts(to Timeouts) SSEOption {
+	return func(t *SSETransport) {
+		t.timeouts = to
+	}
+}
+
+// WithStdioTimeouts closes the stdio connection when no input arrives
+// within to.Read or a write to stdout blocks longer than to.Write
+func WithStdioTimeouts(to Timeouts) StdioOption {
+	return func(t *StdioTransport) {
+		t.timeouts = to
+	}
+}
+
+// applyServerTimeouts sets the HTTP server's timeouts. Write is left to the
+// SSE handler, since a server-wide write timeout would cut off streams.
+func (to Timeouts) applyServerTimeouts(srv *http.Server) {
+	srv.ReadHeaderTimeout = to.ReadHeader
+	srv.ReadTimeout = to.Read
+	srv.IdleTimeout = to.Idle
+}
+
+// setWriteDeadline bounds the next write to w; writers that cannot take a
+// deadline are left unbounded
+func setWriteDeadline(w http.ResponseWriter, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(d)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		slog.Debug("Failed to set SSE write deadline", "error", err)
+	}
+}
+
+// timeoutStream enforces Timeouts on a stream without deadlines of its
+// own, such as stdin and stdout. When one expires, onTimeout is called to
+// tear the connection down; a blocked read or write cannot be interrupted,
+// so its goroutine is abandoned.
+type timeoutStream struct {
+	inner     io.ReadWriteCloser
+	read      time.Duration
+	write     time.Duration
+	idle      *time.Timer // nil when reads are not bounded
+	onTimeout func(reason string)
+	once      sync.Once
+}
+
+// newTimeoutStream wraps inner; it returns inner unchanged when both
+// timeouts are disabled
+func newTimeoutStream(inner io.ReadWriteCloser, to Timeouts, onTimeout func(reason string)) io.ReadWriteCloser {
+	if to.Read <= 0 && to.Write <= 0 {
+		return inner
+	}
+	s := &timeoutStream{inner: inner, read: to.Read, write: to.Write, onTimeout: onTimeout}
+	if to.Read > 0 {
+		s.idle = time.AfterFunc(to.Read, func() { s.expire("no input for " + to.Read.String()) })
+	}
+	return s
+}
+
+// Read reads from inner, restarting the idle timer whenever input arrives
+func (s *timeoutStream) Read(p []byte) (int, error) {
+	n, err := s.inner.Read(p)
+	if n > 0 && s.idle != nil {
+		s.idle.Reset(s.read)
+	}
+	return n, err
+}
+
+// Write writes to inner, giving up after the write timeout
+func (s *timeoutStream) Write(p []byte) (int, error) {
+	if s.write <= 0 {
+		return s.inner.Write(p)
+	}
+
+	type result struct {
+		n   int
+		err error
+	}
+	// The write may outlive this call, so it must not share the caller's buffer
+	buf := append([]byte(nil), p...)
+	done := make(chan result, 1)
+	go func() {
+		n, err := s.inner.Write(buf)
+		done <- result{n, err}
+	}()
+
+	timer := time.NewTimer(s.write)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.n, r.err
+	case <-timer.C:
+		s.expire("write blocked for " + s.write.String())
+		return 0, ErrWriteTimeout
+	}
+}
+
+// Close stops the idle timer and closes inner
+func (s *timeoutStream) Close() error {
+	if s.idle != nil {
+		s.idle.Stop()
+	}
+	return s.inner.Close()
+}
+
+// expire reports the first timeout
+func (s *timeoutStream) expire(reason string) {
+	s.once.Do(func() {
+		slog.Warn("Transport timed out, closing connection", "reason", reason)
+		if s.onTimeout != nil {
+			s.onTimeout(reason)
+		}
+	})
+}
@@ -0,0 +1,174 @@
+// pkg/logging/capture.go
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Record is a captured log record with its attributes flattened; attributes
+// inside groups are keyed "group.key"
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   map[string]interface{}
+}
+
+// String renders the record like a text log line, for test failure output
+func (r Record) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", r.Level, r.Message)
+	for k, v := range r.Attrs {
+		fmt.Fprintf(&b, " %s=%v", k, v)
+	}
+	return b.String()
+}
+
+// captureStore holds records shared by a CaptureHandler and its derivatives
+type captureStore struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+// CaptureHandler is a slog.Handler that keeps records in memory so tests
+// can assert on what was logged. It is safe for concurrent use.
+type CaptureHandler struct {
+	store  *captureStore
+	level  slog.Leveler
+	attrs  []slog.Attr
+	prefix string // group prefix for attributes added later
+}
+
+// Ensure CaptureHandler implements slog.Handler
+var _ slog.Handler = (*CaptureHandler)(nil)
+
+// NewCaptureHandler creates a handler capturing records at level and above
+func NewCaptureHandler(level slog.Leveler) *CaptureHandler {
+	return &CaptureHandler{store: &captureStore{}, level: level}
+}
+
+// Capture installs a CaptureHandler recording every level as the default
+// logger until the test ends
+func Capture(tb testing.TB) *CaptureHandler {
+	tb.Helper()
+	h := NewCaptureHandler(slog.LevelDebug)
+	previous := slog.Default()
+	slog.SetDefault(slog.New(h))
+	tb.Cleanup(func() { slog.SetDefault(previous) })
+	return h
+}
+
+// Enabled implements slog.Handler
+func (h *CaptureHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle implements slog.Handler
+func (h *CaptureHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := make(map[string]interface{})
+	for _, a := range h.attrs {
+		addAttr(attrs, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(attrs, h.prefix, a)
+		return true
+	})
+
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	h.store.records = append(h.store.records, Record{Time: r.Time, Level: r.Level, Message: r.Message, Attrs: attrs})
+	return nil
+}
+
+// WithAttrs implements slog.Handler
+func (h *CaptureHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, a := range attrs {
+		if h.prefix != "" {
+			a = slog.Group(strings.TrimSuffix(h.prefix, "."), a)
+		}
+		clone.attrs = append(clone.attrs, a)
+	}
+	return &clone
+}
+
+// WithGroup implements slog.Handler
+func (h *CaptureHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// addAttr flattens a into attrs under prefix
+func addAttr(attrs map[string]interface{}, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		p := prefix
+		if a.Key != "" {
+			p += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			addAttr(attrs, p, ga)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+	attrs[prefix+a.Key] = v.Any()
+}
+
+// Records returns a copy of everything captured so far
+func (h *CaptureHandler) Records() []Record {
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	return append([]Record(nil), h.store.records...)
+}
+
+// Filter returns the captured records for which keep returns true
+func (h *CaptureHandler) Filter(keep func(Record) bool) []Record {
+	var matched []Record
+	for _, r := range h.Records() {
+		if keep(r) {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+// ByLevel returns the captured records at exactly level
+func (h *CaptureHandler) ByLevel(level slog.Level) []Record {
+	return h.Filter(func(r Record) bool { return r.Level == level })
+}
+
+// Contains reports whether any captured message contains substr
+func (h *CaptureHandler) Contains(substr string) bool {
+	return len(h.Filter(func(r Record) bool { return strings.Contains(r.Message, substr) })) > 0
+}
+
+// ContainsAttr reports whether any captured record has attribute key equal
+// to value, compared by their printed form
+func (h *CaptureHandler) ContainsAttr(key string, value interface{}) bool {
+	want := fmt.Sprint(value)
+	return len(h.Filter(func(r Record) bool {
+		v, ok := r.Attrs[key]
+		return ok && fmt.Sprint(v) == want
+	})) > 0
+}
+
+// Reset discards everything captured so far
+func (h *CaptureHandler) Reset() {
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	h.store.records = nil
+}
@@ -2,6 +2,11 @@
 package mcperrors
 
 import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/cockroachdb/errors"
 )
 
@@ -14,6 +19,22 @@ const (
 	InternalError  = -32603 // Internal JSON-RPC error
 )
 
+// MCP and implementation-defined error codes. JSON-RPC reserves -32000 to
+// -32099 for server-defined errors.
+const (
+	ServerErrorMin = -32099 // Lowest implementation-defined code
+	ServerErrorMax = -32000 // Highest implementation-defined code
+
+	Unauthorized       = -32001 // Caller is not allowed to perform the request
+	ResourceNotFound   = -32002 // Requested resource does not exist (MCP)
+	ToolNotFound       = -32003 // Requested tool does not exist
+	ToolExecutionError = -32004 // Tool ran but failed
+	PromptNotFound     = -32005 // Requested prompt does not exist
+	RequestTimeout     = -32008 // Request exceeded its deadline
+	RequestCancelled   = -32009 // Request was cancelled before completion
+	RateLimited        = -32029 // Caller exceeded a rate limit
+)
+
 // ErrorCode represents a JSON-RPC error code and message
 type ErrorCode struct {
 	Code    int
@@ -27,8 +48,97 @@ var (
 	ErrMethodNotFound = ErrorCode{MethodNotFound, "Method not found"}
 	ErrInvalidParams  = ErrorCode{InvalidParams, "Invalid params"}
 	ErrInternal       = ErrorCode{InternalError, "Internal error"}
+
+	ErrUnauthorized     = ErrorCode{Unauthorized, "Unauthorized"}
+	ErrResourceNotFound = ErrorCode{ResourceNotFound, "Resource not found"}
+	ErrToolNotFound     = ErrorCode{ToolNotFound, "Tool not found"}
+	ErrToolExecution    = ErrorCode{ToolExecutionError, "Tool execution error"}
+	ErrPromptNotFound   = ErrorCode{PromptNotFound, "Prompt not found"}
+	ErrRequestTimeout   = ErrorCode{RequestTimeout, "Request timed out"}
+	ErrRequestCancelled = ErrorCode{RequestCancelled, "Request cancelled"}
+	ErrRateLimited      = ErrorCode{RateLimited, "Rate limited"}
+)
+
+// debugKey carries whether error chains and stack traces may reach the
+// client of a request
+type debugKey struct{}
+
+// WithDebug returns a context whose errors FromError converts with debug
+// details when enabled. Servers set it per request, so servers sharing a
+// process can differ; without it (the production default) FromError never
+// exposes error internals in Data.
+func WithDebug(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, debugKey{}, enabled)
+}
+
+// DebugEnabled reports whether errors of the request in ctx carry debug details
+func DebugEnabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(debugKey{}).(bool)
+	return enabled
+}
+
+// DebugDetails is the structured Data attached to errors in debug mode
+type DebugDetails struct {
+	Chain []string `json:"chain"`           // messages from outermost to innermost error
+	Stack string   `json:"stack,omitempty"` // formatted error with stack traces
+}
+
+// debugDetails builds DebugDetails for err
+func debugDetails(err error) *DebugDetails {
+	details := &DebugDetails{
+		Stack: fmt.Sprintf("%+v", err),
+	}
+	for e := err; e != nil; e = errors.UnwrapOnce(e) {
+		// Wrappers like RPCError repeat their cause's message; skip those
+		msg := e.Error()
+		if n := len(details.Chain); n > 0 && details.Chain[n-1] == msg {
+			continue
+		}
+		details.Chain = append(details.Chain, msg)
+	}
+	return details
+}
+
+// IsServerErrorCode reports whether code lies in the implementation-defined range
+func IsServerErrorCode(code int) bool {
+	return code >= ServerErrorMin && code <= ServerErrorMax
+}
+
+// mapping associates a sentinel error with the code FromError should use for it
+type mapping struct {
+	target error
+	code   ErrorCode
+}
+
+var (
+	mappings = []mapping{
+		{context.DeadlineExceeded, ErrRequestTimeout},
+		{context.Canceled, ErrRequestCancelled},
+	}
+	mappingsMu sync.RWMutex
 )
 
+// RegisterMapping makes FromError report errors matching target (via errors.Is)
+// with the given code. Packages call this for their sentinel errors.
+func RegisterMapping(target error, ec ErrorCode) {
+	mappingsMu.Lock()
+	defer mappingsMu.Unlock()
+	mappings = append(mappings, mapping{target: target, code: ec})
+}
+
+// lookupMapping returns the registered code for err, if any
+func lookupMapping(err error) (ErrorCode, bool) {
+	mappingsMu.RLock()
+	defer mappingsMu.RUnlock()
+
+	for _, m := range mappings {
+		if errors.Is(err, m.target) {
+			return m.code, true
+		}
+	}
+	return ErrorCode{}, false
+}
+
 // RPCError represents an error that will be converted to a JSON-RPC error response
 type RPCError struct {
 	error
@@ -70,8 +180,9 @@ func WithErrorCode(err error, ec ErrorCode, data interface{}) error {
 	}
 }
 
-// FromError creates an appropriate RPC error from a Go error
-func FromError(err error) *RPCError {
+// FromError creates an appropriate RPC error from a Go error returned while
+// serving the request in ctx
+func FromError(ctx context.Context, err error) *RPCError {
 	if err == nil {
 		return nil
 	}
@@ -79,16 +190,30 @@ func FromError(err error) *RPCError {
 	// Check if it's already an RPC error
 	var rpcErr *RPCError
 	if errors.As(err, &rpcErr) {
+		if rpcErr.Data == nil && DebugEnabled(ctx) {
+			withDetails := *rpcErr
+			withDetails.Data = debugDetails(err)
+			return &withDetails
+		}
 		return rpcErr
 	}
 
-	// Default to internal error
-	return &RPCError{
+	// Use a registered mapping for known sentinel errors, or default to internal error
+	ec, ok := lookupMapping(err)
+	if !ok {
+		ec = ErrInternal
+	}
+
+	result := &RPCError{
 		error:   err,
-		Code:    InternalError,
-		Message: "Internal error",
-		Data:    errors.GetAllDetails(err),
+		Code:    ec.Code,
+		Message: ec.Message,
+	}
+	// Error internals are only exposed to clients in debug mode
+	if DebugEnabled(ctx) {
+		result.Data = debugDetails(err)
 	}
+	return result
 }
 
 // Common helper functions for specific error types
@@ -103,12 +228,20 @@ func NewInvalidRequestError(err error) error {
 	return WithErrorCode(err, ErrInvalidRequest, nil)
 }
 
-// NewMethodNotFoundError creates a new method not found error
-func NewMethodNotFoundError(method string) error {
+// NewMethodNotFoundError creates a new method not found error, optionally
+// listing similarly named methods the caller may have meant
+func NewMethodNotFoundError(method string, suggestions ...string) error {
+	var data interface{}
+	if len(suggestions) > 0 {
+		data = map[string]interface{}{
+			"method":     method,
+			"didYouMean": suggestions,
+		}
+	}
 	return WithErrorCode(
 		errors.Newf("method not found: %s", method),
 		ErrMethodNotFound,
-		nil,
+		data,
 	)
 }
 
@@ -121,3 +254,73 @@ func NewInvalidParamsError(err error) error {
 func NewInternalError(err error) error {
 	return WithErrorCode(err, ErrInternal, nil)
 }
+
+// NewUnauthorizedError creates a new unauthorized error
+func NewUnauthorizedError(err error) error {
+	return WithErrorCode(err, ErrUnauthorized, nil)
+}
+
+// NewResourceNotFoundError creates a new resource not found error
+func NewResourceNotFoundError(uri string) error {
+	return WithErrorCode(
+		errors.Newf("resource not found: %s", uri),
+		ErrResourceNotFound,
+		map[string]interface{}{"uri": uri},
+	)
+}
+
+// NewToolNotFoundError creates a new tool not found error
+func NewToolNotFoundError(name string) error {
+	return WithErrorCode(
+		errors.Newf("tool not found: %s", name),
+		ErrToolNotFound,
+		map[string]interface{}{"name": name},
+	)
+}
+
+// NewToolExecutionError creates a new tool execution error
+func NewToolExecutionError(name string, err error) error {
+	return WithErrorCode(
+		errors.Wrapf(err, "tool %s failed", name),
+		ErrToolExecution,
+		map[string]interface{}{"name": name},
+	)
+}
+
+// NewPromptNotFoundError creates a new prompt not found error
+func NewPromptNotFoundError(name string) error {
+	return WithErrorCode(
+		errors.Newf("prompt not found: %s", name),
+		ErrPromptNotFound,
+		map[string]interface{}{"name": name},
+	)
+}
+
+// NewRateLimitedError creates a new rate limited error; retryAfter may be zero
+func NewRateLimitedError(retryAfter time.Duration) error {
+	var data interface{}
+	if retryAfter > 0 {
+		data = map[string]interface{}{"retryAfterMs": retryAfter.Milliseconds()}
+	}
+	return WithErrorCode(
+		fmt.Errorf("rate limit exceeded"),
+		ErrRateLimited,
+		data,
+	)
+}
+
+// ValidationDetail describes one argument that failed validation
+type ValidationDetail struct {
+	Field      string `json:"field"`                // dotted path to the offending argument
+	Message    string `json:"message"`              // human-readable description
+	SchemaPath string `json:"schemaPath,omitempty"` // JSON pointer to the violated schema rule
+}
+
+// NewValidationError creates an invalid params error whose Data lists each failed rule
+func NewValidationError(details []ValidationDetail) error {
+	return WithErrorCode(
+		errors.Newf("invalid arguments: %d validation error(s)", len(details)),
+		ErrInvalidParams,
+		details,
+	)
+}
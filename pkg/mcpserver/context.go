@@ -0,0 +1,73 @@
+// pkg/mcpserver/context.go
+package mcpserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+)
+
+// The context passed to tool handlers carries the calling client's
+// session. These helpers read it so handlers can make per-caller
+// decisions such as quotas, tenancy or personalization. Each returns the
+// zero value when ctx has no session, as in background work the server
+// starts on its own.
+
+// SessionID returns the ID of the calling client's connection
+func SessionID(ctx context.Context) string {
+	if sess := session.FromContext(ctx); sess != nil {
+		return sess.ID()
+	}
+	return ""
+}
+
+// ClientInfo returns the name and version the caller reported at initialize
+func ClientInfo(ctx context.Context) Implementation {
+	if sess := session.FromContext(ctx); sess != nil {
+		return sess.ClientInfo()
+	}
+	return Implementation{}
+}
+
+// ProtocolVersion returns the protocol version negotiated with the caller
+func ProtocolVersion(ctx context.Context) string {
+	if sess := session.FromContext(ctx); sess != nil {
+		return sess.ProtocolVersion()
+	}
+	return ""
+}
+
+// Identity returns the authenticated caller, as configured with
+// WithBearerToken, or "" for anonymous clients
+func Identity(ctx context.Context) string {
+	if sess := session.FromContext(ctx); sess != nil {
+		return sess.Identity()
+	}
+	return ""
+}
+
+// ClientAddr returns the caller's IP, resolved through trusted proxies, or
+// "" over stdio
+func ClientAddr(ctx context.Context) string {
+	if sess := session.FromContext(ctx); sess != nil {
+		return sess.RemoteAddr()
+	}
+	return ""
+}
+
+// RequestClient sends method to the calling client and decodes its reply
+// into result, for server-to-client requests without a helper of their
+// own. The client has timeout to answer, or 30s when it is 0; if ctx ends
+// first the request is cancelled on the client.
+func RequestClient(ctx context.Context, method string, params, result interface{}, timeout time.Duration) error {
+	sess := session.FromContext(ctx)
+	if sess == nil {
+		return session.ErrNoConnection
+	}
+	var opts []session.RequestOption
+	if timeout > 0 {
+		opts = append(opts, session.WithRequestTimeout(timeout))
+	}
+	return sess.Request(ctx, method, params, result, opts...)
+}
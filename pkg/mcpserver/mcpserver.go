@@ -0,0 +1,406 @@
+// pkg/mcpserver/mcpserver.go
+
+// Package mcpserver is the stable, exported API for embedding an Axe Handle
+// MCP server in another binary. Everything under internal/ is an
+// implementation detail; the aliases below are the supported names.
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/internal/events"
+	"github.com/dkoosis/axe-handle/internal/mcp/elicitation"
+	"github.com/dkoosis/axe-handle/internal/mcp/notify"
+	"github.com/dkoosis/axe-handle/internal/mcp/prompts"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+	"github.com/dkoosis/axe-handle/internal/mcp/sampling"
+	"github.com/dkoosis/axe-handle/internal/mcp/server"
+	"github.com/dkoosis/axe-handle/internal/mcp/server/jsonrpc"
+	"github.com/dkoosis/axe-handle/internal/mcp/session"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/interop"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools/manager"
+	"github.com/dkoosis/axe-handle/internal/transport"
+	"github.com/dkoosis/axe-handle/pkg/state"
+)
+
+// Provider interfaces and the types they exchange
+type (
+	ResourceProvider   = resources.Provider
+	Resource           = resources.Resource
+	TemplateProvider   = resources.TemplateProvider
+	ResourceTemplate   = resources.ResourceTemplate
+	CompletionProvider = resources.CompletionProvider
+	Annotations        = protocol.Annotations
+	Role               = protocol.Role
+	ToolProvider       = tools.Provider
+	ProviderTool       = tools.Tool
+	PromptProvider     = prompts.Provider
+	Prompt             = prompts.Prompt
+	PromptArgument     = prompts.PromptArgument
+
+	NotificationPublisher = notify.NotificationPublisher
+	PublisherAware        = notify.PublisherAware
+	ConnectionObserver    = server.ConnectionObserver
+)
+
+// Events published on the server's bus
+type (
+	EventBus  = events.Bus
+	Event     = events.Event
+	EventKind = events.Kind
+)
+
+// Types for registering tools directly with a handler
+type (
+	Tool            = protocol.Tool
+	ToolAnnotations = protocol.ToolAnnotations
+	ToolHandler     = manager.ToolHandler
+	Approver        = manager.Approver
+	ToolsCallResult = protocol.ToolsCallResult
+	Content         = protocol.Content
+	PromptMessage   = protocol.PromptMessage
+	PromptResult    = protocol.PromptsGetResult
+	ResourceContent = protocol.ResourceContents
+	ShutdownHook    = server.ShutdownHook
+	ConnectionHook  = server.ConnectionHook
+	SessionInfo     = server.SessionInfo
+	Health          = server.Health
+	ElicitResult    = protocol.ElicitResult
+	Implementation  = protocol.Implementation
+	Timeouts        = config.TimeoutsConfig
+
+	Sampler             = sampling.Sampler
+	SamplerFunc         = sampling.SamplerFunc
+	SamplingMessage     = protocol.SamplingMessage
+	CreateMessageParams = protocol.CreateMessageParams
+	CreateMessageResult = protocol.CreateMessageResult
+	ModelPreferences    = protocol.ModelPreferences
+)
+
+// Audience roles for Annotations and prompt messages
+const (
+	RoleUser      = protocol.RoleUser
+	RoleAssistant = protocol.RoleAssistant
+)
+
+// Elicitation answers
+const (
+	ElicitAccept  = protocol.ElicitAccept
+	ElicitDecline = protocol.ElicitDecline
+	ElicitCancel  = protocol.ElicitCancel
+)
+
+// Content types
+const (
+	ContentText     = protocol.ContentText
+	ContentImage    = protocol.ContentImage
+	ContentAudio    = protocol.ContentAudio
+	ContentResource = protocol.ContentResource
+)
+
+// Elicit asks the user of the client that made a tool call for input
+// matching a flat object schema; call it from the tool's handler with the
+// handler's context. ErrElicitationUnsupported is returned for clients
+// without the elicitation capability.
+var (
+	Elicit                    = elicitation.Elicit
+	ErrElicitationUnsupported = elicitation.ErrUnsupported
+)
+
+// Sampling asks the model of the client that made a tool call for a
+// completion, so providers need no API key of their own; call it from the
+// tool's handler with the handler's context. SampleText is the one-prompt
+// shorthand. WithSampler substitutes another model, for tests or servers
+// that have one. ErrSamplingUnsupported is returned for clients without the
+// sampling capability.
+var (
+	Sample                 = sampling.CreateMessage
+	SampleText             = sampling.Text
+	SamplerFromContext     = sampling.FromContext
+	WithSampler            = sampling.WithSampler
+	ErrSamplingUnsupported = sampling.ErrUnsupported
+)
+
+// Client requests not covered above go through RequestClient. The client
+// has to answer within the timeout, or ErrClientTimeout is returned; methods
+// it did not negotiate fail with ErrClientNotSupported.
+var (
+	ErrClientTimeout      = session.ErrClientTimeout
+	ErrClientNotSupported = session.ErrNotSupported
+)
+
+// Approvers for SetApprover. AskUser asks the calling client's user through
+// elicitation; approvers refuse calls with an error wrapping ErrNotApproved.
+var (
+	AskUser        = elicitation.Approve
+	DenyAll        = manager.DenyAll
+	ErrNotApproved = manager.ErrNotApproved
+)
+
+// Builders for prompt messages and their content
+var (
+	TextContent      = protocol.TextContent
+	ImageContent     = protocol.ImageContent
+	AudioContent     = protocol.AudioContent
+	ResourceRef      = protocol.ResourceRef
+	UserMessage      = protocol.UserMessage
+	AssistantMessage = protocol.AssistantMessage
+)
+
+// Server is an embeddable MCP server
+type Server struct {
+	cfg       *config.Config
+	server    *server.Server
+	transport transport.Transport
+}
+
+// Option configures a Server
+type Option func(*config.Config)
+
+// WithName sets the server name and version reported to clients
+func WithName(name, version string) Option {
+	return func(cfg *config.Config) {
+		cfg.Server.Name = name
+		cfg.Server.Version = version
+	}
+}
+
+// WithStdio serves over stdin/stdout (the default)
+func WithStdio() Option {
+	return func(cfg *config.Config) {
+		cfg.Transport.Type = "stdio"
+	}
+}
+
+// WithNDJSON expects stdio messages one per line, as some embedded hosts send them
+func WithNDJSON() Option {
+	return func(cfg *config.Config) {
+		cfg.Transport.Stdio.Framing = "ndjson"
+	}
+}
+
+// WithSSE serves over HTTP with Server-Sent Events on host:port
+func WithSSE(host string, port int) Option {
+	return func(cfg *config.Config) {
+		cfg.Transport.Type = "sse"
+		cfg.Transport.SSE.Host = host
+		cfg.Transport.SSE.Port = port
+	}
+}
+
+// WithBasePath serves the SSE endpoints under prefix, such as /api/mcp, so
+// the server can be mounted behind an existing router or gateway
+func WithBasePath(prefix string) Option {
+	return func(cfg *config.Config) {
+		cfg.Transport.SSE.BasePath = prefix
+	}
+}
+
+// WithSSEEndpoints replaces the event stream path (/sse) and the path
+// clients POST messages to (/messages)
+func WithSSEEndpoints(streamPath, messagePath string) Option {
+	return func(cfg *config.Config) {
+		cfg.Transport.SSE.Path = streamPath
+		cfg.Transport.SSE.MessagePath = messagePath
+	}
+}
+
+// WithTrustedProxies believes the X-Forwarded-* headers of requests from
+// these reverse proxies, given as IPs or CIDRs, for client addresses and
+// the endpoint URL announced to clients
+func WithTrustedProxies(proxies ...string) Option {
+	return func(cfg *config.Config) {
+		cfg.Transport.SSE.TrustedProxies = append(cfg.Transport.SSE.TrustedProxies, proxies...)
+	}
+}
+
+// WithBearerToken requires SSE clients to present a bearer token and
+// identifies those presenting token as identity. Call it once per caller;
+// tool handlers read the identity with Identity(ctx).
+func WithBearerToken(identity, token string) Option {
+	return func(cfg *config.Config) {
+		cfg.Transport.SSE.Tokens = append(cfg.Transport.SSE.Tokens, config.AccessTokenConfig{Identity: identity, Token: token})
+	}
+}
+
+// WithCompression gzip/deflate compresses SSE responses for clients that accept it
+func WithCompression(enabled bool) Option {
+	return func(cfg *config.Config) {
+		cfg.Transport.SSE.Compression = enabled
+	}
+}
+
+// WithTimeouts bounds how long the server waits on slow or stalled clients.
+// For stdio only Read (the longest gap between inputs) and Write apply.
+func WithTimeouts(to Timeouts) Option {
+	return func(cfg *config.Config) {
+		cfg.Transport.Stdio.Timeouts = to
+		cfg.Transport.SSE.Timeouts = to
+	}
+}
+
+// WithDebug includes error chains and stack traces in error responses
+func WithDebug(enabled bool) Option {
+	return func(cfg *config.Config) {
+		cfg.Server.Debug = enabled
+	}
+}
+
+// WithSchemaDraft2020 validates tool arguments as JSON Schema draft 2020-12,
+// as used by tools generated from OpenAPI 3.1
+func WithSchemaDraft2020() Option {
+	return func(cfg *config.Config) {
+		cfg.Tools.SchemaValidator = "2020-12"
+	}
+}
+
+// WithToolCache caches the results of the named tool for ttl, so repeated
+// calls with identical arguments skip the tool. Use it for read-only tools
+// whose answer does not depend on the client asking.
+func WithToolCache(tool string, ttl time.Duration) Option {
+	return func(cfg *config.Config) {
+		cfg.Tools.Cache.Tools = append(cfg.Tools.Cache.Tools, config.CachedToolConfig{Name: tool, TTL: ttl})
+	}
+}
+
+// WithApproval sets how calls to tools annotated as not read-only are
+// approved: "run" lets them through, "ask" asks the client's user and
+// "deny" refuses them. Server.SetApprover installs a custom hook instead.
+func WithApproval(mode string) Option {
+	return func(cfg *config.Config) {
+		cfg.Tools.Approval = mode
+	}
+}
+
+// WithStorage keeps provider state in the bbolt file at path
+func WithStorage(path string) Option {
+	return func(cfg *config.Config) {
+		cfg.Storage.Path = path
+	}
+}
+
+// NewServer creates a server with the given options applied to the defaults
+func NewServer(opts ...Option) *Server {
+	cfg := config.Default()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &Server{
+		cfg:    cfg,
+		server: server.NewServer(cfg),
+	}
+}
+
+// RegisterResourceProvider registers a resource provider
+func (s *Server) RegisterResourceProvider(p ResourceProvider) {
+	s.server.RegisterResourceProvider(p)
+}
+
+// RegisterToolProvider registers a tool provider; the tools it lists are
+// served with tools/list and tools/call
+func (s *Server) RegisterToolProvider(p ToolProvider) {
+	s.server.RegisterToolProvider(p)
+}
+
+// RegisterPromptProvider registers a prompt provider
+func (s *Server) RegisterPromptProvider(p PromptProvider) {
+	s.server.RegisterPromptProvider(p)
+}
+
+// RegisterTool registers a single tool and the handler that executes it
+func (s *Server) RegisterTool(tool Tool, handler ToolHandler) {
+	s.server.GetToolsManager().RegisterTool(tool, handler)
+}
+
+// RegisterAsyncTool registers a tool whose calls run in the background. The
+// call returns a job ID at once; clients poll jobs.status and fetch the
+// outcome with jobs.result.
+func (s *Server) RegisterAsyncTool(tool Tool, handler ToolHandler) {
+	s.server.RegisterAsyncTool(tool, handler)
+}
+
+// ExportTools returns the registered tools in the "tools" format of another
+// LLM API ("openai" or "anthropic"), sorted by name and ready to marshal
+func (s *Server) ExportTools(format string) (interface{}, error) {
+	f, err := interop.ParseFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	list := s.server.GetToolsManager().ListTools()
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return interop.Export(list, f)
+}
+
+// OnShutdown registers cleanup to run when Run returns
+func (s *Server) OnShutdown(hook ShutdownHook) {
+	s.server.OnShutdown(hook)
+}
+
+// OnConnect registers a hook to run when a client has initialized
+func (s *Server) OnConnect(hook ConnectionHook) {
+	s.server.OnConnect(hook)
+}
+
+// OnDisconnect registers a hook to run when an initialized client goes away
+func (s *Server) OnDisconnect(hook ConnectionHook) {
+	s.server.OnDisconnect(hook)
+}
+
+// SetApprover sets the hook asked before tools annotated as not read-only
+// run, replacing the WithApproval mode; nil runs them unasked
+func (s *Server) SetApprover(a Approver) {
+	s.server.GetToolsManager().SetApprover(a)
+}
+
+// Events returns the bus the server announces tool, session and resource
+// changes on
+func (s *Server) Events() *EventBus {
+	return s.server.Events()
+}
+
+// State returns the persistent store configured with WithStorage, or nil
+func (s *Server) State() *state.Store {
+	return s.server.State()
+}
+
+// Health returns a snapshot of the server's health
+func (s *Server) Health() Health {
+	return s.server.Health()
+}
+
+// Run connects the configured transport and serves until ctx is cancelled,
+// then shuts the server down.
+func (s *Server) Run(ctx context.Context) error {
+	t, err := server.NewTransport(s.cfg, "")
+	if err != nil {
+		return err
+	}
+	s.transport = t
+
+	handler := jsonrpc.NewHandler(s.server, jsonrpc.WithWorkers(s.cfg.Server.Workers, s.cfg.Server.QueueSize))
+	s.server.OnShutdown(func(context.Context) error {
+		return handler.Close()
+	})
+
+	if _, err := s.transport.Connect(ctx, handler); err != nil {
+		return fmt.Errorf("failed to connect transport: %w", err)
+	}
+
+	s.server.OnShutdown(func(ctx context.Context) error {
+		return t.Close()
+	})
+
+	slog.Info("Embedded MCP server started",
+		"name", s.cfg.Server.Name,
+		"transport", s.cfg.Transport.Type)
+
+	<-ctx.Done()
+	return s.server.Shutdown(context.Background())
+}
@@ -0,0 +1,207 @@
+// pkg/mcptest/client.go
+package mcptest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/server"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// Notification is a notification the server sent to the client
+type Notification struct {
+	Method string
+	Params json.RawMessage
+}
+
+// Decode unmarshals the notification's params into v
+func (n Notification) Decode(v interface{}) error {
+	if len(n.Params) == 0 {
+		return nil
+	}
+	return json.Unmarshal(n.Params, v)
+}
+
+// RequestHandler answers a request the server sends to the client
+type RequestHandler func(params json.RawMessage) (interface{}, error)
+
+// Client is a test client connected to an in-memory server. Failing
+// helpers (Must*, Expect*, Wait*) stop the test with t.Fatalf.
+type Client struct {
+	tb      testing.TB
+	server  *server.Server
+	conn    *jsonrpc2.Conn
+	timeout time.Duration
+
+	mu            sync.Mutex
+	notifications []Notification
+	arrived       chan struct{} // signalled when a notification is queued
+	handlers      map[string]RequestHandler
+}
+
+// newClient creates a client that is not yet connected
+func newClient(tb testing.TB, srv *server.Server, timeout time.Duration) *Client {
+	return &Client{
+		tb:       tb,
+		server:   srv,
+		timeout:  timeout,
+		arrived:  make(chan struct{}, 1),
+		handlers: make(map[string]RequestHandler),
+	}
+}
+
+// Server returns the server under test, e.g. to trigger notifications
+func (c *Client) Server() *server.Server {
+	return c.server
+}
+
+// Call sends a request and decodes its result into result, which may be nil
+func (c *Client) Call(method string, params, result interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	return c.conn.Call(ctx, method, params, result)
+}
+
+// MustCall is Call that fails the test on error
+func (c *Client) MustCall(method string, params, result interface{}) {
+	c.tb.Helper()
+	if err := c.Call(method, params, result); err != nil {
+		c.tb.Fatalf("%s failed: %v", method, err)
+	}
+}
+
+// ExpectError sends a request that must fail with the given JSON-RPC code
+// and returns the error for further checks
+func (c *Client) ExpectError(method string, params interface{}, code int64) *jsonrpc2.Error {
+	c.tb.Helper()
+	err := c.Call(method, params, nil)
+	var rpcErr *jsonrpc2.Error
+	if !errors.As(err, &rpcErr) {
+		c.tb.Fatalf("%s: expected error code %d, got %v", method, code, err)
+	}
+	if rpcErr.Code != code {
+		c.tb.Fatalf("%s: expected error code %d, got %d (%s)", method, code, rpcErr.Code, rpcErr.Message)
+	}
+	return rpcErr
+}
+
+// Notify sends a notification to the server
+func (c *Client) Notify(method string, params interface{}) {
+	c.tb.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	if err := c.conn.Notify(ctx, method, params); err != nil {
+		c.tb.Fatalf("%s notification failed: %v", method, err)
+	}
+}
+
+// Initialize performs the initialize handshake with default client
+// capabilities and returns the server's result
+func (c *Client) Initialize() protocol.InitializeResult {
+	c.tb.Helper()
+	return c.InitializeWith(protocol.InitializeParams{
+		ProtocolVersion: protocol.LatestProtocolVersion,
+		ClientInfo:      protocol.Implementation{Name: "mcptest", Version: "0.0.0"},
+	})
+}
+
+// InitializeWith performs the initialize handshake with params
+func (c *Client) InitializeWith(params protocol.InitializeParams) protocol.InitializeResult {
+	c.tb.Helper()
+	var result protocol.InitializeResult
+	c.MustCall(protocol.MethodInitialize, params, &result)
+	c.Notify(protocol.NotificationInitialized, nil)
+	return result
+}
+
+// OnRequest answers server-to-client requests for method, such as roots/list
+func (c *Client) OnRequest(method string, handler RequestHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[method] = handler
+}
+
+// WaitNotification returns the oldest unconsumed notification for method,
+// waiting for one to arrive
+func (c *Client) WaitNotification(method string) Notification {
+	c.tb.Helper()
+	deadline := time.NewTimer(c.timeout)
+	defer deadline.Stop()
+
+	for {
+		if n, ok := c.take(method); ok {
+			return n
+		}
+		select {
+		case <-c.arrived:
+		case <-deadline.C:
+			c.tb.Fatalf("no %s notification within %s", method, c.timeout)
+			return Notification{}
+		}
+	}
+}
+
+// ExpectNoNotification fails if a notification for method arrives within d
+func (c *Client) ExpectNoNotification(method string, d time.Duration) {
+	c.tb.Helper()
+	time.Sleep(d)
+	if n, ok := c.take(method); ok {
+		c.tb.Fatalf("unexpected %s notification: %s", method, n.Params)
+	}
+}
+
+// Notifications returns and clears every notification received so far
+func (c *Client) Notifications() []Notification {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ns := c.notifications
+	c.notifications = nil
+	return ns
+}
+
+// take removes the oldest notification for method
+func (c *Client) take(method string) (Notification, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, n := range c.notifications {
+		if n.Method == method {
+			c.notifications = append(c.notifications[:i], c.notifications[i+1:]...)
+			return n, true
+		}
+	}
+	return Notification{}, false
+}
+
+// handle records notifications and answers server requests
+func (c *Client) handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (interface{}, error) {
+	var params json.RawMessage
+	if req.Params != nil {
+		params = *req.Params
+	}
+
+	if req.Notif {
+		c.mu.Lock()
+		c.notifications = append(c.notifications, Notification{Method: req.Method, Params: params})
+		c.mu.Unlock()
+		select {
+		case c.arrived <- struct{}{}:
+		default:
+		}
+		return nil, nil
+	}
+
+	c.mu.Lock()
+	handler, ok := c.handlers[req.Method]
+	c.mu.Unlock()
+	if !ok {
+		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeMethodNotFound, Message: fmt.Sprintf("mcptest client does not handle %s", req.Method)}
+	}
+	return handler(params)
+}
@@ -0,0 +1,175 @@
+// pkg/mcptest/clock.go
+package mcptest
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/clock"
+)
+
+// Ensure FakeClock implements clock.Clock
+var _ clock.Clock = (*FakeClock)(nil)
+
+// FakeClock is a clock that only moves when Advance is called. Timers,
+// tickers and timeouts due within an Advance fire in order before it returns.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*waiter
+}
+
+// waiter is a pending timer, ticker or timeout
+type waiter struct {
+	at     time.Time
+	period time.Duration // non-zero for tickers
+	fire   func(now time.Time)
+	done   bool
+}
+
+// NewFakeClock creates a fake clock reading start
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now returns the fake time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Since returns the fake time elapsed since t
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// Advance moves the clock forward by d, firing everything that comes due
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+	for {
+		w := c.next(target)
+		if w == nil {
+			break
+		}
+		c.now = w.at
+		if w.period > 0 {
+			w.at = w.at.Add(w.period)
+		} else {
+			w.done = true
+		}
+		now := c.now
+		c.mu.Unlock()
+		w.fire(now)
+		c.mu.Lock()
+	}
+	c.now = target
+	c.mu.Unlock()
+}
+
+// next returns the earliest waiter due by target, dropping finished ones
+func (c *FakeClock) next(target time.Time) *waiter {
+	var earliest *waiter
+	live := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.done {
+			continue
+		}
+		live = append(live, w)
+		if !w.at.After(target) && (earliest == nil || w.at.Before(earliest.at)) {
+			earliest = w
+		}
+	}
+	c.waiters = live
+	return earliest
+}
+
+// schedule registers a waiter due after d
+func (c *FakeClock) schedule(d, period time.Duration, fire func(time.Time)) *waiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w := &waiter{at: c.now.Add(d), period: period, fire: fire}
+	c.waiters = append(c.waiters, w)
+	return w
+}
+
+// stop cancels w, reporting whether it was still pending
+func (c *FakeClock) stop(w *waiter) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pending := !w.done
+	w.done = true
+	return pending
+}
+
+// NewTicker delivers the fake time on C every d; ticks are dropped if C is full
+func (c *FakeClock) NewTicker(d time.Duration) clock.Ticker {
+	ch := make(chan time.Time, 1)
+	w := c.schedule(d, d, func(now time.Time) {
+		select {
+		case ch <- now:
+		default:
+		}
+	})
+	return &fakeTicker{clock: c, w: w, ch: ch}
+}
+
+// AfterFunc calls f during the Advance that reaches d from now
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	w := c.schedule(d, 0, func(time.Time) { f() })
+	return &fakeTimer{clock: c, w: w}
+}
+
+// WithTimeout returns a context that expires with context.DeadlineExceeded
+// once the fake clock passes d from now
+func (c *FakeClock) WithTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	inner, cancel := context.WithCancel(parent)
+	ctx := &timeoutContext{Context: inner, deadline: c.Now().Add(d)}
+	w := c.schedule(d, 0, func(time.Time) {
+		ctx.expired.Store(true)
+		cancel()
+	})
+	return ctx, func() {
+		c.stop(w)
+		cancel()
+	}
+}
+
+// fakeTicker is a FakeClock ticker
+type fakeTicker struct {
+	clock *FakeClock
+	w     *waiter
+	ch    chan time.Time
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.ch }
+
+func (t *fakeTicker) Stop() { t.clock.stop(t.w) }
+
+// fakeTimer is a FakeClock AfterFunc
+type fakeTimer struct {
+	clock *FakeClock
+	w     *waiter
+}
+
+func (t *fakeTimer) Stop() bool { return t.clock.stop(t.w) }
+
+// timeoutContext reports a fake deadline and DeadlineExceeded once expired
+type timeoutContext struct {
+	context.Context
+	deadline time.Time
+	expired  atomic.Bool
+}
+
+func (c *timeoutContext) Deadline() (time.Time, bool) { return c.deadline, true }
+
+func (c *timeoutContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && c.expired.Load() {
+		return context.DeadlineExceeded
+	}
+	return err
+}
@@ -0,0 +1,128 @@
+// pkg/mcptest/conn.go
+package mcptest
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// Ensure RecordingConn can stand in for a connection
+var _ protocol.ResponderConn = (*RecordingConn)(nil)
+
+// Reply is a response a handler sent through a RecordingConn
+type Reply struct {
+	ID     jsonrpc2.ID
+	Result json.RawMessage // nil for error replies
+	Error  *jsonrpc2.Error
+}
+
+// Decode unmarshals the reply's result into v
+func (r Reply) Decode(v interface{}) error {
+	return json.Unmarshal(r.Result, v)
+}
+
+// RecordingConn records the replies and notifications handlers send, for
+// calling handlers directly without a transport. It is safe for concurrent use.
+type RecordingConn struct {
+	mu            sync.Mutex
+	replies       []Reply
+	notifications []Notification
+	err           error
+	changed       chan struct{} // closed and replaced whenever something is recorded
+}
+
+// NewRecordingConn creates an empty RecordingConn
+func NewRecordingConn() *RecordingConn {
+	return &RecordingConn{changed: make(chan struct{})}
+}
+
+// Reply records a successful response, encoded as it would be on the wire
+func (c *RecordingConn) Reply(ctx context.Context, id jsonrpc2.ID, result interface{}) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return c.record(func() { c.replies = append(c.replies, Reply{ID: id, Result: data}) })
+}
+
+// ReplyWithError records an error response
+func (c *RecordingConn) ReplyWithError(ctx context.Context, id jsonrpc2.ID, respErr *jsonrpc2.Error) error {
+	return c.record(func() { c.replies = append(c.replies, Reply{ID: id, Error: respErr}) })
+}
+
+// Notify records a notification
+func (c *RecordingConn) Notify(ctx context.Context, method string, params interface{}, opts ...jsonrpc2.CallOption) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return c.record(func() { c.notifications = append(c.notifications, Notification{Method: method, Params: data}) })
+}
+
+// FailWith makes every later send fail with err, as a broken connection
+// would; nil restores normal behavior
+func (c *RecordingConn) FailWith(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+// Replies returns a copy of the replies recorded so far
+func (c *RecordingConn) Replies() []Reply {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Reply(nil), c.replies...)
+}
+
+// Notifications returns a copy of the notifications recorded so far
+func (c *RecordingConn) Notifications() []Notification {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Notification(nil), c.notifications...)
+}
+
+// WaitReply returns the reply to id, waiting up to DefaultTimeout for
+// handlers that answer asynchronously
+func (c *RecordingConn) WaitReply(tb testing.TB, id jsonrpc2.ID) Reply {
+	tb.Helper()
+	deadline := time.NewTimer(DefaultTimeout)
+	defer deadline.Stop()
+
+	for {
+		c.mu.Lock()
+		for _, r := range c.replies {
+			if r.ID == id {
+				c.mu.Unlock()
+				return r
+			}
+		}
+		changed := c.changed
+		c.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-deadline.C:
+			tb.Fatalf("no reply to %s within %s", id, DefaultTimeout)
+			return Reply{}
+		}
+	}
+}
+
+// record applies add unless sends are failing, then wakes waiters
+func (c *RecordingConn) record(add func()) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	add()
+	close(c.changed)
+	c.changed = make(chan struct{})
+	return nil
+}
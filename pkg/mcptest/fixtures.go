@@ -0,0 +1,263 @@
+// pkg/mcptest/fixtures.go
+package mcptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+)
+
+// ignored replaces values at a Fixture's Ignore paths before comparison
+const ignored = `"<ignored>"`
+
+// Fixture is a recorded exchange with the server, stored as JSON. Responses
+// are compared with the server's encoded output after compacting both, so
+// key order and number formatting must match exactly.
+type Fixture struct {
+	Name string `json:"-"` // file name without extension
+	Path string `json:"-"`
+
+	Initialize bool     `json:"initialize,omitempty"` // perform the handshake first, unrecorded
+	Ignore     []string `json:"ignore,omitempty"`     // dotted paths not compared, e.g. "result._meta"; "*" matches any key or index
+	Steps      []Step   `json:"steps"`
+}
+
+// Step is one message sent to the server and the response expected back.
+// Notifications have no response.
+type Step struct {
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// LoadFixtures reads every *.json fixture in dir, sorted by name
+func LoadFixtures(dir string) ([]Fixture, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	fixtures := make([]Fixture, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture: %w", err)
+		}
+		var f Fixture
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+		}
+		f.Path = path
+		f.Name = strings.TrimSuffix(filepath.Base(path), ".json")
+		fixtures = append(fixtures, f)
+	}
+	return fixtures, nil
+}
+
+// Run replays the fixture against a fresh server and reports the first
+// response that differs from the recording
+func (f Fixture) Run(opts ...Option) error {
+	return f.replay(opts, func(i int, got []byte) error {
+		return compare(f.Steps[i].Response, got, f.Ignore)
+	})
+}
+
+// Record replays the fixture and replaces its responses with the server's
+func (f *Fixture) Record(opts ...Option) error {
+	return f.replay(opts, func(i int, got []byte) error {
+		f.Steps[i].Response = got
+		return nil
+	})
+}
+
+// Save writes the fixture back to its file
+func (f Fixture) Save() error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(f.Path, append(data, '\n'), 0644)
+}
+
+// replay sends each step to a fresh server and hands check the responses
+func (f Fixture) replay(opts []Option, check func(i int, got []byte) error) error {
+	w := Dial(opts...)
+	defer w.Close()
+
+	if f.Initialize {
+		if err := handshake(w); err != nil {
+			return fmt.Errorf("handshake failed: %w", err)
+		}
+	}
+	for i, step := range f.Steps {
+		got, err := w.RoundTrip(step.Request)
+		if err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+		if err := check(i, got); err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// handshake initializes the session on w
+func handshake(w *Wire) error {
+	init, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      "handshake",
+		"method":  protocol.MethodInitialize,
+		"params": protocol.InitializeParams{
+			ProtocolVersion: protocol.LatestProtocolVersion,
+			ClientInfo:      protocol.Implementation{Name: "mcptest", Version: "0.0.0"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := w.RoundTrip(init); err != nil {
+		return err
+	}
+	return w.Send([]byte(`{"jsonrpc":"2.0","method":"` + protocol.NotificationInitialized + `"}`))
+}
+
+// compare reports how got differs from want after normalization
+func compare(want, got []byte, ignore []string) error {
+	if len(want) == 0 && len(got) == 0 {
+		return nil
+	}
+	if len(want) == 0 || len(got) == 0 {
+		return fmt.Errorf("response differs\n  want: %s\n   got: %s", want, got)
+	}
+	w, err := Normalize(want, ignore...)
+	if err != nil {
+		return fmt.Errorf("invalid recorded response: %w", err)
+	}
+	g, err := Normalize(got, ignore...)
+	if err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	if !bytes.Equal(w, g) {
+		return fmt.Errorf("response differs\n  want: %s\n   got: %s", w, g)
+	}
+	return nil
+}
+
+// Normalize compacts msg, keeping key order and number literals, and
+// replaces the values at the ignore paths with a placeholder
+func Normalize(msg []byte, ignore ...string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(msg))
+	dec.UseNumber()
+
+	var buf bytes.Buffer
+	if err := normalizeValue(dec, &buf, "", ignore); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// normalizeValue copies the next value from dec to buf
+func normalizeValue(dec *json.Decoder, buf *bytes.Buffer, path string, ignore []string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if path != "" && matchesAny(path, ignore) {
+		buf.WriteString(ignored)
+		return skipValue(dec, tok)
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		enc, err := json.Marshal(tok)
+		if err != nil {
+			return err
+		}
+		buf.Write(enc)
+		return nil
+	}
+
+	buf.WriteRune(rune(delim))
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key := strconv.Itoa(i)
+		if delim == '{' {
+			k, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key = k.(string)
+			enc, _ := json.Marshal(key)
+			buf.Write(enc)
+			buf.WriteByte(':')
+		}
+		if err := normalizeValue(dec, buf, joinPath(path, key), ignore); err != nil {
+			return err
+		}
+	}
+	end, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	buf.WriteRune(rune(end.(json.Delim)))
+	return nil
+}
+
+// skipValue consumes the rest of a value whose first token was tok
+func skipValue(dec *json.Decoder, tok json.Token) error {
+	for depth := 0; ; {
+		if d, ok := tok.(json.Delim); ok {
+			if d == '{' || d == '[' {
+				depth++
+			} else {
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+		var err error
+		if tok, err = dec.Token(); err != nil {
+			return err
+		}
+	}
+}
+
+// joinPath appends key to a dotted path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// matchesAny reports whether path matches one of the patterns
+func matchesAny(path string, patterns []string) bool {
+	segments := strings.Split(path, ".")
+	for _, pattern := range patterns {
+		parts := strings.Split(pattern, ".")
+		if len(parts) != len(segments) {
+			continue
+		}
+		match := true
+		for i, part := range parts {
+			if part != "*" && part != segments[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+// pkg/mcptest/mcptest.go
+
+// Package mcptest runs a complete Axe Handle server in memory for tests.
+// New wires the server and JSON-RPC handler to a Client over an in-memory
+// pipe, so requests go through the same routing, validation and encoding
+// as a real stdio connection.
+//
+//	c := mcptest.New(t, mcptest.WithToolProvider(example.NewProvider()))
+//	c.Initialize()
+//	var result struct{ Tools []mcpserver.Tool }
+//	c.MustCall(protocol.MethodToolsList, nil, &result)
+package mcptest
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/codec"
+	"github.com/dkoosis/axe-handle/internal/config"
+	"github.com/dkoosis/axe-handle/internal/mcp/server"
+	"github.com/dkoosis/axe-handle/internal/mcp/server/jsonrpc"
+	"github.com/dkoosis/axe-handle/internal/transport"
+	"github.com/dkoosis/axe-handle/pkg/mcpserver"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// DefaultTimeout bounds every call and notification wait
+const DefaultTimeout = 5 * time.Second
+
+// options collects what New was asked for
+type options struct {
+	cfg      *config.Config
+	server   []server.Option
+	register []func(*server.Server)
+	timeout  time.Duration
+	chaos    *transport.ChaosConfig
+}
+
+// Option configures the server under test
+type Option func(*options)
+
+// WithServerOptions applies embedding options such as mcpserver.WithName
+func WithServerOptions(opts ...mcpserver.Option) Option {
+	return func(o *options) {
+		for _, opt := range opts {
+			opt(o.cfg)
+		}
+	}
+}
+
+// WithResourceProvider registers a resource provider
+func WithResourceProvider(p mcpserver.ResourceProvider) Option {
+	return func(o *options) {
+		o.register = append(o.register, func(s *server.Server) { s.RegisterResourceProvider(p) })
+	}
+}
+
+// WithToolProvider registers a tool provider
+func WithToolProvider(p mcpserver.ToolProvider) Option {
+	return func(o *options) {
+		o.register = append(o.register, func(s *server.Server) { s.RegisterToolProvider(p) })
+	}
+}
+
+// WithPromptProvider registers a prompt provider
+func WithPromptProvider(p mcpserver.PromptProvider) Option {
+	return func(o *options) {
+		o.register = append(o.register, func(s *server.Server) { s.RegisterPromptProvider(p) })
+	}
+}
+
+// WithTool registers a single tool and its handler
+func WithTool(tool mcpserver.Tool, handler mcpserver.ToolHandler) Option {
+	return func(o *options) {
+		o.register = append(o.register, func(s *server.Server) { s.GetToolsManager().RegisterTool(tool, handler) })
+	}
+}
+
+// WithClock drives the server's timeouts, heartbeats and health reporting
+// from c, typically a FakeClock
+func WithClock(c *FakeClock) Option {
+	return func(o *options) {
+		o.server = append(o.server, server.WithClock(c))
+	}
+}
+
+// WithChaos injects latency, dropped and partial writes, and disconnects
+// into the server's side of the connection
+func WithChaos(cfg transport.ChaosConfig) Option {
+	return func(o *options) {
+		o.chaos = &cfg
+	}
+}
+
+// WithTimeout changes how long calls and notification waits may take
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// New starts a server and returns a client connected to it. Both are shut
+// down when the test finishes.
+func New(tb testing.TB, opts ...Option) *Client {
+	tb.Helper()
+
+	o := newOptions(opts)
+	p := start(o)
+
+	c := newClient(tb, p.server, o.timeout)
+	c.conn = jsonrpc2.NewConn(p.ctx, codec.NewObjectStream(p.clientEnd), jsonrpc2.HandlerWithError(c.handle))
+
+	tb.Cleanup(func() {
+		c.conn.Close()
+		p.stop()
+	})
+	return c
+}
+
+// newOptions applies opts to the defaults
+func newOptions(opts []Option) *options {
+	o := &options{cfg: config.Default(), timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// pair is a running server and the client end of its connection
+type pair struct {
+	ctx       context.Context
+	server    *server.Server
+	clientEnd net.Conn
+	stop      func()
+}
+
+// build creates the server and its JSON-RPC handler
+func build(o *options) (*server.Server, *jsonrpc.Handler) {
+	srv := server.NewServer(o.cfg, o.server...)
+	for _, register := range o.register {
+		register(srv)
+	}
+	return srv, jsonrpc.NewHandler(srv, jsonrpc.WithWorkers(o.cfg.Server.Workers, o.cfg.Server.QueueSize))
+}
+
+// start runs a server with its handler on one end of an in-memory pipe
+func start(o *options) pair {
+	srv, handler := build(o)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	serverEnd, clientEnd := net.Pipe()
+	var serverStream io.ReadWriteCloser = serverEnd
+	if o.chaos != nil {
+		serverStream = transport.NewChaosStream(serverEnd, *o.chaos)
+	}
+	serverConn := jsonrpc2.NewConn(ctx, codec.NewObjectStream(serverStream), handler)
+
+	return pair{
+		ctx:       ctx,
+		server:    srv,
+		clientEnd: clientEnd,
+		stop: func() {
+			clientEnd.Close()
+			serverConn.Close()
+			cancel()
+			_ = handler.Close()
+			_ = srv.Shutdown(context.Background())
+		},
+	}
+}
@@ -0,0 +1,56 @@
+// pkg/mcptest/mcptest_test.go
+package mcptest_test
+
+import (
+	"testing"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools"
+	"github.com/dkoosis/axe-handle/pkg/mcptest"
+)
+
+// upperProvider serves one tool that shouts its text argument
+type upperProvider struct{}
+
+func (upperProvider) ListTools() ([]tools.Tool, error) {
+	return []tools.Tool{{
+		Name:        "shout",
+		Description: "Repeats text with an exclamation mark",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"text": map[string]interface{}{"type": "string"}},
+		},
+	}}, nil
+}
+
+func (upperProvider) ExecuteTool(name string, args map[string]interface{}) (interface{}, error) {
+	if name != "shout" {
+		return nil, tools.ErrToolNotFound
+	}
+	text, _ := args["text"].(string)
+	return text + "!", nil
+}
+
+func TestWithToolProvider(t *testing.T) {
+	c := mcptest.New(t, mcptest.WithToolProvider(upperProvider{}))
+	c.Initialize()
+
+	var list struct{ Tools []protocol.Tool }
+	c.MustCall(protocol.MethodToolsList, nil, &list)
+	var listed bool
+	for _, tool := range list.Tools {
+		listed = listed || tool.Name == "shout"
+	}
+	if !listed {
+		t.Fatalf("tools/list = %+v, want the provider's shout tool", list.Tools)
+	}
+
+	var result protocol.ToolsCallResult
+	c.MustCall(protocol.MethodToolsCall, map[string]interface{}{
+		"name":      "shout",
+		"arguments": map[string]interface{}{"text": "hey"},
+	}, &result)
+	if result.IsError || len(result.Content) != 1 || result.Content[0].Text != "hey!" {
+		t.Errorf("tools/call = %+v, want text %q", result, "hey!")
+	}
+}
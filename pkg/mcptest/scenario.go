@@ -0,0 +1,258 @@
+// pkg/mcptest/scenario.go
+package mcptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario is a declarative client session, written in YAML:
+//
+//	name: unknown tools are reported as tool errors
+//	steps:
+//	  - send: initialize
+//	    params: {protocolVersion: "2024-11-05", capabilities: {}, clientInfo: {name: test, version: "1"}}
+//	    expect:
+//	      result: {capabilities: {tools: {listChanged: true}}}
+//	  - notify: notifications/initialized
+//	  - send: tools/call
+//	    params: {name: missing}
+//	    expect:
+//	      result: {isError: true}
+//
+// Expected values match when every key they name is present with a matching
+// value; other keys are ignored. Arrays must have the same length.
+type Scenario struct {
+	Name  string         `yaml:"name"`
+	Path  string         `yaml:"-"`
+	Steps []ScenarioStep `yaml:"steps"`
+}
+
+// ScenarioStep does exactly one of: send a request, send a notification,
+// or wait for a notification from the server
+type ScenarioStep struct {
+	Send   string       `yaml:"send,omitempty"`   // request method
+	Notify string       `yaml:"notify,omitempty"` // notification method
+	Await  string       `yaml:"await,omitempty"`  // server notification method
+	Params interface{}  `yaml:"params,omitempty"`
+	Expect *Expectation `yaml:"expect,omitempty"`
+}
+
+// Expectation describes the response to a request or an awaited
+// notification's params. A request without one must succeed.
+type Expectation struct {
+	Result interface{} `yaml:"result,omitempty"`
+	Error  interface{} `yaml:"error,omitempty"` // e.g. {code: -32601}
+	Params interface{} `yaml:"params,omitempty"`
+}
+
+// LoadScenarios reads every *.yaml and *.yml scenario in dir, sorted by name
+func LoadScenarios(dir string) ([]Scenario, error) {
+	var paths []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+
+	scenarios := make([]Scenario, 0, len(paths))
+	for _, path := range paths {
+		s, err := loadScenario(path)
+		if err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, s)
+	}
+	return scenarios, nil
+}
+
+// loadScenario reads and checks one scenario file
+func loadScenario(path string) (Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Scenario{}, fmt.Errorf("failed to read scenario: %w", err)
+	}
+
+	var s Scenario
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil && !errors.Is(err, io.EOF) {
+		return Scenario{}, fmt.Errorf("invalid scenario %s: %w", path, err)
+	}
+	s.Path = path
+	if s.Name == "" {
+		s.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	for i, step := range s.Steps {
+		actions := 0
+		for _, method := range []string{step.Send, step.Notify, step.Await} {
+			if method != "" {
+				actions++
+			}
+		}
+		if actions != 1 {
+			return Scenario{}, fmt.Errorf("invalid scenario %s: step %d must have exactly one of send, notify or await", path, i+1)
+		}
+	}
+	return s, nil
+}
+
+// Run plays the scenario against a fresh server
+func (s Scenario) Run(opts ...Option) error {
+	w := Dial(opts...)
+	defer w.Close()
+
+	for i, step := range s.Steps {
+		if err := step.run(w, i+1); err != nil {
+			return fmt.Errorf("step %d (%s): %w", i+1, step.method(), err)
+		}
+	}
+	return nil
+}
+
+// method names what the step sends or waits for
+func (st ScenarioStep) method() string {
+	return st.Send + st.Notify + st.Await
+}
+
+// run performs the step; id is used for requests
+func (st ScenarioStep) run(w *Wire, id int) error {
+	msg := map[string]interface{}{"jsonrpc": "2.0", "method": st.method()}
+	if st.Params != nil {
+		msg["params"] = st.Params
+	}
+
+	switch {
+	case st.Notify != "":
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		return w.Send(data)
+
+	case st.Await != "":
+		raw, err := w.WaitNotification(st.Await)
+		if err != nil {
+			return err
+		}
+		if st.Expect == nil || st.Expect.Params == nil {
+			return nil
+		}
+		var n struct {
+			Params interface{} `json:"params"`
+		}
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return err
+		}
+		return matches(st.Expect.Params, n.Params, "params")
+	}
+
+	msg["id"] = id
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	raw, err := w.RoundTrip(data)
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Result interface{} `json:"result"`
+		Error  interface{} `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+
+	expect := st.Expect
+	if expect == nil {
+		expect = &Expectation{}
+	}
+	if expect.Error != nil {
+		if resp.Error == nil {
+			return fmt.Errorf("expected an error, got %s", raw)
+		}
+		return matches(expect.Error, resp.Error, "error")
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("unexpected error: %s", raw)
+	}
+	if expect.Result != nil {
+		return matches(expect.Result, resp.Result, "result")
+	}
+	return nil
+}
+
+// matches checks got against the expected subset want, which is first
+// converted to its JSON form so YAML and JSON values compare equal
+func matches(want, got interface{}, path string) error {
+	data, err := json.Marshal(want)
+	if err != nil {
+		return fmt.Errorf("invalid expectation: %w", err)
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return err
+	}
+	return contains(normalized, got, path)
+}
+
+// contains reports the first place got does not include want
+func contains(want, got interface{}, path string) error {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object, got %s", path, describe(got))
+		}
+		for key, value := range w {
+			actual, ok := g[key]
+			if !ok {
+				return fmt.Errorf("%s.%s: missing", path, key)
+			}
+			if err := contains(value, actual, path+"."+key); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok || len(g) != len(w) {
+			return fmt.Errorf("%s: expected %d elements, got %s", path, len(w), describe(got))
+		}
+		for i := range w {
+			if err := contains(w[i], g[i], fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		if !reflect.DeepEqual(want, got) {
+			return fmt.Errorf("%s: expected %s, got %s", path, describe(want), describe(got))
+		}
+		return nil
+	}
+}
+
+// describe renders a decoded JSON value for error messages
+func describe(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
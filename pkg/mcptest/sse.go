@@ -0,0 +1,235 @@
+// pkg/mcptest/sse.go
+package mcptest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/server"
+	"github.com/dkoosis/axe-handle/internal/transport"
+)
+
+// SSEServer is an SSE transport mounted on an httptest.Server
+type SSEServer struct {
+	URL       string
+	Server    *server.Server
+	Transport *transport.SSETransport
+
+	timeout time.Duration
+}
+
+// SSEEvent is one parsed Server-Sent Event
+type SSEEvent struct {
+	Event string // empty for the default "message" event
+	ID    string
+	Data  string
+}
+
+// SSEStream is an open event stream connection read like an EventSource
+type SSEStream struct {
+	// SessionID is taken from the first event and used when posting
+	SessionID string
+
+	// Endpoint is the absolute message URL the first event announced
+	Endpoint string
+
+	// Events delivers parsed events; it is closed when the stream ends
+	Events <-chan SSEEvent
+
+	url     string // the message endpoint
+	token   string // bearer token sent with every request; "" when anonymous
+	timeout time.Duration
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewSSEServer starts a server behind an SSE transport on an httptest.Server.
+// Everything is shut down when the test finishes.
+func NewSSEServer(tb testing.TB, opts ...Option) *SSEServer {
+	tb.Helper()
+
+	o := newOptions(opts)
+	o.cfg.Transport.Type = "sse"
+	srv, handler := build(o)
+	// Built like the server binary's, but mounted rather than listening
+	built, err := server.NewTransport(o.cfg, "")
+	if err != nil {
+		tb.Fatalf("invalid transport config: %v", err)
+	}
+	t := built.(*transport.SSETransport)
+	hs := httptest.NewServer(t.Handler(handler))
+
+	tb.Cleanup(func() {
+		hs.CloseClientConnections()
+		hs.Close()
+		_ = handler.Close()
+		_ = srv.Shutdown(context.Background())
+	})
+	return &SSEServer{URL: hs.URL, Server: srv, Transport: t, timeout: o.timeout}
+}
+
+// Connect opens an SSE stream and waits for the session to be announced
+func (s *SSEServer) Connect(tb testing.TB) *SSEStream {
+	tb.Helper()
+	return s.ConnectAs(tb, "")
+}
+
+// ConnectAs opens an SSE stream authenticated with a bearer token, as
+// configured with mcpserver.WithBearerToken, and waits for the session to
+// be announced. Messages posted to the stream carry the same token.
+func (s *SSEServer) ConnectAs(tb testing.TB, token string) *SSEStream {
+	tb.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+s.Transport.StreamPath(), nil)
+	if err != nil {
+		cancel()
+		tb.Fatalf("failed to create SSE request: %v", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		cancel()
+		tb.Fatalf("failed to open SSE stream: %v", err)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		resp.Body.Close()
+		cancel()
+		tb.Fatalf("SSE stream has Content-Type %q", ct)
+	}
+
+	events := make(chan SSEEvent, 64)
+	st := &SSEStream{Events: events, url: s.URL + s.Transport.MessagePath(), token: token, timeout: s.timeout, cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(st.done)
+		defer close(events)
+		defer resp.Body.Close()
+		readEvents(resp.Body, events)
+	}()
+	tb.Cleanup(st.Close)
+
+	first := st.Next(tb)
+	var hello struct {
+		SessionID string `json:"sessionId"`
+		Endpoint  string `json:"endpoint"`
+	}
+	if err := json.Unmarshal([]byte(first.Data), &hello); err != nil || hello.SessionID == "" {
+		tb.Fatalf("first SSE event does not announce a session: %q", first.Data)
+	}
+	st.SessionID = hello.SessionID
+	st.Endpoint = hello.Endpoint
+	return st
+}
+
+// WaitSessions waits until the transport has exactly n sessions
+func (s *SSEServer) WaitSessions(tb testing.TB, n int) {
+	tb.Helper()
+	deadline := time.Now().Add(s.timeout)
+	for s.Transport.Sessions() != n {
+		if time.Now().After(deadline) {
+			tb.Fatalf("expected %d SSE sessions, have %d after %s", n, s.Transport.Sessions(), s.timeout)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Next returns the next event, failing the test if none arrives in time or
+// the stream has ended
+func (st *SSEStream) Next(tb testing.TB) SSEEvent {
+	tb.Helper()
+	select {
+	case ev, ok := <-st.Events:
+		if !ok {
+			tb.Fatalf("SSE stream closed")
+		}
+		return ev
+	case <-time.After(st.timeout):
+		tb.Fatalf("no SSE event within %s", st.timeout)
+		return SSEEvent{}
+	}
+}
+
+// Post sends msg, encoded as JSON, to the session's message endpoint and
+// returns the HTTP status and response body
+func (st *SSEStream) Post(tb testing.TB, msg interface{}) (int, string) {
+	tb.Helper()
+	data, err := json.Marshal(msg)
+	if err != nil {
+		tb.Fatalf("failed to encode message: %v", err)
+	}
+	return st.PostRaw(tb, data)
+}
+
+// PostRaw sends data as it is, which need not be valid JSON, to the
+// session's message endpoint and returns the HTTP status and response body
+func (st *SSEStream) PostRaw(tb testing.TB, data []byte) (int, string) {
+	tb.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), st.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, st.url+"?sessionId="+st.SessionID, bytes.NewReader(data))
+	if err != nil {
+		tb.Fatalf("failed to create POST: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if st.token != "" {
+		req.Header.Set("Authorization", "Bearer "+st.token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		tb.Fatalf("POST failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+// Close disconnects the stream and waits for its reader to finish
+func (st *SSEStream) Close() {
+	st.cancel()
+	<-st.done
+}
+
+// readEvents parses an event stream into events until r ends
+func readEvents(r io.Reader, events chan<- SSEEvent) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+
+	var ev SSEEvent
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if data != nil {
+				ev.Data = strings.Join(data, "\n")
+				events <- ev
+			}
+			ev, data = SSEEvent{}, nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // comment
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			ev.Event = value
+		case "id":
+			ev.ID = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// pkg/mcptest/wire.go
+package mcptest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Wire is a raw connection to an in-memory server. Messages are written
+// and read exactly as encoded, for checking serialization byte-for-byte.
+type Wire struct {
+	p       pair
+	r       *bufio.Reader
+	timeout time.Duration
+
+	// Notifications holds notifications read while waiting for responses
+	Notifications []json.RawMessage
+}
+
+// Dial starts a server and returns a raw connection to it. Close it when done.
+func Dial(opts ...Option) *Wire {
+	o := newOptions(opts)
+	p := start(o)
+	return &Wire{p: p, r: bufio.NewReader(p.clientEnd), timeout: o.timeout}
+}
+
+// Send writes one encoded message
+func (w *Wire) Send(msg []byte) error {
+	if err := w.p.clientEnd.SetDeadline(time.Now().Add(w.timeout)); err != nil {
+		return err
+	}
+	_, err := w.p.clientEnd.Write(append(bytes.TrimSpace(msg), '\n'))
+	return err
+}
+
+// RoundTrip sends a request and returns the encoded response with the same
+// id. Notifications for requests (no id) return nil without waiting.
+func (w *Wire) RoundTrip(msg []byte) ([]byte, error) {
+	var req struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if err := w.Send(msg); err != nil {
+		return nil, err
+	}
+	if req.ID == nil {
+		return nil, nil
+	}
+
+	for {
+		raw, err := w.read()
+		if err != nil {
+			return nil, err
+		}
+		var resp struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			return nil, fmt.Errorf("invalid message from server: %w", err)
+		}
+		switch {
+		case resp.Method != "" && resp.ID == nil:
+			w.Notifications = append(w.Notifications, raw)
+		case resp.Method == "" && bytes.Equal(resp.ID, req.ID):
+			return raw, nil
+		}
+	}
+}
+
+// read returns the next newline-delimited message from the server
+func (w *Wire) read() ([]byte, error) {
+	if err := w.p.clientEnd.SetDeadline(time.Now().Add(w.timeout)); err != nil {
+		return nil, err
+	}
+	line, err := w.r.ReadBytes('\n')
+	if err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("failed to read from server: %w", err)
+	}
+	return bytes.TrimSpace(line), nil
+}
+
+// Close shuts the server down
+func (w *Wire) Close() error {
+	w.p.stop()
+	return nil
+}
+
+// WaitNotification returns the oldest notification for method, reading
+// from the server until one arrives
+func (w *Wire) WaitNotification(method string) ([]byte, error) {
+	for {
+		for i, raw := range w.Notifications {
+			var n struct {
+				Method string `json:"method"`
+			}
+			if json.Unmarshal(raw, &n) == nil && n.Method == method {
+				w.Notifications = append(w.Notifications[:i], w.Notifications[i+1:]...)
+				return raw, nil
+			}
+		}
+
+		raw, err := w.read()
+		if err != nil {
+			return nil, fmt.Errorf("waiting for %s: %w", method, err)
+		}
+		var msg struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			return nil, fmt.Errorf("invalid message from server: %w", err)
+		}
+		if msg.Method != "" && msg.ID == nil {
+			w.Notifications = append(w.Notifications, raw)
+		}
+	}
+}
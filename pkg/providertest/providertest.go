@@ -0,0 +1,222 @@
+// pkg/providertest/providertest.go
+
+// Package providertest checks that a resources, tools or prompts provider
+// keeps the contract the server relies on. Call Run from a provider's tests:
+//
+//	func TestContract(t *testing.T) {
+//		providertest.Run(t, myprovider.New())
+//	}
+//
+// Every provider interface p implements is checked in its own subtest.
+package providertest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/prompts"
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/mcp/resources"
+	"github.com/dkoosis/axe-handle/internal/mcp/tools"
+	jsonschema "github.com/xeipuuv/gojsonschema"
+)
+
+// Names that no provider is expected to serve
+const (
+	missingURI  = "providertest://missing"
+	missingName = "providertest-missing"
+)
+
+// Run checks every provider interface p implements. It fails the test if p
+// implements none of them.
+func Run(t *testing.T, p interface{}) {
+	t.Helper()
+
+	checked := false
+	if rp, ok := p.(resources.Provider); ok {
+		checked = true
+		t.Run("resources", func(t *testing.T) { RunResources(t, rp) })
+	}
+	if tp, ok := p.(tools.Provider); ok {
+		checked = true
+		t.Run("tools", func(t *testing.T) { RunTools(t, tp) })
+	}
+	if pp, ok := p.(prompts.Provider); ok {
+		checked = true
+		t.Run("prompts", func(t *testing.T) { RunPrompts(t, pp) })
+	}
+	if !checked {
+		t.Fatalf("%T implements no provider interface", p)
+	}
+}
+
+// RunResources checks that listing is deterministic, URIs are unique and
+// absolute, every listed resource can be read, and unknown URIs report
+// resources.ErrResourceNotFound
+func RunResources(t *testing.T, p resources.Provider) {
+	t.Helper()
+
+	list := deterministic(t, "ListResources", p.ListResources)
+	seen := make(map[string]bool)
+	for _, r := range list {
+		if r.Name == "" {
+			t.Errorf("resource %q has no name", r.URI)
+		}
+		if u, err := url.Parse(r.URI); err != nil || u.Scheme == "" {
+			t.Errorf("resource URI %q is not an absolute URI", r.URI)
+		}
+		if seen[r.URI] {
+			t.Errorf("resource URI %q is listed more than once", r.URI)
+		}
+		seen[r.URI] = true
+
+		if _, err := p.GetResource(r.URI); err != nil {
+			t.Errorf("GetResource(%q) for a listed resource failed: %v", r.URI, err)
+		}
+	}
+
+	if _, err := p.GetResource(missingURI); !errors.Is(err, resources.ErrResourceNotFound) {
+		t.Errorf("GetResource(%q) = %v, want an error wrapping resources.ErrResourceNotFound", missingURI, err)
+	}
+}
+
+// RunTools checks that listing is deterministic, names are unique, input
+// schemas are valid JSON Schema objects, and unknown tools report
+// tools.ErrToolNotFound
+func RunTools(t *testing.T, p tools.Provider) {
+	t.Helper()
+
+	list := deterministic(t, "ListTools", p.ListTools)
+	seen := make(map[string]bool)
+	for _, tool := range list {
+		if tool.Name == "" {
+			t.Errorf("tool %+v has no name", tool)
+		}
+		if seen[tool.Name] {
+			t.Errorf("tool %q is listed more than once", tool.Name)
+		}
+		seen[tool.Name] = true
+
+		if err := checkSchema(tool.InputSchema); err != nil {
+			t.Errorf("tool %q: %v", tool.Name, err)
+		}
+	}
+
+	if _, err := p.ExecuteTool(missingName, map[string]interface{}{}); !errors.Is(err, tools.ErrToolNotFound) {
+		t.Errorf("ExecuteTool(%q) = %v, want an error wrapping tools.ErrToolNotFound", missingName, err)
+	}
+}
+
+// RunPrompts checks that listing is deterministic, names and argument names
+// are unique, every prompt renders with its required arguments, and unknown
+// prompts report prompts.ErrPromptNotFound
+func RunPrompts(t *testing.T, p prompts.Provider) {
+	t.Helper()
+
+	list := deterministic(t, "ListPrompts", p.ListPrompts)
+	seen := make(map[string]bool)
+	for _, prompt := range list {
+		if prompt.Name == "" {
+			t.Errorf("prompt %+v has no name", prompt)
+		}
+		if seen[prompt.Name] {
+			t.Errorf("prompt %q is listed more than once", prompt.Name)
+		}
+		seen[prompt.Name] = true
+
+		args := make(map[string]string)
+		for _, arg := range prompt.Arguments {
+			if arg.Name == "" {
+				t.Errorf("prompt %q has an argument with no name", prompt.Name)
+			}
+			if _, dup := args[arg.Name]; dup {
+				t.Errorf("prompt %q declares argument %q more than once", prompt.Name, arg.Name)
+			}
+			args[arg.Name] = "providertest"
+		}
+
+		result, err := p.GetPrompt(prompt.Name, args)
+		if err != nil {
+			t.Errorf("GetPrompt(%q) with all arguments failed: %v", prompt.Name, err)
+			continue
+		}
+		if err := checkPromptResult(result); err != nil {
+			t.Errorf("GetPrompt(%q): %v", prompt.Name, err)
+		}
+	}
+
+	if _, err := p.GetPrompt(missingName, nil); !errors.Is(err, prompts.ErrPromptNotFound) {
+		t.Errorf("GetPrompt(%q) = %v, want an error wrapping prompts.ErrPromptNotFound", missingName, err)
+	}
+}
+
+// deterministic calls list twice and fails unless both calls succeed with
+// the same result
+func deterministic[T any](t *testing.T, name string, list func() ([]T, error)) []T {
+	t.Helper()
+
+	first, err := list()
+	if err != nil {
+		t.Fatalf("%s failed: %v", name, err)
+	}
+	second, err := list()
+	if err != nil {
+		t.Fatalf("second %s failed: %v", name, err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("%s is not deterministic:\n first: %+v\nsecond: %+v", name, first, second)
+	}
+	return first
+}
+
+// checkSchema reports whether schema is a usable tool input schema
+func checkSchema(schema interface{}) error {
+	if schema == nil {
+		return fmt.Errorf("input schema is missing")
+	}
+	if _, err := jsonschema.NewSchema(jsonschema.NewGoLoader(schema)); err != nil {
+		return fmt.Errorf("input schema is invalid: %w", err)
+	}
+
+	// The server validates calls without arguments as {}, so an object schema is required
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("input schema cannot be encoded: %w", err)
+	}
+	var top struct {
+		Type interface{} `json:"type"`
+	}
+	if err := json.Unmarshal(data, &top); err != nil || top.Type != "object" {
+		return fmt.Errorf(`input schema must have "type": "object"`)
+	}
+	return nil
+}
+
+// checkPromptResult validates a GetPrompt result the way the server will
+func checkPromptResult(result interface{}) error {
+	var rendered protocol.PromptsGetResult
+	switch r := result.(type) {
+	case protocol.PromptsGetResult:
+		rendered = r
+	case *protocol.PromptsGetResult:
+		if r == nil {
+			return fmt.Errorf("returned a nil result")
+		}
+		rendered = *r
+	case []protocol.PromptMessage:
+		rendered.Messages = r
+	default:
+		if _, err := json.Marshal(result); err != nil {
+			return fmt.Errorf("result cannot be encoded: %w", err)
+		}
+		return nil
+	}
+	if len(rendered.Messages) == 0 {
+		return fmt.Errorf("returned no messages")
+	}
+	return rendered.Validate()
+}
@@ -0,0 +1,341 @@
+// pkg/state/state.go
+
+// Package state is the embedded persistent store shared by providers. One
+// bbolt file holds a namespace per provider, so notes, audit trails and
+// idempotency keys survive restarts without each provider inventing its
+// own file format. Deployments shared by several teams give each tenant a
+// view of its own with Store.Tenant.
+//
+// Values are opaque bytes; GetJSON and PutJSON cover the common case of
+// storing structs. Entries written with PutTTL expire and read as missing
+// once their time has passed; Sweep reclaims their space.
+package state
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// DefaultOpenTimeout bounds how long Open waits for another process to
+// release the file
+const DefaultOpenTimeout = time.Second
+
+// Errors returned by the store
+var (
+	ErrNotFound     = errors.New("state: key not found")
+	ErrBadNamespace = errors.New("state: namespace name must be non-empty and must not contain '/'")
+	ErrBadTenant    = errors.New("state: tenant name must be non-empty and must not contain '/'")
+)
+
+// Store is an open state file, or one tenant's view of it. It is safe for
+// concurrent use.
+type Store struct {
+	db     *bolt.DB
+	now    func() time.Time
+	prefix string // "<tenant>/" in a tenant's view; empty for the whole file
+}
+
+// Open opens or creates the store at path, creating its directory if
+// needed. A zero timeout uses DefaultOpenTimeout.
+func Open(path string, timeout time.Duration) (*Store, error) {
+	if timeout <= 0 {
+		timeout = DefaultOpenTimeout
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: timeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state store %s: %w", path, err)
+	}
+	return &Store{db: db, now: time.Now}, nil
+}
+
+// Close releases the file. Closing a tenant's view does nothing; the file
+// stays open until the store Open returned is closed.
+func (s *Store) Close() error {
+	if s.prefix != "" {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// Path returns the file the store lives in
+func (s *Store) Path() string {
+	return s.db.Path()
+}
+
+// Tenant returns the view of the store belonging to the named tenant. Its
+// namespaces are separate from the same names at the root and in every
+// other tenant's view, so one provider instance per tenant can share the
+// file without seeing another tenant's data.
+func (s *Store) Tenant(name string) (*Store, error) {
+	if name == "" || strings.Contains(name, "/") {
+		return nil, ErrBadTenant
+	}
+	return &Store{db: s.db, now: s.now, prefix: s.prefix + name + "/"}, nil
+}
+
+// Namespace returns the namespace called name, which is created on first
+// write. Providers use their own name so their keys never collide.
+func (s *Store) Namespace(name string) (*Namespace, error) {
+	if name == "" || strings.Contains(name, "/") {
+		return nil, ErrBadNamespace
+	}
+	return &Namespace{store: s, name: []byte(s.prefix + name)}, nil
+}
+
+// Namespaces returns the names of the namespaces that hold data in this
+// view; tenants' namespaces are not included at the root
+func (s *Store) Namespaces() ([]string, error) {
+	var names []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			if rest, ok := strings.CutPrefix(string(name), s.prefix); ok && !strings.Contains(rest, "/") {
+				names = append(names, rest)
+			}
+			return nil
+		})
+	})
+	return names, err
+}
+
+// Sweep deletes expired entries in every namespace of this view, and at
+// the root in every tenant's too, and returns how many it removed
+func (s *Store) Sweep() (int, error) {
+	now := s.now()
+	removed := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			if !strings.HasPrefix(string(name), s.prefix) {
+				return nil
+			}
+			var expired [][]byte
+			err := b.ForEach(func(k, v []byte) error {
+				if _, ok := decode(v, now); !ok {
+					expired = append(expired, append([]byte(nil), k...))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, k := range expired {
+				if err := b.Delete(k); err != nil {
+					return err
+				}
+			}
+			removed += len(expired)
+			return nil
+		})
+	})
+	return removed, err
+}
+
+// Namespace is one provider's share of the store
+type Namespace struct {
+	store *Store
+	name  []byte
+}
+
+// Name returns the namespace's name
+func (n *Namespace) Name() string {
+	return strings.TrimPrefix(string(n.name), n.store.prefix)
+}
+
+// Get returns the value stored under key, or ErrNotFound
+func (n *Namespace) Get(key string) ([]byte, error) {
+	var value []byte
+	err := n.View(func(tx *Tx) error {
+		v, err := tx.Get(key)
+		value = v
+		return err
+	})
+	return value, err
+}
+
+// Put stores value under key, replacing any previous value
+func (n *Namespace) Put(key string, value []byte) error {
+	return n.Update(func(tx *Tx) error {
+		return tx.Put(key, value)
+	})
+}
+
+// PutTTL stores value under key until ttl has passed
+func (n *Namespace) PutTTL(key string, value []byte, ttl time.Duration) error {
+	return n.Update(func(tx *Tx) error {
+		return tx.PutTTL(key, value, ttl)
+	})
+}
+
+// Delete removes key; deleting a missing key is not an error
+func (n *Namespace) Delete(key string) error {
+	return n.Update(func(tx *Tx) error {
+		return tx.Delete(key)
+	})
+}
+
+// GetJSON decodes the value under key into v
+func (n *Namespace) GetJSON(key string, v interface{}) error {
+	data, err := n.Get(key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// PutJSON stores v encoded as JSON under key
+func (n *Namespace) PutJSON(key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return n.Put(key, data)
+}
+
+// Scan calls fn for each live entry whose key starts with prefix, in key
+// order. Returning an error from fn stops the scan and returns it.
+func (n *Namespace) Scan(prefix string, fn func(key string, value []byte) error) error {
+	return n.View(func(tx *Tx) error {
+		return tx.Scan(prefix, fn)
+	})
+}
+
+// NextSequence returns a number larger than any it returned before, for
+// keys that must sort in insertion order such as log entries
+func (n *Namespace) NextSequence() (uint64, error) {
+	var seq uint64
+	err := n.Update(func(tx *Tx) error {
+		var err error
+		seq, err = tx.bucket.NextSequence()
+		return err
+	})
+	return seq, err
+}
+
+// View runs fn in a read-only transaction
+func (n *Namespace) View(fn func(tx *Tx) error) error {
+	return n.store.db.View(func(btx *bolt.Tx) error {
+		b := btx.Bucket(n.name)
+		if b == nil {
+			// Nothing written yet; reads see an empty namespace
+			return fn(&Tx{now: n.store.now()})
+		}
+		return fn(&Tx{bucket: b, now: n.store.now()})
+	})
+}
+
+// Update runs fn in a read-write transaction. Its writes are applied
+// together when fn returns nil and discarded when it returns an error.
+func (n *Namespace) Update(fn func(tx *Tx) error) error {
+	return n.store.db.Update(func(btx *bolt.Tx) error {
+		b, err := btx.CreateBucketIfNotExists(n.name)
+		if err != nil {
+			return err
+		}
+		return fn(&Tx{bucket: b, now: n.store.now(), writable: true})
+	})
+}
+
+// Tx is a transaction within one namespace. It is only valid inside the
+// View or Update call that created it, and so are the values it returns.
+type Tx struct {
+	bucket   *bolt.Bucket // nil in a view of an empty namespace
+	now      time.Time
+	writable bool
+}
+
+// Get returns the value stored under key, or ErrNotFound
+func (tx *Tx) Get(key string) ([]byte, error) {
+	if tx.bucket == nil {
+		return nil, ErrNotFound
+	}
+	value, ok := decode(tx.bucket.Get([]byte(key)), tx.now)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), value...), nil
+}
+
+// Put stores value under key
+func (tx *Tx) Put(key string, value []byte) error {
+	return tx.put(key, value, time.Time{})
+}
+
+// PutTTL stores value under key until ttl has passed
+func (tx *Tx) PutTTL(key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("state: ttl must be positive")
+	}
+	return tx.put(key, value, tx.now.Add(ttl))
+}
+
+// put stores an encoded entry
+func (tx *Tx) put(key string, value []byte, expires time.Time) error {
+	if !tx.writable {
+		return bolt.ErrTxNotWritable
+	}
+	if key == "" {
+		return fmt.Errorf("state: key must not be empty")
+	}
+	return tx.bucket.Put([]byte(key), encode(value, expires))
+}
+
+// Delete removes key
+func (tx *Tx) Delete(key string) error {
+	if !tx.writable {
+		return bolt.ErrTxNotWritable
+	}
+	return tx.bucket.Delete([]byte(key))
+}
+
+// Scan calls fn for each live entry whose key starts with prefix, in key order
+func (tx *Tx) Scan(prefix string, fn func(key string, value []byte) error) error {
+	if tx.bucket == nil {
+		return nil
+	}
+	c := tx.bucket.Cursor()
+	p := []byte(prefix)
+	for k, v := c.Seek(p); k != nil && strings.HasPrefix(string(k), prefix); k, v = c.Next() {
+		value, ok := decode(v, tx.now)
+		if !ok {
+			continue
+		}
+		if err := fn(string(k), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Entries are stored as an 8-byte big-endian expiry in Unix nanoseconds,
+// zero for none, followed by the value
+
+// encode prefixes value with its expiry
+func encode(value []byte, expires time.Time) []byte {
+	buf := make([]byte, 8+len(value))
+	if !expires.IsZero() {
+		binary.BigEndian.PutUint64(buf, uint64(expires.UnixNano()))
+	}
+	copy(buf[8:], value)
+	return buf
+}
+
+// decode returns an entry's value unless it is missing or expired at now
+func decode(entry []byte, now time.Time) ([]byte, bool) {
+	if len(entry) < 8 {
+		return nil, false
+	}
+	if exp := binary.BigEndian.Uint64(entry); exp != 0 && now.UnixNano() >= int64(exp) {
+		return nil, false
+	}
+	return entry[8:], true
+}
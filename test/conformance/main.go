@@ -0,0 +1,112 @@
+// test/conformance/main.go
+
+// Command conformance replays the recorded protocol fixtures in testdata
+// against an in-memory server and fails if any response's encoding has
+// changed, then plays the YAML scenarios in scenarios. Run with -update to
+// re-record the fixtures after an intended change. go test runs the same
+// checks.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dkoosis/axe-handle/internal/mcp/protocol"
+	"github.com/dkoosis/axe-handle/internal/providers/example"
+	"github.com/dkoosis/axe-handle/pkg/mcpserver"
+	"github.com/dkoosis/axe-handle/pkg/mcptest"
+)
+
+func main() {
+	dir := flag.String("dir", "test/conformance/testdata", "Directory of fixture files")
+	scenarioDir := flag.String("scenarios", "test/conformance/scenarios", "Directory of scenario files")
+	update := flag.Bool("update", false, "Re-record fixture responses instead of comparing them")
+	flag.Parse()
+
+	fixtures, err := mcptest.LoadFixtures(*dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "conformance: %v\n", err)
+		os.Exit(1)
+	}
+	if len(fixtures) == 0 {
+		fmt.Fprintf(os.Stderr, "conformance: no fixtures in %s\n", *dir)
+		os.Exit(1)
+	}
+
+	scenarios, err := mcptest.LoadScenarios(*scenarioDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "conformance: %v\n", err)
+		os.Exit(1)
+	}
+
+	failed := 0
+	report := func(name string, err error) {
+		if err != nil {
+			failed++
+			fmt.Printf("FAIL %s: %v\n", name, err)
+			return
+		}
+		fmt.Printf("ok   %s\n", name)
+	}
+
+	for _, f := range fixtures {
+		if *update {
+			err = f.Record(serverOptions()...)
+			if err == nil {
+				err = f.Save()
+			}
+		} else {
+			err = f.Run(serverOptions()...)
+		}
+		report(f.Name, err)
+	}
+	if !*update {
+		for _, s := range scenarios {
+			report(s.Name, s.Run(serverOptions()...))
+		}
+	}
+
+	if total := len(fixtures) + len(scenarios); failed > 0 {
+		fmt.Printf("%d of %d checks failed\n", failed, total)
+		os.Exit(1)
+	}
+}
+
+// serverOptions configures the server the fixtures were recorded against
+func serverOptions() []mcptest.Option {
+	provider := example.NewProvider()
+	return []mcptest.Option{
+		mcptest.WithServerOptions(mcpserver.WithName("conformance", "1.0.0")),
+		mcptest.WithResourceProvider(provider),
+		mcptest.WithToolProvider(provider),
+		mcptest.WithPromptProvider(provider),
+		mcptest.WithTool(echoTool, echo),
+	}
+}
+
+// echoTool is a tool registered directly with the tools manager
+var echoTool = protocol.Tool{
+	Name:        "echo",
+	Description: "Echoes back the input",
+	InputSchema: map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"message": map[string]interface{}{"type": "string"},
+		},
+		"required": []string{"message"},
+	},
+}
+
+// echo returns its message argument as text
+func echo(ctx context.Context, args json.RawMessage, progress chan<- float64) (protocol.ToolsCallResult, error) {
+	var params struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return protocol.ToolsCallResult{}, err
+	}
+	return protocol.ToolsCallResult{Content: []protocol.Content{protocol.TextContent(params.Message)}}, nil
+}
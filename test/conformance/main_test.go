@@ -0,0 +1,45 @@
+// test/conformance/main_test.go
+package main
+
+import (
+	"testing"
+
+	"github.com/dkoosis/axe-handle/pkg/mcptest"
+)
+
+// TestConformance replays the recorded fixtures, so go test fails when a
+// response's encoding changes; re-record with go run ./test/conformance -update
+func TestConformance(t *testing.T) {
+	fixtures, err := mcptest.LoadFixtures("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no fixtures in testdata")
+	}
+	for _, f := range fixtures {
+		t.Run(f.Name, func(t *testing.T) {
+			if err := f.Run(serverOptions()...); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// TestScenarios plays the YAML scenarios against the same server
+func TestScenarios(t *testing.T) {
+	scenarios, err := mcptest.LoadScenarios("scenarios")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scenarios) == 0 {
+		t.Fatal("no scenarios in scenarios")
+	}
+	for _, s := range scenarios {
+		t.Run(s.Name, func(t *testing.T) {
+			if err := s.Run(serverOptions()...); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}